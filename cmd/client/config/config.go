@@ -8,19 +8,21 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env"
 	"gopkg.in/yaml.v3"
 )
 
 type config struct {
-	Address        string `yaml:"address" env:"ADDRESS"`
-	ConfigPath     string `yaml:"config_path,omitempty" env:"CONFIG"`
-	LogLevel       string `yaml:"log_level" env:"LOG_LEVEL"`
-	LogType        string `yaml:"log_type" env:"LOG_TYPE"`
-	LogOutputPaths string `yaml:"log_output_paths" env:"LOG_OUTPUT_PATHS"`
-	CertPath       string `yaml:"cert_path" env:"CERT_PATH"`
-	DSN            string `yaml:"dsn" env:"DSN"`
+	Address          string        `yaml:"address" env:"ADDRESS"`
+	ConfigPath       string        `yaml:"config_path,omitempty" env:"CONFIG"`
+	LogLevel         string        `yaml:"log_level" env:"LOG_LEVEL"`
+	LogType          string        `yaml:"log_type" env:"LOG_TYPE"`
+	LogOutputPaths   string        `yaml:"log_output_paths" env:"LOG_OUTPUT_PATHS"`
+	CertPath         string        `yaml:"cert_path" env:"CERT_PATH"`
+	DSN              string        `yaml:"dsn" env:"DSN"`
+	ClipboardTimeout time.Duration `yaml:"clipboard_timeout" env:"CLIPBOARD_TIMEOUT"`
 }
 
 //go:embed config.yaml
@@ -94,6 +96,7 @@ func (c *config) readFlags() {
 	flag.StringVar(&c.LogOutputPaths, "log_output_paths", c.LogOutputPaths, "log output paths")
 	flag.StringVar(&c.CertPath, "cert_path", c.CertPath, "cert path")
 	flag.StringVar(&c.DSN, "dsn", c.DSN, "database DSN")
+	flag.DurationVar(&c.ClipboardTimeout, "clipboard_timeout", c.ClipboardTimeout, "copied secret clearing timeout")
 	flag.Parse()
 }
 
@@ -116,12 +119,13 @@ func (c config) print() {
 func (c *config) toConfig() clientcfg.Config {
 	cert := c.readCert()
 	return clientcfg.Config{
-		Address:        c.Address,
-		LogLevel:       c.LogLevel,
-		LogType:        c.LogType,
-		LogOutputPaths: c.parseLogOutputPaths(),
-		Cert:           cert,
-		DSN:            c.DSN,
+		Address:          c.Address,
+		LogLevel:         c.LogLevel,
+		LogType:          c.LogType,
+		LogOutputPaths:   c.parseLogOutputPaths(),
+		Cert:             cert,
+		DSN:              c.DSN,
+		ClipboardTimeout: c.ClipboardTimeout,
 	}
 }
 
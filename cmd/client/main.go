@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"flag"
 	"github.com/dlomanov/gophkeeper/cmd/client/config"
 	"github.com/dlomanov/gophkeeper/internal/apps/client"
 	"log"
+	"os"
 )
 
 var (
@@ -18,6 +20,12 @@ func main() {
 	c.BuildVersion = buildVersion
 	c.BuildDate = buildDate
 	c.BuildCommit = buildCommit
+	if args := flag.Args(); len(args) != 0 {
+		if err := client.RunCLI(context.Background(), &c, args); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
 	if err := client.Run(context.Background(), &c); err != nil {
 		log.Fatal(err)
 	}
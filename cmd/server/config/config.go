@@ -20,9 +20,10 @@ type config struct {
 	PassHashCost   int           `yaml:"pass_hash_cost" env:"PASS_HASH_COST"`
 	TokenSecretKey string        `yaml:"token_secret_key" env:"TOKEN_SECRET_KEY"`
 	TokenExpires   time.Duration `yaml:"token_expires" env:"TOKEN_EXPIRES"`
+	RefreshExpires time.Duration `yaml:"refresh_expires" env:"REFRESH_EXPIRES"`
+	TrashRetention time.Duration `yaml:"trash_retention" env:"TRASH_RETENTION"`
 	LogLevel       string        `yaml:"log_level" env:"LOG_LEVEL"`
 	LogType        string        `yaml:"log_type" env:"LOG_TYPE"`
-	DataSecretKey  string        `yaml:"data_secret_key" env:"DATA_SECRET_KEY"`
 	CertPath       string        `yaml:"cert_path" env:"CERT_PATH"`
 	CertKeyPath    string        `yaml:"cert_key_path" env:"CERT_KEY_PATH"`
 }
@@ -86,9 +87,10 @@ func (c *config) readFlags() {
 	flag.IntVar(&c.PassHashCost, "pass_hash_cost", c.PassHashCost, "password hash cost")
 	flag.StringVar(&c.TokenSecretKey, "token_secret_key", c.TokenSecretKey, "token secret key")
 	flag.DurationVar(&c.TokenExpires, "token_expires", c.TokenExpires, "token expires")
+	flag.DurationVar(&c.RefreshExpires, "refresh_expires", c.RefreshExpires, "refresh token expires")
+	flag.DurationVar(&c.TrashRetention, "trash_retention", c.TrashRetention, "deleted entries retention, 0 keeps forever")
 	flag.StringVar(&c.LogLevel, "log_level", c.LogLevel, "log level")
 	flag.StringVar(&c.LogType, "log_type", c.LogType, "log type")
-	flag.StringVar(&c.DataSecretKey, "data_secret_key", c.DataSecretKey, "data secret key 16/24/32 bytes")
 	flag.StringVar(&c.CertPath, "cert_path", c.CertPath, "TLS-certificate file path")
 	flag.StringVar(&c.CertKeyPath, "cert_key_path", c.CertKeyPath, "TLS-certificate key file path")
 	flag.Parse()
@@ -103,7 +105,6 @@ func (c *config) readEnv() {
 
 func (c config) print() {
 	c.TokenSecretKey = "**********"
-	c.DataSecretKey = "**********"
 	c.CertPath = "**********"
 	c.CertKeyPath = "**********"
 	content, err := yaml.Marshal(c)
@@ -125,9 +126,10 @@ func (c *config) toConfig() *srvcfg.Config {
 		PassHashCost:   c.PassHashCost,
 		TokenSecretKey: []byte(c.TokenSecretKey),
 		TokenExpires:   c.TokenExpires,
+		RefreshExpires: c.RefreshExpires,
+		TrashRetention: c.TrashRetention,
 		LogLevel:       c.LogLevel,
 		LogType:        c.LogType,
-		DataSecretKey:  []byte(c.DataSecretKey),
 		Cert:           cert,
 		CertKey:        certKey,
 	}
@@ -28,7 +28,6 @@ import (
 	"net"
 	"reflect"
 	"slices"
-	"strings"
 	"testing"
 	"time"
 )
@@ -70,7 +69,6 @@ func (s *AppSuite) SetupSuite() {
 	c.Cert, c.CertKey = s.readCert()
 	s.cert = c.Cert
 	c.TokenSecretKey = s.generateKey()
-	c.DataSecretKey = s.generateKey()
 	s.pgc, c.DatabaseDSN, err = testcont.RunPostgres(s.teardownCtx, c.DatabaseDSN)
 	require.NoError(s.T(), err, "failed to run postgres container")
 
@@ -151,6 +149,7 @@ func (s *AppSuite) TestApp() {
 	require.NoError(s.T(), err, "no error expected")
 	require.NotNil(s.T(), signInResp, "expected response not nil")
 	require.NotEmpty(s.T(), signInResp.Token, "expected token not empty")
+	require.Equal(s.T(), signUpResp.VaultSalt, signInResp.VaultSalt, "expected same vault salt")
 
 	// 2 Entries
 	entryService := pb.NewEntryServiceClient(conn)
@@ -217,7 +216,7 @@ func (s *AppSuite) TestApp() {
 	deleted, err := entryService.Delete(ctx, &pb.DeleteEntryRequest{Id: entries[0].Id})
 	require.NoError(s.T(), err, "no error expected")
 	assert.Equal(s.T(), entries[0].Id, deleted.Id, "deleted entry id mismatch")
-	assert.Equal(s.T(), entries[0].Version, deleted.Version, "deleted entry version mismatch")
+	assert.Equal(s.T(), entries[0].Version+1, deleted.Version, "deleted entry version mismatch")
 	entries = entries[1:]
 	getAll, err = entryService.GetAll(ctx, &pb.GetEntriesRequest{})
 	require.NoError(s.T(), err, "no error expected")
@@ -259,30 +258,37 @@ func (s *AppSuite) TestApp() {
 	assert.Equal(s.T(), updated.Version, get.Entry.Version, "get entry version mismatch")
 
 	// 3 Conflict resolution
-	entries[0].Meta["updated_key"] = "updated_value"
-	entries[0].Data = []byte("updated_data")
-	entries[0].Version = entries[0].Version + 10
-	conflict, err := entryService.Update(ctx, &pb.UpdateEntryRequest{
+	conflictData := []byte("conflict_data")
+	_, err = entryService.Update(ctx, &pb.UpdateEntryRequest{
 		Id:      entries[0].Id,
 		Version: entries[0].Version,
 		Meta:    entries[0].Meta,
-		Data:    entries[0].Data,
+		Data:    conflictData,
 	})
-	require.NoError(s.T(), err, "no error expected on update")
-	require.NotEqual(s.T(), entries[0].Id, conflict.Id, "expected updated id to be different")
-	require.NotEqual(s.T(), entries[0].Version, conflict.Version, "expected updated version to be different")
-	require.Equal(s.T(), int64(1), conflict.Version, "expected conflict version to be 1")
-	get, err = entryService.Get(ctx, &pb.GetEntryRequest{Id: conflict.Id})
-	require.NoError(s.T(), err, "no error expected on get")
-	require.NotNil(s.T(), get, "expected response not nil")
-	require.NotNil(s.T(), get.Entry, "expected entry not nil")
-	assert.Equal(s.T(), conflict.Id, get.Entry.Id, "get entry id mismatch")
-	assert.NotEqual(s.T(), entries[0].Key, get.Entry.Key, "get entry key mismatch")
-	assert.True(s.T(), strings.HasPrefix(get.Entry.Key, entries[0].Key), "get entry key prefix mismatch")
-	assert.Equal(s.T(), entries[0].Type, get.Entry.Type, "get entry type mismatch")
-	assert.True(s.T(), reflect.DeepEqual(entries[0].Meta, get.Entry.Meta), "get entry meta mismatch")
-	assert.Equal(s.T(), entries[0].Data, get.Entry.Data, "get entry data mismatch")
-	assert.Equal(s.T(), conflict.Version, get.Entry.Version, "get entry version mismatch")
+	require.Error(s.T(), err, "expected conflict error on stale update")
+	require.Equal(s.T(), codes.Aborted, status.Code(err), "expected aborted code")
+	var conflict *pb.EntryConflict
+	for _, detail := range status.Convert(err).Details() {
+		if v, ok := detail.(*pb.EntryConflict); ok {
+			conflict = v
+		}
+	}
+	require.NotNil(s.T(), conflict, "expected conflict details")
+	assert.Equal(s.T(), entries[0].Id, conflict.Server.Id, "conflict server entry id mismatch")
+	assert.Equal(s.T(), updated.Version, conflict.Server.Version, "conflict server version mismatch")
+	assert.Equal(s.T(), entries[0].Data, conflict.Server.Data, "conflict server data mismatch")
+	assert.Equal(s.T(), conflictData, conflict.Client.Data, "conflict client data mismatch")
+	require.NotNil(s.T(), conflict.Base, "expected conflict base version")
+	assert.Equal(s.T(), entries[0].Version, conflict.Base.Version, "conflict base version mismatch")
+	_, err = entryService.Create(ctx, &pb.CreateEntryRequest{
+		Key:  entries[0].Key,
+		Type: entries[0].Type,
+		Data: conflictData,
+	})
+	require.Equal(s.T(), codes.Aborted, status.Code(err), "expected aborted code on key conflict")
+	getAll, err = entryService.GetAll(ctx, &pb.GetEntriesRequest{})
+	require.NoError(s.T(), err, "no error expected")
+	require.Len(s.T(), getAll.Entries, len(entries), "expected conflicts do not create entries")
 
 	// 4 Get difference
 	getAll, err = entryService.GetAll(ctx, &pb.GetEntriesRequest{})
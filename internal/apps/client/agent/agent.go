@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	SocketEnv          = "GOPHKEEPER_AGENT_SOCK"
+	DefaultIdleTimeout = 15 * time.Minute
+	serviceName        = "Agent"
+)
+
+var (
+	ErrAgentRunning = errors.New("agent: agent is already running")
+	ErrReadOnly     = errors.New("agent: entries can't be changed through the agent")
+	// ErrSocketDirUnsafe is returned when the socket directory could be accessed by other users
+	ErrSocketDirUnsafe = errors.New("agent: socket directory is unsafe")
+)
+
+type (
+	EntryUC interface {
+		GetAll(ctx context.Context) (entities.GetEntriesResponse, error)
+	}
+	GetAllRequest  struct{}
+	GetAllResponse struct {
+		Entries []Entry
+	}
+	// Entry is the unlocked entry, data is marshaled with the entry marshaler
+	Entry struct {
+		ID            uuid.UUID
+		Key           string
+		Type          core.EntryType
+		Meta          map[string]string
+		Folder        string
+		Tags          []string
+		Data          []byte
+		GlobalVersion int64
+		Version       int64
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+	}
+)
+
+// SocketPath returns the agent socket path from the environment,
+// otherwise the socket is placed in the user runtime directory
+func SocketPath(lookupEnv func(key string) (string, bool)) string {
+	if path, ok := lookupEnv(SocketEnv); ok && path != "" {
+		return path
+	}
+	dir, ok := lookupEnv("XDG_RUNTIME_DIR")
+	if !ok || dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("gophkeeper-%d", os.Getuid()), "agent.sock")
+}
+
+// Listen creates the socket available only to the current user in the directory private to the user,
+// the stale socket left by the stopped agent is removed
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("agent: failed to create socket directory: %w", err)
+	}
+	if err := checkSocketDir(dir); err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrAgentRunning, path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("agent: failed to remove stale socket: %w", err)
+	}
+	l, err := listenSocket(path)
+	if err != nil {
+		return nil, fmt.Errorf("agent: failed to listen: %w", err)
+	}
+	if err = os.Chmod(path, 0o600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("agent: failed to restrict socket permissions: %w", err)
+	}
+	return l, nil
+}
@@ -0,0 +1,84 @@
+package agent_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/agent"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type entryUC struct {
+	entries []entities.GetEntryResponse
+}
+
+func (uc entryUC) GetAll(context.Context) (entities.GetEntriesResponse, error) {
+	return entities.GetEntriesResponse{Entries: uc.entries}, nil
+}
+
+func TestAgent(t *testing.T) {
+	ctx := context.Background()
+	// socket path length is limited, so the short temp dir is used
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "gophkeeper", "agent.sock")
+
+	entries := []entities.GetEntryResponse{
+		{
+			ID:        uuid.New(),
+			Key:       "db",
+			Type:      core.EntryTypePassword,
+			Meta:      map[string]string{"env": "prod"},
+			Data:      entities.EntryDataPassword{Login: "admin", Password: "1"},
+			Version:   1,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		},
+		{
+			ID:        uuid.New(),
+			Key:       "file",
+			Type:      core.EntryTypeBinary,
+			Data:      entities.EntryDataBinary{0, 1, 2},
+			Version:   2,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		},
+	}
+	l, err := agent.Listen(path)
+	require.NoError(t, err, "failed to listen")
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm(), "socket should be available only to the user")
+	_, err = agent.Listen(path)
+	require.ErrorIs(t, err, agent.ErrAgentRunning)
+
+	served := make(chan error, 1)
+	go func() { served <- agent.NewServer(entryUC{entries: entries}, 500*time.Millisecond).Serve(ctx, l) }()
+
+	client, err := agent.Dial(path)
+	require.NoError(t, err, "failed to connect")
+	got, err := client.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Equal(t, entries, got.Entries)
+	require.ErrorIs(t, client.Delete(ctx, entities.DeleteEntryRequest{ID: entries[0].ID}), agent.ErrReadOnly)
+	require.NoError(t, client.Close())
+
+	select {
+	case err = <-served:
+		require.NoError(t, err, "agent should stop after idle timeout")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "agent should stop after idle timeout")
+	}
+	_, err = agent.Dial(path)
+	require.Error(t, err, "socket should be closed")
+
+	l, err = agent.Listen(path)
+	require.NoError(t, err, "socket should be reusable")
+	require.NoError(t, l.Close())
+}
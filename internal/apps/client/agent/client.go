@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/marshal"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+)
+
+const dialTimeout = time.Second
+
+// Client reads entries from the running agent, changes require the unlocked vault
+type Client struct {
+	rpc *rpc.Client
+}
+
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("agent: failed to connect: %w", err)
+	}
+	return &Client{rpc: rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn))}, nil
+}
+
+func (c *Client) GetAll(ctx context.Context) (entities.GetEntriesResponse, error) {
+	var response GetAllResponse
+	call := c.rpc.Go(serviceName+".GetAll", GetAllRequest{}, &response, nil)
+	select {
+	case <-ctx.Done():
+		return entities.GetEntriesResponse{}, ctx.Err()
+	case <-call.Done:
+	}
+	if call.Error != nil {
+		return entities.GetEntriesResponse{}, fmt.Errorf("agent: failed to get entries: %w", call.Error)
+	}
+
+	result := entities.GetEntriesResponse{Entries: make([]entities.GetEntryResponse, 0, len(response.Entries))}
+	for _, v := range response.Entries {
+		data, err := marshal.EntryMarshaler{}.Unmarshal(v.Type, v.Data)
+		if err != nil {
+			return entities.GetEntriesResponse{}, err
+		}
+		result.Entries = append(result.Entries, entities.GetEntryResponse{
+			ID:            v.ID,
+			Key:           v.Key,
+			Type:          v.Type,
+			Meta:          v.Meta,
+			Folder:        v.Folder,
+			Tags:          v.Tags,
+			Data:          data,
+			GlobalVersion: v.GlobalVersion,
+			Version:       v.Version,
+			CreatedAt:     v.CreatedAt,
+			UpdatedAt:     v.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (c *Client) Create(context.Context, entities.CreateEntryRequest) (entities.CreateEntryResponse, error) {
+	return entities.CreateEntryResponse{}, ErrReadOnly
+}
+
+func (c *Client) Update(context.Context, entities.UpdateEntryRequest) error {
+	return ErrReadOnly
+}
+
+func (c *Client) Delete(context.Context, entities.DeleteEntryRequest) error {
+	return ErrReadOnly
+}
+
+func (c *Client) Sync(context.Context) error {
+	return ErrReadOnly
+}
+
+func (c *Client) Import(context.Context, entities.ImportEntriesRequest) (entities.ImportEntriesResponse, error) {
+	return entities.ImportEntriesResponse{}, ErrReadOnly
+}
+
+func (c *Client) ExportBackup(context.Context, entities.ExportBackupRequest) (entities.ExportBackupResponse, error) {
+	return entities.ExportBackupResponse{}, ErrReadOnly
+}
+
+func (c *Client) RestoreBackup(context.Context, entities.RestoreBackupRequest) (entities.RestoreBackupResponse, error) {
+	return entities.RestoreBackupResponse{}, ErrReadOnly
+}
+
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/marshal"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+)
+
+type (
+	// Server serves the unlocked entries over JSON-RPC until it's idle for idleTimeout
+	Server struct {
+		entryUC     EntryUC
+		idleTimeout time.Duration
+	}
+	service struct {
+		ctx     context.Context
+		entryUC EntryUC
+		touch   func()
+	}
+)
+
+func NewServer(entryUC EntryUC, idleTimeout time.Duration) *Server {
+	return &Server{
+		entryUC:     entryUC,
+		idleTimeout: idleTimeout,
+	}
+}
+
+// Serve accepts connections until the context is done or the agent is idle,
+// the listener is closed on return
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := time.AfterFunc(s.idleTimeout, cancel)
+	defer idle.Stop()
+
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(serviceName, &service{
+		ctx:     ctx,
+		entryUC: s.entryUC,
+		touch:   func() { idle.Reset(s.idleTimeout) },
+	}); err != nil {
+		return fmt.Errorf("agent: failed to register service: %w", err)
+	}
+	stopListen := context.AfterFunc(ctx, func() { _ = l.Close() })
+	defer stopListen()
+
+	var serving sync.WaitGroup
+	defer serving.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("agent: failed to accept connection: %w", err)
+		}
+		serving.Add(1)
+		go func() {
+			defer serving.Done()
+			stopConn := context.AfterFunc(ctx, func() { _ = conn.Close() })
+			defer stopConn()
+			srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}()
+	}
+}
+
+func (s *service) GetAll(_ GetAllRequest, response *GetAllResponse) error {
+	s.touch()
+	got, err := s.entryUC.GetAll(s.ctx)
+	if err != nil {
+		return err
+	}
+	response.Entries = make([]Entry, 0, len(got.Entries))
+	for _, v := range got.Entries {
+		data, err := marshal.EntryMarshaler{}.Marshal(v.Data)
+		if err != nil {
+			return err
+		}
+		response.Entries = append(response.Entries, Entry{
+			ID:            v.ID,
+			Key:           v.Key,
+			Type:          v.Type,
+			Meta:          v.Meta,
+			Folder:        v.Folder,
+			Tags:          v.Tags,
+			Data:          data,
+			GlobalVersion: v.GlobalVersion,
+			Version:       v.Version,
+			CreatedAt:     v.CreatedAt,
+			UpdatedAt:     v.UpdatedAt,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// checkSocketDir verifies that the socket directory isn't a symlink, the permissions are left to the system ACL
+func checkSocketDir(dir string) error {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("agent: failed to stat socket directory: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrSocketDirUnsafe, dir)
+	}
+	return nil
+}
+
+func listenSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
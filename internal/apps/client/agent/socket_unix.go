@@ -0,0 +1,36 @@
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// checkSocketDir verifies that the socket directory isn't a symlink, is owned by the current user
+// and is available only to the user, the directory in the shared temp dir could be created by another user
+func checkSocketDir(dir string) error {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("agent: failed to stat socket directory: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrSocketDirUnsafe, dir)
+	}
+	if stat, ok := fi.Sys().(*syscall.Stat_t); !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%w: %s is owned by another user", ErrSocketDirUnsafe, dir)
+	}
+	if fi.Mode().Perm() != 0o700 {
+		return fmt.Errorf("%w: %s permissions are %s", ErrSocketDirUnsafe, dir, fi.Mode().Perm())
+	}
+	return nil
+}
+
+// listenSocket creates the socket with the user only permissions, so it's never available to others
+func listenSocket(path string) (net.Listener, error) {
+	mask := syscall.Umask(0o177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package agent_test
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/client/agent"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListen_UnsafeDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	shared := filepath.Join(dir, "shared")
+	require.NoError(t, os.Mkdir(shared, 0o700))
+	require.NoError(t, os.Chmod(shared, 0o755))
+	_, err = agent.Listen(filepath.Join(shared, "agent.sock"))
+	require.ErrorIs(t, err, agent.ErrSocketDirUnsafe, "directory available to others should be rejected")
+
+	private := filepath.Join(dir, "private")
+	require.NoError(t, os.Mkdir(private, 0o700))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(private, link))
+	_, err = agent.Listen(filepath.Join(link, "agent.sock"))
+	require.ErrorIs(t, err, agent.ErrSocketDirUnsafe, "symlinked directory should be rejected")
+
+	l, err := agent.Listen(filepath.Join(private, "agent.sock"))
+	require.NoError(t, err, "failed to listen")
+	require.NoError(t, l.Close())
+}
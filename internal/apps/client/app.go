@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/agent"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/cli"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/config"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/deps"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/clipboard"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/ui"
 	"github.com/dlomanov/gophkeeper/internal/infra/logging"
 	"go.uber.org/zap"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func Run(ctx context.Context, config *config.Config) error {
@@ -39,6 +45,66 @@ func Run(ctx context.Context, config *config.Config) error {
 	return nil
 }
 
+// RunCLI runs the non-interactive command, results are printed to stdout as JSON.
+// Read-only commands are served by the running agent when it's available.
+func RunCLI(ctx context.Context, config *config.Config, args []string) (err error) {
+	defer func() {
+		if err != nil {
+			cli.WriteError(os.Stderr, err)
+		}
+	}()
+	cmd, err := cli.Parse(args, os.Stdin)
+	if err != nil {
+		return err
+	}
+	if !cmd.NeedsVault() {
+		return cmd.Run(ctx, nil, os.Stdout)
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	cmd.Clipboard = clipboard.NewClipboard(zap.NewNop(), clipboard.System{}, config.ClipboardTimeout)
+	if cmd.ReadOnly() {
+		if client, err := agent.Dial(agent.SocketPath(os.LookupEnv)); err == nil {
+			defer func() { _ = client.Close() }()
+			return cmd.Run(ctx, client, os.Stdout)
+		}
+	}
+	if err = config.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	pass, err := cmd.MasterPass(os.Stdin, os.Stderr, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if err = cmd.ReadPassphrase(os.Stdin, os.Stderr, os.LookupEnv); err != nil {
+		return err
+	}
+	var (
+		logger *zap.Logger
+		c      *deps.Container
+	)
+	if logger, err = logging.NewLogger(logging.Config{
+		Level:       config.LogLevel,
+		Type:        config.LogType,
+		OutputPaths: config.LogOutputPaths,
+	}); err != nil {
+		return err
+	}
+	defer func(logger *zap.Logger) { _ = logger.Sync() }(logger)
+	if c, err = deps.NewContainer(logger, config); err != nil {
+		logger.Error("failed to init container", zap.Error(err))
+		return err
+	}
+	if err = c.Register(ctx, pass); err != nil {
+		logger.Error("failed to register dependencies", zap.Error(err))
+		return err
+	}
+	defer closeContainer(c)
+
+	c.Logger.Debug("running command", zap.String("command", cmd.Name))
+	return cmd.Run(ctx, c.EntryUC, os.Stdout)
+}
+
 func closeContainer(c *deps.Container) {
 	if err := c.Close(); err != nil {
 		c.Logger.Error("failed to close container", zap.Error(err))
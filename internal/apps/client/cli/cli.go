@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/agent"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUsage         = errors.New("cli: invalid usage")
+	ErrEntryNotFound = errors.New("cli: entry not found")
+)
+
+var usage = `Usage: gophkeeper [flags] <command> [command flags]
+
+Commands:
+  get <key> [--field name]     print the entry, or only its data field
+  copy <key> [--field name]    copy the password, card number, totp code or the data field to the clipboard,
+                               waits for the clipboard timeout and clears it, ctrl+c clears it at once
+  code <key>                   print the current totp code and the seconds left until it expires
+  set <key> [--type type] ...  create or update the entry
+  list [--type type]           list entries without data,
+       [--folder path]         --folder includes subfolders,
+       [--tag name]            --tag lists entries with the tag
+  rm <key>                     delete the entry
+  sync                         sync entries with the server
+  import <file> --format name  import entries from keepass (XML), bitwarden (JSON) or csv export,
+                               '-' reads stdin, --dry-run only reports what would be imported
+  export <file>                write all entries into the passphrase-encrypted backup
+  restore <file> [--merge]     restore entries from the backup into the empty vault,
+                               --merge keeps existing entries and reports conflicts
+  agent [--idle-timeout 15m]   keep the vault unlocked and serve get/list to other processes
+  help                         print this help
+
+Data flags of set:
+  password:  --login, --password
+  note:      --note ('-' reads stdin)
+  card:      --number, --expires, --cvc, --owner
+  binary:    --file ('-' reads stdin)
+  totp:      --secret (base32 secret or otpauth:// uri), --algorithm (SHA1, SHA256 or SHA512),
+             --digits (6 or 8), --period (seconds)
+` + fieldsUsage() + `  any:       --meta key=value (repeatable), --folder path (e.g. Work/Servers),
+             --tag name (repeatable, --tag '' removes tags)
+--field takes name=value and is repeatable, file fields take the file path ('-' reads stdin),
+the empty value removes the field.
+
+The master password is read from --master-password-fd, then from the ` + MasterPassEnv + `
+environment variable, otherwise it's prompted in the terminal.
+The backup passphrase is read from --passphrase-fd, then from the ` + PassphraseEnv + `
+environment variable, otherwise it's prompted in the terminal.
+get, copy, code and list are served by the running agent without the master password,
+the agent socket path could be set with the ` + agent.SocketEnv + ` environment variable.
+Results are printed as JSON, errors are printed as JSON to stderr.
+`
+
+type (
+	EntryUC interface {
+		GetAll(ctx context.Context) (entities.GetEntriesResponse, error)
+		Create(ctx context.Context, request entities.CreateEntryRequest) (entities.CreateEntryResponse, error)
+		Update(ctx context.Context, request entities.UpdateEntryRequest) error
+		Delete(ctx context.Context, request entities.DeleteEntryRequest) error
+		Sync(ctx context.Context) error
+		Import(ctx context.Context, request entities.ImportEntriesRequest) (entities.ImportEntriesResponse, error)
+		ExportBackup(ctx context.Context, request entities.ExportBackupRequest) (entities.ExportBackupResponse, error)
+		RestoreBackup(ctx context.Context, request entities.RestoreBackupRequest) (entities.RestoreBackupResponse, error)
+	}
+	Clipboard interface {
+		Copy(value string) error
+		Clear() error
+		Timeout() time.Duration
+	}
+	// Command is a parsed non-interactive command, it's run after the vault is opened
+	Command struct {
+		Name            string
+		Clipboard       Clipboard // used by copy
+		passFD          int
+		passphraseFD    int
+		passphrase      core.Pass
+		stdin           io.Reader
+		needsUC         bool
+		needsPassphrase bool
+		readOnly        bool
+		run             func(ctx context.Context, entryUC EntryUC, out io.Writer) error
+		setFlags        setFlags
+	}
+	setFlags struct {
+		typ string
+		// fields are the data field values of the data flags and --field
+		fields metaFlag
+		meta   metaFlag
+		folder *string
+		tags   tagsFlag
+	}
+	// metaFlag collects the repeated key=value pairs
+	metaFlag map[string]string
+	// tagsFlag collects the repeated tags, set distinguishes the removed tags from the unset flag
+	tagsFlag struct {
+		tags []string
+		set  bool
+	}
+)
+
+// Parse parses the command line arguments left after the client flags,
+// stdin is used by set when data is passed with '-'.
+func Parse(args []string, stdin io.Reader) (*Command, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: command expected", ErrUsage)
+	}
+	c := &Command{Name: args[0], passFD: -1, passphraseFD: -1, stdin: stdin, needsUC: true}
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.IntVar(&c.passFD, "master-password-fd", -1, "file descriptor to read the master password from")
+
+	var (
+		field       string
+		typ         string
+		folder      string
+		tag         string
+		idleTimeout time.Duration
+		format      string
+		dryRun      bool
+		merge       bool
+	)
+	switch c.Name {
+	case "get":
+		fs.StringVar(&field, "field", "", "data field to print")
+	case "copy":
+		fs.StringVar(&field, "field", "", "data field to copy")
+	case "set":
+		c.setFlags.meta = metaFlag{}
+		c.setFlags.fields = metaFlag{}
+		fs.StringVar(&c.setFlags.typ, "type", "", "entry type")
+		fs.Func("login", "password login", c.setFlags.fields.field("login"))
+		fs.Func("password", "password", c.setFlags.fields.field("password"))
+		fs.Func("note", "note text", c.setFlags.fields.field("note"))
+		fs.Func("number", "card number", c.setFlags.fields.field("number"))
+		fs.Func("expires", "card expiration date", c.setFlags.fields.field("expires"))
+		fs.Func("cvc", "card CVC", c.setFlags.fields.field("cvc"))
+		fs.Func("owner", "card owner", c.setFlags.fields.field("owner"))
+		fs.Func("file", "binary file path", c.setFlags.fields.field("binary"))
+		fs.Func("secret", "totp secret or otpauth:// uri", c.setFlags.fields.field("secret"))
+		fs.Func("algorithm", "totp algorithm", c.setFlags.fields.field("algorithm"))
+		fs.Func("digits", "totp code digits", c.setFlags.fields.field("digits"))
+		fs.Func("period", "totp period in seconds", c.setFlags.fields.field("period"))
+		fs.Var(c.setFlags.fields, "field", "entry field name=value")
+		fs.Var(c.setFlags.meta, "meta", "entry meta key=value")
+		fs.Func("folder", "entry folder path", func(value string) error {
+			c.setFlags.folder = &value
+			return nil
+		})
+		fs.Var(&c.setFlags.tags, "tag", "entry tag")
+	case "list":
+		fs.StringVar(&typ, "type", "", "entry type")
+		fs.StringVar(&folder, "folder", "", "entry folder path")
+		fs.StringVar(&tag, "tag", "", "entry tag")
+	case "import":
+		fs.StringVar(&format, "format", "", "export format")
+		fs.BoolVar(&dryRun, "dry-run", false, "only report what would be imported")
+	case "export":
+		c.needsPassphrase = true
+		fs.IntVar(&c.passphraseFD, "passphrase-fd", -1, "file descriptor to read the backup passphrase from")
+	case "restore":
+		c.needsPassphrase = true
+		fs.IntVar(&c.passphraseFD, "passphrase-fd", -1, "file descriptor to read the backup passphrase from")
+		fs.BoolVar(&merge, "merge", false, "restore into the vault with entries")
+	case "agent":
+		fs.DurationVar(&idleTimeout, "idle-timeout", agent.DefaultIdleTimeout, "lock the vault after inactivity")
+	case "code", "rm", "sync":
+	case "help", "-h", "--help":
+		c.needsUC = false
+		c.run = func(_ context.Context, _ EntryUC, out io.Writer) error {
+			_, err := io.WriteString(out, usage)
+			return err
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown command %q", ErrUsage, c.Name)
+	}
+
+	// flags are allowed after the key
+	positional, err := parseInterspersed(fs, args[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrUsage, c.Name, err)
+	}
+	switch c.Name {
+	case "get", "copy", "code", "set", "rm", "import", "export", "restore":
+		if len(positional) != 1 || positional[0] == "" {
+			return nil, fmt.Errorf("%w: %s: key expected", ErrUsage, c.Name)
+		}
+	default:
+		if len(positional) != 0 {
+			return nil, fmt.Errorf("%w: %s: unexpected arguments %v", ErrUsage, c.Name, positional)
+		}
+	}
+
+	switch c.Name {
+	case "get":
+		c.readOnly = true
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return get(ctx, entryUC, out, positional[0], field)
+		}
+	case "copy":
+		c.readOnly = true
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return c.copyField(ctx, entryUC, out, positional[0], field)
+		}
+	case "code":
+		c.readOnly = true
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return code(ctx, entryUC, out, positional[0])
+		}
+	case "set":
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return c.set(ctx, entryUC, out, positional[0])
+		}
+	case "list":
+		if typ != "" && !core.EntryType(typ).Valid() {
+			return nil, fmt.Errorf("%w: list: %w: %s", ErrUsage, entities.ErrEntryTypeInvalid, typ)
+		}
+		c.readOnly = true
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return list(ctx, entryUC, out, entities.SearchEntriesRequest{
+				Type:   core.EntryType(typ),
+				Folder: core.NormalizeFolder(folder),
+				Tag:    strings.TrimSpace(tag),
+			})
+		}
+	case "rm":
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return remove(ctx, entryUC, out, positional[0])
+		}
+	case "sync":
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			if err := entryUC.Sync(ctx); err != nil {
+				return err
+			}
+			return writeJSON(out, map[string]bool{"synced": true})
+		}
+	case "import":
+		if !entities.ImportFormat(format).Valid() {
+			return nil, fmt.Errorf("%w: import: --format expected: keepass, bitwarden or csv", ErrUsage)
+		}
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return c.importEntries(ctx, entryUC, out, positional[0], entities.ImportFormat(format), dryRun)
+		}
+	case "export":
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return c.exportBackup(ctx, entryUC, out, positional[0])
+		}
+	case "restore":
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return c.restoreBackup(ctx, entryUC, out, positional[0], merge)
+		}
+	case "agent":
+		if idleTimeout <= 0 {
+			return nil, fmt.Errorf("%w: agent: positive --idle-timeout expected", ErrUsage)
+		}
+		c.run = func(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+			return serveAgent(ctx, entryUC, out, idleTimeout)
+		}
+	}
+	return c, nil
+}
+
+// NeedsVault reports that the command works with entries, so the master password is required
+func (c *Command) NeedsVault() bool {
+	return c.needsUC
+}
+
+// ReadOnly reports that the command could be served by the agent
+func (c *Command) ReadOnly() bool {
+	return c.readOnly
+}
+
+func (c *Command) Run(ctx context.Context, entryUC EntryUC, out io.Writer) error {
+	return c.run(ctx, entryUC, out)
+}
+
+// WriteError prints the error as JSON
+func WriteError(w io.Writer, err error) {
+	_ = writeJSON(w, map[string]string{"error": err.Error()})
+}
+
+// fieldsUsage returns the data flags of the schema-driven types
+func fieldsUsage() string {
+	sb := strings.Builder{}
+	for _, schema := range core.EntrySchemas() {
+		if schema.Data != nil {
+			continue
+		}
+		names := make([]string, len(schema.Fields))
+		for i, f := range schema.Fields {
+			names[i] = f.Name
+			switch f.Kind {
+			case core.FieldKindFile:
+				names[i] += " (file)"
+			case core.FieldKindDate:
+				names[i] += " (YYYY-MM-DD)"
+			}
+		}
+		_, _ = fmt.Fprintf(&sb, "  %-10s --field %s\n", schema.Type+":", strings.Join(names, ", "))
+	}
+	return sb.String()
+}
+
+func serveAgent(ctx context.Context, entryUC EntryUC, out io.Writer, idleTimeout time.Duration) error {
+	path := agent.SocketPath(os.LookupEnv)
+	l, err := agent.Listen(path)
+	if err != nil {
+		return err
+	}
+	if err = writeJSON(out, map[string]string{"socket": path, "idle_timeout": idleTimeout.String()}); err != nil {
+		_ = l.Close()
+		return err
+	}
+	return agent.NewServer(entryUC, idleTimeout).Serve(ctx, l)
+}
+
+func parseInterspersed(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (m metaFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m metaFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("key=value expected: %s", value)
+	}
+	m[k] = v
+	return nil
+}
+
+// field returns the setter of the named field, so data flags share the values with --field
+func (m metaFlag) field(name string) func(string) error {
+	return func(value string) error {
+		m[name] = value
+		return nil
+	}
+}
+
+func (f *tagsFlag) String() string {
+	return strings.Join(f.tags, ",")
+}
+
+func (f *tagsFlag) Set(value string) error {
+	f.tags = append(f.tags, value)
+	f.set = true
+	return nil
+}
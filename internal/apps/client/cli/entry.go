@@ -0,0 +1,460 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/totp"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	entryView struct {
+		ID        uuid.UUID         `json:"id"`
+		Key       string            `json:"key"`
+		Type      core.EntryType    `json:"type"`
+		Meta      map[string]string `json:"meta,omitempty"`
+		Folder    string            `json:"folder,omitempty"`
+		Tags      []string          `json:"tags,omitempty"`
+		Data      map[string]any    `json:"data,omitempty"`
+		Version   int64             `json:"version"`
+		CreatedAt time.Time         `json:"created_at"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+	codeView struct {
+		Key       string `json:"key"`
+		Code      string `json:"code"`
+		ExpiresIn int    `json:"expires_in"` // seconds
+	}
+	copyView struct {
+		Key      string `json:"key"`
+		Field    string `json:"field"`
+		ClearsIn string `json:"clears_in,omitempty"`
+	}
+	setView struct {
+		ID      uuid.UUID `json:"id"`
+		Key     string    `json:"key"`
+		Created bool      `json:"created"`
+	}
+	rmView struct {
+		ID      uuid.UUID `json:"id"`
+		Key     string    `json:"key"`
+		Deleted bool      `json:"deleted"`
+	}
+	exportView struct {
+		Path string   `json:"path"`
+		Keys []string `json:"keys"`
+	}
+	restoreView struct {
+		Created   []string `json:"created"`
+		Unchanged []string `json:"unchanged"`
+		Conflicts []string `json:"conflicts"`
+	}
+	importView struct {
+		Created    []string `json:"created"`
+		Duplicates []string `json:"duplicates"`
+		DryRun     bool     `json:"dry_run"`
+	}
+)
+
+func get(ctx context.Context, entryUC EntryUC, out io.Writer, key string, field string) error {
+	entry, err := find(ctx, entryUC, key)
+	if err != nil {
+		return err
+	}
+	data := dataFields(entry.Data)
+	if field == "" {
+		view := toView(entry)
+		view.Data = data
+		return writeJSON(out, view)
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return fmt.Errorf("%w: %s entry has no field %q", ErrUsage, entry.Type, field)
+	}
+	switch value := value.(type) {
+	case []byte:
+		_, err = out.Write(value)
+	default:
+		_, err = fmt.Fprintln(out, value)
+	}
+	return err
+}
+
+// copyField copies the entry secret, the process waits for the clipboard timeout to clear the clipboard
+func (c *Command) copyField(ctx context.Context, entryUC EntryUC, out io.Writer, key string, field string) error {
+	entry, err := find(ctx, entryUC, key)
+	if err != nil {
+		return err
+	}
+	if field == "" {
+		schema, _ := core.LookupEntrySchema(entry.Type)
+		if field = schema.CopyField(); field == "" {
+			return fmt.Errorf("%w: --field of the %s entry expected", ErrUsage, entry.Type)
+		}
+	}
+	value, ok := dataFields(entry.Data)[field].(string)
+	if !ok {
+		return fmt.Errorf("%w: %s entry has no text field %q", ErrUsage, entry.Type, field)
+	}
+	if value == "" {
+		return fmt.Errorf("%w: %s entry field %q is empty", ErrUsage, entry.Type, field)
+	}
+	if err = c.Clipboard.Copy(value); err != nil {
+		return err
+	}
+	view := copyView{Key: key, Field: field}
+	timeout := c.Clipboard.Timeout()
+	if timeout > 0 {
+		view.ClearsIn = timeout.String()
+	}
+	if err = writeJSON(out, view); err != nil || timeout <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return c.Clipboard.Clear()
+}
+
+func code(ctx context.Context, entryUC EntryUC, out io.Writer, key string) error {
+	entry, err := find(ctx, entryUC, key)
+	if err != nil {
+		return err
+	}
+	data, ok := entry.Data.(entities.EntryDataTOTP)
+	if !ok {
+		return fmt.Errorf("%w: %s entry has no code", ErrUsage, entry.Type)
+	}
+	value, remaining, err := totp.Code(data, time.Now())
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, codeView{Key: key, Code: value, ExpiresIn: int(remaining / time.Second)})
+}
+
+func (c *Command) set(ctx context.Context, entryUC EntryUC, out io.Writer, key string) error {
+	entry, err := find(ctx, entryUC, key)
+	switch {
+	case err == nil:
+		typ := core.EntryType(c.setFlags.typ)
+		if typ != "" && typ != entry.Type {
+			return fmt.Errorf("%w: %s entry type can't be changed to %s", ErrUsage, entry.Type, typ)
+		}
+		data, err := c.entryData(entry.Type, entry.Data)
+		if err != nil {
+			return err
+		}
+		meta := entry.Meta
+		if len(c.setFlags.meta) != 0 {
+			meta = c.setFlags.meta
+		}
+		folder, tags := entry.Folder, entry.Tags
+		if c.setFlags.folder != nil {
+			folder = *c.setFlags.folder
+		}
+		if c.setFlags.tags.set {
+			tags = c.setFlags.tags.tags
+		}
+		if err = entryUC.Update(ctx, entities.UpdateEntryRequest{
+			ID:     entry.ID,
+			Meta:   meta,
+			Folder: folder,
+			Tags:   tags,
+			Data:   data,
+		}); err != nil {
+			return err
+		}
+		return writeJSON(out, setView{ID: entry.ID, Key: key})
+	case !errors.Is(err, ErrEntryNotFound):
+		return err
+	}
+
+	typ := core.EntryType(c.setFlags.typ)
+	if !typ.Valid() {
+		return fmt.Errorf("%w: --type of the new entry expected: %s", ErrUsage, entryTypes())
+	}
+	data, err := c.entryData(typ, nil)
+	if err != nil {
+		return err
+	}
+	var meta map[string]string
+	if len(c.setFlags.meta) != 0 {
+		meta = c.setFlags.meta
+	}
+	var folder string
+	if c.setFlags.folder != nil {
+		folder = *c.setFlags.folder
+	}
+	created, err := entryUC.Create(ctx, entities.CreateEntryRequest{
+		Key:    key,
+		Type:   typ,
+		Meta:   meta,
+		Folder: folder,
+		Tags:   c.setFlags.tags.tags,
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, setView{ID: created.ID, Key: key, Created: true})
+}
+
+// list filters entries itself, since the agent serves all entries
+func list(ctx context.Context, entryUC EntryUC, out io.Writer, filter entities.SearchEntriesRequest) error {
+	got, err := entryUC.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	views := make([]entryView, 0, len(got.Entries))
+	for _, v := range got.Entries {
+		if filter.Type != "" && v.Type != filter.Type ||
+			!core.InFolder(v.Folder, filter.Folder) ||
+			filter.Tag != "" && !slices.Contains(v.Tags, filter.Tag) {
+			continue
+		}
+		views = append(views, toView(v))
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Key < views[j].Key })
+	return writeJSON(out, views)
+}
+
+func remove(ctx context.Context, entryUC EntryUC, out io.Writer, key string) error {
+	entry, err := find(ctx, entryUC, key)
+	if err != nil {
+		return err
+	}
+	if err = entryUC.Delete(ctx, entities.DeleteEntryRequest{ID: entry.ID}); err != nil {
+		return err
+	}
+	return writeJSON(out, rmView{ID: entry.ID, Key: key, Deleted: true})
+}
+
+func (c *Command) importEntries(
+	ctx context.Context,
+	entryUC EntryUC,
+	out io.Writer,
+	path string,
+	format entities.ImportFormat,
+	dryRun bool,
+) error {
+	content, err := c.readValue(path, func() ([]byte, error) { return os.ReadFile(path) })
+	if err != nil {
+		return err
+	}
+	got, err := entryUC.Import(ctx, entities.ImportEntriesRequest{Format: format, Content: content, DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, importView{
+		Created:    nonNil(got.Created),
+		Duplicates: nonNil(got.Duplicates),
+		DryRun:     dryRun,
+	})
+}
+
+func (c *Command) exportBackup(ctx context.Context, entryUC EntryUC, out io.Writer, path string) error {
+	got, err := entryUC.ExportBackup(ctx, entities.ExportBackupRequest{Path: path, Passphrase: c.passphrase})
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, exportView{Path: path, Keys: nonNil(got.Keys)})
+}
+
+func (c *Command) restoreBackup(ctx context.Context, entryUC EntryUC, out io.Writer, path string, merge bool) error {
+	got, err := entryUC.RestoreBackup(ctx, entities.RestoreBackupRequest{
+		Path:       path,
+		Passphrase: c.passphrase,
+		Merge:      merge,
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(out, restoreView{
+		Created:   nonNil(got.Created),
+		Unchanged: nonNil(got.Unchanged),
+		Conflicts: nonNil(got.Conflicts),
+	})
+}
+
+func find(ctx context.Context, entryUC EntryUC, key string) (entities.GetEntryResponse, error) {
+	got, err := entryUC.GetAll(ctx)
+	if err != nil {
+		return entities.GetEntryResponse{}, err
+	}
+	for _, v := range got.Entries {
+		if v.Key == key {
+			return v, nil
+		}
+	}
+	return entities.GetEntryResponse{}, ErrEntryNotFound
+}
+
+// entryData builds the entry data from the data flags, unset fields are taken from prev
+func (c *Command) entryData(typ core.EntryType, prev entities.EntryData) (entities.EntryData, error) {
+	schema, ok := core.LookupEntrySchema(typ)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entities.ErrEntryTypeInvalid, typ)
+	}
+	values := make(map[string]string, len(c.setFlags.fields))
+	for name, value := range c.setFlags.fields {
+		field, ok := schema.Field(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s entry has no field %q", ErrUsage, typ, name)
+		}
+		value, err := c.fieldValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	if schema.Data != nil {
+		return typedData(schema, prev, values)
+	}
+
+	data := make(entities.EntryDataFields)
+	if prev, ok := prev.(entities.EntryDataFields); ok {
+		maps.Copy(data, prev)
+	}
+	for name, value := range values {
+		if value == "" {
+			delete(data, name)
+		} else {
+			data[name] = value
+		}
+	}
+	return data, nil
+}
+
+// typedData sets the values to the data of the built-in type, the new entry expects the required fields
+func typedData(schema core.EntrySchema, prev entities.EntryData, values map[string]string) (entities.EntryData, error) {
+	data, ok := prev.(core.EntryData)
+	if !ok {
+		data = schema.Data
+		for _, f := range schema.Fields {
+			if f.Required && values[f.Name] == "" {
+				return nil, fmt.Errorf("%w: %s entry field %q expected", ErrUsage, schema.Type, f.Name)
+			}
+		}
+	}
+	// the totp secret could be the otpauth:// uri with the other fields
+	if secret := values["secret"]; schema.Type == core.EntryTypeTOTP && secret != "" {
+		parsed, err := totp.Parse(secret)
+		if err != nil {
+			return nil, err
+		}
+		if totp.IsURI(secret) {
+			data = parsed
+		}
+		values["secret"] = parsed.Secret
+	}
+	data, err := data.WithFields(values)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := data.(entities.EntryDataTOTP); ok {
+		return data, totp.Valid(data)
+	}
+	return data, nil
+}
+
+// fieldValue reads the file of the file field, the multiline value could be read from stdin
+func (c *Command) fieldValue(field core.EntryField, value string) (string, error) {
+	var read func() ([]byte, error)
+	switch {
+	case value == "":
+		return value, nil
+	case field.Kind == core.FieldKindFile:
+		read = func() ([]byte, error) { return os.ReadFile(value) }
+	case field.Kind == core.FieldKindMultiline:
+		read = func() ([]byte, error) { return []byte(value), nil }
+	default:
+		return value, nil
+	}
+	content, err := c.readValue(value, read)
+	return string(content), err
+}
+
+// readValue reads stdin when the value is '-'
+func (c *Command) readValue(value string, read func() ([]byte, error)) ([]byte, error) {
+	if value == "-" {
+		read = func() ([]byte, error) { return io.ReadAll(c.stdin) }
+	}
+	content, err := read()
+	if err != nil {
+		return nil, fmt.Errorf("cli: failed to read data: %w", err)
+	}
+	return content, nil
+}
+
+func dataFields(data entities.EntryData) map[string]any {
+	switch data := data.(type) {
+	case entities.EntryDataTOTP:
+		// the code is computed when the entry is printed
+		fields := data.Fields()
+		fields["code"] = totpCode(data)
+		return fields
+	case core.EntryData:
+		return data.Fields()
+	case entities.EntryDataFields:
+		fields := make(map[string]any, len(data))
+		for k, v := range data {
+			fields[k] = v
+		}
+		return fields
+	default:
+		return nil
+	}
+}
+
+func totpCode(data entities.EntryDataTOTP) string {
+	value, _, err := totp.Code(data, time.Now())
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func toView(entry entities.GetEntryResponse) entryView {
+	return entryView{
+		ID:        entry.ID,
+		Key:       entry.Key,
+		Type:      entry.Type,
+		Meta:      entry.Meta,
+		Folder:    entry.Folder,
+		Tags:      entry.Tags,
+		Version:   entry.Version,
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func entryTypes() string {
+	types := core.EntryTypes()
+	names := make([]string, len(types))
+	for i, v := range types {
+		names[i] = string(v)
+	}
+	return strings.Join(names, ", ")
+}
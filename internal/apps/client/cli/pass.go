@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/x/term"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	MasterPassEnv = "GOPHKEEPER_MASTER_PASSWORD"
+	PassphraseEnv = "GOPHKEEPER_BACKUP_PASSPHRASE"
+)
+
+var (
+	ErrMasterPassNotFound = errors.New("cli: master password not found")
+	ErrPassphraseNotFound = errors.New("cli: backup passphrase not found")
+	ErrPassphraseMismatch = errors.New("cli: backup passphrases don't match")
+)
+
+type secret struct {
+	fd       int
+	fdFlag   string
+	env      string
+	prompt   string
+	notFound error
+}
+
+// MasterPass reads the master password from the file descriptor, the environment or the terminal prompt
+func (c *Command) MasterPass(
+	stdin *os.File,
+	prompt io.Writer,
+	lookupEnv func(key string) (string, bool),
+) (core.Pass, error) {
+	return readSecret(secret{
+		fd:       c.passFD,
+		fdFlag:   "--master-password-fd",
+		env:      MasterPassEnv,
+		prompt:   "Master password: ",
+		notFound: ErrMasterPassNotFound,
+	}, stdin, prompt, lookupEnv)
+}
+
+// ReadPassphrase reads the backup passphrase the same way as the master password,
+// the prompted passphrase of the new backup is asked twice. Other commands don't need it.
+func (c *Command) ReadPassphrase(
+	stdin *os.File,
+	prompt io.Writer,
+	lookupEnv func(key string) (string, bool),
+) (err error) {
+	if !c.needsPassphrase {
+		return nil
+	}
+	s := secret{
+		fd:       c.passphraseFD,
+		fdFlag:   "--passphrase-fd",
+		env:      PassphraseEnv,
+		prompt:   "Backup passphrase: ",
+		notFound: ErrPassphraseNotFound,
+	}
+	if c.passphrase, err = readSecret(s, stdin, prompt, lookupEnv); err != nil {
+		return err
+	}
+	if c.Name != "export" || s.fd >= 0 {
+		return nil
+	}
+	if _, ok := lookupEnv(s.env); ok {
+		return nil
+	}
+	s.prompt = "Repeat backup passphrase: "
+	repeated, err := readSecret(s, stdin, prompt, lookupEnv)
+	if err != nil {
+		return err
+	}
+	if string(repeated) != string(c.passphrase) {
+		return ErrPassphraseMismatch
+	}
+	return nil
+}
+
+func readSecret(
+	s secret,
+	stdin *os.File,
+	prompt io.Writer,
+	lookupEnv func(key string) (string, bool),
+) (core.Pass, error) {
+	if s.fd >= 0 {
+		f := os.NewFile(uintptr(s.fd), "secret")
+		if f == nil {
+			return nil, fmt.Errorf("%w: invalid file descriptor %d", s.notFound, s.fd)
+		}
+		defer func() { _ = f.Close() }()
+		line, err := bufio.NewReader(f).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("cli: failed to read secret: %w", err)
+		}
+		return nonEmpty(strings.TrimRight(line, "\r\n"), s.notFound)
+	}
+	if value, ok := lookupEnv(s.env); ok {
+		return nonEmpty(value, s.notFound)
+	}
+	if stdin == nil || !term.IsTerminal(stdin.Fd()) {
+		return nil, fmt.Errorf("%w: use %s or %s without a terminal", s.notFound, s.fdFlag, s.env)
+	}
+
+	_, _ = io.WriteString(prompt, s.prompt)
+	value, err := term.ReadPassword(stdin.Fd())
+	_, _ = io.WriteString(prompt, "\n")
+	if err != nil {
+		return nil, fmt.Errorf("cli: failed to read secret: %w", err)
+	}
+	return nonEmpty(string(value), s.notFound)
+}
+
+func nonEmpty(value string, notFound error) (core.Pass, error) {
+	if value == "" {
+		return nil, notFound
+	}
+	return core.Pass(value), nil
+}
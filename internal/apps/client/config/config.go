@@ -1,17 +1,21 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type Config struct {
-	Address        string   // GRPC-server address
-	LogLevel       string   // log level
-	LogType        string   // logger type
-	LogOutputPaths []string // logger output paths
-	Cert           []byte   // TLS certificate
-	DSN            string   // database DSN
-	BuildVersion   string   // build version info
-	BuildDate      string   // build date info
-	BuildCommit    string   // build commit info
+	Address          string        // GRPC-server address
+	LogLevel         string        // log level
+	LogType          string        // logger type
+	LogOutputPaths   []string      // logger output paths
+	Cert             []byte        // TLS certificate
+	DSN              string        // database DSN
+	ClipboardTimeout time.Duration // copied secret clearing timeout, zero keeps the secret
+	BuildVersion     string        // build version info
+	BuildDate        string        // build date info
+	BuildCommit      string        // build commit info
 }
 
 func (c Config) Validate() error {
@@ -31,5 +35,8 @@ func (c Config) Validate() error {
 	if c.DSN == "" {
 		errs = append(errs, errors.New("database DSN should be specified"))
 	}
+	if c.ClipboardTimeout < 0 {
+		errs = append(errs, errors.New("clipboard timeout should not be negative"))
+	}
 	return errors.Join(errs...)
 }
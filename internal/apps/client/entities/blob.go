@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+)
+
+const (
+	EntryBlobChunkSize = 512 * 1024
+	EntryBlobKeySize   = 32
+	EntryMetaBlobSize  = "blob_size"
+)
+
+type (
+	// EntryBlob is stored as binary entry data when the file content lives in the server blob storage
+	EntryBlob struct {
+		ID       uuid.UUID `json:"id"`
+		Key      []byte    `json:"key"`
+		Size     int64     `json:"size"`
+		Checksum string    `json:"checksum"`
+	}
+	CreateBlobEntryRequest struct {
+		Key    string
+		Meta   map[string]string
+		Folder string
+		Tags   []string
+		Path   string
+	}
+	DownloadBlobRequest struct {
+		ID   uuid.UUID
+		Path string
+	}
+)
+
+func NewEntryBlob(data EntryDataBinary) (EntryBlob, error) {
+	var blob EntryBlob
+	if err := json.Unmarshal(data, &blob); err != nil {
+		return blob, fmt.Errorf("%w: %w", ErrEntryBlobInvalid, err)
+	}
+	if blob.ID == uuid.Nil || len(blob.Key) != EntryBlobKeySize || blob.Checksum == "" {
+		return blob, ErrEntryBlobInvalid
+	}
+	return blob, nil
+}
+
+func (b EntryBlob) Data() (EntryDataBinary, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrEntryBlobInvalid, err)
+	}
+	return data, nil
+}
+
+func (r GetEntryResponse) HasBlob() bool {
+	return r.Type == core.EntryTypeBinary && r.Meta[EntryMetaBlobSize] != ""
+}
+
+func (r CreateBlobEntryRequest) Validate() (err error) {
+	if r.Key == "" {
+		err = errors.Join(err, ErrEntryKeyInvalid)
+	}
+	if r.Path == "" {
+		err = errors.Join(err, ErrEntryBlobPathInvalid)
+	}
+	return err
+}
+
+func (r DownloadBlobRequest) Validate() (err error) {
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	if r.Path == "" {
+		err = errors.Join(err, ErrEntryBlobPathInvalid)
+	}
+	return err
+}
@@ -19,14 +19,18 @@ type (
 		Key           string
 		Type          core.EntryType
 		Meta          map[string]string
+		Folder        string
+		Tags          []string
 		Data          []byte
 		GlobalVersion int64
 		Version       int64
 		CreatedAt     time.Time
 		UpdatedAt     time.Time
 	}
+	// EntrySync is the pending local change, RequestID is renewed on every change
 	EntrySync struct {
 		ID        uuid.UUID
+		RequestID string
 		CreatedAt time.Time
 	}
 	EntryUpdateOption func(e *Entry) error
@@ -105,9 +109,24 @@ func UpdateEntryMeta(meta map[string]string) EntryUpdateOption {
 	}
 }
 
+func UpdateEntryFolder(folder string) EntryUpdateOption {
+	return func(e *Entry) error {
+		e.Folder = core.NormalizeFolder(folder)
+		return nil
+	}
+}
+
+func UpdateEntryTags(tags []string) EntryUpdateOption {
+	return func(e *Entry) error {
+		e.Tags = core.NormalizeTags(tags)
+		return nil
+	}
+}
+
 func NewEntrySync(id uuid.UUID) *EntrySync {
 	return &EntrySync{
 		ID:        id,
+		RequestID: uuid.NewString(),
 		CreatedAt: time.Now().UTC(),
 	}
 }
@@ -121,6 +140,8 @@ type (
 		Key           string
 		Type          core.EntryType
 		Meta          map[string]string
+		Folder        string
+		Tags          []string
 		Data          EntryData
 		GlobalVersion int64
 		Version       int64
@@ -128,22 +149,40 @@ type (
 		UpdatedAt     time.Time
 	}
 	CreateEntryRequest struct {
-		Key  string
-		Type core.EntryType
-		Meta map[string]string
-		Data EntryData
+		ID     uuid.UUID // generated if nil
+		Key    string
+		Type   core.EntryType
+		Meta   map[string]string
+		Folder string
+		Tags   []string
+		Data   EntryData
 	}
 	CreateEntryResponse struct {
 		ID uuid.UUID
 	}
 	UpdateEntryRequest struct {
-		ID   uuid.UUID ``
-		Meta map[string]string
-		Data EntryData
+		ID     uuid.UUID ``
+		Meta   map[string]string
+		Folder string
+		Tags   []string
+		Data   EntryData
+	}
+	GetEntryRequest struct {
+		ID uuid.UUID
 	}
 	DeleteEntryRequest struct {
 		ID uuid.UUID
 	}
+	GetEntryHistoryRequest struct {
+		ID uuid.UUID
+	}
+	RestoreEntryRequest struct {
+		ID      uuid.UUID
+		Version int64
+	}
+	UndeleteEntryRequest struct {
+		ID uuid.UUID
+	}
 	EntryData         any
 	EntryDataPassword struct {
 		Login    string
@@ -157,6 +196,15 @@ type (
 		Cvc     string
 		Owner   string
 	}
+	// EntryDataTOTP is the authenticator key, the zero algorithm, digits and period are the defaults
+	EntryDataTOTP struct {
+		Secret    string
+		Algorithm string
+		Digits    int
+		Period    int // seconds
+	}
+	// EntryDataFields is the data of the schema-driven types, see core.EntrySchema
+	EntryDataFields map[string]string
 )
 
 func (r CreateEntryRequest) Validate() (err error) {
@@ -169,28 +217,8 @@ func (r CreateEntryRequest) Validate() (err error) {
 	if !entryDataValid(r.Data) {
 		err = errors.Join(err, ErrEntryTypeInvalid)
 	}
-	mismatchErr := func() error {
-		return fmt.Errorf("%w: type and data type mismatch: %s vs %T", ErrEntryTypeInvalid, r.Type, r.Data)
-	}
-	switch r.Type {
-	case core.EntryTypePassword:
-		if _, ok := r.Data.(EntryDataPassword); !ok {
-			err = errors.Join(err, mismatchErr())
-		}
-	case core.EntryTypeNote:
-		if _, ok := r.Data.(EntryDataNote); !ok {
-			err = errors.Join(err, mismatchErr())
-		}
-	case core.EntryTypeCard:
-		if _, ok := r.Data.(EntryDataCard); !ok {
-			err = errors.Join(err, mismatchErr())
-		}
-	case core.EntryTypeBinary:
-		if _, ok := r.Data.(EntryDataBinary); !ok {
-			err = errors.Join(err, mismatchErr())
-		}
-	default:
-		err = errors.Join(err, fmt.Errorf("%w: unknown entry type: %s", ErrEntryTypeInvalid, r.Type))
+	if r.Type.Valid() && entryDataValid(r.Data) {
+		err = errors.Join(err, ValidateEntryData(r.Type, r.Data))
 	}
 	return err
 }
@@ -205,14 +233,68 @@ func (r UpdateEntryRequest) Validate() (err error) {
 	return err
 }
 
+func (r GetEntryRequest) Validate() error {
+	if r.ID == uuid.Nil {
+		return ErrEntryIDInvalid
+	}
+	return nil
+}
+
+func (r GetEntryHistoryRequest) Validate() error {
+	if r.ID == uuid.Nil {
+		return ErrEntryIDInvalid
+	}
+	return nil
+}
+
+func (r RestoreEntryRequest) Validate() (err error) {
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	if r.Version <= 0 {
+		err = errors.Join(err, ErrEntryVersionInvalid)
+	}
+	return err
+}
+
+func (r UndeleteEntryRequest) Validate() error {
+	if r.ID == uuid.Nil {
+		return ErrEntryIDInvalid
+	}
+	return nil
+}
+
+// ValidateEntryData checks that the data matches the type, the field map is validated by the type schema
+func ValidateEntryData(typ core.EntryType, data EntryData) error {
+	schema, found := core.LookupEntrySchema(typ)
+	if !found {
+		return fmt.Errorf("%w: unknown entry type: %s", ErrEntryTypeInvalid, typ)
+	}
+	switch data := data.(type) {
+	case core.EntryData:
+		if t, ok := core.LookupEntryDataType(data); ok && t == typ {
+			return nil
+		}
+	case EntryDataFields:
+		if schema.Data != nil {
+			break
+		}
+		if err := schema.Validate(data); err != nil {
+			return fmt.Errorf("%w: %w", ErrEntryFieldInvalid, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: type and data type mismatch: %s vs %T", ErrEntryTypeInvalid, typ, data)
+}
+
 func entryDataValid(data EntryData) bool {
-	switch data.(type) {
-	case EntryDataPassword:
-	case EntryDataNote:
-	case EntryDataCard:
-	case EntryDataBinary:
+	switch data := data.(type) {
+	case core.EntryData:
+		_, ok := core.LookupEntryDataType(data)
+		return ok
+	case EntryDataFields:
+		return true
 	default:
 		return false
 	}
-	return true
 }
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"errors"
+	"github.com/dlomanov/gophkeeper/internal/core"
+)
+
+type (
+	ExportBackupRequest struct {
+		Path       string
+		Passphrase core.Pass
+	}
+	ExportBackupResponse struct {
+		Keys []string // keys of the exported entries
+	}
+	RestoreBackupRequest struct {
+		Path       string
+		Passphrase core.Pass
+		Merge      bool // allows restoring into the vault that already has entries
+	}
+	RestoreBackupResponse struct {
+		Created   []string // keys of the restored entries
+		Unchanged []string // keys of the entries that are equal to the archived ones
+		Conflicts []string // keys of the entries that differ from the archived ones, they are kept as is
+	}
+)
+
+func (r ExportBackupRequest) Validate() (err error) {
+	if r.Path == "" {
+		err = errors.Join(err, ErrBackupPathInvalid)
+	}
+	if len(r.Passphrase) == 0 {
+		err = errors.Join(err, ErrBackupPassInvalid)
+	}
+	return err
+}
+
+func (r RestoreBackupRequest) Validate() (err error) {
+	if r.Path == "" {
+		err = errors.Join(err, ErrBackupPathInvalid)
+	}
+	if len(r.Passphrase) == 0 {
+		err = errors.Join(err, ErrBackupPassInvalid)
+	}
+	return err
+}
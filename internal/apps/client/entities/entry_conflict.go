@@ -0,0 +1,201 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	EntryConflictKeepLocal EntryConflictResolution = iota
+	EntryConflictKeepRemote
+	EntryConflictMerge
+)
+
+const (
+	EntryFieldDescription = "description"
+	EntryFieldLogin       = "login"
+	EntryFieldPassword    = "password"
+	EntryFieldNumber      = "number"
+	EntryFieldExpires     = "expires"
+	EntryFieldCvc         = "cvc"
+	EntryFieldOwner       = "owner"
+)
+
+type (
+	// EntryConflict keeps both versions of the entry rejected by the server until it's resolved,
+	// data of every version is encrypted with the local key.
+	EntryConflict struct {
+		ID        uuid.UUID // server entry ID
+		Key       string
+		Local     EntryConflictVersion
+		Remote    EntryConflictVersion
+		Base      *EntryConflictVersion // version the local change is based on, nil if unknown
+		CreatedAt time.Time
+	}
+	EntryConflictVersion struct {
+		Type    core.EntryType
+		Meta    map[string]string
+		Folder  string
+		Tags    []string
+		Data    []byte
+		Version int64
+	}
+	EntryConflictResolution   int
+	GetEntryConflictsResponse struct {
+		Conflicts []GetEntryConflictResponse
+	}
+	GetEntryConflictResponse struct {
+		ID        uuid.UUID
+		Key       string
+		Local     GetEntryResponse
+		Remote    GetEntryResponse
+		Base      *GetEntryResponse
+		CreatedAt time.Time
+	}
+	ResolveEntryConflictRequest struct {
+		ID         uuid.UUID
+		Resolution EntryConflictResolution
+		Meta       map[string]string // merged meta, used with EntryConflictMerge
+		Data       EntryData         // merged data, used with EntryConflictMerge
+	}
+)
+
+func (r ResolveEntryConflictRequest) Validate() (err error) {
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	switch r.Resolution {
+	case EntryConflictKeepLocal, EntryConflictKeepRemote:
+	case EntryConflictMerge:
+		if !entryDataValid(r.Data) {
+			err = errors.Join(err, ErrEntryTypeInvalid)
+		}
+	default:
+		err = errors.Join(err, fmt.Errorf("%w: unknown resolution: %d", ErrEntryConflictInvalid, r.Resolution))
+	}
+	return err
+}
+
+// Mergeable reports whether versions could be merged field by field
+func (c GetEntryConflictResponse) Mergeable() bool {
+	return c.Local.Type == c.Remote.Type && len(c.Fields()) > 0
+}
+
+// Fields returns fields available for the field-by-field merge
+func (c GetEntryConflictResponse) Fields() []string {
+	switch c.Remote.Data.(type) {
+	case EntryDataPassword:
+		return []string{EntryFieldDescription, EntryFieldLogin, EntryFieldPassword}
+	case EntryDataCard:
+		return []string{EntryFieldDescription, EntryFieldNumber, EntryFieldExpires, EntryFieldCvc, EntryFieldOwner}
+	case EntryDataFields:
+		schema, ok := core.LookupEntrySchema(c.Remote.Type)
+		if !ok {
+			return nil
+		}
+		fields := []string{EntryFieldDescription}
+		for _, f := range schema.Fields {
+			fields = append(fields, f.Name)
+		}
+		return fields
+	default:
+		return nil
+	}
+}
+
+// Values returns the field value of local, remote and base versions, base is empty if unknown
+func (c GetEntryConflictResponse) Values(field string) (local, remote, base string) {
+	local = entryField(c.Local, field)
+	remote = entryField(c.Remote, field)
+	if c.Base != nil {
+		base = entryField(*c.Base, field)
+	}
+	return local, remote, base
+}
+
+// Suggest picks the changed side for every field compared to the base version,
+// the local value is picked if both sides are changed or the base is unknown.
+func (c GetEntryConflictResponse) Suggest() map[string]bool {
+	useLocal := make(map[string]bool)
+	for _, field := range c.Fields() {
+		local, _, base := c.Values(field)
+		useLocal[field] = c.Base == nil || local != base
+	}
+	return useLocal
+}
+
+// Merge builds meta and data from local or remote field values
+func (c GetEntryConflictResponse) Merge(useLocal map[string]bool) (map[string]string, EntryData, error) {
+	if !c.Mergeable() {
+		return nil, nil, fmt.Errorf("%w: %s can't be merged", ErrEntryConflictInvalid, c.Remote.Type)
+	}
+	value := func(field string) string {
+		local, remote, _ := c.Values(field)
+		if useLocal[field] {
+			return local
+		}
+		return remote
+	}
+	meta := make(map[string]string, len(c.Remote.Meta))
+	for k, v := range c.Remote.Meta {
+		meta[k] = v
+	}
+	meta[EntryFieldDescription] = value(EntryFieldDescription)
+
+	switch c.Remote.Data.(type) {
+	case EntryDataPassword:
+		return meta, EntryDataPassword{
+			Login:    value(EntryFieldLogin),
+			Password: value(EntryFieldPassword),
+		}, nil
+	case EntryDataCard:
+		return meta, EntryDataCard{
+			Number:  value(EntryFieldNumber),
+			Expires: value(EntryFieldExpires),
+			Cvc:     value(EntryFieldCvc),
+			Owner:   value(EntryFieldOwner),
+		}, nil
+	case EntryDataFields:
+		data := make(EntryDataFields)
+		for _, field := range c.Fields()[1:] {
+			if v := value(field); v != "" {
+				data[field] = v
+			}
+		}
+		return meta, data, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unexpected data type: %T", ErrEntryConflictInvalid, c.Remote.Data)
+	}
+}
+
+func entryField(entry GetEntryResponse, field string) string {
+	if field == EntryFieldDescription {
+		return entry.Meta[EntryFieldDescription]
+	}
+	switch data := entry.Data.(type) {
+	case EntryDataPassword:
+		switch field {
+		case EntryFieldLogin:
+			return data.Login
+		case EntryFieldPassword:
+			return data.Password
+		}
+	case EntryDataCard:
+		switch field {
+		case EntryFieldNumber:
+			return data.Number
+		case EntryFieldExpires:
+			return data.Expires
+		case EntryFieldCvc:
+			return data.Cvc
+		case EntryFieldOwner:
+			return data.Owner
+		}
+	case EntryDataFields:
+		return data[field]
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"strconv"
+	"strings"
+)
+
+var (
+	_ core.EntryData = EntryDataPassword{}
+	_ core.EntryData = EntryDataNote("")
+	_ core.EntryData = EntryDataCard{}
+	_ core.EntryData = EntryDataBinary(nil)
+	_ core.EntryData = EntryDataTOTP{}
+)
+
+func init() {
+	core.RegisterEntryData(core.EntryTypePassword, EntryDataPassword{})
+	core.RegisterEntryData(core.EntryTypeNote, EntryDataNote(""))
+	core.RegisterEntryData(core.EntryTypeCard, EntryDataCard{})
+	core.RegisterEntryData(core.EntryTypeBinary, EntryDataBinary(nil))
+	core.RegisterEntryData(core.EntryTypeTOTP, EntryDataTOTP{})
+}
+
+func (d EntryDataPassword) Marshal() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func (EntryDataPassword) Unmarshal(data []byte) (core.EntryData, error) {
+	var d EntryDataPassword
+	err := json.Unmarshal(data, &d)
+	return d, err
+}
+
+func (d EntryDataPassword) Fields() map[string]any {
+	return map[string]any{"login": d.Login, "password": d.Password}
+}
+
+func (d EntryDataPassword) WithFields(values map[string]string) (core.EntryData, error) {
+	d.Login = or(values["login"], d.Login)
+	d.Password = or(values["password"], d.Password)
+	return d, nil
+}
+
+func (d EntryDataNote) Marshal() ([]byte, error) {
+	return []byte(d), nil
+}
+
+func (EntryDataNote) Unmarshal(data []byte) (core.EntryData, error) {
+	return EntryDataNote(data), nil
+}
+
+func (d EntryDataNote) Fields() map[string]any {
+	return map[string]any{"note": string(d)}
+}
+
+func (d EntryDataNote) WithFields(values map[string]string) (core.EntryData, error) {
+	return EntryDataNote(or(values["note"], string(d))), nil
+}
+
+func (d EntryDataCard) Marshal() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func (EntryDataCard) Unmarshal(data []byte) (core.EntryData, error) {
+	var d EntryDataCard
+	err := json.Unmarshal(data, &d)
+	return d, err
+}
+
+func (d EntryDataCard) Fields() map[string]any {
+	return map[string]any{
+		"number":  d.Number,
+		"expires": d.Expires,
+		"cvc":     d.Cvc,
+		"owner":   d.Owner,
+	}
+}
+
+func (d EntryDataCard) WithFields(values map[string]string) (core.EntryData, error) {
+	d.Number = or(values["number"], d.Number)
+	d.Expires = or(values["expires"], d.Expires)
+	d.Cvc = or(values["cvc"], d.Cvc)
+	d.Owner = or(values["owner"], d.Owner)
+	return d, nil
+}
+
+func (d EntryDataBinary) Marshal() ([]byte, error) {
+	return d, nil
+}
+
+func (EntryDataBinary) Unmarshal(data []byte) (core.EntryData, error) {
+	return EntryDataBinary(data), nil
+}
+
+func (d EntryDataBinary) Fields() map[string]any {
+	return map[string]any{"binary": []byte(d)}
+}
+
+func (d EntryDataBinary) WithFields(values map[string]string) (core.EntryData, error) {
+	if value := values["binary"]; value != "" {
+		return EntryDataBinary(value), nil
+	}
+	return d, nil
+}
+
+func (d EntryDataTOTP) Marshal() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func (EntryDataTOTP) Unmarshal(data []byte) (core.EntryData, error) {
+	var d EntryDataTOTP
+	err := json.Unmarshal(data, &d)
+	return d, err
+}
+
+func (d EntryDataTOTP) Fields() map[string]any {
+	return map[string]any{
+		"secret":    d.Secret,
+		"algorithm": d.Algorithm,
+		"digits":    d.Digits,
+		"period":    d.Period,
+	}
+}
+
+func (d EntryDataTOTP) WithFields(values map[string]string) (core.EntryData, error) {
+	d.Secret = or(values["secret"], d.Secret)
+	d.Algorithm = or(strings.ToUpper(values["algorithm"]), d.Algorithm)
+	for name, v := range map[string]*int{"digits": &d.Digits, "period": &d.Period} {
+		if values[name] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(values[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %q", ErrEntryTOTPInvalid, name, values[name])
+		}
+		*v = n
+	}
+	return d, nil
+}
+
+func or(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
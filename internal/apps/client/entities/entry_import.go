@@ -0,0 +1,41 @@
+package entities
+
+import "errors"
+
+const (
+	ImportFormatKeePass   ImportFormat = "keepass"
+	ImportFormatBitwarden ImportFormat = "bitwarden"
+	ImportFormatCSV       ImportFormat = "csv"
+)
+
+type (
+	ImportFormat         string
+	ImportEntriesRequest struct {
+		Format  ImportFormat
+		Content []byte
+		DryRun  bool // only reports what would be imported
+	}
+	ImportEntriesResponse struct {
+		Created    []string // keys of the created entries
+		Duplicates []string // keys that already exist, the records are skipped
+	}
+)
+
+func (f ImportFormat) Valid() bool {
+	switch f {
+	case ImportFormatKeePass, ImportFormatBitwarden, ImportFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r ImportEntriesRequest) Validate() (err error) {
+	if !r.Format.Valid() {
+		err = errors.Join(err, ErrImportFormatInvalid)
+	}
+	if len(r.Content) == 0 {
+		err = errors.Join(err, ErrImportContentInvalid)
+	}
+	return err
+}
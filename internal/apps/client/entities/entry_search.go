@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"errors"
+	"github.com/dlomanov/gophkeeper/internal/core"
+)
+
+const (
+	EntrySortName    EntrySort = "name"
+	EntrySortUpdated EntrySort = "updated"
+)
+
+type (
+	// EntrySort is the order of the found entries, by name when empty
+	EntrySort string
+	// SearchEntriesRequest filters entries by the fuzzy query over key, type, folder, tags and meta,
+	// the folder matches its subfolders too, empty filters match all entries
+	SearchEntriesRequest struct {
+		Query  string
+		Type   core.EntryType
+		Folder string
+		Tag    string
+		Sort   EntrySort
+	}
+	// GetEntryFoldersResponse contains the sorted folder paths with their parent folders
+	GetEntryFoldersResponse struct {
+		Folders []string
+	}
+	GetEntryTagsResponse struct {
+		Tags []string
+	}
+)
+
+func (s EntrySort) Valid() bool {
+	switch s {
+	case "", EntrySortName, EntrySortUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r SearchEntriesRequest) Validate() (err error) {
+	if r.Type != "" && !r.Type.Valid() {
+		err = errors.Join(err, ErrEntryTypeInvalid)
+	}
+	if !r.Sort.Valid() {
+		err = errors.Join(err, ErrEntrySortInvalid)
+	}
+	return err
+}
@@ -195,3 +195,87 @@ func TestUpdateVersion_invalidOptions(t *testing.T) {
 	require.ErrorIs(t, errs, entities.ErrEntryDataEmpty, "want data empty error")
 	require.ErrorIs(t, errs, entities.ErrEntryDataSizeExceeded, "want data size exceeded error")
 }
+
+func TestValidateEntryData(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     core.EntryType
+		data    entities.EntryData
+		wantErr error
+	}{
+		{
+			name: "valid: typed data",
+			typ:  core.EntryTypePassword,
+			data: entities.EntryDataPassword{Login: "login", Password: "password"},
+		},
+		{
+			name: "valid: fields data",
+			typ:  core.EntryTypeAPIToken,
+			data: entities.EntryDataFields{"service": "github", "token": "ghp_1", "expires": "2030-01-31"},
+		},
+		{
+			name:    "invalid: typed data mismatch",
+			typ:     core.EntryTypePassword,
+			data:    entities.EntryDataNote("note"),
+			wantErr: entities.ErrEntryTypeInvalid,
+		},
+		{
+			name:    "invalid: typed data pointer",
+			typ:     core.EntryTypePassword,
+			data:    &entities.EntryDataPassword{Login: "login", Password: "password"},
+			wantErr: entities.ErrEntryTypeInvalid,
+		},
+		{
+			name:    "invalid: field map of typed type",
+			typ:     core.EntryTypeNote,
+			data:    entities.EntryDataFields{"note": "note"},
+			wantErr: entities.ErrEntryTypeInvalid,
+		},
+		{
+			name:    "invalid: fields data mismatch",
+			typ:     core.EntryTypeWiFi,
+			data:    entities.EntryDataNote("note"),
+			wantErr: entities.ErrEntryTypeInvalid,
+		},
+		{
+			name:    "invalid: required field",
+			typ:     core.EntryTypeAPIToken,
+			data:    entities.EntryDataFields{"service": "github"},
+			wantErr: entities.ErrEntryFieldInvalid,
+		},
+		{
+			name:    "invalid: unknown field",
+			typ:     core.EntryTypeWiFi,
+			data:    entities.EntryDataFields{"ssid": "home", "channel": "6"},
+			wantErr: entities.ErrEntryFieldInvalid,
+		},
+		{
+			name:    "invalid: date field",
+			typ:     core.EntryTypeIdentity,
+			data:    entities.EntryDataFields{"document": "passport", "number": "1", "expires": "31.01.2030"},
+			wantErr: entities.ErrEntryFieldInvalid,
+		},
+		{
+			name:    "invalid: field validator",
+			typ:     core.EntryTypeWiFi,
+			data:    entities.EntryDataFields{"ssid": "home", "security": "open"},
+			wantErr: entities.ErrEntryFieldInvalid,
+		},
+		{
+			name:    "invalid: unknown type",
+			typ:     "unknown",
+			data:    entities.EntryDataFields{},
+			wantErr: entities.ErrEntryTypeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entities.ValidateEntryData(tt.typ, tt.data)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -11,6 +11,13 @@ var (
 	ErrEntryDataSizeExceeded = apperrors.NewInvalid("entry data size exceeded")
 	ErrEntryExists           = apperrors.NewInvalid("entry already exists")
 	ErrEntryNotFound         = apperrors.NewNotFound("entry not found")
+	ErrEntryVersionInvalid   = apperrors.NewInvalid("invalid entry version")
+	ErrEntryHistoryNotFound  = apperrors.NewNotFound("entry version not found in history")
+	ErrEntryConflict         = apperrors.NewConflict("entry conflict")
+	ErrEntryConflictInvalid  = apperrors.NewInvalid("invalid entry conflict")
+	ErrEntryConflictNotFound = apperrors.NewNotFound("entry conflict not found")
+	ErrEntrySyncNotFound     = apperrors.NewNotFound("entry sync not found")
+	ErrEntryUndecryptable    = apperrors.NewInvalid("entry can't be decrypted with the vault key")
 	ErrUserExists            = apperrors.NewInvalid("user already exists")
 	ErrUserCredsInvalid      = apperrors.NewInvalid("user credentials are invalid")
 	ErrUserLoginInvalid      = apperrors.NewInvalid("user login is invalid")
@@ -18,8 +25,31 @@ var (
 	ErrUserTokenNotFound     = apperrors.NewNotFound("token not found")
 	ErrUserTokenInvalid      = apperrors.NewInvalid("token is invalid")
 	ErrUserMasterPassInvalid = apperrors.NewInvalid("master password is invalid")
+	ErrUserVaultLocked       = apperrors.NewNotFound("vault key not found")
+	ErrUserVaultSaltInvalid  = apperrors.NewInvalid("vault salt is invalid")
+	ErrUserVaultKeyInvalid   = apperrors.NewInvalid("vault key is invalid")
+	ErrUserVaultKeyMismatch  = apperrors.NewInvalid("vault key doesn't match the account, use the master password of the other devices")
+	ErrUserOTPRequired       = apperrors.NewInvalid("two-factor code required")
+	ErrUserOTPInvalid        = apperrors.NewInvalid("two-factor code is invalid")
+	ErrUserOTPLocked         = apperrors.NewInvalid("too many two-factor attempts, try again later")
+	ErrUserOTPStateInvalid   = apperrors.NewInvalid("two-factor authentication state is invalid")
+	ErrSessionIDInvalid      = apperrors.NewInvalid("invalid session ID")
+	ErrSessionNotFound       = apperrors.NewNotFound("session not found")
 	ErrKVPairNotFound        = apperrors.NewNotFound("key-value pair not found")
 	ErrServerInternal        = apperrors.NewInternal("internal server error")
 	ErrServerUnavailable     = apperrors.NewInternal("server unavailable")
 	ErrEntryDataTypeInvalid  = apperrors.NewInvalid("invalid entry data type")
+	ErrEntryBlobInvalid      = apperrors.NewInvalid("invalid entry blob")
+	ErrEntryBlobPathInvalid  = apperrors.NewInvalid("invalid entry blob path")
+	ErrEntryBlobCorrupted    = apperrors.NewInvalid("entry blob checksum mismatch")
+	ErrEntryBlobNotFound     = apperrors.NewNotFound("entry blob not found")
+	ErrEntrySortInvalid      = apperrors.NewInvalid("invalid entry sort")
+	ErrEntryTOTPInvalid      = apperrors.NewInvalid("invalid authenticator key")
+	ErrEntryFieldInvalid     = apperrors.NewInvalid("invalid entry field")
+	ErrImportFormatInvalid   = apperrors.NewInvalid("invalid import format")
+	ErrImportContentInvalid  = apperrors.NewInvalid("invalid import content")
+	ErrBackupPathInvalid     = apperrors.NewInvalid("invalid backup path")
+	ErrBackupInvalid         = apperrors.NewInvalid("invalid backup archive")
+	ErrBackupPassInvalid     = apperrors.NewInvalid("backup passphrase is invalid")
+	ErrBackupVaultNotEmpty   = apperrors.NewInvalid("vault is not empty")
 )
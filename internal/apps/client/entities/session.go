@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type (
+	Session struct {
+		ID         uuid.UUID
+		Device     string
+		IP         string
+		CreatedAt  time.Time
+		LastSeenAt time.Time
+		Current    bool
+	}
+	GetSessionsResponse struct {
+		Sessions []Session
+	}
+	RevokeSessionRequest struct {
+		ID uuid.UUID
+	}
+	RevokeOtherSessionsResponse struct {
+		Revoked int64
+	}
+)
+
+func (r RevokeSessionRequest) Validate() error {
+	if r.ID == uuid.Nil {
+		return ErrSessionIDInvalid
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package entities
 
-import "errors"
+import (
+	"errors"
+	"github.com/dlomanov/gophkeeper/internal/core"
+)
 
 type (
 	SignUpUserRequest struct {
@@ -10,6 +13,36 @@ type (
 	SignInUserRequest struct {
 		Login    string
 		Password string
+		// OTP is a two-factor code or a recovery code, it's asked after ErrUserOTPRequired
+		OTP string
+	}
+	EnrollOTPResponse struct {
+		Secret string
+		URI    string
+	}
+	ConfirmOTPRequest struct {
+		Code string
+	}
+	ConfirmOTPResponse struct {
+		RecoveryCodes []string
+	}
+	DisableOTPRequest struct {
+		Code string
+	}
+	ChangeMasterPassRequest struct {
+		Old core.Pass
+		New core.Pass
+	}
+	// ChangePasswordRequest changes the server account password, other devices are signed out
+	ChangePasswordRequest struct {
+		Old string
+		New string
+		// OTP is a two-factor code or a recovery code, it's asked after ErrUserOTPRequired
+		OTP string
+	}
+	DeleteAccountRequest struct {
+		Password string
+		OTP      string
 	}
 )
 
@@ -32,3 +65,44 @@ func (r SignUpUserRequest) Validate() (err error) {
 	}
 	return err
 }
+
+func (r ConfirmOTPRequest) Validate() error {
+	if r.Code == "" {
+		return ErrUserOTPInvalid
+	}
+	return nil
+}
+
+func (r DisableOTPRequest) Validate() error {
+	if r.Code == "" {
+		return ErrUserOTPInvalid
+	}
+	return nil
+}
+
+func (r ChangeMasterPassRequest) Validate() (err error) {
+	if len(r.Old) == 0 {
+		err = errors.Join(err, ErrUserMasterPassInvalid)
+	}
+	if len(r.New) == 0 || string(r.New) == string(r.Old) {
+		err = errors.Join(err, ErrUserPasswordInvalid)
+	}
+	return err
+}
+
+func (r ChangePasswordRequest) Validate() (err error) {
+	if r.Old == "" {
+		err = errors.Join(err, ErrUserCredsInvalid)
+	}
+	if r.New == "" || r.New == r.Old {
+		err = errors.Join(err, ErrUserPasswordInvalid)
+	}
+	return err
+}
+
+func (r DeleteAccountRequest) Validate() error {
+	if r.Password == "" {
+		return ErrUserCredsInvalid
+	}
+	return nil
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/client/config"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/repo"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/auth"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/clipboard"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/marshal"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/pass"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/vault"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/migrations"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
 	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
@@ -24,22 +27,33 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"os"
+	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type Container struct {
-	registered atomic.Bool
-	Logger     *zap.Logger
-	Config     *config.Config
-	DB         *sqlx.DB
-	Conn       *grpc.ClientConn
-	Tx         *manager.Manager
-	Memcache   *mem.Cache
-	Memstorage *mem.Storage
-	UserUC     *usecases.UserUC
-	EntryUC    *usecases.EntryUC
+	registered   atomic.Bool
+	watching     sync.WaitGroup
+	stopWatch    context.CancelFunc
+	Logger       *zap.Logger
+	Config       *config.Config
+	DB           *sqlx.DB
+	Conn         *grpc.ClientConn
+	Tx           *manager.Manager
+	Memcache     *mem.Cache
+	Memstorage   *mem.Storage
+	Clipboard    *clipboard.Clipboard
+	UserUC       *usecases.UserUC
+	SessionUC    *usecases.SessionUC
+	TwoFactorUC  *usecases.TwoFactorUC
+	MasterPassUC *usecases.MasterPassUC
+	EntryUC      *usecases.EntryUC
+	// EntryChanges receives a signal when entries are synced in background
+	EntryChanges chan struct{}
 }
 
 func NewContainer(
@@ -48,15 +62,21 @@ func NewContainer(
 ) (*Container, error) {
 	memcache := mem.NewCache()
 	return &Container{
-		Logger:     logger,
-		Config:     config,
-		Memcache:   memcache,
-		Memstorage: nil,
-		DB:         nil,
-		Conn:       nil,
-		Tx:         nil,
-		UserUC:     nil,
-		EntryUC:    nil,
+		Logger:       logger,
+		Config:       config,
+		Memcache:     memcache,
+		Memstorage:   nil,
+		Clipboard:    clipboard.NewClipboard(logger, clipboard.System{}, config.ClipboardTimeout),
+		DB:           nil,
+		Conn:         nil,
+		Tx:           nil,
+		UserUC:       nil,
+		SessionUC:    nil,
+		TwoFactorUC:  nil,
+		MasterPassUC: nil,
+		EntryUC:      nil,
+
+		EntryChanges: make(chan struct{}, 1),
 	}, nil
 }
 
@@ -84,7 +104,7 @@ func (c *Container) Register(ctx context.Context, password core.Pass) error {
 	}
 
 	// grpc
-	conn, err := createGRPCConn(ctx, c.Config)
+	conn, err := createGRPCConn(ctx, c.Config, auth.NewRefresher(c.Logger, c.Memcache))
 	if err != nil {
 		return fmt.Errorf("container: failed to create grpc connection: %w", err)
 	}
@@ -92,23 +112,47 @@ func (c *Container) Register(ctx context.Context, password core.Pass) error {
 	// repos
 	entryRepo := repo.NewEntryRepo(db, getter)
 	entrySyncRepo := repo.NewEntrySyncRepo(db, getter)
+	entryConflictRepo := repo.NewEntryConflictRepo(db, getter)
 
 	// services
 	userClient := pb.NewUserServiceClient(conn)
+	sessionClient := pb.NewSessionServiceClient(conn)
+	twoFactorClient := pb.NewTwoFactorServiceClient(conn)
 	entryClient := pb.NewEntryServiceClient(conn)
 	encrypter, err := encrypto.NewEncrypter(hash)
 	if err != nil {
 		return fmt.Errorf("container: failed to create encrypter: %w", err)
 	}
+	keyring := vault.NewKeyring(&pass.Hasher{}, password)
 
 	// use-cases
-	userUC := usecases.NewUserUC(c.Logger, c.Memcache, userClient)
+	userUC := usecases.NewUserUC(c.Logger, c.Memcache, userClient, keyring, deviceName())
+	if err = userUC.UnlockVault(); err != nil {
+		return fmt.Errorf("container: failed to unlock vault: %w", err)
+	}
+	sessionUC := usecases.NewSessionUC(c.Logger, c.Memcache, sessionClient)
+	twoFactorUC := usecases.NewTwoFactorUC(c.Logger, c.Memcache, twoFactorClient)
+	masterPassUC := usecases.NewMasterPassUC(
+		c.Logger,
+		userAuthUC,
+		userUC,
+		&pass.Hasher{},
+		kvRepo,
+		entryRepo,
+		entryConflictRepo,
+		repo.NewDBKeyRepo(db),
+		keyring,
+		c.Memcache,
+		trm,
+	)
 	entryUC := usecases.NewEntriesUC(
 		c.Logger,
 		entryClient,
 		entryRepo,
 		entrySyncRepo,
+		entryConflictRepo,
 		encrypter,
+		keyring,
 		marshal.EntryMarshaler{},
 		c.Memcache,
 		trm,
@@ -120,6 +164,9 @@ func (c *Container) Register(ctx context.Context, password core.Pass) error {
 	c.Tx = trm
 	c.Memstorage = memstorage
 	c.UserUC = userUC
+	c.SessionUC = sessionUC
+	c.TwoFactorUC = twoFactorUC
+	c.MasterPassUC = masterPassUC
 	c.EntryUC = entryUC
 	return nil
 }
@@ -132,6 +179,15 @@ func (c *Container) Close() (merr error) {
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if c.stopWatch != nil {
+		c.stopWatch()
+		c.watching.Wait()
+	}
+
+	if err := c.Clipboard.Clear(); err != nil {
+		merr = errors.Join(merr, fmt.Errorf("container: failed to clear clipboard: %w", err))
+	}
+
 	if err := c.Conn.Close(); err != nil {
 		merr = errors.Join(merr, fmt.Errorf("container: failed to close GRPC-connection: %w", err))
 	}
@@ -145,19 +201,54 @@ func (c *Container) Close() (merr error) {
 	return merr
 }
 
-func createGRPCConn(ctx context.Context, conf *config.Config) (*grpc.ClientConn, error) {
+// Watch syncs entries in background until the container is closed, EntryChanges is signaled on every sync
+func (c *Container) Watch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopWatch = cancel
+	c.watching.Add(1)
+	go func() {
+		defer c.watching.Done()
+		c.EntryUC.Watch(ctx, func() {
+			select {
+			case c.EntryChanges <- struct{}{}:
+			default:
+			}
+		})
+	}()
+}
+
+func createGRPCConn(
+	ctx context.Context,
+	conf *config.Config,
+	refresher *auth.Refresher,
+) (*grpc.ClientConn, error) {
 	certPool := x509.NewCertPool()
 	if !certPool.AppendCertsFromPEM(conf.Cert) {
 		return nil, errors.New("container: failed to append cert to pool")
 	}
 	creds := credentials.NewClientTLSFromCert(certPool, "")
-	conn, err := grpc.DialContext(ctx, conf.Address, grpc.WithTransportCredentials(creds))
+	conn, err := grpc.DialContext(
+		ctx,
+		conf.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(refresher.Unary()),
+		grpc.WithChainStreamInterceptor(refresher.Stream()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("container: failed to dial: %w", err)
 	}
 	return conn, nil
 }
 
+// deviceName is shown in the server account sessions list
+func deviceName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s (%s/%s)", host, runtime.GOOS, runtime.GOARCH)
+}
+
 func (c *Container) createDB(password core.Pass) (*sqlx.DB, error) {
 	dsn := c.Config.DSN
 	if strings.HasSuffix(dsn, ".db") {
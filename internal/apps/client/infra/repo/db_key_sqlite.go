@@ -0,0 +1,28 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+type DBKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewDBKeyRepo(db *sqlx.DB) *DBKeyRepo {
+	return &DBKeyRepo{db: db}
+}
+
+// Rekey re-encrypts the database file with the new key, it can't be done within a transaction.
+// The database should be reopened after that, pooled connections keep the previous key.
+func (r *DBKeyRepo) Rekey(ctx context.Context, key core.Pass) error {
+	// pragma arguments can't be bound
+	quoted := strings.ReplaceAll(string(key), "'", "''")
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("PRAGMA rekey = '%s';", quoted)); err != nil {
+		return fmt.Errorf("db_key_repo: failed to rekey database: %w", err)
+	}
+	return nil
+}
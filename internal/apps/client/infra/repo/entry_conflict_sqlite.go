@@ -0,0 +1,285 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type (
+	EntryConflictRepo struct {
+		db     *sqlx.DB
+		getter *trmsqlx.CtxGetter
+	}
+	entryConflictRow struct {
+		ID            string         `db:"id"`
+		Key           string         `db:"key"`
+		LocalType     string         `db:"local_type"`
+		LocalMeta     sql.NullString `db:"local_meta"`
+		LocalFolder   string         `db:"local_folder"`
+		LocalTags     sql.NullString `db:"local_tags"`
+		LocalData     []byte         `db:"local_data"`
+		LocalVersion  int64          `db:"local_version"`
+		RemoteType    string         `db:"remote_type"`
+		RemoteMeta    sql.NullString `db:"remote_meta"`
+		RemoteFolder  string         `db:"remote_folder"`
+		RemoteTags    sql.NullString `db:"remote_tags"`
+		RemoteData    []byte         `db:"remote_data"`
+		RemoteVersion int64          `db:"remote_version"`
+		BaseType      sql.NullString `db:"base_type"`
+		BaseMeta      sql.NullString `db:"base_meta"`
+		BaseFolder    sql.NullString `db:"base_folder"`
+		BaseTags      sql.NullString `db:"base_tags"`
+		BaseData      []byte         `db:"base_data"`
+		BaseVersion   sql.NullInt64  `db:"base_version"`
+		CreatedAt     string         `db:"created_at"`
+	}
+)
+
+func NewEntryConflictRepo(
+	db *sqlx.DB,
+	getter *trmsqlx.CtxGetter,
+) *EntryConflictRepo {
+	return &EntryConflictRepo{
+		db:     db,
+		getter: getter,
+	}
+}
+
+func (r *EntryConflictRepo) GetAll(ctx context.Context) ([]entities.EntryConflict, error) {
+	var rows []entryConflictRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `
+		SELECT id, key,
+		       local_type, local_meta, local_folder, local_tags, local_data, local_version,
+		       remote_type, remote_meta, remote_folder, remote_tags, remote_data, remote_version,
+		       base_type, base_meta, base_folder, base_tags, base_data, base_version,
+		       created_at
+		FROM entries_conflicts
+		ORDER BY created_at;`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_conflict_repo: failed to get conflicts: %w", err)
+	}
+	result := make([]entities.EntryConflict, len(rows))
+	for i, row := range rows {
+		if result[i], err = r.toEntity(row); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *EntryConflictRepo) Get(ctx context.Context, id uuid.UUID) (entities.EntryConflict, error) {
+	row := entryConflictRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT id, key,
+		       local_type, local_meta, local_folder, local_tags, local_data, local_version,
+		       remote_type, remote_meta, remote_folder, remote_tags, remote_data, remote_version,
+		       base_type, base_meta, base_folder, base_tags, base_data, base_version,
+		       created_at
+		FROM entries_conflicts
+		WHERE id = $1;`, id.String())
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entities.EntryConflict{}, fmt.Errorf("entry_conflict_repo: %w", entities.ErrEntryConflictNotFound)
+	case err != nil:
+		return entities.EntryConflict{}, fmt.Errorf("entry_conflict_repo: failed to get conflict: %w", err)
+	}
+	return r.toEntity(row)
+}
+
+// Save creates the conflict or replaces the existing one of the same entry
+func (r *EntryConflictRepo) Save(ctx context.Context, conflict entities.EntryConflict) error {
+	row, err := r.toRow(conflict)
+	if err != nil {
+		return err
+	}
+	_, err = r.getDB(ctx).NamedExecContext(ctx, `
+		insert into entries_conflicts (id, key,
+		                               local_type, local_meta, local_folder, local_tags, local_data, local_version,
+		                               remote_type, remote_meta, remote_folder, remote_tags, remote_data, remote_version,
+		                               base_type, base_meta, base_folder, base_tags, base_data, base_version,
+		                               created_at)
+		values (:id, :key,
+		        :local_type, :local_meta, :local_folder, :local_tags, :local_data, :local_version,
+		        :remote_type, :remote_meta, :remote_folder, :remote_tags, :remote_data, :remote_version,
+		        :base_type, :base_meta, :base_folder, :base_tags, :base_data, :base_version,
+		        :created_at)
+		on conflict (id) do update
+		set key = excluded.key,
+		    local_type = excluded.local_type,
+		    local_meta = excluded.local_meta,
+		    local_folder = excluded.local_folder,
+		    local_tags = excluded.local_tags,
+		    local_data = excluded.local_data,
+		    local_version = excluded.local_version,
+		    remote_type = excluded.remote_type,
+		    remote_meta = excluded.remote_meta,
+		    remote_folder = excluded.remote_folder,
+		    remote_tags = excluded.remote_tags,
+		    remote_data = excluded.remote_data,
+		    remote_version = excluded.remote_version,
+		    base_type = excluded.base_type,
+		    base_meta = excluded.base_meta,
+		    base_folder = excluded.base_folder,
+		    base_tags = excluded.base_tags,
+		    base_data = excluded.base_data,
+		    base_version = excluded.base_version;`,
+		row)
+	if err != nil {
+		return fmt.Errorf("entry_conflict_repo: failed to save conflict: %w", err)
+	}
+	return nil
+}
+
+func (r *EntryConflictRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.getDB(ctx).ExecContext(ctx, `delete from entries_conflicts where id = $1;`, id.String())
+	if err != nil {
+		return fmt.Errorf("entry_conflict_repo: failed to delete conflict: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("entry_conflict_repo: failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("entry_conflict_repo: %w", entities.ErrEntryConflictNotFound)
+	}
+	return nil
+}
+
+func (r *EntryConflictRepo) getDB(ctx context.Context) trmsqlx.Tr {
+	return r.getter.DefaultTrOrDB(ctx, r.db)
+}
+
+func (r *EntryConflictRepo) toEntity(row entryConflictRow) (conflict entities.EntryConflict, err error) {
+	conflict.Key = row.Key
+	conflict.ID, err = uuid.Parse(row.ID)
+	if err != nil {
+		return conflict, fmt.Errorf("entry_conflict_repo: invalid entry id: %s", row.ID)
+	}
+	conflict.CreatedAt, err = time.Parse(time.RFC3339Nano, row.CreatedAt)
+	if err != nil {
+		return conflict, fmt.Errorf("entry_conflict_repo: failed to parse created_at: %w", err)
+	}
+	conflict.Local, err = r.toVersion(
+		row.LocalType, row.LocalMeta, row.LocalFolder, row.LocalTags, row.LocalData, row.LocalVersion)
+	if err != nil {
+		return conflict, err
+	}
+	conflict.Remote, err = r.toVersion(
+		row.RemoteType, row.RemoteMeta, row.RemoteFolder, row.RemoteTags, row.RemoteData, row.RemoteVersion)
+	if err != nil {
+		return conflict, err
+	}
+	if row.BaseType.Valid {
+		base, err := r.toVersion(
+			row.BaseType.String, row.BaseMeta, row.BaseFolder.String, row.BaseTags, row.BaseData, row.BaseVersion.Int64)
+		if err != nil {
+			return conflict, err
+		}
+		conflict.Base = &base
+	}
+	return conflict, nil
+}
+
+func (r *EntryConflictRepo) toVersion(
+	typ string,
+	meta sql.NullString,
+	folder string,
+	tags sql.NullString,
+	data []byte,
+	version int64,
+) (result entities.EntryConflictVersion, err error) {
+	result.Type = core.EntryType(typ)
+	result.Folder = folder
+	result.Data = data
+	result.Version = version
+	if !result.Type.Valid() {
+		return result, fmt.Errorf("entry_conflict_repo: invalid entry type: %s", typ)
+	}
+	if meta.Valid {
+		err = json.Unmarshal([]byte(meta.String), &result.Meta)
+		if err != nil {
+			return result, fmt.Errorf("entry_conflict_repo: failed to unmarshal meta: %w", err)
+		}
+	}
+	if tags.Valid {
+		err = json.Unmarshal([]byte(tags.String), &result.Tags)
+		if err != nil {
+			return result, fmt.Errorf("entry_conflict_repo: failed to unmarshal tags: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (r *EntryConflictRepo) toRow(conflict entities.EntryConflict) (row entryConflictRow, err error) {
+	row.ID = conflict.ID.String()
+	row.Key = conflict.Key
+	row.CreatedAt = conflict.CreatedAt.Format(time.RFC3339Nano)
+
+	row.LocalType = string(conflict.Local.Type)
+	row.LocalData = conflict.Local.Data
+	row.LocalVersion = conflict.Local.Version
+	row.LocalFolder = conflict.Local.Folder
+	if row.LocalMeta, err = r.toMeta(conflict.Local.Meta); err != nil {
+		return row, err
+	}
+	if row.LocalTags, err = r.toTags(conflict.Local.Tags); err != nil {
+		return row, err
+	}
+	row.RemoteType = string(conflict.Remote.Type)
+	row.RemoteData = conflict.Remote.Data
+	row.RemoteVersion = conflict.Remote.Version
+	row.RemoteFolder = conflict.Remote.Folder
+	if row.RemoteMeta, err = r.toMeta(conflict.Remote.Meta); err != nil {
+		return row, err
+	}
+	if row.RemoteTags, err = r.toTags(conflict.Remote.Tags); err != nil {
+		return row, err
+	}
+	if base := conflict.Base; base != nil {
+		row.BaseType = sql.NullString{Valid: true, String: string(base.Type)}
+		row.BaseData = base.Data
+		row.BaseVersion = sql.NullInt64{Valid: true, Int64: base.Version}
+		row.BaseFolder = sql.NullString{Valid: true, String: base.Folder}
+		if row.BaseMeta, err = r.toMeta(base.Meta); err != nil {
+			return row, err
+		}
+		if row.BaseTags, err = r.toTags(base.Tags); err != nil {
+			return row, err
+		}
+	}
+	return row, nil
+}
+
+func (r *EntryConflictRepo) toMeta(meta map[string]string) (sql.NullString, error) {
+	if meta == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("entry_conflict_repo: failed to marshal meta: %w", err)
+	}
+	return sql.NullString{Valid: true, String: string(data)}, nil
+}
+
+func (r *EntryConflictRepo) toTags(tags []string) (sql.NullString, error) {
+	if tags == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("entry_conflict_repo: failed to marshal tags: %w", err)
+	}
+	return sql.NullString{Valid: true, String: string(data)}, nil
+}
@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/migrations"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/migrator"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"testing"
+	"time"
+)
+
+type TestEntryConflictRepoSuite struct {
+	suite.Suite
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func (s *TestEntryConflictRepoSuite) SetupSuite() {
+	var err error
+	s.logger = zaptest.NewLogger(s.T(), zaptest.Level(zap.DebugLevel))
+	s.db, err = sqlx.Open("sqlite3", "file:test.db?cache=shared&mode=memory")
+	require.NoError(s.T(), err, "failed to open database")
+	ms, err := migrations.GetMigrations()
+	require.NoError(s.T(), err, "failed to get migrations")
+	err = migrator.Migrate(s.logger.Sugar(), s.db.DB, ms)
+	require.NoError(s.T(), err, "failed to up migrations")
+}
+
+func (s *TestEntryConflictRepoSuite) TearDownSuite() {
+	err := s.db.Close()
+	require.NoError(s.T(), err, "failed to close database")
+}
+
+func TestEntryConflictRepo(t *testing.T) {
+	suite.Run(t, new(TestEntryConflictRepoSuite))
+}
+
+func (s *TestEntryConflictRepoSuite) TestMethods() {
+	ctx := context.Background()
+	sut := NewEntryConflictRepo(s.db, trmsqlx.DefaultCtxGetter)
+
+	conflicts, err := sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get conflicts")
+	require.Empty(s.T(), conflicts, "conflicts should be empty")
+
+	conflict := entities.EntryConflict{
+		ID:  uuid.New(),
+		Key: "key1",
+		Local: entities.EntryConflictVersion{
+			Type:    core.EntryTypePassword,
+			Meta:    map[string]string{"description": "local"},
+			Data:    []byte("local"),
+			Version: 1,
+		},
+		Remote: entities.EntryConflictVersion{
+			Type:    core.EntryTypePassword,
+			Data:    []byte("remote"),
+			Version: 2,
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	err = sut.Save(ctx, conflict)
+	require.NoError(s.T(), err, "failed to save conflict")
+	got, err := sut.Get(ctx, conflict.ID)
+	require.NoError(s.T(), err, "failed to get conflict")
+	require.Equal(s.T(), conflict, got, "conflict mismatch")
+
+	conflict.Remote.Version = 3
+	conflict.Base = &entities.EntryConflictVersion{
+		Type:    core.EntryTypePassword,
+		Meta:    map[string]string{"description": "base"},
+		Data:    []byte("base"),
+		Version: 1,
+	}
+	err = sut.Save(ctx, conflict)
+	require.NoError(s.T(), err, "failed to replace conflict")
+	conflicts, err = sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get conflicts")
+	require.Equal(s.T(), []entities.EntryConflict{conflict}, conflicts, "conflicts mismatch")
+
+	err = sut.Delete(ctx, conflict.ID)
+	require.NoError(s.T(), err, "failed to delete conflict")
+	_, err = sut.Get(ctx, conflict.ID)
+	require.ErrorIs(s.T(), err, entities.ErrEntryConflictNotFound, "expected conflict not found")
+	err = sut.Delete(ctx, conflict.ID)
+	require.ErrorIs(s.T(), err, entities.ErrEntryConflictNotFound, "expected conflict not found")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"strings"
 	"time"
 )
 
@@ -24,6 +25,8 @@ type (
 		Key           string         `db:"key"`
 		Type          string         `db:"type"`
 		Meta          sql.NullString `db:"meta"`
+		Folder        string         `db:"folder"`
+		Tags          sql.NullString `db:"tags"`
 		Data          []byte         `db:"data"`
 		GlobalVersion int64          `db:"global_version"`
 		Version       int64          `db:"version"`
@@ -49,7 +52,7 @@ func NewEntryRepo(
 func (r *EntryRepo) GetAll(ctx context.Context) ([]entities.Entry, error) {
 	var rows []entryRow
 	err := r.getDB(ctx).SelectContext(ctx, &rows, `
-		SELECT id, key, type, meta, data, global_version, version, created_at, updated_at
+		SELECT id, key, type, meta, folder, tags, data, global_version, version, created_at, updated_at
 		FROM entries
 		ORDER BY created_at;`)
 	switch {
@@ -61,10 +64,85 @@ func (r *EntryRepo) GetAll(ctx context.Context) ([]entities.Entry, error) {
 	return r.toEntities(rows)
 }
 
+// Search finds entries with key, type, folder, tags or meta values containing all query characters in order,
+// the encrypted data isn't read, so found entries come without it
+func (r *EntryRepo) Search(ctx context.Context, request entities.SearchEntriesRequest) ([]entities.Entry, error) {
+	order := "key COLLATE NOCASE"
+	if request.Sort == entities.EntrySortUpdated {
+		order = "updated_at DESC, key COLLATE NOCASE"
+	}
+	folder := core.NormalizeFolder(request.Folder)
+	tag, err := tagElement(request.Tag)
+	if err != nil {
+		return nil, err
+	}
+	var rows []entryRow
+	err = r.getDB(ctx).SelectContext(ctx, &rows, `
+		SELECT id, key, type, meta, folder, tags, global_version, version, created_at, updated_at
+		FROM entries
+		WHERE ($1 = '' OR type = $1)
+		  AND ($2 = '' OR folder = $2 OR substr(folder, 1, length($3)) = $3)
+		  AND ($4 = '' OR instr(tags, $4) > 0)
+		  AND lower(key || ' ' || type || ' ' || folder || ' ' || coalesce(tags, '') || ' ' ||
+		            coalesce((SELECT group_concat(value, ' ') FROM json_each(entries.meta)), ''))
+		      LIKE $5 ESCAPE '\'
+		ORDER BY `+order+`;`,
+		string(request.Type),
+		folder,
+		folder+core.FolderSeparator,
+		tag,
+		fuzzyPattern(request.Query))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to search entries: %w", err)
+	}
+	return r.toEntities(rows)
+}
+
+// GetFolders returns the sorted distinct folders of entries, the root folder is omitted
+func (r *EntryRepo) GetFolders(ctx context.Context) ([]string, error) {
+	var folders []string
+	err := r.getDB(ctx).SelectContext(ctx, &folders, `
+		SELECT DISTINCT folder
+		FROM entries
+		WHERE folder <> ''
+		ORDER BY folder;`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get folders: %w", err)
+	}
+	return folders, nil
+}
+
+// GetTags returns the sorted distinct tags of entries
+func (r *EntryRepo) GetTags(ctx context.Context) ([]string, error) {
+	var rows []string
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `SELECT DISTINCT tags FROM entries WHERE tags IS NOT NULL;`)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get tags: %w", err)
+	}
+	var tags []string
+	for _, row := range rows {
+		var v []string
+		if err = json.Unmarshal([]byte(row), &v); err != nil {
+			return nil, fmt.Errorf("entry_repo: failed to unmarshal tags: %w", err)
+		}
+		tags = append(tags, v...)
+	}
+	return core.NormalizeTags(tags), nil
+}
+
 func (r *EntryRepo) Get(ctx context.Context, id uuid.UUID) (entities.Entry, error) {
 	row := entryRow{}
 	err := r.getDB(ctx).GetContext(ctx, &row, `
-		SELECT id, key, type, meta, data, global_version, version, created_at, updated_at
+		SELECT id, key, type, meta, folder, tags, data, global_version, version, created_at, updated_at
 		FROM entries
 		WHERE id = $1;`, id)
 	switch {
@@ -78,7 +156,8 @@ func (r *EntryRepo) Get(ctx context.Context, id uuid.UUID) (entities.Entry, erro
 
 func (r *EntryRepo) GetVersions(ctx context.Context) ([]core.EntryVersion, error) {
 	var rows []entryVersionRow
-	err := r.getDB(ctx).SelectContext(ctx, &rows, `SELECT id, global_version FROM entries`)
+	// unpushed entries are unknown to the server and have no version yet
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `SELECT id, global_version FROM entries WHERE global_version <> 0`)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
@@ -101,8 +180,8 @@ func (r *EntryRepo) Create(ctx context.Context, entry entities.Entry) error {
 		return fmt.Errorf("entry_repo: failed to map entry to row: %w", err)
 	}
 	res, err := r.getDB(ctx).NamedExecContext(ctx, `
-		insert into entries (id, key, type, meta, data, global_version, version, created_at, updated_at)
-		values (:id, :key, :type, :meta, :data, :global_version, :version, :created_at, :updated_at)
+		insert into entries (id, key, type, meta, folder, tags, data, global_version, version, created_at, updated_at)
+		values (:id, :key, :type, :meta, :folder, :tags, :data, :global_version, :version, :created_at, :updated_at)
 		on conflict do nothing;`,
 		row)
 	if err != nil {
@@ -126,6 +205,8 @@ func (r *EntryRepo) Update(ctx context.Context, entry entities.Entry) error {
 	res, err := r.getDB(ctx).NamedExecContext(ctx, `
 		update entries
 		set meta = :meta,
+		    folder = :folder,
+		    tags = :tags,
 		    data = :data,
 		    global_version = :global_version,
 		    version = :version,
@@ -160,6 +241,36 @@ func (r *EntryRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// tagElement returns the tag as the JSON string that is found in the tags JSON array,
+// the empty string is returned for the empty tag
+func tagElement(tag string) (string, error) {
+	if tag = strings.TrimSpace(tag); tag == "" {
+		return "", nil
+	}
+	quoted, err := json.Marshal(tag)
+	if err != nil {
+		return "", fmt.Errorf("entry_repo: failed to marshal tag: %w", err)
+	}
+	return string(quoted), nil
+}
+
+// fuzzyPattern makes the LIKE pattern that matches values with all query characters in order
+func fuzzyPattern(query string) string {
+	sb := strings.Builder{}
+	sb.WriteByte('%')
+	for _, ch := range strings.ToLower(query) {
+		switch ch {
+		case ' ':
+			continue
+		case '%', '_', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(ch)
+		sb.WriteByte('%')
+	}
+	return sb.String()
+}
+
 func (r *EntryRepo) getDB(ctx context.Context) trmsqlx.Tr {
 	return r.getter.DefaultTrOrDB(ctx, r.db)
 }
@@ -178,6 +289,7 @@ func (r *EntryRepo) toEntities(rows []entryRow) ([]entities.Entry, error) {
 
 func (r *EntryRepo) toEntry(row entryRow) (entry entities.Entry, err error) {
 	entry.Key = row.Key
+	entry.Folder = row.Folder
 	entry.Data = row.Data
 	entry.GlobalVersion = row.GlobalVersion
 	entry.Version = row.Version
@@ -203,12 +315,19 @@ func (r *EntryRepo) toEntry(row entryRow) (entry entities.Entry, err error) {
 			return entry, fmt.Errorf("entry_repo: failed to unmarshal meta: %w", err)
 		}
 	}
+	if row.Tags.Valid {
+		err = json.Unmarshal([]byte(row.Tags.String), &entry.Tags)
+		if err != nil {
+			return entry, fmt.Errorf("entry_repo: failed to unmarshal tags: %w", err)
+		}
+	}
 	return entry, nil
 }
 
 func (r *EntryRepo) toRow(entry entities.Entry) (row entryRow, err error) {
 	row.ID = entry.ID.String()
 	row.Key = entry.Key
+	row.Folder = entry.Folder
 	row.Type = string(entry.Type)
 	row.Data = entry.Data
 	row.GlobalVersion = entry.GlobalVersion
@@ -222,5 +341,12 @@ func (r *EntryRepo) toRow(entry entities.Entry) (row entryRow, err error) {
 		}
 		row.Meta = sql.NullString{Valid: true, String: string(meta)}
 	}
+	if entry.Tags != nil {
+		tags, err := json.Marshal(entry.Tags)
+		if err != nil {
+			return row, fmt.Errorf("entry_repo: failed to marshal tags: %w", err)
+		}
+		row.Tags = sql.NullString{Valid: true, String: string(tags)}
+	}
 	return row, nil
 }
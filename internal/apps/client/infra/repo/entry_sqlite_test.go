@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap/zaptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type TestEntryRepoSuite struct {
@@ -65,6 +66,7 @@ func (s *TestEntryRepoSuite) TestMethods() {
 	require.NoError(s.T(), err, "failed to create entry")
 	createEntries[entry.ID] = entry
 	for _, v := range createEntries {
+		v.GlobalVersion = 1
 		err = sut.Create(ctx, *v)
 		require.NoError(s.T(), err, "failed to create entry")
 	}
@@ -109,9 +111,12 @@ func (s *TestEntryRepoSuite) TestMethods() {
 	require.ErrorIs(s.T(), err, entities.ErrEntryNotFound, "expected entry not found error")
 
 	// get versions
+	entry, err = entities.NewEntry("key4", core.EntryTypeNote, []byte("data4"))
+	require.NoError(s.T(), err, "failed to create entry")
+	require.NoError(s.T(), sut.Create(ctx, *entry), "failed to create unpushed entry")
 	versions, err := sut.GetVersions(ctx)
 	require.NoError(s.T(), err, "failed to get entry versions")
-	require.Len(s.T(), versions, len(createEntries)-1, "expected 2 versions")
+	require.Len(s.T(), versions, len(createEntries)-1, "expected 2 versions, unpushed entry should be skipped")
 	for _, v := range versions {
 		entry, ok := createEntries[v.ID]
 		require.True(s.T(), ok, "entry not found")
@@ -119,3 +124,108 @@ func (s *TestEntryRepoSuite) TestMethods() {
 		require.Equal(s.T(), v.Version, entry.GlobalVersion)
 	}
 }
+
+func (s *TestEntryRepoSuite) TestSearch() {
+	ctx := context.Background()
+	sut := NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entries, err := sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get entries")
+	for _, v := range entries {
+		require.NoError(s.T(), sut.Delete(ctx, v.ID), "failed to clean up entries")
+	}
+
+	newEntry := func(key string, typ core.EntryType, meta map[string]string, updatedAt time.Time) {
+		entry, err := entities.NewEntry(key, typ, []byte("data"))
+		require.NoError(s.T(), err, "failed to create entry")
+		entry.Meta = meta
+		entry.UpdatedAt = updatedAt
+		require.NoError(s.T(), sut.Create(ctx, *entry), "failed to create entry")
+	}
+	now := time.Now().UTC()
+	newEntry("GitHub", core.EntryTypePassword, map[string]string{"url": "https://github.com"}, now.Add(-time.Hour))
+	newEntry("gmail", core.EntryTypePassword, nil, now)
+	newEntry("visa", core.EntryTypeCard, map[string]string{"description": "salary 100%"}, now.Add(-2*time.Hour))
+	newEntry("todo", core.EntryTypeNote, nil, now.Add(-3*time.Hour))
+
+	keys := func(request entities.SearchEntriesRequest) []string {
+		found, err := sut.Search(ctx, request)
+		require.NoError(s.T(), err, "failed to search entries")
+		result := make([]string, len(found))
+		for i, v := range found {
+			result[i] = v.Key
+		}
+		return result
+	}
+	require.Equal(s.T(), []string{"GitHub", "gmail", "todo", "visa"}, keys(entities.SearchEntriesRequest{}), "sorted by name ignoring case")
+	require.Equal(s.T(), []string{"gmail", "GitHub", "visa", "todo"}, keys(entities.SearchEntriesRequest{Sort: entities.EntrySortUpdated}))
+	require.Equal(s.T(), []string{"GitHub", "gmail"}, keys(entities.SearchEntriesRequest{Query: "gi"}), "characters should match in order")
+	require.Equal(s.T(), []string{"GitHub"}, keys(entities.SearchEntriesRequest{Query: "github.com"}), "meta values should match")
+	require.Empty(s.T(), keys(entities.SearchEntriesRequest{Query: "description"}), "meta keys shouldn't match")
+	require.Equal(s.T(), []string{"visa"}, keys(entities.SearchEntriesRequest{Query: "card"}), "type should match")
+	require.Equal(s.T(), []string{"visa"}, keys(entities.SearchEntriesRequest{Query: "100%"}), "wildcards should be escaped")
+	require.Empty(s.T(), keys(entities.SearchEntriesRequest{Query: "1_0"}), "wildcards should be escaped")
+	require.Equal(s.T(), []string{"gmail", "GitHub"}, keys(entities.SearchEntriesRequest{
+		Query: "g",
+		Type:  core.EntryTypePassword,
+		Sort:  entities.EntrySortUpdated,
+	}))
+	require.Equal(s.T(), []string{"todo"}, keys(entities.SearchEntriesRequest{Type: core.EntryTypeNote}))
+}
+
+func (s *TestEntryRepoSuite) TestFoldersAndTags() {
+	ctx := context.Background()
+	sut := NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entries, err := sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get entries")
+	for _, v := range entries {
+		require.NoError(s.T(), sut.Delete(ctx, v.ID), "failed to clean up entries")
+	}
+	defer func() {
+		entries, err := sut.GetAll(ctx)
+		require.NoError(s.T(), err, "failed to get entries")
+		for _, v := range entries {
+			require.NoError(s.T(), sut.Delete(ctx, v.ID), "failed to clean up entries")
+		}
+	}()
+
+	newEntry := func(key string, folder string, tags ...string) {
+		entry, err := entities.NewEntry(key, core.EntryTypeNote, []byte("data"))
+		require.NoError(s.T(), err, "failed to create entry")
+		entry.Folder = folder
+		entry.Tags = tags
+		require.NoError(s.T(), sut.Create(ctx, *entry), "failed to create entry")
+	}
+	newEntry("db", "Work/Servers", "prod", `say "hi"`)
+	newEntry("mail", "Work", "production")
+	newEntry("vpn", "Workshop")
+	newEntry("todo", "")
+
+	folders, err := sut.GetFolders(ctx)
+	require.NoError(s.T(), err, "failed to get folders")
+	require.Equal(s.T(), []string{"Work", "Work/Servers", "Workshop"}, folders)
+	tags, err := sut.GetTags(ctx)
+	require.NoError(s.T(), err, "failed to get tags")
+	require.Equal(s.T(), []string{"prod", "production", `say "hi"`}, tags)
+
+	keys := func(request entities.SearchEntriesRequest) []string {
+		found, err := sut.Search(ctx, request)
+		require.NoError(s.T(), err, "failed to search entries")
+		result := make([]string, len(found))
+		for i, v := range found {
+			result[i] = v.Key
+		}
+		return result
+	}
+	require.Equal(s.T(), []string{"db", "mail"}, keys(entities.SearchEntriesRequest{Folder: "Work"}), "subfolders should match")
+	require.Equal(s.T(), []string{"db"}, keys(entities.SearchEntriesRequest{Folder: "/Work/Servers/"}))
+	require.Empty(s.T(), keys(entities.SearchEntriesRequest{Folder: "work"}), "folders are case-sensitive")
+	require.Equal(s.T(), []string{"db"}, keys(entities.SearchEntriesRequest{Tag: "prod"}), "tag should match exactly")
+	require.Equal(s.T(), []string{"db"}, keys(entities.SearchEntriesRequest{Tag: `say "hi"`}))
+	require.Equal(s.T(), []string{"vpn"}, keys(entities.SearchEntriesRequest{Query: "shop"}), "folder should match the query")
+	require.Equal(s.T(), []string{"mail"}, keys(entities.SearchEntriesRequest{Query: "tion"}), "tags should match the query")
+
+	found, err := sut.Search(ctx, entities.SearchEntriesRequest{Tag: "prod"})
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Work/Servers", found[0].Folder)
+	require.Equal(s.T(), []string{"prod", `say "hi"`}, found[0].Tags)
+}
@@ -19,6 +19,7 @@ type (
 	}
 	entrySyncRow struct {
 		ID        string `db:"id"`
+		RequestID string `db:"request_id"`
 		CreatedAt string `db:"created_at"`
 	}
 )
@@ -35,7 +36,7 @@ func NewEntrySyncRepo(
 
 func (r *EntrySyncRepo) GetAll(ctx context.Context) ([]entities.EntrySync, error) {
 	var rows []entrySyncRow
-	err := r.getDB(ctx).SelectContext(ctx, &rows, `select id, created_at from entries_sync order by created_at;`)
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `select id, request_id, created_at from entries_sync order by created_at;`)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
@@ -50,26 +51,52 @@ func (r *EntrySyncRepo) GetAll(ctx context.Context) ([]entities.EntrySync, error
 		}
 		res[i] = entities.EntrySync{
 			ID:        uuid.MustParse(row.ID),
+			RequestID: row.RequestID,
 			CreatedAt: createdAt,
 		}
 	}
 	return res, nil
 }
 
+func (r *EntrySyncRepo) Get(ctx context.Context, id uuid.UUID) (entities.EntrySync, error) {
+	var row entrySyncRow
+	err := r.getDB(ctx).GetContext(ctx, &row, `select id, request_id, created_at from entries_sync where id = $1;`, id.String())
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entities.EntrySync{}, fmt.Errorf("entry_sync_repo: %w", entities.ErrEntrySyncNotFound)
+	case err != nil:
+		return entities.EntrySync{}, fmt.Errorf("entry_sync_repo: failed to get entry sync: %w", err)
+	}
+	createdAt, err := time.Parse(time.RFC3339, row.CreatedAt)
+	if err != nil {
+		return entities.EntrySync{}, fmt.Errorf("entry_sync_repo: failed to parse created_at: %w", err)
+	}
+	return entities.EntrySync{
+		ID:        uuid.MustParse(row.ID),
+		RequestID: row.RequestID,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// Create adds the entry sync, the existing one keeps its position and gets the new request ID,
+// so the change made after the unacknowledged push isn't replayed with the pushed data
 func (r *EntrySyncRepo) Create(ctx context.Context, entrySync entities.EntrySync) error {
 	_, err := r.getDB(ctx).ExecContext(ctx, `
-		insert into entries_sync (id, created_at)
-		values (:id, :created_at)
-		on conflict (id) do nothing ;`,
-		entrySync.ID.String(), entrySync.CreatedAt.Format(time.RFC3339))
+		insert into entries_sync (id, request_id, created_at)
+		values (:id, :request_id, :created_at)
+		on conflict (id) do update set request_id = excluded.request_id ;`,
+		entrySync.ID.String(), entrySync.RequestID, entrySync.CreatedAt.Format(time.RFC3339))
 	if err != nil {
 		return fmt.Errorf("entry_sync_repo: failed to create entry sync: %w", err)
 	}
 	return nil
 }
 
-func (r *EntrySyncRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.getDB(ctx).ExecContext(ctx, `delete from entries_sync where id = $1 ;`, id.String())
+// Delete removes the entry sync unless the entry changed after the request
+func (r *EntrySyncRepo) Delete(ctx context.Context, id uuid.UUID, requestID string) error {
+	_, err := r.getDB(ctx).ExecContext(ctx, `
+		delete from entries_sync
+		where id = $1 and request_id = $2 ;`, id.String(), requestID)
 	if err != nil {
 		return fmt.Errorf("entry_sync_repo: failed to delete entry sync: %w", err)
 	}
@@ -59,10 +59,42 @@ func (s *TestEntrySyncRepoSuite) TestMethods() {
 	require.NoError(s.T(), err, "failed to get entries")
 	for _, entry := range entries {
 		require.Equal(s.T(), entry.ID, createEntries[entry.ID].ID)
-		err = sut.Delete(ctx, createEntries[entry.ID].ID)
+		require.Equal(s.T(), entry.RequestID, createEntries[entry.ID].RequestID)
+		err = sut.Delete(ctx, entry.ID, entry.RequestID)
 		require.NoError(s.T(), err, "failed to delete entry")
 	}
 	entries, err = sut.GetAll(ctx)
 	require.NoError(s.T(), err, "failed to get entries")
 	require.Empty(s.T(), entries, "entries should be empty")
 }
+
+func (s *TestEntrySyncRepoSuite) TestDeleteChanged() {
+	ctx := context.Background()
+	sut := NewEntrySyncRepo(s.db, trmsqlx.DefaultCtxGetter)
+
+	pushed := entities.NewEntrySync(uuid.New())
+	err := sut.Create(ctx, *pushed)
+	require.NoError(s.T(), err, "failed to create entry sync")
+	changed := entities.NewEntrySync(pushed.ID)
+	err = sut.Create(ctx, *changed)
+	require.NoError(s.T(), err, "failed to create entry sync")
+
+	got, err := sut.Get(ctx, pushed.ID)
+	require.NoError(s.T(), err, "failed to get entry sync")
+	require.Equal(s.T(), changed.RequestID, got.RequestID, "entry sync should get the new request ID")
+
+	err = sut.Delete(ctx, pushed.ID, pushed.RequestID)
+	require.NoError(s.T(), err, "failed to delete entry sync")
+	entries, err := sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get entry syncs")
+	require.Len(s.T(), entries, 1, "changed entry sync should be kept")
+	require.Equal(s.T(), changed.RequestID, entries[0].RequestID)
+
+	err = sut.Delete(ctx, changed.ID, changed.RequestID)
+	require.NoError(s.T(), err, "failed to delete entry sync")
+	entries, err = sut.GetAll(ctx)
+	require.NoError(s.T(), err, "failed to get entry syncs")
+	require.Empty(s.T(), entries, "entry syncs should be empty")
+	_, err = sut.Get(ctx, changed.ID)
+	require.ErrorIs(s.T(), err, entities.ErrEntrySyncNotFound)
+}
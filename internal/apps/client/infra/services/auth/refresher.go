@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	sharedmd "github.com/dlomanov/gophkeeper/internal/apps/shared/md"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cacheKeyToken        = "token"
+	cacheKeyRefreshToken = "refresh_token"
+	// streamRefreshMargin covers the clock skew and the stream setup,
+	// the token is checked by the server only when the stream is started
+	streamRefreshMargin = time.Minute
+)
+
+// anonymousMethods are called without the token, so there is nothing to refresh
+var anonymousMethods = map[string]bool{
+	pb.UserService_SignUp_FullMethodName:  true,
+	pb.UserService_SignIn_FullMethodName:  true,
+	pb.UserService_Refresh_FullMethodName: true,
+}
+
+type (
+	// Refresher renews the expired token by the refresh token and retries the rejected call
+	Refresher struct {
+		mu     sync.Mutex
+		logger *zap.Logger
+		cache  *mem.Cache
+	}
+	refreshStream struct {
+		grpc.ClientStream
+		ctx       context.Context
+		cc        *grpc.ClientConn
+		method    string
+		refresher *Refresher
+	}
+)
+
+func NewRefresher(logger *zap.Logger, cache *mem.Cache) *Refresher {
+	return &Refresher{
+		logger: logger,
+		cache:  cache,
+	}
+}
+
+func (r *Refresher) Unary() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !refreshable(method, err) {
+			return err
+		}
+		token, ok := r.refresh(ctx, cc, usedToken(ctx))
+		if !ok {
+			return err
+		}
+		return invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// Stream refreshes the token that is about to expire before the stream is started,
+// client streams can't be retried, the sent messages aren't kept.
+// The stream rejected on creation is retried, streams rejected on receive
+// get the token refreshed for the next attempt.
+func (r *Refresher) Stream() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if used := usedToken(ctx); !anonymousMethods[method] && expiresSoon(used, time.Now()) {
+			if token, ok := r.refresh(ctx, cc, used); ok {
+				ctx = withToken(ctx, token)
+			}
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if refreshable(method, err) {
+			token, ok := r.refresh(ctx, cc, usedToken(ctx))
+			if !ok {
+				return nil, err
+			}
+			stream, err = streamer(withToken(ctx, token), desc, cc, method, opts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &refreshStream{
+			ClientStream: stream,
+			ctx:          ctx,
+			cc:           cc,
+			method:       method,
+			refresher:    r,
+		}, nil
+	}
+}
+
+func (s *refreshStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if refreshable(s.method, err) {
+		s.refresher.refresh(s.ctx, s.cc, usedToken(s.ctx))
+	}
+	return err
+}
+
+// refresh returns the token to retry with, the refresh is skipped
+// when the used token has been already refreshed by a concurrent call.
+func (r *Refresher) refresh(ctx context.Context, cc *grpc.ClientConn, used string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.cache.GetString(cacheKeyToken); ok && token != "" && token != used {
+		return token, true
+	}
+	refreshToken, ok := r.cache.GetString(cacheKeyRefreshToken)
+	if !ok || refreshToken == "" {
+		return "", false
+	}
+	resp, err := pb.NewUserServiceClient(cc).Refresh(ctx, &pb.RefreshTokenRequest{RefreshToken: refreshToken})
+	switch {
+	case status.Code(err) == codes.Unauthenticated:
+		r.logger.Debug("refresh token rejected", zap.Error(err))
+		r.cache.SetString(cacheKeyRefreshToken, "")
+		return "", false
+	case err != nil:
+		r.logger.Debug("failed to refresh token", zap.Error(err))
+		return "", false
+	}
+	r.cache.SetString(cacheKeyToken, resp.Token)
+	r.cache.SetString(cacheKeyRefreshToken, resp.RefreshToken)
+	return resp.Token, true
+}
+
+func refreshable(method string, err error) bool {
+	return status.Code(err) == codes.Unauthenticated && !anonymousMethods[method]
+}
+
+// expiresSoon reads the token expiration without the signature check, the server checks the token anyway
+func expiresSoon(token string, now time.Time) bool {
+	if token == "" {
+		return false
+	}
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == nil {
+		return false
+	}
+	return claims.ExpiresAt.Before(now.Add(streamRefreshMargin))
+}
+
+func usedToken(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	values := md.Get(sharedmd.AuthKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(values[len(values)-1], sharedmd.Schema+" ")
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(sharedmd.AuthKey, sharedmd.Schema+" "+token)
+	return metadata.NewOutgoingContext(ctx, md)
+}
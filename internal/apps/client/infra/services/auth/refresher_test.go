@@ -0,0 +1,144 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/auth"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	sharedmd "github.com/dlomanov/gophkeeper/internal/apps/shared/md"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type (
+	userServer struct {
+		pb.UnimplementedUserServiceServer
+		refreshed atomic.Int32
+	}
+	entryServer struct {
+		pb.UnimplementedEntryServiceServer
+	}
+)
+
+func (s *userServer) Refresh(_ context.Context, in *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if in.RefreshToken != "refresh_token" {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+	s.refreshed.Add(1)
+	return &pb.RefreshTokenResponse{Token: "new_token", RefreshToken: "new_refresh_token"}, nil
+}
+
+func (s *entryServer) GetAll(ctx context.Context, _ *pb.GetEntriesRequest) (*pb.GetEntriesResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(sharedmd.AuthKey); len(v) != 1 || v[0] != sharedmd.Schema+" new_token" {
+		return nil, status.Error(codes.Unauthenticated, "token expired")
+	}
+	return &pb.GetEntriesResponse{}, nil
+}
+
+// UploadBlob rejects the stale token only when the stream is closed, like the server auth interceptor does
+func (s *entryServer) UploadBlob(stream pb.EntryService_UploadBlobServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if v := md.Get(sharedmd.AuthKey); len(v) != 1 || v[0] != sharedmd.Schema+" new_token" {
+		return status.Error(codes.Unauthenticated, "token expired")
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return stream.SendAndClose(&pb.UploadBlobResponse{})
+		}
+	}
+}
+
+func TestRefresher(t *testing.T) {
+	ctx := context.Background()
+	users := &userServer{}
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterUserServiceServer(server, users)
+	pb.RegisterEntryServiceServer(server, &entryServer{})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	cache := mem.NewCache()
+	cache.SetString("token", "old_token")
+	cache.SetString("refresh_token", "refresh_token")
+	refresher := auth.NewRefresher(zaptest.NewLogger(t), cache)
+	conn, err := grpc.DialContext(
+		ctx,
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(refresher.Unary()),
+		grpc.WithChainStreamInterceptor(refresher.Stream()),
+	)
+	require.NoError(t, err, "failed to create connection")
+	defer func() { _ = conn.Close() }()
+	client := pb.NewEntryServiceClient(conn)
+
+	callCtx := metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV("old_token")...)
+	_, err = client.GetAll(callCtx, &pb.GetEntriesRequest{})
+	require.NoError(t, err, "call should be retried with refreshed token")
+	token, _ := cache.GetString("token")
+	require.Equal(t, "new_token", token, "refreshed token should be cached")
+	refreshToken, _ := cache.GetString("refresh_token")
+	require.Equal(t, "new_refresh_token", refreshToken, "rotated refresh token should be cached")
+
+	_, err = client.GetAll(callCtx, &pb.GetEntriesRequest{})
+	require.NoError(t, err, "call with stale token should be retried with cached token")
+	require.Equal(t, int32(1), users.refreshed.Load(), "token should be refreshed once")
+
+	cache.SetString("token", "expired_token")
+	callCtx = metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV("expired_token")...)
+	_, err = client.GetAll(callCtx, &pb.GetEntriesRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), "rejected refresh token should not be retried")
+	refreshToken, _ = cache.GetString("refresh_token")
+	require.Empty(t, refreshToken, "rejected refresh token should be dropped")
+}
+
+func TestRefresher_Stream(t *testing.T) {
+	ctx := context.Background()
+	users := &userServer{}
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterUserServiceServer(server, users)
+	pb.RegisterEntryServiceServer(server, &entryServer{})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	expiring, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Second)),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err, "failed to create token")
+	cache := mem.NewCache()
+	cache.SetString("token", expiring)
+	cache.SetString("refresh_token", "refresh_token")
+	refresher := auth.NewRefresher(zaptest.NewLogger(t), cache)
+	conn, err := grpc.DialContext(
+		ctx,
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainStreamInterceptor(refresher.Stream()),
+	)
+	require.NoError(t, err, "failed to create connection")
+	defer func() { _ = conn.Close() }()
+	client := pb.NewEntryServiceClient(conn)
+
+	stream, err := client.UploadBlob(metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV(expiring)...))
+	require.NoError(t, err, "failed to open stream")
+	require.NoError(t, stream.Send(&pb.UploadBlobRequest{Chunk: []byte("chunk")}), "failed to send chunk")
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err, "expiring token should be refreshed before the stream is started")
+	require.Equal(t, int32(1), users.refreshed.Load(), "token should be refreshed once")
+}
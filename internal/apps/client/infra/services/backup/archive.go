@@ -0,0 +1,160 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"time"
+)
+
+const (
+	Version = 1
+	magic   = "gophkeeper-backup"
+	kdf     = "scrypt"
+	// scrypt parameters of the new archives, old archives keep their own in the header
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+	// limits of the header parameters that keep key derivation memory reasonable
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+	keySize    = 32
+	saltSize   = 16
+)
+
+type (
+	// Archive is the decrypted backup content
+	Archive struct {
+		CreatedAt time.Time `json:"created_at"`
+		Entries   []Entry   `json:"entries"`
+	}
+	// Entry is the entry with the marshaled data, Blob holds the file content of the blob entry
+	Entry struct {
+		Key       string            `json:"key"`
+		Type      core.EntryType    `json:"type"`
+		Meta      map[string]string `json:"meta,omitempty"`
+		Folder    string            `json:"folder,omitempty"`
+		Tags      []string          `json:"tags,omitempty"`
+		Data      []byte            `json:"data"`
+		Blob      []byte            `json:"blob,omitempty"`
+		CreatedAt time.Time         `json:"created_at"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+	// header is stored in plain text after the magic line, the rest of the file is
+	// the gzipped archive JSON encrypted with AES-GCM by the key derived from the passphrase
+	header struct {
+		Version int    `json:"version"`
+		KDF     string `json:"kdf"`
+		N       int    `json:"n"`
+		R       int    `json:"r"`
+		P       int    `json:"p"`
+		Salt    []byte `json:"salt"`
+	}
+)
+
+func Write(w io.Writer, passphrase core.Pass, archive Archive) error {
+	h := header{Version: Version, KDF: kdf, N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, saltSize)}
+	if _, err := rand.Read(h.Salt); err != nil {
+		return fmt.Errorf("backup: failed to generate salt: %w", err)
+	}
+	key, err := h.key(passphrase)
+	if err != nil {
+		return err
+	}
+
+	var payload bytes.Buffer
+	zw := gzip.NewWriter(&payload)
+	if err = json.NewEncoder(zw).Encode(archive); err != nil {
+		return fmt.Errorf("backup: failed to marshal archive: %w", err)
+	}
+	if err = zw.Close(); err != nil {
+		return fmt.Errorf("backup: failed to compress archive: %w", err)
+	}
+	encrypted, err := encrypto.Encrypt(key, payload.Bytes())
+	if err != nil {
+		return fmt.Errorf("backup: failed to encrypt archive: %w", err)
+	}
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("backup: failed to marshal header: %w", err)
+	}
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(magic + "\n")
+	_, _ = bw.Write(headerJSON)
+	_ = bw.WriteByte('\n')
+	_, _ = bw.Write(encrypted)
+	if err = bw.Flush(); err != nil {
+		return fmt.Errorf("backup: failed to write archive: %w", err)
+	}
+	return nil
+}
+
+func Read(r io.Reader, passphrase core.Pass) (archive Archive, err error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	if err != nil || line != magic+"\n" {
+		return archive, fmt.Errorf("backup: %w: not a gophkeeper backup", entities.ErrBackupInvalid)
+	}
+	headerJSON, err := br.ReadBytes('\n')
+	if err != nil {
+		return archive, fmt.Errorf("backup: %w: failed to read header: %w", entities.ErrBackupInvalid, err)
+	}
+	var h header
+	if err = json.Unmarshal(headerJSON, &h); err != nil {
+		return archive, fmt.Errorf("backup: %w: failed to parse header: %w", entities.ErrBackupInvalid, err)
+	}
+	if err = h.validate(); err != nil {
+		return archive, err
+	}
+	encrypted, err := io.ReadAll(br)
+	if err != nil {
+		return archive, fmt.Errorf("backup: failed to read archive: %w", err)
+	}
+
+	key, err := h.key(passphrase)
+	if err != nil {
+		return archive, err
+	}
+	payload, err := encrypto.Decrypt(key, encrypted)
+	if err != nil {
+		return archive, fmt.Errorf("backup: %w: failed to decrypt archive", entities.ErrBackupPassInvalid)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return archive, fmt.Errorf("backup: %w: failed to decompress archive: %w", entities.ErrBackupInvalid, err)
+	}
+	if err = json.NewDecoder(zr).Decode(&archive); err != nil {
+		return archive, fmt.Errorf("backup: %w: failed to parse archive: %w", entities.ErrBackupInvalid, err)
+	}
+	return archive, nil
+}
+
+func (h header) validate() error {
+	switch {
+	case h.Version != Version:
+		return fmt.Errorf("backup: %w: unsupported version %d", entities.ErrBackupInvalid, h.Version)
+	case h.KDF != kdf:
+		return fmt.Errorf("backup: %w: unsupported key derivation %q", entities.ErrBackupInvalid, h.KDF)
+	case h.N <= 1 || h.N > maxScryptN || h.R <= 0 || h.R > maxScryptR || h.P <= 0 || h.P > maxScryptP || len(h.Salt) == 0:
+		return fmt.Errorf("backup: %w: invalid key derivation parameters", entities.ErrBackupInvalid)
+	}
+	return nil
+}
+
+func (h header) key(passphrase core.Pass) ([]byte, error) {
+	key, err := scrypt.Key(passphrase, h.Salt, h.N, h.R, h.P, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w: failed to derive key: %w", entities.ErrBackupInvalid, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,49 @@
+package backup_test
+
+import (
+	"bytes"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/backup"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	archive := backup.Archive{
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Entries: []backup.Entry{
+			{
+				Key:       "db",
+				Type:      core.EntryTypePassword,
+				Meta:      map[string]string{"env": "prod"},
+				Data:      []byte(`{"Login":"admin","Password":"1"}`),
+				CreatedAt: time.Now().UTC().Truncate(time.Second),
+				UpdatedAt: time.Now().UTC().Truncate(time.Second),
+			},
+			{
+				Key:  "file",
+				Type: core.EntryTypeBinary,
+				Data: []byte(`{"id":"ref"}`),
+				Blob: []byte{0, 1, 2},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, backup.Write(&buf, core.Pass("passphrase"), archive), "failed to write archive")
+	require.NotContains(t, buf.String(), "admin", "archive should be encrypted")
+
+	got, err := backup.Read(bytes.NewReader(buf.Bytes()), core.Pass("passphrase"))
+	require.NoError(t, err, "failed to read archive")
+	require.Equal(t, archive, got)
+
+	_, err = backup.Read(bytes.NewReader(buf.Bytes()), core.Pass("invalid"))
+	require.ErrorIs(t, err, entities.ErrBackupPassInvalid)
+	_, err = backup.Read(strings.NewReader("SQLite format 3"), core.Pass("passphrase"))
+	require.ErrorIs(t, err, entities.ErrBackupInvalid)
+	unsupported := strings.Replace(buf.String(), `"version":1`, `"version":2`, 1)
+	_, err = backup.Read(strings.NewReader(unsupported), core.Pass("passphrase"))
+	require.ErrorIs(t, err, entities.ErrBackupInvalid, "unsupported version")
+}
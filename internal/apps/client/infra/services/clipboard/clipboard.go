@@ -0,0 +1,124 @@
+package clipboard
+
+import (
+	"fmt"
+	"github.com/atotto/clipboard"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	_ Backend = System{}
+	_ Backend = (*Memory)(nil)
+)
+
+type (
+	// Backend reads and writes the clipboard text, the system one is replaced with Memory in tests
+	Backend interface {
+		ReadAll() (string, error)
+		WriteAll(text string) error
+	}
+	// System is the OS clipboard
+	System struct{}
+	// Memory is the in-process clipboard
+	Memory struct {
+		mu   sync.Mutex
+		text string
+	}
+	// Clipboard copies the secrets and clears them after the timeout,
+	// the clipboard is left as is when it doesn't hold the copied value anymore
+	Clipboard struct {
+		logger  *zap.Logger
+		backend Backend
+		timeout time.Duration
+		mu      sync.Mutex
+		value   string
+		timer   *time.Timer
+	}
+)
+
+// NewClipboard creates the clipboard, the zero timeout disables clearing
+func NewClipboard(
+	logger *zap.Logger,
+	backend Backend,
+	timeout time.Duration,
+) *Clipboard {
+	return &Clipboard{
+		logger:  logger,
+		backend: backend,
+		timeout: timeout,
+	}
+}
+
+func (c *Clipboard) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Copy writes the value to the clipboard and schedules clearing
+func (c *Clipboard) Copy(value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.backend.WriteAll(value); err != nil {
+		return fmt.Errorf("clipboard: failed to copy: %w", err)
+	}
+	c.value = value
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if c.timeout > 0 {
+		c.timer = time.AfterFunc(c.timeout, func() {
+			if err := c.Clear(); err != nil {
+				c.logger.Error("failed to clear clipboard", zap.Error(err))
+			}
+		})
+	}
+	return nil
+}
+
+// Clear clears the clipboard if it still holds the copied value
+func (c *Clipboard) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.value == "" {
+		return nil
+	}
+	value := c.value
+	c.value = ""
+	current, err := c.backend.ReadAll()
+	if err != nil {
+		return fmt.Errorf("clipboard: failed to read: %w", err)
+	}
+	if current != value {
+		return nil
+	}
+	if err = c.backend.WriteAll(""); err != nil {
+		return fmt.Errorf("clipboard: failed to clear: %w", err)
+	}
+	return nil
+}
+
+func (System) ReadAll() (string, error) {
+	return clipboard.ReadAll()
+}
+
+func (System) WriteAll(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+func (m *Memory) ReadAll() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.text, nil
+}
+
+func (m *Memory) WriteAll(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.text = text
+	return nil
+}
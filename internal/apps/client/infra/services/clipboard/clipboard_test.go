@@ -0,0 +1,47 @@
+package clipboard_test
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/clipboard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"testing"
+	"time"
+)
+
+func TestClipboard_Copy(t *testing.T) {
+	backend := &clipboard.Memory{}
+	c := clipboard.NewClipboard(zaptest.NewLogger(t), backend, 50*time.Millisecond)
+
+	require.NoError(t, c.Copy("secret"))
+	text, err := backend.ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "secret", text)
+	require.Eventually(t, func() bool {
+		text, _ = backend.ReadAll()
+		return text == ""
+	}, time.Second, 10*time.Millisecond, "clipboard should be cleared after timeout")
+}
+
+func TestClipboard_Clear(t *testing.T) {
+	backend := &clipboard.Memory{}
+	c := clipboard.NewClipboard(zaptest.NewLogger(t), backend, time.Hour)
+
+	require.NoError(t, c.Copy("secret"))
+	require.NoError(t, c.Clear())
+	text, _ := backend.ReadAll()
+	require.Empty(t, text, "copied value should be cleared")
+
+	// the value copied by another app is kept
+	require.NoError(t, c.Copy("secret"))
+	require.NoError(t, backend.WriteAll("other"))
+	require.NoError(t, c.Clear())
+	text, _ = backend.ReadAll()
+	require.Equal(t, "other", text, "foreign value should be kept")
+
+	// nothing is scheduled without timeout
+	c = clipboard.NewClipboard(zaptest.NewLogger(t), backend, 0)
+	require.NoError(t, c.Copy("secret"))
+	time.Sleep(20 * time.Millisecond)
+	text, _ = backend.ReadAll()
+	require.Equal(t, "secret", text, "value should be kept without timeout")
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"sort"
+	"strings"
+)
+
+const (
+	bitwardenLogin    = 1
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+
+	bitwardenFieldText    = 0
+	bitwardenFieldBoolean = 2
+)
+
+type (
+	bitwardenExport struct {
+		Encrypted bool `json:"encrypted"`
+		Folders   []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"folders"`
+		Items []bitwardenItem `json:"items"`
+	}
+	bitwardenItem struct {
+		Type     int     `json:"type"`
+		Name     string  `json:"name"`
+		Notes    *string `json:"notes"`
+		FolderID *string `json:"folderId"`
+		Login    *struct {
+			Username *string `json:"username"`
+			Password *string `json:"password"`
+			URIs     []struct {
+				URI *string `json:"uri"`
+			} `json:"uris"`
+		} `json:"login"`
+		Card *struct {
+			CardholderName *string `json:"cardholderName"`
+			Number         *string `json:"number"`
+			ExpMonth       *string `json:"expMonth"`
+			ExpYear        *string `json:"expYear"`
+			Code           *string `json:"code"`
+		} `json:"card"`
+		Identity map[string]*string `json:"identity"`
+		Fields   []struct {
+			Name  *string `json:"name"`
+			Value *string `json:"value"`
+			Type  int     `json:"type"`
+		} `json:"fields"`
+	}
+)
+
+// parseBitwarden parses the unencrypted Bitwarden JSON export,
+// identities are imported as notes, hidden custom fields are skipped
+func parseBitwarden(content []byte) ([]entities.CreateEntryRequest, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("importer: %w: failed to parse Bitwarden JSON: %w", entities.ErrImportContentInvalid, err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("importer: %w: encrypted Bitwarden export is not supported", entities.ErrImportContentInvalid)
+	}
+	folders := make(map[string]string, len(export.Folders))
+	for _, f := range export.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	result := make([]entities.CreateEntryRequest, 0, len(export.Items))
+	for _, item := range export.Items {
+		r := record{
+			title:  item.Name,
+			notes:  str(item.Notes),
+			folder: folders[str(item.FolderID)],
+			fields: make(map[string]string),
+		}
+		for _, f := range item.Fields {
+			if str(f.Name) != "" && (f.Type == bitwardenFieldText || f.Type == bitwardenFieldBoolean) {
+				r.fields[str(f.Name)] = str(f.Value)
+			}
+		}
+
+		switch {
+		case item.Type == bitwardenLogin && item.Login != nil:
+			r.username = str(item.Login.Username)
+			r.password = str(item.Login.Password)
+			for _, u := range item.Login.URIs {
+				r.urls = append(r.urls, str(u.URI))
+			}
+		case item.Type == bitwardenCard && item.Card != nil:
+			result = append(result, entities.CreateEntryRequest{
+				Key:  r.key(),
+				Type: core.EntryTypeCard,
+				Meta: r.meta(true),
+				Data: entities.EntryDataCard{
+					Number:  str(item.Card.Number),
+					Expires: expires(str(item.Card.ExpMonth), str(item.Card.ExpYear)),
+					Cvc:     str(item.Card.Code),
+					Owner:   str(item.Card.CardholderName),
+				},
+			})
+			continue
+		case item.Type == bitwardenIdentity && item.Identity != nil:
+			r.notes = identityNote(item.Identity, r.notes)
+		}
+		if entry, ok := r.entry(); ok {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func identityNote(identity map[string]*string, notes string) string {
+	keys := make([]string, 0, len(identity))
+	for k, v := range identity {
+		if str(v) != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	sb := strings.Builder{}
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteString(": ")
+		sb.WriteString(*identity[k])
+		sb.WriteString("\n")
+	}
+	if notes != "" {
+		sb.WriteString("\n")
+		sb.WriteString(notes)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// expires formats the card expiration date as MM/YY
+func expires(month string, year string) string {
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
+
+func str(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"io"
+	"strings"
+)
+
+const (
+	csvTitle = iota
+	csvUsername
+	csvPassword
+	csvURL
+	csvNotes
+	csvFolder
+	csvTags
+)
+
+// csvColumns are header names used by 1Password, Bitwarden, LastPass and KeePass CSV exports
+var csvColumns = map[string]int{
+	"title":          csvTitle,
+	"name":           csvTitle,
+	"account":        csvTitle,
+	"username":       csvUsername,
+	"user name":      csvUsername,
+	"login":          csvUsername,
+	"login_username": csvUsername,
+	"password":       csvPassword,
+	"login_password": csvPassword,
+	"url":            csvURL,
+	"uri":            csvURL,
+	"website":        csvURL,
+	"web site":       csvURL,
+	"login_uri":      csvURL,
+	"notes":          csvNotes,
+	"note":           csvNotes,
+	"extra":          csvNotes,
+	"comments":       csvNotes,
+	"folder":         csvFolder,
+	"group":          csvFolder,
+	"grouping":       csvFolder,
+	"tags":           csvTags,
+}
+
+// parseCSV parses the CSV with the header row, columns are matched by name case-insensitively,
+// unknown columns are skipped since they could contain secrets like TOTP seeds
+func parseCSV(content []byte) ([]entities.CreateEntryRequest, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("importer: %w: failed to read CSV header: %w", entities.ErrImportContentInvalid, err)
+	}
+	columns := make(map[int]int, len(header))
+	known := make(map[int]bool, len(header))
+	for i, name := range header {
+		if column, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok && !known[column] {
+			columns[i] = column
+			known[column] = true
+		}
+	}
+	if !known[csvTitle] && !known[csvPassword] {
+		return nil, fmt.Errorf("importer: %w: CSV header has neither title nor password column", entities.ErrImportContentInvalid)
+	}
+
+	var result []entities.CreateEntryRequest
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: %w: failed to read CSV row: %w", entities.ErrImportContentInvalid, err)
+		}
+		var r record
+		for i, value := range row {
+			column, ok := columns[i]
+			if !ok {
+				continue
+			}
+			switch column {
+			case csvTitle:
+				r.title = value
+			case csvUsername:
+				r.username = value
+			case csvPassword:
+				r.password = value
+			case csvURL:
+				r.urls = append(r.urls, value)
+			case csvNotes:
+				r.notes = value
+			case csvFolder:
+				r.folder = value
+			case csvTags:
+				r.tags = splitTags(value)
+			}
+		}
+		if entry, ok := r.entry(); ok {
+			result = append(result, entry)
+		}
+	}
+}
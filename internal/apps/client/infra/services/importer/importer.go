@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"strconv"
+	"strings"
+)
+
+const (
+	MetaURL   = "url"
+	MetaNotes = "notes"
+	untitled  = "untitled"
+)
+
+// record is the exported login or note, meta is not encrypted on the server,
+// so only URLs, notes and non-secret custom fields are kept there
+type record struct {
+	title    string
+	username string
+	password string
+	notes    string
+	folder   string
+	tags     []string
+	urls     []string
+	fields   map[string]string
+}
+
+// Parse maps the exported records into entries keyed by the record title,
+// records without any data are skipped
+func Parse(format entities.ImportFormat, content []byte) ([]entities.CreateEntryRequest, error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	switch format {
+	case entities.ImportFormatKeePass:
+		return parseKeePass(content)
+	case entities.ImportFormatBitwarden:
+		return parseBitwarden(content)
+	case entities.ImportFormatCSV:
+		return parseCSV(content)
+	default:
+		return nil, fmt.Errorf("importer: %w: %s", entities.ErrImportFormatInvalid, format)
+	}
+}
+
+func (r record) entry() (entities.CreateEntryRequest, bool) {
+	switch {
+	case r.username != "" || r.password != "":
+		return entities.CreateEntryRequest{
+			Key:    r.key(),
+			Type:   core.EntryTypePassword,
+			Meta:   r.meta(true),
+			Folder: core.NormalizeFolder(r.folder),
+			Tags:   core.NormalizeTags(r.tags),
+			Data:   entities.EntryDataPassword{Login: r.username, Password: r.password},
+		}, true
+	case r.notes != "":
+		return entities.CreateEntryRequest{
+			Key:    r.key(),
+			Type:   core.EntryTypeNote,
+			Meta:   r.meta(false),
+			Folder: core.NormalizeFolder(r.folder),
+			Tags:   core.NormalizeTags(r.tags),
+			Data:   entities.EntryDataNote(r.notes),
+		}, true
+	default:
+		return entities.CreateEntryRequest{}, false
+	}
+}
+
+func (r record) key() string {
+	for _, v := range append([]string{r.title}, r.urls...) {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	if v := strings.TrimSpace(r.username); v != "" {
+		return v
+	}
+	return untitled
+}
+
+func (r record) meta(withNotes bool) map[string]string {
+	meta := make(map[string]string, len(r.fields)+len(r.urls)+1)
+	for k, v := range r.fields {
+		if v != "" {
+			meta[k] = v
+		}
+	}
+	var i int
+	for _, v := range r.urls {
+		if v == "" {
+			continue
+		}
+		if i++; i == 1 {
+			meta[MetaURL] = v
+		} else {
+			meta[MetaURL+strconv.Itoa(i)] = v
+		}
+	}
+	if withNotes && r.notes != "" {
+		meta[MetaNotes] = r.notes
+	}
+	if len(meta) == 0 {
+		return nil
+	}
+	return meta
+}
+
+// splitTags splits the exported tags list, exports separate tags by commas or semicolons
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ';' })
+}
@@ -0,0 +1,165 @@
+package importer_test
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/importer"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  entities.ImportFormat
+		content string
+		want    []entities.CreateEntryRequest
+	}{
+		{
+			name:   "keepass",
+			format: entities.ImportFormatKeePass,
+			content: `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta><RecycleBinUUID>bin</RecycleBinUUID></Meta>
+	<Root>
+		<Group>
+			<UUID>root</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<Tags>web;personal</Tags>
+				<String><Key>Title</Key><Value>mail</Value></String>
+				<String><Key>UserName</Key><Value>user</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">pass</Value></String>
+				<String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+				<String><Key>Notes</Key><Value>personal</Value></String>
+				<String><Key>Recovery</Key><Value ProtectInMemory="True">secret</Value></String>
+				<String><Key>Plan</Key><Value>free</Value></String>
+				<History>
+					<Entry><String><Key>Title</Key><Value>mail_old</Value></String></Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>work</UUID>
+				<Name>Work</Name>
+				<Group>
+					<UUID>servers</UUID>
+					<Name>Servers</Name>
+					<Entry>
+						<String><Key>Title</Key><Value>todo</Value></String>
+						<String><Key>Notes</Key><Value>buy milk</Value></String>
+					</Entry>
+					<Entry>
+						<String><Key>Title</Key><Value>empty</Value></String>
+					</Entry>
+				</Group>
+			</Group>
+			<Group>
+				<UUID>bin</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>deleted</Value></String>
+					<String><Key>Password</Key><Value>deleted</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`,
+			want: []entities.CreateEntryRequest{
+				{
+					Key:  "mail",
+					Type: core.EntryTypePassword,
+					Meta: map[string]string{"url": "https://mail.example.com", "notes": "personal", "Plan": "free"},
+					Tags: []string{"personal", "web"},
+					Data: entities.EntryDataPassword{Login: "user", Password: "pass"},
+				},
+				{
+					Key:    "todo",
+					Type:   core.EntryTypeNote,
+					Folder: "Work/Servers",
+					Data:   entities.EntryDataNote("buy milk"),
+				},
+			},
+		},
+		{
+			name:   "bitwarden",
+			format: entities.ImportFormatBitwarden,
+			content: `{
+	"encrypted": false,
+	"folders": [{"id": "f1", "name": "Work"}],
+	"items": [
+		{
+			"type": 1, "name": "mail", "notes": null, "folderId": "f1",
+			"login": {"username": "user", "password": "pass", "totp": "seed", "uris": [{"uri": "https://a"}, {"uri": "https://b"}]},
+			"fields": [{"name": "plan", "value": "free", "type": 0}, {"name": "pin", "value": "1234", "type": 1}]
+		},
+		{"type": 2, "name": "todo", "notes": "buy milk", "folderId": null, "secureNote": {"type": 0}},
+		{
+			"type": 3, "name": "visa", "notes": null, "folderId": null,
+			"card": {"cardholderName": "John", "brand": "Visa", "number": "4111", "expMonth": "3", "expYear": "2030", "code": "123"}
+		},
+		{"type": 4, "name": "me", "notes": "main", "folderId": null, "identity": {"firstName": "John", "lastName": "Doe", "ssn": null}}
+	]
+}`,
+			want: []entities.CreateEntryRequest{
+				{
+					Key:    "mail",
+					Type:   core.EntryTypePassword,
+					Meta:   map[string]string{"url": "https://a", "url2": "https://b", "plan": "free"},
+					Folder: "Work",
+					Data:   entities.EntryDataPassword{Login: "user", Password: "pass"},
+				},
+				{
+					Key:  "todo",
+					Type: core.EntryTypeNote,
+					Data: entities.EntryDataNote("buy milk"),
+				},
+				{
+					Key:  "visa",
+					Type: core.EntryTypeCard,
+					Data: entities.EntryDataCard{Number: "4111", Expires: "03/30", Cvc: "123", Owner: "John"},
+				},
+				{
+					Key:  "me",
+					Type: core.EntryTypeNote,
+					Data: entities.EntryDataNote("firstName: John\nlastName: Doe\n\nmain"),
+				},
+			},
+		},
+		{
+			name:   "1password csv",
+			format: entities.ImportFormatCSV,
+			content: "\ufeffTitle,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+				"mail,https://mail.example.com,user,pass,otpauth://totp/x,false,false,\"web, personal\",\"multi\nline\"\n" +
+				",https://untitled.example.com,user,pass,,false,false,,\n",
+			want: []entities.CreateEntryRequest{
+				{
+					Key:  "mail",
+					Type: core.EntryTypePassword,
+					Meta: map[string]string{"url": "https://mail.example.com", "notes": "multi\nline"},
+					Tags: []string{"personal", "web"},
+					Data: entities.EntryDataPassword{Login: "user", Password: "pass"},
+				},
+				{
+					Key:  "https://untitled.example.com",
+					Type: core.EntryTypePassword,
+					Meta: map[string]string{"url": "https://untitled.example.com"},
+					Data: entities.EntryDataPassword{Login: "user", Password: "pass"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importer.Parse(tt.format, []byte(tt.content))
+			require.NoError(t, err, "failed to parse")
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := importer.Parse(entities.ImportFormatBitwarden, []byte(`{"encrypted": true, "items": []}`))
+	require.ErrorIs(t, err, entities.ErrImportContentInvalid, "encrypted export isn't supported")
+	_, err = importer.Parse(entities.ImportFormatKeePass, []byte(`<Database/>`))
+	require.ErrorIs(t, err, entities.ErrImportContentInvalid)
+	_, err = importer.Parse("unknown", []byte("title\n"))
+	require.ErrorIs(t, err, entities.ErrImportFormatInvalid)
+}
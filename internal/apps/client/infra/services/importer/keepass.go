@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"strings"
+)
+
+type (
+	keePassFile struct {
+		XMLName xml.Name `xml:"KeePassFile"`
+		Meta    struct {
+			RecycleBinUUID string `xml:"RecycleBinUUID"`
+		} `xml:"Meta"`
+		Root struct {
+			Groups []keePassGroup `xml:"Group"`
+		} `xml:"Root"`
+	}
+	keePassGroup struct {
+		UUID    string         `xml:"UUID"`
+		Name    string         `xml:"Name"`
+		Entries []keePassEntry `xml:"Entry"`
+		Groups  []keePassGroup `xml:"Group"`
+	}
+	keePassEntry struct {
+		Tags    string `xml:"Tags"`
+		Strings []struct {
+			Key   string `xml:"Key"`
+			Value struct {
+				Text      string `xml:",chardata"`
+				Protected bool   `xml:"ProtectInMemory,attr"`
+			} `xml:"Value"`
+		} `xml:"String"`
+	}
+)
+
+// parseKeePass parses the KeePass 2 XML export, the root group is not a part of folders,
+// entries from the recycle bin and entry history are skipped
+func parseKeePass(content []byte) ([]entities.CreateEntryRequest, error) {
+	var file keePassFile
+	if err := xml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("importer: %w: failed to parse KeePass XML: %w", entities.ErrImportContentInvalid, err)
+	}
+
+	var (
+		result []entities.CreateEntryRequest
+		walk   func(g keePassGroup, folder string)
+	)
+	walk = func(g keePassGroup, folder string) {
+		if file.Meta.RecycleBinUUID != "" && g.UUID == file.Meta.RecycleBinUUID {
+			return
+		}
+		for _, e := range g.Entries {
+			if entry, ok := e.record(folder).entry(); ok {
+				result = append(result, entry)
+			}
+		}
+		for _, child := range g.Groups {
+			path := child.Name
+			if folder != "" {
+				path = folder + core.FolderSeparator + child.Name
+			}
+			walk(child, path)
+		}
+	}
+	for _, root := range file.Root.Groups {
+		walk(root, "")
+	}
+	return result, nil
+}
+
+func (e keePassEntry) record(folder string) record {
+	r := record{folder: folder, tags: splitTags(e.Tags), fields: make(map[string]string)}
+	for _, s := range e.Strings {
+		value := s.Value.Text
+		switch s.Key {
+		case "Title":
+			r.title = value
+		case "UserName":
+			r.username = value
+		case "Password":
+			r.password = value
+		case "URL":
+			r.urls = append(r.urls, value)
+		case "Notes":
+			r.notes = value
+		default:
+			if !s.Value.Protected && !strings.HasPrefix(s.Key, "KPEX_") {
+				r.fields[s.Key] = value
+			}
+		}
+	}
+	return r
+}
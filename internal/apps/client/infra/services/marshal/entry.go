@@ -14,18 +14,17 @@ func (EntryMarshaler) Marshal(data entities.EntryData) (result []byte, err error
 		return nil, fmt.Errorf("%w: data empty", entities.ErrEntryDataEmpty)
 	}
 	switch data := data.(type) {
-	case entities.EntryDataPassword:
-		if result, err = json.Marshal(data); err != nil {
-			return nil, fmt.Errorf("entry_marshaler: failed to marshal password data: %w", err)
+	case core.EntryData:
+		if _, ok := core.LookupEntryDataType(data); !ok {
+			return nil, fmt.Errorf("entry_marshaler: unregistered type %T: %w", data, entities.ErrEntryDataTypeInvalid)
+		}
+		if result, err = data.Marshal(); err != nil {
+			return nil, fmt.Errorf("entry_marshaler: failed to marshal %T data: %w", data, err)
 		}
-	case entities.EntryDataNote:
-		result = []byte(data)
-	case entities.EntryDataCard:
+	case entities.EntryDataFields:
 		if result, err = json.Marshal(data); err != nil {
-			return nil, fmt.Errorf("entry_marshaler: failed to marshal card data: %w", err)
+			return nil, fmt.Errorf("entry_marshaler: failed to marshal fields data: %w", err)
 		}
-	case entities.EntryDataBinary:
-		result = data
 	default:
 		return nil, fmt.Errorf("entry_marshaler: unknown type: %w", entities.ErrEntryDataTypeInvalid)
 	}
@@ -35,28 +34,25 @@ func (EntryMarshaler) Marshal(data entities.EntryData) (result []byte, err error
 	return result, nil
 }
 
+// Unmarshal decodes the typed data of built-in types and the field map of other types, see core.EntrySchema
 func (EntryMarshaler) Unmarshal(typ core.EntryType, data []byte) (entities.EntryData, error) {
 	if len(data) == 0 {
 		return nil, fmt.Errorf("entry_marshaler: %w", entities.ErrEntryDataEmpty)
 	}
-	switch typ {
-	case core.EntryTypePassword:
-		var passData entities.EntryDataPassword
-		if err := json.Unmarshal(data, &passData); err != nil {
-			return nil, fmt.Errorf("entry_marshaler: failed to unmarshal password data: %w", err)
-		}
-		return passData, nil
-	case core.EntryTypeNote:
-		return entities.EntryDataNote(data), nil
-	case core.EntryTypeCard:
-		var cardData entities.EntryDataCard
-		if err := json.Unmarshal(data, &cardData); err != nil {
-			return nil, fmt.Errorf("entry_marshaler: failed to unmarshal card data: %w", err)
-		}
-		return cardData, nil
-	case core.EntryTypeBinary:
-		return entities.EntryDataBinary(data), nil
-	default:
+	schema, ok := core.LookupEntrySchema(typ)
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", entities.ErrEntryTypeInvalid, typ)
 	}
+	if schema.Data != nil {
+		result, err := schema.Data.Unmarshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("entry_marshaler: failed to unmarshal %s data: %w", typ, err)
+		}
+		return result, nil
+	}
+	var fields entities.EntryDataFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("entry_marshaler: failed to unmarshal %s data: %w", typ, err)
+	}
+	return fields, nil
 }
@@ -47,6 +47,25 @@ func TestEntryMarshaler(t *testing.T) {
 			typ:  core.EntryTypeBinary,
 			data: entities.EntryDataBinary("binary"),
 		},
+		{
+			name: "totp",
+			typ:  core.EntryTypeTOTP,
+			data: entities.EntryDataTOTP{
+				Secret:    "JBSWY3DPEHPK3PXP",
+				Algorithm: "SHA256",
+				Digits:    8,
+				Period:    60,
+			},
+		},
+		{
+			name: "wifi",
+			typ:  core.EntryTypeWiFi,
+			data: entities.EntryDataFields{
+				"ssid":     "home",
+				"password": "secret",
+				"security": "WPA2",
+			},
+		},
 	}
 
 	sut := EntryMarshaler{}
@@ -149,6 +168,20 @@ func TestEntryMarshaler_Unmarshal(t *testing.T) {
 			data:    []byte(""),
 			wantErr: emptyDataErr,
 		},
+		{
+			name: "invalid: unknown type",
+			typ:  core.EntryType("unknown"),
+			data: []byte("{}"),
+			wantErr: func(t require.TestingT, err error, args ...interface{}) {
+				require.ErrorIs(t, err, entities.ErrEntryTypeInvalid, "want type invalid error")
+			},
+		},
+		{
+			name:    "invalid: fields data",
+			typ:     core.EntryTypeSSHKey,
+			data:    []byte("key"),
+			wantErr: require.Error,
+		},
 	}
 	sut := EntryMarshaler{}
 	for _, tt := range tests {
@@ -0,0 +1,183 @@
+package passgen
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	lowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	upperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+	symbolChars    = "!#$%&*+-=?@^_~"
+	ambiguousChars = "0O1lI|"
+)
+
+var (
+	ErrPolicyInvalid = errors.New("passgen: invalid policy")
+
+	//go:embed words.txt
+	wordsFile string
+	words     = strings.Fields(wordsFile)
+)
+
+type (
+	// Policy describes the random password: its length and the character classes,
+	// every enabled class is present in the generated password at least once
+	Policy struct {
+		Length           int
+		Lower            bool
+		Upper            bool
+		Digits           bool
+		Symbols          bool
+		ExcludeAmbiguous bool // excludes the look-alike chars like 0/O and 1/l/I
+	}
+	// PassphrasePolicy describes the pronounceable passphrase of the embedded list words
+	PassphrasePolicy struct {
+		Words      int
+		Separator  string
+		Capitalize bool // capitalizes the first letter of every word
+		Digit      bool // appends a random digit to the random word
+	}
+)
+
+var (
+	DefaultPolicy = Policy{
+		Length:           20,
+		Lower:            true,
+		Upper:            true,
+		Digits:           true,
+		Symbols:          true,
+		ExcludeAmbiguous: true,
+	}
+	DefaultPassphrasePolicy = PassphrasePolicy{
+		Words:      5,
+		Separator:  "-",
+		Capitalize: true,
+		Digit:      true,
+	}
+)
+
+func (p Policy) Validate() error {
+	classes := p.classes()
+	if len(classes) == 0 {
+		return fmt.Errorf("%w: no character classes", ErrPolicyInvalid)
+	}
+	if p.Length < len(classes) || p.Length > 128 {
+		return fmt.Errorf("%w: length should be between %d and 128", ErrPolicyInvalid, len(classes))
+	}
+	return nil
+}
+
+func (p PassphrasePolicy) Validate() error {
+	if p.Words < 2 || p.Words > 32 {
+		return fmt.Errorf("%w: words count should be between 2 and 32", ErrPolicyInvalid)
+	}
+	return nil
+}
+
+// Generate returns the random password of the policy
+func Generate(policy Policy) (string, error) {
+	if err := policy.Validate(); err != nil {
+		return "", err
+	}
+	classes := policy.classes()
+	all := strings.Join(classes, "")
+	result := make([]byte, 0, policy.Length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, c)
+	}
+	for len(result) < policy.Length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		result = append(result, c)
+	}
+	// the required chars are at the beginning, shuffle them away
+	for i := len(result) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result), nil
+}
+
+// GeneratePassphrase returns the random passphrase of the policy
+func GeneratePassphrase(policy PassphrasePolicy) (string, error) {
+	if err := policy.Validate(); err != nil {
+		return "", err
+	}
+	result := make([]string, policy.Words)
+	for i := range result {
+		idx, err := randomInt(len(words))
+		if err != nil {
+			return "", err
+		}
+		result[i] = words[idx]
+		if policy.Capitalize {
+			result[i] = strings.ToUpper(result[i][:1]) + result[i][1:]
+		}
+	}
+	if policy.Digit {
+		idx, err := randomInt(len(result))
+		if err != nil {
+			return "", err
+		}
+		digit, err := randomChar(digitChars)
+		if err != nil {
+			return "", err
+		}
+		result[idx] += string(digit)
+	}
+	return strings.Join(result, policy.Separator), nil
+}
+
+func (p Policy) classes() []string {
+	var classes []string
+	add := func(enabled bool, chars string) {
+		if !enabled {
+			return
+		}
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+	add(p.Lower, lowerChars)
+	add(p.Upper, upperChars)
+	add(p.Digits, digitChars)
+	add(p.Symbols, symbolChars)
+	return classes
+}
+
+func randomChar(chars string) (byte, error) {
+	idx, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[idx], nil
+}
+
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("passgen: failed to generate random number: %w", err)
+	}
+	return int(v.Int64()), nil
+}
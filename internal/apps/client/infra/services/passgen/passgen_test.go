@@ -0,0 +1,77 @@
+package passgen_test
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/passgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGenerate(t *testing.T) {
+	password, err := passgen.Generate(passgen.DefaultPolicy)
+	require.NoError(t, err)
+	require.Len(t, password, passgen.DefaultPolicy.Length)
+	assert.True(t, strings.ContainsFunc(password, unicode.IsLower), "lower char expected")
+	assert.True(t, strings.ContainsFunc(password, unicode.IsUpper), "upper char expected")
+	assert.True(t, strings.ContainsFunc(password, unicode.IsDigit), "digit expected")
+	assert.True(t, strings.ContainsFunc(password, unicode.IsPunct), "symbol expected")
+	assert.False(t, strings.ContainsAny(password, "0O1lI|"), "ambiguous chars are not expected")
+
+	password, err = passgen.Generate(passgen.Policy{Length: 8, Digits: true})
+	require.NoError(t, err)
+	require.Len(t, password, 8)
+	assert.False(t, strings.ContainsFunc(password, func(r rune) bool { return !unicode.IsDigit(r) }), "only digits expected")
+
+	_, err = passgen.Generate(passgen.Policy{Length: 8})
+	require.ErrorIs(t, err, passgen.ErrPolicyInvalid)
+	_, err = passgen.Generate(passgen.Policy{Length: 1, Lower: true, Upper: true})
+	require.ErrorIs(t, err, passgen.ErrPolicyInvalid)
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	passphrase, err := passgen.GeneratePassphrase(passgen.DefaultPassphrasePolicy)
+	require.NoError(t, err)
+	words := strings.Split(passphrase, passgen.DefaultPassphrasePolicy.Separator)
+	require.Len(t, words, passgen.DefaultPassphrasePolicy.Words)
+	for _, word := range words {
+		assert.True(t, unicode.IsUpper([]rune(word)[0]), "capitalized word expected")
+	}
+	assert.True(t, strings.ContainsFunc(passphrase, unicode.IsDigit), "digit expected")
+
+	_, err = passgen.GeneratePassphrase(passgen.PassphrasePolicy{Words: 1})
+	require.ErrorIs(t, err, passgen.ErrPolicyInvalid)
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		password string
+		score    passgen.Score
+		pattern  string
+	}{
+		{password: "password", score: passgen.ScoreVeryWeak, pattern: passgen.PatternCommon},
+		{password: "P@ssw0rd", score: passgen.ScoreVeryWeak, pattern: passgen.PatternCommon},
+		{password: "abcdefgh", score: passgen.ScoreVeryWeak, pattern: passgen.PatternSequence},
+		{password: "zzzzzzzz", score: passgen.ScoreVeryWeak, pattern: passgen.PatternRepeat},
+		{password: "asdfghjk", score: passgen.ScoreVeryWeak, pattern: passgen.PatternKeyboard},
+		{password: "Tiger1987", score: passgen.ScoreVeryWeak, pattern: passgen.PatternYear},
+		{password: "xK9#mQ2$vL7!pR4&", score: passgen.ScoreVeryStrong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			strength := passgen.Estimate(tt.password)
+			assert.Equal(t, tt.score, strength.Score, "score mismatch, entropy %.1f", strength.Entropy)
+			if tt.pattern != "" {
+				assert.Contains(t, strength.Patterns, tt.pattern)
+			} else {
+				assert.Empty(t, strength.Patterns)
+			}
+		})
+	}
+
+	assert.Equal(t, passgen.Strength{}, passgen.Estimate(""))
+	generated, err := passgen.Generate(passgen.DefaultPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, passgen.ScoreVeryStrong, passgen.Estimate(generated).Score)
+}
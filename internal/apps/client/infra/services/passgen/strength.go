@@ -0,0 +1,334 @@
+package passgen
+
+import (
+	_ "embed"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	ScoreVeryWeak Score = iota
+	ScoreWeak
+	ScoreFair
+	ScoreStrong
+	ScoreVeryStrong
+)
+
+const (
+	PatternCommon     = "common password"
+	PatternDictionary = "dictionary word"
+	PatternSequence   = "sequence"
+	PatternRepeat     = "repeated chars"
+	PatternKeyboard   = "keyboard pattern"
+	PatternYear       = "year"
+)
+
+var (
+	//go:embed common.txt
+	commonFile string
+	commonRank = rank(strings.Fields(commonFile))
+	wordRank   = rank(words)
+
+	keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+	leetChars    = map[rune][]rune{
+		'4': {'a'}, '@': {'a'}, '3': {'e'}, '1': {'i', 'l'}, '!': {'i'},
+		'0': {'o'}, '$': {'s'}, '5': {'s'}, '7': {'t'}, '|': {'l'},
+	}
+)
+
+type (
+	Score int
+	// Strength is the password strength estimation: the password entropy is the sum of
+	// the brute force entropy of the single chars and the entropy of the guessable patterns
+	// like the common passwords, dictionary words, sequences, repeats, keyboard rows and years
+	Strength struct {
+		Entropy  float64 // bits
+		Score    Score
+		Patterns []string
+	}
+	match struct {
+		start   int
+		end     int
+		entropy float64
+		pattern string
+	}
+)
+
+func (s Score) String() string {
+	switch s {
+	case ScoreVeryWeak:
+		return "very weak"
+	case ScoreWeak:
+		return "weak"
+	case ScoreFair:
+		return "fair"
+	case ScoreStrong:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
+
+// Estimate estimates the password strength, the cheapest way to guess the password is
+// the sequence of the pattern matches and brute forced chars with the minimal entropy
+func Estimate(password string) Strength {
+	chars := []rune(password)
+	if len(chars) == 0 {
+		return Strength{}
+	}
+	matches := findMatches(chars)
+	charEntropy := math.Log2(float64(cardinality(chars)))
+
+	// minimal entropy of the password prefix of i chars
+	best := make([]float64, len(chars)+1)
+	prev := make([]*match, len(chars)+1)
+	for i := 1; i <= len(chars); i++ {
+		best[i] = best[i-1] + charEntropy
+		for j := range matches {
+			m := &matches[j]
+			if m.end == i && best[m.start]+m.entropy < best[i] {
+				best[i] = best[m.start] + m.entropy
+				prev[i] = m
+			}
+		}
+	}
+
+	var patterns []string
+	for i := len(chars); i > 0; {
+		m := prev[i]
+		if m == nil {
+			i--
+			continue
+		}
+		if !slices.Contains(patterns, m.pattern) {
+			patterns = append(patterns, m.pattern)
+		}
+		i = m.start
+	}
+	slices.Reverse(patterns)
+	entropy := best[len(chars)]
+	return Strength{
+		Entropy:  entropy,
+		Score:    score(entropy),
+		Patterns: patterns,
+	}
+}
+
+func score(entropy float64) Score {
+	switch {
+	case entropy < 28:
+		return ScoreVeryWeak
+	case entropy < 36:
+		return ScoreWeak
+	case entropy < 60:
+		return ScoreFair
+	case entropy < 80:
+		return ScoreStrong
+	default:
+		return ScoreVeryStrong
+	}
+}
+
+func findMatches(chars []rune) []match {
+	var matches []match
+	matches = append(matches, dictionaryMatches(chars)...)
+	matches = append(matches, sequenceMatches(chars)...)
+	matches = append(matches, repeatMatches(chars)...)
+	matches = append(matches, keyboardMatches(chars)...)
+	matches = append(matches, yearMatches(chars)...)
+	return matches
+}
+
+func dictionaryMatches(chars []rune) []match {
+	var matches []match
+	for i := range chars {
+		for j := i + 3; j <= len(chars); j++ {
+			token := chars[i:j]
+			entropy, pattern, ok := dictionaryEntropy(token)
+			if !ok {
+				continue
+			}
+			if hasUpper(token) {
+				entropy++
+			}
+			matches = append(matches, match{start: i, end: j, entropy: entropy, pattern: pattern})
+		}
+	}
+	return matches
+}
+
+// dictionaryEntropy looks the token up in the dictionaries as is, reversed and with the leet chars replaced
+func dictionaryEntropy(token []rune) (entropy float64, pattern string, ok bool) {
+	lower := []rune(strings.ToLower(string(token)))
+	variants := append([][]rune{lower, reversed(lower)}, unleet(lower)...)
+	entropy = math.Inf(1)
+	for i, v := range variants {
+		extra := 0.0
+		if i > 0 {
+			extra = 1
+		}
+		if r, found := commonRank[string(v)]; found && math.Log2(float64(r))+extra < entropy {
+			entropy, pattern, ok = math.Log2(float64(r))+extra, PatternCommon, true
+		}
+		// the dictionary words are guessed from the whole list
+		if _, found := wordRank[string(v)]; found && math.Log2(float64(len(wordRank)))+extra < entropy {
+			entropy, pattern, ok = math.Log2(float64(len(wordRank)))+extra, PatternDictionary, true
+		}
+	}
+	return entropy, pattern, ok
+}
+
+func unleet(chars []rune) [][]rune {
+	var result [][]rune
+	for _, alt := range []int{0, 1} {
+		replaced := false
+		v := make([]rune, len(chars))
+		for i, c := range chars {
+			v[i] = c
+			if subs, ok := leetChars[c]; ok {
+				v[i] = subs[min(alt, len(subs)-1)]
+				replaced = true
+			}
+		}
+		if replaced {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func sequenceMatches(chars []rune) []match {
+	var matches []match
+	for i := 0; i < len(chars)-2; {
+		delta := chars[i+1] - chars[i]
+		j := i + 1
+		for j < len(chars) && chars[j]-chars[j-1] == delta && (delta == 1 || delta == -1) {
+			j++
+		}
+		if j-i >= 3 {
+			entropy := math.Log2(float64(len(chars[i:j])))
+			switch first := unicode.ToLower(chars[i]); {
+			case first == 'a' || first == '1' || first == 'z' || first == '9':
+				entropy += 1
+			case unicode.IsDigit(first):
+				entropy += math.Log2(10)
+			default:
+				entropy += math.Log2(26)
+			}
+			if delta < 0 {
+				entropy++
+			}
+			matches = append(matches, match{start: i, end: j, entropy: entropy, pattern: PatternSequence})
+			i = j
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+func repeatMatches(chars []rune) []match {
+	var matches []match
+	for i := 0; i < len(chars); {
+		j := i + 1
+		for j < len(chars) && chars[j] == chars[i] {
+			j++
+		}
+		if j-i >= 3 {
+			entropy := math.Log2(float64(cardinality(chars[i:i+1]))) + math.Log2(float64(j-i))
+			matches = append(matches, match{start: i, end: j, entropy: entropy, pattern: PatternRepeat})
+		}
+		i = j
+	}
+	return matches
+}
+
+func keyboardMatches(chars []rune) []match {
+	var matches []match
+	lower := []rune(strings.ToLower(string(chars)))
+	for _, row := range keyboardRows {
+		for _, r := range []string{row, string(reversed([]rune(row)))} {
+			for i := range chars {
+				for j := len(chars); j >= i+4; j-- {
+					if strings.Contains(r, string(lower[i:j])) {
+						// the row, the start key and the length are guessed
+						entropy := math.Log2(float64(len(keyboardRows)*2)) +
+							math.Log2(float64(len(row))) +
+							math.Log2(float64(j-i))
+						matches = append(matches, match{start: i, end: j, entropy: entropy, pattern: PatternKeyboard})
+						break
+					}
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func yearMatches(chars []rune) []match {
+	var matches []match
+	for i := 0; i+4 <= len(chars); i++ {
+		token := string(chars[i : i+4])
+		if (strings.HasPrefix(token, "19") || strings.HasPrefix(token, "20")) &&
+			strings.IndexFunc(token, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			matches = append(matches, match{start: i, end: i + 4, entropy: math.Log2(200), pattern: PatternYear})
+		}
+	}
+	return matches
+}
+
+// cardinality is the size of the alphabet the chars are brute forced from
+func cardinality(chars []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, c := range chars {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		case c < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	result := 0
+	for _, v := range []struct {
+		ok   bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if v.ok {
+			result += v.size
+		}
+	}
+	return result
+}
+
+func hasUpper(chars []rune) bool {
+	for _, c := range chars {
+		if unicode.IsUpper(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func reversed(chars []rune) []rune {
+	result := slices.Clone(chars)
+	slices.Reverse(result)
+	return result
+}
+
+func rank(values []string) map[string]int {
+	result := make(map[string]int, len(values))
+	for i, v := range values {
+		if _, ok := result[v]; !ok {
+			result[v] = i + 1
+		}
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package totp
+
+import (
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/infra/otp"
+	"strings"
+	"time"
+)
+
+// Parse returns the authenticator key of the otpauth://totp URI or the base32 secret
+func Parse(value string) (entities.EntryDataTOTP, error) {
+	value = strings.TrimSpace(value)
+	if !IsURI(value) {
+		data := entities.EntryDataTOTP{Secret: strings.ToUpper(strings.ReplaceAll(value, " ", ""))}
+		return data, Valid(data)
+	}
+	key, err := otp.ParseURI(value)
+	if err != nil {
+		return entities.EntryDataTOTP{}, fmt.Errorf("%w: %w", entities.ErrEntryTOTPInvalid, err)
+	}
+	return entities.EntryDataTOTP{
+		Secret:    key.Secret,
+		Algorithm: key.Algorithm,
+		Digits:    key.Digits,
+		Period:    int(key.Period / time.Second),
+	}, nil
+}
+
+// IsURI reports that the value is the otpauth:// URI rather than the plain secret
+func IsURI(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "otpauth://")
+}
+
+// Valid checks the secret, algorithm, digits and period of the key
+func Valid(data entities.EntryDataTOTP) error {
+	if data.Period < 0 {
+		return fmt.Errorf("%w: invalid period %d", entities.ErrEntryTOTPInvalid, data.Period)
+	}
+	if err := key(data).Valid(); err != nil {
+		return fmt.Errorf("%w: %w", entities.ErrEntryTOTPInvalid, err)
+	}
+	return nil
+}
+
+// Code returns the code of t and the time left until it expires
+func Code(data entities.EntryDataTOTP, t time.Time) (code string, remaining time.Duration, err error) {
+	if err = Valid(data); err != nil {
+		return "", 0, err
+	}
+	k := key(data)
+	if code, err = k.Code(t); err != nil {
+		return "", 0, fmt.Errorf("%w: %w", entities.ErrEntryTOTPInvalid, err)
+	}
+	return code, k.Remaining(t), nil
+}
+
+func key(data entities.EntryDataTOTP) otp.Key {
+	return otp.Key{
+		Secret:    data.Secret,
+		Algorithm: strings.ToUpper(data.Algorithm),
+		Digits:    data.Digits,
+		Period:    time.Duration(data.Period) * time.Second,
+	}
+}
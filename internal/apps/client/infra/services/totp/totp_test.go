@@ -0,0 +1,46 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/totp"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	got, err := totp.Parse("otpauth://totp/ACME:john?secret=jbswy3dpehpk3pxp&algorithm=sha256&digits=8&period=60")
+	require.NoError(t, err)
+	require.Equal(t, entities.EntryDataTOTP{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA256", Digits: 8, Period: 60}, got)
+
+	got, err = totp.Parse(" jbsw y3dp ehpk 3pxp ")
+	require.NoError(t, err)
+	require.Equal(t, entities.EntryDataTOTP{Secret: "JBSWY3DPEHPK3PXP"}, got)
+
+	for _, value := range []string{"", "!", "otpauth://hotp/john?secret=JBSWY3DPEHPK3PXP"} {
+		_, err = totp.Parse(value)
+		require.ErrorIs(t, err, entities.ErrEntryTOTPInvalid, value)
+	}
+}
+
+func TestCode(t *testing.T) {
+	data := entities.EntryDataTOTP{
+		Secret: base32.StdEncoding.EncodeToString([]byte("12345678901234567890")),
+		Digits: 8,
+	}
+	code, remaining, err := totp.Code(data, time.Unix(59, 0))
+	require.NoError(t, err)
+	require.Equal(t, "94287082", code)
+	require.Equal(t, time.Second, remaining)
+
+	data.Period = 60
+	_, remaining, err = totp.Code(data, time.Unix(59, 0))
+	require.NoError(t, err)
+	require.Equal(t, time.Second, remaining)
+
+	_, _, err = totp.Code(entities.EntryDataTOTP{Secret: data.Secret, Algorithm: "MD5"}, time.Now())
+	require.ErrorIs(t, err, entities.ErrEntryTOTPInvalid)
+	_, _, err = totp.Code(entities.EntryDataTOTP{Secret: data.Secret, Period: -1}, time.Now())
+	require.ErrorIs(t, err, entities.ErrEntryTOTPInvalid)
+}
@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"sync"
+)
+
+type (
+	// Keyring holds the vault key derived from the master password and the account vault salt
+	Keyring struct {
+		mu        sync.RWMutex
+		hasher    Hasher
+		pass      core.Pass
+		encrypter *encrypto.Encrypter
+	}
+	Hasher interface {
+		Hash(pass core.Pass, salt core.Salt) (core.PassHash, error)
+	}
+)
+
+func NewKeyring(hasher Hasher, pass core.Pass) *Keyring {
+	return &Keyring{
+		hasher: hasher,
+		pass:   pass,
+	}
+}
+
+func (k *Keyring) Unlock(salt core.Salt) error {
+	if len(salt) == 0 {
+		return fmt.Errorf("keyring: %w", entities.ErrUserVaultSaltInvalid)
+	}
+	key, err := k.hasher.Hash(k.pass, salt)
+	if err != nil {
+		return fmt.Errorf("keyring: failed to derive vault key: %w", err)
+	}
+	encrypter, err := encrypto.NewEncrypter(key)
+	if err != nil {
+		return fmt.Errorf("keyring: failed to create encrypter: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.encrypter = encrypter
+	return nil
+}
+
+// Restore unlocks the keyring with the vault key kept after the master password change
+func (k *Keyring) Restore(key []byte) error {
+	encrypter, err := encrypto.NewEncrypter(key)
+	if err != nil {
+		return fmt.Errorf("keyring: failed to create encrypter: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.encrypter = encrypter
+	return nil
+}
+
+// Key returns the current vault key
+func (k *Keyring) Key() ([]byte, error) {
+	encrypter, err := k.get()
+	if err != nil {
+		return nil, err
+	}
+	return encrypter.Key, nil
+}
+
+func (k *Keyring) Encrypt(data []byte) ([]byte, error) {
+	encrypter, err := k.get()
+	if err != nil {
+		return nil, err
+	}
+	return encrypter.Encrypt(data)
+}
+
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	encrypter, err := k.get()
+	if err != nil {
+		return nil, err
+	}
+	return encrypter.Decrypt(data)
+}
+
+func (k *Keyring) get() (*encrypto.Encrypter, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.encrypter == nil {
+		return nil, fmt.Errorf("keyring: %w", entities.ErrUserVaultLocked)
+	}
+	return k.encrypter, nil
+}
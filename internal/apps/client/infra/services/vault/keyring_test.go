@@ -0,0 +1,58 @@
+package vault_test
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/pass"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/vault"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	data := []byte("secret")
+	salt := core.Salt("1234567890123456")
+
+	locked := vault.NewKeyring(pass.Hasher{}, core.Pass("master"))
+	_, err := locked.Encrypt(data)
+	require.ErrorIs(t, err, entities.ErrUserVaultLocked, "locked keyring should not encrypt")
+	require.ErrorIs(t, locked.Unlock(nil), entities.ErrUserVaultSaltInvalid, "empty salt should be rejected")
+
+	device1 := vault.NewKeyring(pass.Hasher{}, core.Pass("master"))
+	require.NoError(t, device1.Unlock(salt))
+	device2 := vault.NewKeyring(pass.Hasher{}, core.Pass("master"))
+	require.NoError(t, device2.Unlock(salt))
+
+	encrypted, err := device1.Encrypt(data)
+	require.NoError(t, err)
+	require.NotEqual(t, data, encrypted, "data should be encrypted")
+	decrypted, err := device2.Decrypt(encrypted)
+	require.NoError(t, err, "same master password and salt should derive the same key")
+	require.Equal(t, data, decrypted)
+
+	other := vault.NewKeyring(pass.Hasher{}, core.Pass("other"))
+	require.NoError(t, other.Unlock(salt))
+	_, err = other.Decrypt(encrypted)
+	require.Error(t, err, "other master password should not decrypt data")
+}
+
+func TestKeyring_Restore(t *testing.T) {
+	data := []byte("secret")
+	salt := core.Salt("1234567890123456")
+
+	keyring := vault.NewKeyring(pass.Hasher{}, core.Pass("master"))
+	_, err := keyring.Key()
+	require.ErrorIs(t, err, entities.ErrUserVaultLocked, "locked keyring has no key")
+	require.NoError(t, keyring.Unlock(salt))
+	key, err := keyring.Key()
+	require.NoError(t, err)
+	encrypted, err := keyring.Encrypt(data)
+	require.NoError(t, err)
+
+	restored := vault.NewKeyring(pass.Hasher{}, core.Pass("changed"))
+	require.Error(t, restored.Restore([]byte("short")), "invalid key should be rejected")
+	require.NoError(t, restored.Restore(key))
+	decrypted, err := restored.Decrypt(encrypted)
+	require.NoError(t, err, "restored key should decrypt data of the previous master password")
+	require.Equal(t, data, decrypted)
+}
@@ -0,0 +1,18 @@
+create table if not exists entries_conflicts
+(
+    id             text primary key,
+    key            text not null,
+    local_type     text not null,
+    local_meta     text,
+    local_data     blob not null,
+    local_version  int8 not null,
+    remote_type    text not null,
+    remote_meta    text,
+    remote_data    blob not null,
+    remote_version int8 not null,
+    base_type      text,
+    base_meta      text,
+    base_data      blob,
+    base_version   int8,
+    created_at     text not null
+);
@@ -0,0 +1,9 @@
+alter table entries add column folder text not null default '';
+alter table entries add column tags text;
+
+alter table entries_conflicts add column local_folder text not null default '';
+alter table entries_conflicts add column local_tags text;
+alter table entries_conflicts add column remote_folder text not null default '';
+alter table entries_conflicts add column remote_tags text;
+alter table entries_conflicts add column base_folder text;
+alter table entries_conflicts add column base_tags text;
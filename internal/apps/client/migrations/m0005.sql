@@ -0,0 +1,3 @@
+alter table entries_sync add column request_id text not null default '';
+
+update entries_sync set request_id = lower(hex(randomblob(16))) where request_id = '';
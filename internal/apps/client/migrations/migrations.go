@@ -11,6 +11,9 @@ var fs embed.FS
 var files = []file{
 	{Name: "m0001.sql", Title: "M0001: User-preferences table", NoTx: false},
 	{Name: "m0002.sql", Title: "M0002: Entries table", NoTx: false},
+	{Name: "m0003.sql", Title: "M0003: Entries conflicts table", NoTx: false},
+	{Name: "m0004.sql", Title: "M0004: Entries folders and tags", NoTx: false},
+	{Name: "m0005.sql", Title: "M0005: Entries sync request IDs", NoTx: false},
 }
 
 type file struct {
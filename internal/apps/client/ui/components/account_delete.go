@@ -0,0 +1,203 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*AccountDelete)(nil)
+
+type (
+	AccountDelete struct {
+		title     string
+		logger    *zap.Logger
+		back      base.Component
+		accountUC AccountUC
+		input     textinput.Model
+		// code is asked after ErrUserOTPRequired, the typed keys go to it then
+		code      textinput.Model
+		codeAsked bool
+		syncing   bool
+	}
+	accountDeleteMsg struct {
+		err error
+	}
+)
+
+// NewAccountDelete deletes the server account with all synced entries, the password is asked to confirm
+func NewAccountDelete(
+	title string,
+	logger *zap.Logger,
+	accountUC AccountUC,
+) *AccountDelete {
+	c := &AccountDelete{
+		title:     title,
+		logger:    logger,
+		accountUC: accountUC,
+	}
+	c.input = c.passwordInput()
+	c.code = c.codeInput()
+	return c
+}
+
+func (c *AccountDelete) Title() string {
+	return c.title
+}
+
+func (c *AccountDelete) Init() (result base.InitResult) {
+	c.syncing = false
+	c.codeAsked = false
+	c.input.SetValue("")
+	c.code.SetValue("")
+	c.code.Blur()
+	return result.AppendCmd(c.input.Focus())
+}
+
+func (c *AccountDelete) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case accountDeleteMsg:
+		c.syncing = false
+		if errors.Is(msg.err, entities.ErrUserOTPRequired) {
+			c.codeAsked = true
+			c.input.Blur()
+			result.Status = "enter two-factor code or recovery code 🔐"
+			return result.AppendCmd(c.code.Focus())
+		}
+		if msg.err != nil {
+			result.Status = c.errStatus(msg.err)
+			return result
+		}
+		result.Status = "account deleted, local entries are kept 👋"
+		result.Prev = c.back
+		return result
+	case tea.KeyMsg:
+		return c.updateKeyMsg(msg, result)
+	}
+	cmds := make([]tea.Cmd, 2)
+	c.input, cmds[0] = c.input.Update(msg)
+	c.code, cmds[1] = c.code.Update(msg)
+	return result.AppendCmd(cmds...)
+}
+
+func (c *AccountDelete) View() string {
+	sb := strings.Builder{}
+	sb.WriteString("The server account and all synced entries will be deleted permanently.\n")
+	sb.WriteString("Local entries are kept on this device.\n\n")
+	sb.WriteString(c.input.View())
+	if c.codeAsked {
+		sb.WriteRune('\n')
+		sb.WriteString(c.code.View())
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(styles.SubtleStyle.Render("enter: delete account"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	return sb.String()
+}
+
+func (c *AccountDelete) SetPrev(back base.Component) {
+	c.back = back
+}
+
+func (c *AccountDelete) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "enter":
+		password := c.input.Value()
+		if password == "" {
+			result.Status = "enter the account password to confirm 🤔"
+			return result
+		}
+		code := c.code.Value()
+		if c.codeAsked && code == "" {
+			result.Status = "enter two-factor code or recovery code 🔐"
+			return result
+		}
+		c.syncing = true
+		result.Status = "deleting account..."
+		return result.AppendCmd(c.deleteCmd(entities.DeleteAccountRequest{Password: password, OTP: code}))
+	}
+	var cmd tea.Cmd
+	if c.codeAsked {
+		c.code, cmd = c.code.Update(msg)
+		return result.AppendCmd(cmd)
+	}
+	c.input, cmd = c.input.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *AccountDelete) deleteCmd(request entities.DeleteAccountRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.accountUC.DeleteAccount(ctx, request)
+		if err != nil {
+			c.logger.Error("failed to delete account", zap.Error(err))
+		}
+		return accountDeleteMsg{err: err}
+	}
+}
+
+func (c *AccountDelete) passwordInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Password"
+	ti.CharLimit = 32
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.PromptStyle = styles.FocusedStyle
+	ti.TextStyle = styles.FocusedStyle
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
+func (c *AccountDelete) codeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Two-factor code"
+	ti.CharLimit = 32
+	ti.PromptStyle = styles.FocusedStyle
+	ti.TextStyle = styles.FocusedStyle
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
+func (c *AccountDelete) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound):
+		return "account could be deleted after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrUserCredsInvalid):
+		if c.codeAsked {
+			return "password or two-factor code is invalid 🤨"
+		}
+		return "password is invalid 🤨"
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return "too many two-factor attempts, try again later ⏳"
+	default:
+		return "can't delete account 🤨: internal server error 💀"
+	}
+}
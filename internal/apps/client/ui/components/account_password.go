@@ -0,0 +1,257 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*AccountPassword)(nil)
+
+// accountPasswordInputsCount is the number of password inputs, the two-factor code input follows them
+const accountPasswordInputsCount = 3
+
+type (
+	AccountPassword struct {
+		title      string
+		logger     *zap.Logger
+		back       base.Component
+		accountUC  AccountUC
+		inputs     []textinput.Model
+		focusIndex int
+		syncing    bool
+	}
+	AccountUC interface {
+		ChangePassword(ctx context.Context, request entities.ChangePasswordRequest) error
+		DeleteAccount(ctx context.Context, request entities.DeleteAccountRequest) error
+	}
+	accountPasswordMsg struct {
+		err error
+	}
+)
+
+// NewAccountPassword changes the server account password, other devices are signed out
+func NewAccountPassword(
+	title string,
+	logger *zap.Logger,
+	accountUC AccountUC,
+) *AccountPassword {
+	c := &AccountPassword{
+		title:     title,
+		logger:    logger,
+		accountUC: accountUC,
+	}
+	c.inputs = []textinput.Model{
+		c.passwordInput("Current password"),
+		c.passwordInput("New password"),
+		c.passwordInput("Repeat new password"),
+	}
+	return c
+}
+
+func (c *AccountPassword) Title() string {
+	return c.title
+}
+
+func (c *AccountPassword) Init() (result base.InitResult) {
+	c.syncing = false
+	c.inputs = c.inputs[:accountPasswordInputsCount]
+	for i := range c.inputs {
+		c.inputs[i].SetValue("")
+	}
+	return result.AppendCmd(c.setFocus(0))
+}
+
+func (c *AccountPassword) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case accountPasswordMsg:
+		return c.updateAccountPasswordMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil || result.Prev != nil {
+			return result
+		}
+		if c.syncing {
+			return result
+		}
+	}
+	cmds := make([]tea.Cmd, len(c.inputs))
+	for i := range c.inputs {
+		c.inputs[i], cmds[i] = c.inputs[i].Update(msg)
+	}
+	return result.AppendCmd(cmds...)
+}
+
+func (c *AccountPassword) View() string {
+	sb := strings.Builder{}
+	sb.WriteString("Other devices will be signed out.\n\n")
+	for i := range c.inputs {
+		sb.WriteString(c.inputs[i].View())
+		sb.WriteRune('\n')
+	}
+	sb.WriteByte('\n')
+	if c.focusIndex == len(c.inputs) {
+		sb.WriteString(styles.FocusedButton)
+	} else {
+		sb.WriteString(styles.BlurredButton)
+	}
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("tab: next"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	return sb.String()
+}
+
+func (c *AccountPassword) SetPrev(back base.Component) {
+	c.back = back
+}
+
+func (c *AccountPassword) updateAccountPasswordMsg(
+	msg accountPasswordMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	// two-factor authentication is enabled, the code is asked and sent with the same passwords
+	if errors.Is(msg.err, entities.ErrUserOTPRequired) {
+		if len(c.inputs) == accountPasswordInputsCount {
+			c.inputs = append(c.inputs, c.codeInput())
+		}
+		result.Status = "enter two-factor code or recovery code 🔐"
+		return result.AppendCmd(c.setFocus(len(c.inputs) - 1))
+	}
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	result.Status = "password changed, other devices are signed out 🔐"
+	result.Prev = c.back
+	return result
+}
+
+func (c *AccountPassword) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	k := msg.String()
+	switch k {
+	case "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "tab", "shift+tab", "up", "down", "enter":
+		if k == "enter" && c.focusIndex == len(c.inputs) {
+			if c.inputs[1].Value() != c.inputs[2].Value() {
+				result.Status = "new passwords don't match 🤨"
+				return result
+			}
+			request := entities.ChangePasswordRequest{
+				Old: c.inputs[0].Value(),
+				New: c.inputs[1].Value(),
+			}
+			if len(c.inputs) > accountPasswordInputsCount {
+				request.OTP = c.inputs[accountPasswordInputsCount].Value()
+			}
+			c.syncing = true
+			result.Status = "changing password..."
+			return result.AppendCmd(c.changeCmd(request))
+		}
+
+		focusIndex := c.focusIndex
+		if k == "up" || k == "shift+tab" {
+			focusIndex--
+		} else {
+			focusIndex++
+		}
+		if focusIndex > len(c.inputs) {
+			focusIndex = 0
+		} else if focusIndex < 0 {
+			focusIndex = len(c.inputs)
+		}
+		return result.AppendCmd(c.setFocus(focusIndex))
+	}
+	return result
+}
+
+func (c *AccountPassword) setFocus(focusIndex int) tea.Cmd {
+	c.focusIndex = focusIndex
+	cmds := make([]tea.Cmd, len(c.inputs))
+	for i := range c.inputs {
+		if i == c.focusIndex {
+			cmds[i] = c.inputs[i].Focus()
+			c.inputs[i].PromptStyle = styles.FocusedStyle
+			c.inputs[i].TextStyle = styles.FocusedStyle
+			continue
+		}
+		c.inputs[i].Blur()
+		c.inputs[i].PromptStyle = styles.NoStyle
+		c.inputs[i].TextStyle = styles.NoStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+func (c *AccountPassword) changeCmd(request entities.ChangePasswordRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.accountUC.ChangePassword(ctx, request)
+		if err != nil {
+			c.logger.Error("failed to change password", zap.Error(err))
+		}
+		return accountPasswordMsg{err: err}
+	}
+}
+
+func (c *AccountPassword) passwordInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 32
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
+func (c *AccountPassword) codeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Two-factor code"
+	ti.CharLimit = 32
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
+func (c *AccountPassword) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound):
+		return "password could be changed after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrUserCredsInvalid):
+		if len(c.inputs) > accountPasswordInputsCount {
+			return "current password or two-factor code is invalid 🤨"
+		}
+		return "current password is invalid 🤨"
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return "too many two-factor attempts, try again later ⏳"
+	case errors.Is(err, entities.ErrUserPasswordInvalid):
+		return "new password should differ from the current one 🤔"
+	default:
+		return "can't change password 🤨: internal server error 💀"
+	}
+}
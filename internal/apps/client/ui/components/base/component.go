@@ -28,6 +28,8 @@ type (
 	UpdateStatusMsg struct {
 		Status string
 	}
+	// EntriesChangedMsg is sent when entries are synced in background
+	EntriesChangedMsg struct{}
 )
 
 func (r UpdateResult) AppendCmd(cmds ...tea.Cmd) UpdateResult {
@@ -56,3 +58,10 @@ func UpdateStatusCmd(status string) tea.Cmd {
 		return UpdateStatusMsg{Status: status}
 	}
 }
+
+func WaitEntriesChangedCmd(changes <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-changes
+		return EntriesChangedMsg{}
+	}
+}
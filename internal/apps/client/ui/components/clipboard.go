@@ -0,0 +1,79 @@
+package components
+
+import (
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"go.uber.org/zap"
+	"time"
+)
+
+type (
+	Clipboard interface {
+		Copy(value string) error
+		Timeout() time.Duration
+	}
+	// clip is the copied entry field, the first clip is the secret and the second one is its pair,
+	// e.g. the password and the login
+	clip struct {
+		name  string
+		value func() string
+	}
+)
+
+// entryClips returns the copied fields of the saved entry
+func entryClips(entry entities.GetEntryResponse) []clip {
+	switch data := entry.Data.(type) {
+	case entities.EntryDataPassword:
+		return []clip{
+			{name: "password", value: func() string { return data.Password }},
+			{name: "login", value: func() string { return data.Login }},
+		}
+	case entities.EntryDataCard:
+		return []clip{
+			{name: "number", value: func() string { return data.Number }},
+			{name: "cvc", value: func() string { return data.Cvc }},
+		}
+	case entities.EntryDataTOTP:
+		return []clip{
+			{name: "code", value: func() string { return totpCode(data) }},
+		}
+	case entities.EntryDataFields:
+		schema, ok := core.LookupEntrySchema(entry.Type)
+		if !ok {
+			return nil
+		}
+		return fieldsClips(schema, func() entities.EntryDataFields { return data })
+	default:
+		return nil
+	}
+}
+
+func copyClip(
+	logger *zap.Logger,
+	clipboard Clipboard,
+	clips []clip,
+	idx int,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if idx >= len(clips) {
+		return result
+	}
+	c := clips[idx]
+	value := c.value()
+	if value == "" {
+		result.Status = c.name + " is empty 🤨"
+		return result
+	}
+	if err := clipboard.Copy(value); err != nil {
+		logger.Error("failed to copy", zap.Error(err))
+		result.Status = "can't copy " + c.name + " 🤨"
+		return result
+	}
+	result.Status = c.name + " copied 📋"
+	if timeout := clipboard.Timeout(); timeout > 0 {
+		result.Status = fmt.Sprintf("%s copied, clears in %s 📋", c.name, timeout)
+	}
+	return result
+}
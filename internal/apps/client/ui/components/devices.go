@@ -0,0 +1,260 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*Devices)(nil)
+
+type (
+	Devices struct {
+		title     string
+		logger    *zap.Logger
+		back      base.Component
+		table     table.Model
+		sessionUC SessionUC
+		sessions  []entities.Session
+		syncing   bool
+	}
+	SessionUC interface {
+		GetAll(ctx context.Context) (entities.GetSessionsResponse, error)
+		Revoke(ctx context.Context, request entities.RevokeSessionRequest) error
+		RevokeOthers(ctx context.Context) (entities.RevokeOtherSessionsResponse, error)
+	}
+	sessionsMsg struct {
+		sessions []entities.Session
+		err      error
+	}
+	revokeMsg struct {
+		status string
+		err    error
+	}
+)
+
+// NewDevices shows devices signed in to the server account, any of them could be signed out
+func NewDevices(
+	title string,
+	logger *zap.Logger,
+	sessionUC SessionUC,
+) *Devices {
+	c := &Devices{
+		title:     title,
+		logger:    logger,
+		sessionUC: sessionUC,
+	}
+	c.table = c.newTable()
+	return c
+}
+
+func (c *Devices) Title() string {
+	return c.title
+}
+
+func (c *Devices) Init() (result base.InitResult) {
+	c.table.SetRows(nil)
+	c.syncing = true
+	result.Status = "loading devices..."
+	return result.AppendCmd(c.sessionsCmd())
+}
+
+func (c *Devices) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case sessionsMsg:
+		return c.updateSessionsMsg(msg, result)
+	case revokeMsg:
+		return c.updateRevokeMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
+			return result
+		}
+	}
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *Devices) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(c.table.View())
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("d: sign out device"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("o: sign out other devices"))
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *Devices) SetPrev(back base.Component) {
+	c.back = back
+}
+
+func (c *Devices) newTable() table.Model {
+	columns := []table.Column{
+		{Title: "Device", Width: 30},
+		{Title: "IP", Width: 15},
+		{Title: "Last seen", Width: 20},
+		{Title: "Signed in", Width: 20},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(7),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+	return t
+}
+
+func (c *Devices) updateSessionsMsg(
+	msg sessionsMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	c.sessions = msg.sessions
+	rows := make([]table.Row, len(c.sessions))
+	for i, session := range c.sessions {
+		device := session.Device
+		if session.Current {
+			device = "★ " + device
+		}
+		rows[i] = table.Row{
+			device,
+			session.IP,
+			session.LastSeenAt.Local().Format(time.DateTime),
+			session.CreatedAt.Local().Format(time.DateTime),
+		}
+	}
+	c.table.SetRows(rows)
+	c.table.SetCursor(0)
+	result.Status = "devices loaded, ★ marks this device"
+	return result
+}
+
+func (c *Devices) updateRevokeMsg(
+	msg revokeMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	reload := c.Init()
+	result.Status = msg.status
+	return result.AppendCmd(reload.Cmd)
+}
+
+func (c *Devices) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "d":
+		idx := c.table.Cursor()
+		if idx < 0 || idx >= len(c.sessions) {
+			return result
+		}
+		if c.sessions[idx].Current {
+			result.Status = "this device can be signed out from another device only 🤷"
+			return result
+		}
+		c.syncing = true
+		result.Status = "signing out device..."
+		return result.AppendCmd(c.revokeCmd(c.sessions[idx].ID))
+	case "o":
+		c.syncing = true
+		result.Status = "signing out other devices..."
+		return result.AppendCmd(c.revokeOthersCmd())
+	}
+	return result
+}
+
+func (c *Devices) sessionsCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.sessionUC.GetAll(ctx)
+		if err != nil {
+			c.logger.Error("failed to get sessions", zap.Error(err))
+			return sessionsMsg{err: err}
+		}
+		return sessionsMsg{sessions: resp.Sessions}
+	}
+}
+
+func (c *Devices) revokeCmd(id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.sessionUC.Revoke(ctx, entities.RevokeSessionRequest{ID: id})
+		if err != nil {
+			c.logger.Error("failed to revoke session", zap.Error(err))
+		}
+		return revokeMsg{status: "device signed out 🔥", err: err}
+	}
+}
+
+func (c *Devices) revokeOthersCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.sessionUC.RevokeOthers(ctx)
+		if err != nil {
+			c.logger.Error("failed to revoke other sessions", zap.Error(err))
+		}
+		return revokeMsg{status: fmt.Sprintf("%d device(s) signed out 🔥", resp.Revoked), err: err}
+	}
+}
+
+func (c *Devices) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound):
+		return "devices are available after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrSessionNotFound):
+		return "device is already signed out 🤷"
+	default:
+		return "can't load devices 🤨: internal server error 💀"
+	}
+}
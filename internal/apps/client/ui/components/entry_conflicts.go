@@ -0,0 +1,199 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*EntryConflicts)(nil)
+
+type (
+	EntryConflicts struct {
+		title     string
+		logger    *zap.Logger
+		back      base.Component
+		table     table.Model
+		entryUC   EntryConflictUC
+		conflicts []entities.GetEntryConflictResponse
+		loading   bool
+	}
+	EntryConflictUC interface {
+		GetConflicts(ctx context.Context) (entities.GetEntryConflictsResponse, error)
+		ResolveConflict(ctx context.Context, request entities.ResolveEntryConflictRequest) error
+	}
+	conflictsMsg struct {
+		conflicts []entities.GetEntryConflictResponse
+		err       error
+	}
+)
+
+// NewEntryConflicts shows entries rejected by the server, back is also shown after the conflict is resolved
+func NewEntryConflicts(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryConflictUC,
+) *EntryConflicts {
+	c := &EntryConflicts{
+		title:   title,
+		logger:  logger,
+		back:    back,
+		entryUC: entryUC,
+	}
+	c.table = c.newTable()
+	return c
+}
+
+func (c *EntryConflicts) Title() string {
+	return c.title
+}
+
+func (c *EntryConflicts) Init() (result base.InitResult) {
+	c.table.SetRows(nil)
+	c.loading = true
+	result.Status = "loading conflicts..."
+	return result.AppendCmd(c.conflictsCmd())
+}
+
+func (c *EntryConflicts) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case conflictsMsg:
+		return c.updateConflictsMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
+			return result
+		}
+	}
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *EntryConflicts) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(c.table.View())
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("enter: resolve"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *EntryConflicts) newTable() table.Model {
+	columns := []table.Column{
+		{Title: "Key", Width: 15},
+		{Title: "Type", Width: 10},
+		{Title: "Version", Width: 8},
+		{Title: "Detected", Width: 25},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(7),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+	return t
+}
+
+func (c *EntryConflicts) updateConflictsMsg(
+	msg conflictsMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.loading = false
+	if msg.err != nil {
+		result.Status = "can't load conflicts 🤨: internal error 💀"
+		return result
+	}
+	c.conflicts = msg.conflicts
+	rows := make([]table.Row, len(c.conflicts))
+	for i, conflict := range c.conflicts {
+		rows[i] = table.Row{
+			conflict.Key,
+			string(conflict.Remote.Type),
+			strconv.FormatInt(conflict.Remote.Version, 10),
+			conflict.CreatedAt.Local().Format(time.DateTime),
+		}
+	}
+	c.table.SetRows(rows)
+	c.table.SetCursor(0)
+	result.Status = "conflicts loaded"
+	if len(c.conflicts) == 0 {
+		result.Status = "no conflicts 🦾"
+	}
+	return result
+}
+
+func (c *EntryConflicts) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.loading {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "enter":
+		idx := c.table.Cursor()
+		if idx < 0 || idx >= len(c.conflicts) {
+			return result
+		}
+		result.Next = NewEntryMerge(c.title, c.logger, c, c.back, c.entryUC, c.conflicts[idx])
+		return result
+	}
+	return result
+}
+
+func (c *EntryConflicts) conflictsCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.entryUC.GetConflicts(ctx)
+		if err != nil {
+			c.logger.Error("failed to get entry conflicts", zap.Error(err))
+			return conflictsMsg{err: err}
+		}
+		return conflictsMsg{conflicts: resp.Conflicts}
+	}
+}
+
+func conflictErrStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrEntryConflictNotFound):
+		return "conflict is already resolved 🤷"
+	case errors.Is(err, entities.ErrEntryExists):
+		return "entry key is taken, rename the entry first 🤷"
+	case errors.Is(err, entities.ErrEntryConflictInvalid):
+		return "entry versions can't be merged 🤷"
+	default:
+		return "can't resolve conflict 🤨: internal error 💀"
+	}
+}
@@ -26,14 +26,18 @@ type (
 		syncing    bool
 
 		inputs    []input.Input
+		password  input.Input // the generated password input, nil for entries without passwords
 		entryUC   EntryCreateUC
 		entryType core.EntryType
 		validator func([]input.Input) error
 		requester func([]input.Input) (entities.CreateEntryRequest, error)
+		creator   func(context.Context, []input.Input) (entities.CreateEntryResponse, error)
+		timeout   time.Duration
 		logger    *zap.Logger
 	}
 	EntryCreateUC interface {
 		Create(ctx context.Context, request entities.CreateEntryRequest) (entities.CreateEntryResponse, error)
+		CreateBlob(ctx context.Context, request entities.CreateBlobEntryRequest) (entities.CreateEntryResponse, error)
 	}
 	entryCreateMsg struct {
 		id      uuid.UUID
@@ -54,13 +58,17 @@ func NewEntryCreatePassword(
 		loginIndex
 		passwordIndex
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[keyIndex] = input.NewText("key", 32)
 	inputs[loginIndex] = input.NewText("login", 32)
-	inputs[passwordIndex] = input.NewText("password", 32)
+	inputs[passwordIndex] = input.NewText("password", 64)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryCreate{
 		title:      fmt.Sprintf("%s/%s", title, typ),
 		logger:     logger,
@@ -70,6 +78,7 @@ func NewEntryCreatePassword(
 		entryType: typ,
 		entryUC:   entryUC,
 		inputs:    inputs,
+		password:  inputs[passwordIndex],
 		validator: func(inputs []input.Input) error {
 			if inputs[keyIndex].Value() == "" {
 				return errors.New("key should not be empty")
@@ -84,9 +93,11 @@ func NewEntryCreatePassword(
 		},
 		requester: func(inputs []input.Input) (entities.CreateEntryRequest, error) {
 			return entities.CreateEntryRequest{
-				Key:  inputs[keyIndex].Value(),
-				Type: typ,
-				Meta: map[string]string{"description": inputs[descIndex].Value()},
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
 				Data: entities.EntryDataPassword{
 					Login:    inputs[loginIndex].Value(),
 					Password: inputs[passwordIndex].Value(),
@@ -110,6 +121,8 @@ func NewEntryCreateCard(
 		expiresIndex
 		cvvIndex
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
@@ -119,6 +132,8 @@ func NewEntryCreateCard(
 	inputs[expiresIndex] = input.NewText("expires", 32)
 	inputs[cvvIndex] = input.NewText("cvv", 32)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryCreate{
 		title:      fmt.Sprintf("%s/%s", title, typ),
 		logger:     logger,
@@ -139,9 +154,11 @@ func NewEntryCreateCard(
 		},
 		requester: func(inputs []input.Input) (entities.CreateEntryRequest, error) {
 			return entities.CreateEntryRequest{
-				Key:  inputs[keyIndex].Value(),
-				Type: typ,
-				Meta: map[string]string{"description": inputs[descIndex].Value()},
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
 				Data: entities.EntryDataCard{
 					Number:  inputs[numberIndex].Value(),
 					Owner:   inputs[ownerIndex].Value(),
@@ -163,11 +180,15 @@ func NewEntryCreateNote(
 	const (
 		keyIndex = iota
 		noteIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[keyIndex] = input.NewText("key", 32)
 	inputs[noteIndex] = input.NewArea("note", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryCreate{
 		title:      fmt.Sprintf("%s/%s", title, typ),
 		logger:     logger,
@@ -188,10 +209,12 @@ func NewEntryCreateNote(
 		},
 		requester: func(inputs []input.Input) (entities.CreateEntryRequest, error) {
 			return entities.CreateEntryRequest{
-				Key:  inputs[keyIndex].Value(),
-				Type: typ,
-				Meta: nil,
-				Data: entities.EntryDataNote(inputs[noteIndex].Value()),
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   nil,
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   entities.EntryDataNote(inputs[noteIndex].Value()),
 			}, nil
 		},
 	}
@@ -208,12 +231,16 @@ func NewEntryCreateBinary(
 		keyIndex = iota
 		pathIndex
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[keyIndex] = input.NewText("key", 32)
 	inputs[pathIndex] = input.NewText("filepath", 64)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryCreate{
 		title:      fmt.Sprintf("%s/%s", title, typ),
 		logger:     logger,
@@ -232,34 +259,173 @@ func NewEntryCreateBinary(
 			}
 			return nil
 		},
-		requester: func(inputs []input.Input) (request entities.CreateEntryRequest, err error) {
+		timeout: 10 * time.Minute,
+		creator: func(ctx context.Context, inputs []input.Input) (response entities.CreateEntryResponse, err error) {
 			path := inputs[pathIndex].Value()
 			fileInfo, err := os.Stat(path)
 			if err != nil {
 				logger.Error("failed to get file info", zap.Error(err))
-				return request, fmt.Errorf("failed to get file info: %w", err)
+				return response, fmt.Errorf("failed to get file info: %w", err)
 			}
 			if fileInfo.IsDir() {
 				logger.Error("path must be a file", zap.String("path", path))
-				return request, fmt.Errorf("path must be a file")
+				return response, fmt.Errorf("path must be a file")
+			}
+			meta := map[string]string{
+				"filename":    filepath.Base(path),
+				"description": inputs[descIndex].Value(),
 			}
 			if fileInfo.Size() > entities.EntryMaxDataSize {
-				logger.Error("file size must be less than 1MB", zap.String("path", path))
-				return request, fmt.Errorf("file size must be less than %d bytes", entities.EntryMaxDataSize)
+				// large files are streamed to the server blob storage
+				return entryUC.CreateBlob(ctx, entities.CreateBlobEntryRequest{
+					Key:    inputs[keyIndex].Value(),
+					Meta:   meta,
+					Folder: inputs[folderIndex].Value(),
+					Tags:   inputTags(inputs[tagsIndex].Value()),
+					Path:   path,
+				})
 			}
 			data, err := os.ReadFile(path)
 			if err != nil {
 				logger.Error("failed to read file", zap.Error(err))
-				return request, fmt.Errorf("failed to read file: %w", err)
+				return response, fmt.Errorf("failed to read file: %w", err)
+			}
+			return entryUC.Create(ctx, entities.CreateEntryRequest{
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   meta,
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   entities.EntryDataBinary(data),
+			})
+		},
+	}
+}
+
+func NewEntryCreateTOTP(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryCreateUC,
+) *EntryCreate {
+	typ := core.EntryTypeTOTP
+	const (
+		keyIndex = iota
+		secretIndex
+		algorithmIndex
+		digitsIndex
+		periodIndex
+		descIndex
+		folderIndex
+		tagsIndex
+		inputCount
+	)
+	inputs := make([]input.Input, inputCount)
+	inputs[keyIndex] = input.NewText("key", 32)
+	inputs[secretIndex] = input.NewText("secret or otpauth:// uri", 256)
+	inputs[algorithmIndex] = input.NewText("algorithm (SHA1)", 8)
+	inputs[digitsIndex] = input.NewText("digits (6)", 1)
+	inputs[periodIndex] = input.NewText("period, sec (30)", 4)
+	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
+	data := func(inputs []input.Input) (entities.EntryDataTOTP, error) {
+		return totpData(
+			inputs[secretIndex].Value(),
+			inputs[algorithmIndex].Value(),
+			inputs[digitsIndex].Value(),
+			inputs[periodIndex].Value(),
+		)
+	}
+	return &EntryCreate{
+		title:      fmt.Sprintf("%s/%s", title, typ),
+		logger:     logger,
+		back:       back,
+		focusIndex: 0,
+
+		entryType: typ,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		validator: func(inputs []input.Input) error {
+			if inputs[keyIndex].Value() == "" {
+				return errors.New("key should not be empty")
+			}
+			if inputs[secretIndex].Value() == "" {
+				return errors.New("secret should not be empty")
+			}
+			_, err := data(inputs)
+			return err
+		},
+		requester: func(inputs []input.Input) (entities.CreateEntryRequest, error) {
+			totp, err := data(inputs)
+			if err != nil {
+				return entities.CreateEntryRequest{}, err
 			}
-			name := filepath.Base(path)
 			return entities.CreateEntryRequest{
-				Key:  inputs[keyIndex].Value(),
-				Type: typ,
-				Meta: map[string]string{
-					"filename": name,
-				},
-				Data: entities.EntryDataBinary(data),
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   totp,
+			}, nil
+		},
+	}
+}
+
+// NewEntryCreateFields returns the form of the schema-driven type, the inputs are built from the schema fields
+func NewEntryCreateFields(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryCreateUC,
+	schema core.EntrySchema,
+) *EntryCreate {
+	typ := schema.Type
+	fieldCount := len(schema.Fields)
+	var (
+		keyIndex    = 0
+		descIndex   = fieldCount + 1
+		folderIndex = fieldCount + 2
+		tagsIndex   = fieldCount + 3
+	)
+	inputs := []input.Input{input.NewText("key", 32)}
+	inputs = append(inputs, fieldInputs(schema, false)...)
+	inputs = append(inputs,
+		input.NewArea("description", 280),
+		input.NewText("folder", 64),
+		input.NewText("tags", 64))
+	fields := func(inputs []input.Input) []input.Input {
+		return inputs[keyIndex+1 : descIndex]
+	}
+	return &EntryCreate{
+		title:      fmt.Sprintf("%s/%s", title, typ),
+		logger:     logger,
+		back:       back,
+		focusIndex: 0,
+
+		entryType: typ,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		validator: func(inputs []input.Input) error {
+			if inputs[keyIndex].Value() == "" {
+				return errors.New("key should not be empty")
+			}
+			_, err := fieldsData(schema, fields(inputs), nil)
+			return err
+		},
+		requester: func(inputs []input.Input) (entities.CreateEntryRequest, error) {
+			data, err := fieldsData(schema, fields(inputs), nil)
+			if err != nil {
+				return entities.CreateEntryRequest{}, err
+			}
+			return entities.CreateEntryRequest{
+				Key:    inputs[keyIndex].Value(),
+				Type:   typ,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   data,
 			}, nil
 		},
 	}
@@ -294,6 +460,10 @@ func (c *EntryCreate) View() string {
 	sb := strings.Builder{}
 	for i := range c.inputs {
 		sb.WriteString(c.inputs[i].View())
+		if c.password != nil && c.inputs[i] == c.password {
+			sb.WriteString("  ")
+			sb.WriteString(passwordStrength(c.password))
+		}
 		sb.WriteByte('\n')
 	}
 	sb.WriteByte('\n')
@@ -307,6 +477,12 @@ func (c *EntryCreate) View() string {
 	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
 	sb.WriteString(styles.DotStyle)
 	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	if c.password != nil {
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("ctrl+g: generate password"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("ctrl+t: generate passphrase"))
+	}
 	return sb.String()
 }
 
@@ -344,6 +520,17 @@ func (c *EntryCreate) updateKeyMsg(
 		}
 		result.Prev = c.back
 		return result
+	case "ctrl+g", "ctrl+t":
+		if c.password == nil {
+			return result
+		}
+		if err := generatePassword(c.password, k == "ctrl+t"); err != nil {
+			c.logger.Error("failed to generate password", zap.Error(err))
+			result.Status = "can't generate password 🤨"
+			return result
+		}
+		result.Status = "password generated 🎲"
+		return result.AppendCmd(func() tea.Msg { return nil })
 	case "tab", "shift+tab", "enter", "up", "down":
 		if k == "enter" && c.focusIndex == len(c.inputs) {
 			if err := c.validator(c.inputs); err != nil {
@@ -391,14 +578,13 @@ func (c *EntryCreate) reset() (cmd tea.Cmd) {
 
 func (c *EntryCreate) entryCreateCmd() tea.Cmd {
 	return func() tea.Msg {
-		request, err := c.requester(c.inputs)
-		if err != nil {
-			c.logger.Error("failed to create entry request", zap.Error(err))
-			return entryCreateMsg{err: err}
+		timeout := c.timeout
+		if timeout == 0 {
+			timeout = 15 * time.Second
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		resp, err := c.entryUC.Create(ctx, request)
+		resp, err := c.create(ctx)
 		if err != nil {
 			c.logger.Error("failed to create entry", zap.Error(err))
 			return entryCreateMsg{err: err}
@@ -409,3 +595,20 @@ func (c *EntryCreate) entryCreateCmd() tea.Cmd {
 		}
 	}
 }
+
+func (c *EntryCreate) create(ctx context.Context) (entities.CreateEntryResponse, error) {
+	if c.creator != nil {
+		return c.creator(ctx, c.inputs)
+	}
+	request, err := c.requester(c.inputs)
+	if err != nil {
+		c.logger.Error("failed to create entry request", zap.Error(err))
+		return entities.CreateEntryResponse{}, err
+	}
+	return c.entryUC.Create(ctx, request)
+}
+
+// inputTags splits the comma-separated tags input
+func inputTags(value string) []string {
+	return core.NormalizeTags(strings.Split(value, ","))
+}
@@ -10,7 +10,18 @@ import (
 	"strings"
 )
 
-var _ base.Component = (*EntryCreateSelector)(nil)
+var (
+	_ base.Component = (*EntryCreateSelector)(nil)
+
+	// entryCreates are the forms of the built-in types, other types use the schema-driven form
+	entryCreates = map[core.EntryType]func(string, *zap.Logger, base.Component, EntryCreateUC) *EntryCreate{
+		core.EntryTypePassword: NewEntryCreatePassword,
+		core.EntryTypeNote:     NewEntryCreateNote,
+		core.EntryTypeCard:     NewEntryCreateCard,
+		core.EntryTypeBinary:   NewEntryCreateBinary,
+		core.EntryTypeTOTP:     NewEntryCreateTOTP,
+	}
+)
 
 type (
 	EntryCreateSelector struct {
@@ -27,24 +38,17 @@ func NewEntryCreateSelector(
 	back base.Component,
 	entryUC EntryCreateUC,
 ) *EntryCreateSelector {
-	l := navlist.New([]navlist.Item{
-		{
-			Name: string(core.EntryTypePassword),
-			Next: NewEntryCreatePassword(title, logger, back, entryUC),
-		},
-		{
-			Name: string(core.EntryTypeNote),
-			Next: NewEntryCreateNote(title, logger, back, entryUC),
-		},
-		{
-			Name: string(core.EntryTypeCard),
-			Next: NewEntryCreateCard(title, logger, back, entryUC),
-		},
-		{
-			Name: string(core.EntryTypeBinary),
-			Next: NewEntryCreateBinary(title, logger, back, entryUC),
-		},
-	})
+	items := make([]navlist.Item, 0)
+	for _, schema := range core.EntrySchemas() {
+		var next base.Component
+		if newEntryCreate, ok := entryCreates[schema.Type]; ok {
+			next = newEntryCreate(title, logger, back, entryUC)
+		} else {
+			next = NewEntryCreateFields(title, logger, back, entryUC, schema)
+		}
+		items = append(items, navlist.Item{Name: string(schema.Type), Next: next})
+	}
+	l := navlist.New(items)
 	c := &EntryCreateSelector{
 		title:  title,
 		logger: logger,
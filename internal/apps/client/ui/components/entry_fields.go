@@ -0,0 +1,104 @@
+package components
+
+import (
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/input"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"os"
+	"strings"
+)
+
+// fieldInputs returns the inputs of the schema fields, the file inputs take the file path,
+// the empty file path of the updated entry keeps the current content
+func fieldInputs(schema core.EntrySchema, update bool) []input.Input {
+	inputs := make([]input.Input, len(schema.Fields))
+	for i, f := range schema.Fields {
+		switch f.Kind {
+		case core.FieldKindSecret:
+			inputs[i] = input.NewTextPassword(f.Name, 256)
+		case core.FieldKindMultiline:
+			inputs[i] = input.NewArea(f.Name, 4096)
+		case core.FieldKindDate:
+			inputs[i] = input.NewText(f.Name+" (YYYY-MM-DD)", len(core.DateLayout))
+		case core.FieldKindFile:
+			placeholder := f.Name + " file path"
+			if update {
+				placeholder += ", empty keeps the current one"
+			}
+			inputs[i] = input.NewText(placeholder, 256)
+		default:
+			inputs[i] = input.NewText(f.Name, 64)
+		}
+	}
+	return inputs
+}
+
+// resetFieldInputs sets the input values of the saved entry, the file inputs are left empty
+func resetFieldInputs(schema core.EntrySchema, inputs []input.Input, data entities.EntryDataFields) {
+	for i, f := range schema.Fields {
+		if f.Kind != core.FieldKindFile {
+			inputs[i].SetValue(data[f.Name])
+		}
+	}
+}
+
+// fieldsData builds and validates the field map, the files are read from the file inputs paths
+func fieldsData(
+	schema core.EntrySchema,
+	inputs []input.Input,
+	prev entities.EntryDataFields,
+) (entities.EntryDataFields, error) {
+	data := make(entities.EntryDataFields, len(schema.Fields))
+	for i, f := range schema.Fields {
+		value := strings.TrimSpace(inputs[i].Value())
+		if f.Kind == core.FieldKindFile {
+			if value == "" {
+				value = prev[f.Name]
+			} else {
+				content, err := os.ReadFile(value)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s file: %w", f.Name, err)
+				}
+				value = string(content)
+			}
+		}
+		if value != "" {
+			data[f.Name] = value
+		}
+	}
+	if err := schema.Validate(data); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrEntryFieldInvalid, err)
+	}
+	return data, nil
+}
+
+// fieldsClips returns the secret fields first, then the required ones
+func fieldsClips(schema core.EntrySchema, data func() entities.EntryDataFields) []clip {
+	var secrets, required []clip
+	for _, f := range schema.Fields {
+		c := clip{name: f.Name, value: func() string { return data()[f.Name] }}
+		switch {
+		case f.Kind == core.FieldKindSecret || f.Kind == core.FieldKindFile:
+			secrets = append(secrets, c)
+		case f.Required:
+			required = append(required, c)
+		}
+	}
+	clips := append(secrets, required...)
+	return clips[:min(len(clips), 2)]
+}
+
+// fieldsPreview returns the first required field which isn't secret
+func fieldsPreview(typ core.EntryType, data entities.EntryDataFields) string {
+	schema, ok := core.LookupEntrySchema(typ)
+	if !ok {
+		return ""
+	}
+	for _, f := range schema.Fields {
+		if f.Required && f.Kind != core.FieldKindSecret && f.Kind != core.FieldKindFile {
+			return f.Name + ": " + data[f.Name]
+		}
+	}
+	return string(typ)
+}
@@ -0,0 +1,215 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*EntryFolders)(nil)
+
+type (
+	EntryFolders struct {
+		title    string
+		logger   *zap.Logger
+		back     base.Component
+		table    table.Model
+		entryUC  EntryFoldersUC
+		filter   entities.SearchEntriesRequest
+		selector func(folder string, tag string)
+		items    []folderItem
+		loading  bool
+	}
+	EntryFoldersUC interface {
+		GetFolders(ctx context.Context) (entities.GetEntryFoldersResponse, error)
+		GetTags(ctx context.Context) (entities.GetEntryTagsResponse, error)
+	}
+	// folderItem is the row of the folder tree or the tag, the first row clears both filters
+	folderItem struct {
+		folder string
+		tag    string
+	}
+	foldersMsg struct {
+		folders []string
+		tags    []string
+		err     error
+	}
+)
+
+// NewEntryFolders shows the folder tree and tags of entries,
+// the chosen folder or tag is passed to the selector before returning back
+func NewEntryFolders(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryFoldersUC,
+	filter entities.SearchEntriesRequest,
+	selector func(folder string, tag string),
+) *EntryFolders {
+	c := &EntryFolders{
+		title:    title,
+		logger:   logger,
+		back:     back,
+		entryUC:  entryUC,
+		filter:   filter,
+		selector: selector,
+	}
+	c.table = c.newTable()
+	return c
+}
+
+func (c *EntryFolders) Title() string {
+	return c.title
+}
+
+func (c *EntryFolders) Init() (result base.InitResult) {
+	c.table.SetRows(nil)
+	c.loading = true
+	result.Status = "loading folders..."
+	return result.AppendCmd(c.foldersCmd())
+}
+
+func (c *EntryFolders) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case foldersMsg:
+		return c.updateFoldersMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil || result.Prev != nil {
+			return result
+		}
+	}
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *EntryFolders) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(c.table.View())
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("enter: filter"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *EntryFolders) newTable() table.Model {
+	columns := []table.Column{
+		{Title: "Folder / #tag", Width: 40},
+		{Title: "", Width: 10},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+	return t
+}
+
+func (c *EntryFolders) updateFoldersMsg(
+	msg foldersMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.loading = false
+	if msg.err != nil {
+		result.Status = "can't load folders 🤨"
+		return result
+	}
+	c.items = make([]folderItem, 0, len(msg.folders)+len(msg.tags)+1)
+	rows := make([]table.Row, 0, cap(c.items))
+	c.items = append(c.items, folderItem{})
+	rows = append(rows, table.Row{"all entries", ""})
+	for _, folder := range msg.folders {
+		names := strings.Split(folder, core.FolderSeparator)
+		c.items = append(c.items, folderItem{folder: folder})
+		rows = append(rows, table.Row{strings.Repeat("  ", len(names)-1) + names[len(names)-1] + "/", ""})
+	}
+	for _, tag := range msg.tags {
+		c.items = append(c.items, folderItem{tag: tag})
+		rows = append(rows, table.Row{"#" + tag, ""})
+	}
+	for i, item := range c.items {
+		switch {
+		case item.folder != "" && item.folder == c.filter.Folder,
+			item.tag != "" && item.tag == c.filter.Tag:
+			rows[i][1] = "✓"
+		}
+	}
+	c.table.SetRows(rows)
+	c.table.SetCursor(0)
+	result.Status = "folders loaded"
+	return result
+}
+
+func (c *EntryFolders) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.loading {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "enter":
+		idx := c.table.Cursor()
+		if idx < 0 || idx >= len(c.items) {
+			return result
+		}
+		folder, tag := c.filter.Folder, c.filter.Tag
+		switch item := c.items[idx]; {
+		case item.folder != "":
+			folder = item.folder
+		case item.tag != "":
+			tag = item.tag
+		default:
+			folder, tag = "", ""
+		}
+		c.selector(folder, tag)
+		result.Prev = c.back
+		return result
+	}
+	return result
+}
+
+func (c *EntryFolders) foldersCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		folders, ferr := c.entryUC.GetFolders(ctx)
+		tags, terr := c.entryUC.GetTags(ctx)
+		if err := errors.Join(ferr, terr); err != nil {
+			c.logger.Error("failed to get folders", zap.Error(err))
+			return foldersMsg{err: err}
+		}
+		return foldersMsg{folders: folders.Folders, tags: tags.Tags}
+	}
+}
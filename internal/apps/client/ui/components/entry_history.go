@@ -0,0 +1,271 @@
+package components
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*EntryHistory)(nil)
+
+type (
+	EntryHistory struct {
+		title   string
+		logger  *zap.Logger
+		back    base.Component
+		done    base.Component
+		table   table.Model
+		entryUC EntryHistoryUC
+		entry   entities.GetEntryResponse
+		history []entities.GetEntryResponse
+		syncing bool
+	}
+	EntryHistoryUC interface {
+		GetHistory(ctx context.Context, request entities.GetEntryHistoryRequest) (entities.GetEntriesResponse, error)
+		Restore(ctx context.Context, request entities.RestoreEntryRequest) error
+	}
+	historyMsg struct {
+		history []entities.GetEntryResponse
+		err     error
+	}
+	restoreMsg struct {
+		err error
+	}
+)
+
+// NewEntryHistory shows previous versions of the entry,
+// back is used to return without changes and done after the version is restored.
+func NewEntryHistory(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	done base.Component,
+	entryUC EntryHistoryUC,
+	entry entities.GetEntryResponse,
+) *EntryHistory {
+	c := &EntryHistory{
+		title:   title,
+		logger:  logger,
+		back:    back,
+		done:    done,
+		entryUC: entryUC,
+		entry:   entry,
+	}
+	c.table = c.newTable()
+	return c
+}
+
+func (c *EntryHistory) Title() string {
+	return fmt.Sprintf("%s/%s", c.title, c.entry.Key)
+}
+
+func (c *EntryHistory) Init() (result base.InitResult) {
+	c.table.SetRows(nil)
+	c.syncing = true
+	result.Status = "loading history..."
+	return result.AppendCmd(c.historyCmd())
+}
+
+func (c *EntryHistory) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case historyMsg:
+		return c.updateHistoryMsg(msg, result)
+	case restoreMsg:
+		return c.updateRestoreMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
+			return result
+		}
+	}
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *EntryHistory) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(c.table.View())
+	sb.WriteByte('\n')
+	if idx := c.table.Cursor(); idx >= 0 && idx < len(c.history) {
+		sb.WriteString(styles.SubtleStyle.Render(entryPreview(c.history[idx])))
+	}
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("enter: restore"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *EntryHistory) newTable() table.Model {
+	columns := []table.Column{
+		{Title: "Version", Width: 8},
+		{Title: "Description", Width: 30},
+		{Title: "Updated", Width: 25},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(7),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+	return t
+}
+
+func (c *EntryHistory) updateHistoryMsg(
+	msg historyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	c.history = msg.history
+	rows := make([]table.Row, len(c.history))
+	for i, entry := range c.history {
+		rows[i] = table.Row{
+			strconv.FormatInt(entry.Version, 10),
+			entry.Meta["description"],
+			entry.UpdatedAt.Local().Format(time.DateTime),
+		}
+	}
+	c.table.SetRows(rows)
+	c.table.SetCursor(0)
+	result.Status = "history loaded"
+	if len(c.history) == 0 {
+		result.Status = "no previous versions 🤷"
+	}
+	return result
+}
+
+func (c *EntryHistory) updateRestoreMsg(
+	msg restoreMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	result.Status = "entry restored 🔥"
+	result.Jump = c.done
+	return result
+}
+
+func (c *EntryHistory) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "enter":
+		idx := c.table.Cursor()
+		if idx < 0 || idx >= len(c.history) {
+			return result
+		}
+		c.syncing = true
+		result.Status = "restoring..."
+		return result.AppendCmd(c.restoreCmd(c.history[idx].Version))
+	}
+	return result
+}
+
+func (c *EntryHistory) historyCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.entryUC.GetHistory(ctx, entities.GetEntryHistoryRequest{ID: c.entry.ID})
+		if err != nil {
+			c.logger.Error("failed to get entry history", zap.Error(err))
+			return historyMsg{err: err}
+		}
+		return historyMsg{history: resp.Entries}
+	}
+}
+
+func (c *EntryHistory) restoreCmd(version int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.entryUC.Restore(ctx, entities.RestoreEntryRequest{
+			ID:      c.entry.ID,
+			Version: version,
+		})
+		if err != nil {
+			c.logger.Error("failed to restore entry", zap.Error(err))
+		}
+		return restoreMsg{err: err}
+	}
+}
+
+func (c *EntryHistory) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound),
+		errors.Is(err, entities.ErrUserVaultLocked):
+		return "history is available after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrEntryHistoryNotFound):
+		return "entry version not found 🤷"
+	default:
+		return "can't load history 🤨: internal server error 💀"
+	}
+}
+
+func entryPreview(entry entities.GetEntryResponse) string {
+	switch data := entry.Data.(type) {
+	case entities.EntryDataPassword:
+		return "login: " + data.Login
+	case entities.EntryDataNote:
+		note, _, _ := strings.Cut(string(data), "\n")
+		return "note: " + note
+	case entities.EntryDataCard:
+		number := data.Number
+		if len(number) > 4 {
+			number = strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+		}
+		return "card: " + number
+	case entities.EntryDataBinary:
+		return "file: " + entry.Meta["filename"]
+	case entities.EntryDataTOTP:
+		return "totp: " + cmp.Or(data.Algorithm, "SHA1")
+	case entities.EntryDataFields:
+		return fieldsPreview(entry.Type, data)
+	default:
+		return ""
+	}
+}
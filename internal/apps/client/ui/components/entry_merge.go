@@ -0,0 +1,196 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*EntryMerge)(nil)
+
+type (
+	EntryMerge struct {
+		title    string
+		logger   *zap.Logger
+		back     base.Component
+		done     base.Component
+		entryUC  EntryConflictUC
+		conflict entities.GetEntryConflictResponse
+		fields   []string
+		useLocal map[string]bool
+		cursor   int
+		syncing  bool
+	}
+	resolveMsg struct {
+		err error
+	}
+)
+
+// NewEntryMerge shows both versions of the conflicted entry,
+// password and card versions could be merged field by field.
+func NewEntryMerge(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	done base.Component,
+	entryUC EntryConflictUC,
+	conflict entities.GetEntryConflictResponse,
+) *EntryMerge {
+	c := &EntryMerge{
+		title:    title,
+		logger:   logger,
+		back:     back,
+		done:     done,
+		entryUC:  entryUC,
+		conflict: conflict,
+	}
+	if conflict.Mergeable() {
+		c.fields = conflict.Fields()
+		c.useLocal = conflict.Suggest()
+	}
+	return c
+}
+
+func (c *EntryMerge) Title() string {
+	return fmt.Sprintf("%s/%s", c.title, c.conflict.Key)
+}
+
+func (c *EntryMerge) Init() (result base.InitResult) {
+	c.syncing = false
+	c.cursor = 0
+	return result
+}
+
+func (c *EntryMerge) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case resolveMsg:
+		c.syncing = false
+		if msg.err != nil {
+			result.Status = conflictErrStatus(msg.err)
+			return result
+		}
+		result.Status = "conflict resolved 🔥"
+		result.Jump = c.done
+		return result
+	case tea.KeyMsg:
+		return c.updateKeyMsg(msg, result)
+	}
+	return result
+}
+
+func (c *EntryMerge) View() string {
+	sb := strings.Builder{}
+	if len(c.fields) == 0 {
+		sb.WriteString("mine:   " + entryPreview(c.conflict.Local))
+		sb.WriteByte('\n')
+		sb.WriteString("theirs: " + entryPreview(c.conflict.Remote))
+		sb.WriteByte('\n')
+	}
+	for i, field := range c.fields {
+		local, remote, base := c.conflict.Values(field)
+		mine, theirs := "  ", "  "
+		if c.useLocal[field] {
+			mine = "✔ "
+		} else {
+			theirs = "✔ "
+		}
+		line := fmt.Sprintf("%-12s %s%-20s %s%-20s", field, mine, local, theirs, remote)
+		if c.conflict.Base != nil {
+			line += styles.SubtleStyle.Render(" was: " + base)
+		}
+		if i == c.cursor {
+			line = styles.FocusedStyle.Render(line)
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	if len(c.fields) > 0 {
+		sb.WriteString(styles.SubtleStyle.Render("space: toggle field"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("enter: merge"))
+		sb.WriteString(styles.DotStyle)
+	}
+	sb.WriteString(styles.SubtleStyle.Render("m: keep mine"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("r: keep theirs"))
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *EntryMerge) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "up", "k":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down", "j":
+		if c.cursor < len(c.fields)-1 {
+			c.cursor++
+		}
+	case " ", "left", "right", "h", "l":
+		if len(c.fields) > 0 {
+			field := c.fields[c.cursor]
+			c.useLocal[field] = !c.useLocal[field]
+		}
+	case "m":
+		return c.resolve(entities.ResolveEntryConflictRequest{Resolution: entities.EntryConflictKeepLocal}, result)
+	case "r":
+		return c.resolve(entities.ResolveEntryConflictRequest{Resolution: entities.EntryConflictKeepRemote}, result)
+	case "enter":
+		if len(c.fields) == 0 {
+			return result
+		}
+		meta, data, err := c.conflict.Merge(c.useLocal)
+		if err != nil {
+			result.Status = conflictErrStatus(err)
+			return result
+		}
+		return c.resolve(entities.ResolveEntryConflictRequest{
+			Resolution: entities.EntryConflictMerge,
+			Meta:       meta,
+			Data:       data,
+		}, result)
+	}
+	return result
+}
+
+func (c *EntryMerge) resolve(
+	request entities.ResolveEntryConflictRequest,
+	result base.UpdateResult,
+) base.UpdateResult {
+	request.ID = c.conflict.ID
+	c.syncing = true
+	result.Status = "resolving..."
+	return result.AppendCmd(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.entryUC.ResolveConflict(ctx, request)
+		if err != nil {
+			c.logger.Error("failed to resolve entry conflict", zap.Error(err))
+		}
+		return resolveMsg{err: err}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
@@ -21,24 +22,49 @@ var _ base.Component = (*EntryTable)(nil)
 
 type (
 	EntryTable struct {
-		title   string
-		back    base.Component
-		table   table.Model
-		logger  *zap.Logger
-		entryUC EntryUC
-		entries []entities.GetEntryResponse
-		syncing bool
+		title     string
+		back      base.Component
+		table     table.Model
+		logger    *zap.Logger
+		entryUC   EntryUC
+		clipboard Clipboard
+		entries   []entities.GetEntryResponse
+		syncing   bool
+		search    textinput.Model
+		// searching is set while the search input is focused
+		searching bool
+		filter    entities.SearchEntriesRequest
+		// searchSeq drops results of outdated searches
+		searchSeq int
 	}
 	EntryUC interface {
 		EntryCreateUC
 		EntryUpdateUC
+		EntryTrashUC
+		EntryConflictUC
+		EntryFoldersUC
 		Sync(ctx context.Context) error
 		GetAll(ctx context.Context) (entities.GetEntriesResponse, error)
+		Get(ctx context.Context, request entities.GetEntryRequest) (entities.GetEntryResponse, error)
+		Search(ctx context.Context, request entities.SearchEntriesRequest) (entities.GetEntriesResponse, error)
 		Delete(ctx context.Context, request entities.DeleteEntryRequest) error
 	}
 	syncMsg struct {
 		entries []entities.GetEntryResponse
 		err     error
+		seq     int
+	}
+	searchMsg struct {
+		entries []entities.GetEntryResponse
+		err     error
+		seq     int
+	}
+	// entryMsg carries the decrypted entry, it's opened for update when clip is negative
+	// and the clip field is copied otherwise
+	entryMsg struct {
+		entry entities.GetEntryResponse
+		clip  int
+		err   error
 	}
 	deleteMsg struct {
 		err error
@@ -49,14 +75,17 @@ func NewEntryTable(
 	title string,
 	logger *zap.Logger,
 	entryUC EntryUC,
+	clipboard Clipboard,
 ) *EntryTable {
 	c := &EntryTable{
-		title:   title,
-		back:    nil,
-		entryUC: entryUC,
-		logger:  logger,
+		title:     title,
+		back:      nil,
+		entryUC:   entryUC,
+		clipboard: clipboard,
+		logger:    logger,
 	}
 	c.table = c.newTable()
+	c.search = c.newSearch()
 	return c
 }
 
@@ -74,9 +103,22 @@ func (c *EntryTable) Update(msg tea.Msg) (result base.UpdateResult) {
 	switch msg := msg.(type) {
 	case syncMsg:
 		return c.updateSyncMsg(msg, result)
+	case searchMsg:
+		return c.updateSearchMsg(msg, result)
+	case entryMsg:
+		return c.updateEntryMsg(msg, result)
 	case deleteMsg:
 		return c.updateDeleteMsg(msg, result)
+	case base.EntriesChangedMsg:
+		if c.syncing {
+			return result
+		}
+		c.syncing = true
+		return result.AppendCmd(c.loadCmd())
 	case tea.KeyMsg:
+		if c.searching {
+			return c.updateSearchKeyMsg(msg, result)
+		}
 		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
 			return result
 		}
@@ -88,6 +130,30 @@ func (c *EntryTable) Update(msg tea.Msg) (result base.UpdateResult) {
 
 func (c *EntryTable) View() string {
 	sb := strings.Builder{}
+	if c.searching || c.filter.Query != "" {
+		sb.WriteString(c.search.View())
+		sb.WriteByte('\n')
+	}
+	typ := string(c.filter.Type)
+	if typ == "" {
+		typ = "all"
+	}
+	sort := string(c.filter.Sort)
+	if sort == "" {
+		sort = string(entities.EntrySortName)
+	}
+	sb.WriteString(styles.SubtleStyle.Render("type: " + typ))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("sort: " + sort))
+	if c.filter.Folder != "" {
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("folder: " + c.filter.Folder))
+	}
+	if c.filter.Tag != "" {
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("tag: #" + c.filter.Tag))
+	}
+	sb.WriteByte('\n')
 	sb.WriteString(c.table.View())
 	sb.WriteByte('\n')
 	sb.WriteByte('\n')
@@ -96,7 +162,29 @@ func (c *EntryTable) View() string {
 	sb.WriteString(styles.SubtleStyle.Render("enter: select"))
 	sb.WriteString(styles.DotStyle)
 	sb.WriteString(styles.SubtleStyle.Render("d: delete"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("y: copy password/number"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("Y: copy login/cvc"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("t: trash"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("c: conflicts"))
 	sb.WriteByte('\n')
+	if c.searching {
+		sb.WriteString(styles.SubtleStyle.Render("enter: done"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("esc: clear search"))
+		return sb.String()
+	}
+	sb.WriteString(styles.SubtleStyle.Render("/: search"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("f: filter type"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("o: sort"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("g: folders & tags"))
+	sb.WriteString(styles.DotStyle)
 	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
 	sb.WriteString(styles.DotStyle)
 	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
@@ -110,8 +198,10 @@ func (c *EntryTable) SetPrev(back base.Component) {
 func (c *EntryTable) newTable() table.Model {
 	columns := []table.Column{
 		{Title: "Key", Width: 15},
+		{Title: "Type", Width: 10},
 		{Title: "Description", Width: 30},
-		{Title: "Updated", Width: 25},
+		{Title: "Folder", Width: 20},
+		{Title: "Updated", Width: 20},
 	}
 	t := table.New(
 		table.WithColumns(columns),
@@ -132,6 +222,17 @@ func (c *EntryTable) newTable() table.Model {
 	return t
 }
 
+func (c *EntryTable) newSearch() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "search by key, type, folder, tags or meta"
+	ti.CharLimit = 64
+	ti.PromptStyle = styles.FocusedStyle
+	ti.TextStyle = styles.FocusedStyle
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
 func (c *EntryTable) updateSyncMsg(
 	msg syncMsg,
 	result base.UpdateResult,
@@ -146,22 +247,66 @@ func (c *EntryTable) updateSyncMsg(
 			result.Status = "for syncing try sign-in/sign-up first 🤔"
 		case errors.Is(msg.err, entities.ErrUserTokenNotFound):
 			result.Status = "for syncing try sign-in/sign-up first 🤔"
+		case errors.Is(msg.err, entities.ErrUserVaultLocked):
+			result.Status = "for syncing try sign-in/sign-up first 🤔"
+		case errors.Is(msg.err, entities.ErrEntryUndecryptable):
+			result.Status = "synced, some server entries can't be decrypted with your master password 🔒"
 		default:
 			result.Status = "can't sync 🤨: internal server error 💀"
 		}
 	}
-	c.entries = msg.entries
+	if msg.seq == c.searchSeq {
+		c.setEntries(msg.entries)
+	}
+	return result
+}
+
+func (c *EntryTable) updateSearchMsg(
+	msg searchMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if msg.seq != c.searchSeq {
+		return result
+	}
+	if msg.err != nil {
+		result.Status = "can't search entries 🤨"
+		return result
+	}
+	c.setEntries(msg.entries)
+	return result
+}
+
+func (c *EntryTable) setEntries(entries []entities.GetEntryResponse) {
+	c.entries = entries
 	rows := make([]table.Row, len(c.entries)+1)
-	rows[0] = table.Row{"press enter", "to create new entry"}
+	rows[0] = table.Row{"press enter", "", "to create new entry"}
 	for i, entry := range c.entries {
 		rows[i+1] = table.Row{
 			entry.Key,
+			string(entry.Type),
 			entry.Meta["description"],
+			entry.Folder,
 			entry.UpdatedAt.Format(time.DateTime),
 		}
 	}
 	c.table.SetRows(rows)
-	return result
+	if c.table.Cursor() >= len(rows) {
+		c.table.SetCursor(len(rows) - 1)
+	}
+}
+
+func (c *EntryTable) updateEntryMsg(
+	msg entryMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if msg.err != nil {
+		result.Status = "can't get entry 🤨"
+		return result
+	}
+	if msg.clip < 0 {
+		return c.entryUpdate(msg.entry, result)
+	}
+	return copyClip(c.logger, c.clipboard, entryClips(msg.entry), msg.clip, result)
 }
 
 func (c *EntryTable) updateDeleteMsg(
@@ -195,8 +340,39 @@ func (c *EntryTable) updateKeyMsg(
 			result.Status = "🤔"
 			return result
 		}
+		if c.filter.Query != "" {
+			c.search.SetValue("")
+			return c.searchEntries(result)
+		}
+		if c.filter.Folder != "" || c.filter.Tag != "" {
+			c.filter.Folder, c.filter.Tag = "", ""
+			return c.searchEntries(result)
+		}
 		result.Prev = c.back
 		return result
+	case "/":
+		c.searching = true
+		c.table.Blur()
+		return result.AppendCmd(c.search.Focus())
+	case "f":
+		c.filter.Type = nextEntryType(c.filter.Type)
+		return c.searchEntries(result)
+	case "g":
+		if c.syncing {
+			result.Status = "🤔"
+			return result
+		}
+		result.Next = NewEntryFolders(c.title+"/folders", c.logger, c, c.entryUC, c.filter, func(folder, tag string) {
+			c.filter.Folder, c.filter.Tag = folder, tag
+		})
+		return result
+	case "o":
+		if c.filter.Sort == entities.EntrySortUpdated {
+			c.filter.Sort = entities.EntrySortName
+		} else {
+			c.filter.Sort = entities.EntrySortUpdated
+		}
+		return c.searchEntries(result)
 	case "j", "k", "up", "down":
 		if !c.table.Focused() {
 			c.table.Focus()
@@ -216,7 +392,8 @@ func (c *EntryTable) updateKeyMsg(
 		if idx == -1 {
 			return c.entryCreateSelector(result)
 		}
-		return c.entryUpdate(c.entries[idx], result)
+		result.Status = "🤔"
+		return result.AppendCmd(c.getCmd(c.entries[idx].ID, -1))
 	case "s":
 		result.Status = "🤔"
 		if c.syncing {
@@ -224,6 +401,30 @@ func (c *EntryTable) updateKeyMsg(
 		}
 		c.syncing = true
 		return result.AppendCmd(c.syncCmd())
+	case "t":
+		if c.syncing {
+			result.Status = "🤔"
+			return result
+		}
+		result.Next = NewEntryTrash(c.title+"/trash", c.logger, c, c.entryUC)
+		return result
+	case "c":
+		if c.syncing {
+			result.Status = "🤔"
+			return result
+		}
+		result.Next = NewEntryConflicts(c.title+"/conflicts", c.logger, c, c.entryUC)
+		return result
+	case "y", "Y":
+		entry, ok := c.selectedEntry()
+		if !ok {
+			return result
+		}
+		idx := 0
+		if k == "Y" {
+			idx = 1
+		}
+		return result.AppendCmd(c.getCmd(entry.ID, idx))
 	case "delete", "d":
 		if c.syncing {
 			result.Status = "🤔"
@@ -245,7 +446,70 @@ func (c *EntryTable) updateKeyMsg(
 	return result
 }
 
+func (c *EntryTable) selectedEntry() (entities.GetEntryResponse, bool) {
+	row := c.table.SelectedRow()
+	if len(row) == 0 {
+		return entities.GetEntryResponse{}, false
+	}
+	idx := slices.IndexFunc(c.entries, func(entry entities.GetEntryResponse) bool { return entry.Key == row[0] })
+	if idx == -1 {
+		return entities.GetEntryResponse{}, false
+	}
+	return c.entries[idx], true
+}
+
+func (c *EntryTable) updateSearchKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	switch msg.String() {
+	case "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		c.stopSearching()
+		c.search.SetValue("")
+		return c.searchEntries(result)
+	case "enter":
+		c.stopSearching()
+		return result
+	}
+	var cmd tea.Cmd
+	c.search, cmd = c.search.Update(msg)
+	result = result.AppendCmd(cmd)
+	if c.search.Value() == c.filter.Query {
+		return result
+	}
+	return c.searchEntries(result)
+}
+
+func (c *EntryTable) stopSearching() {
+	c.searching = false
+	c.search.Blur()
+	c.table.Focus()
+}
+
+// searchEntries runs the search with the current filter, results of previous searches are dropped
+func (c *EntryTable) searchEntries(result base.UpdateResult) base.UpdateResult {
+	c.filter.Query = c.search.Value()
+	c.searchSeq++
+	return result.AppendCmd(c.searchCmd(c.filter, c.searchSeq))
+}
+
+func (c *EntryTable) searchCmd(filter entities.SearchEntriesRequest, seq int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.entryUC.Search(ctx, filter)
+		if err != nil {
+			c.logger.Error("failed to search entries", zap.Error(err))
+		}
+		return searchMsg{entries: resp.Entries, err: err, seq: seq}
+	}
+}
+
 func (c *EntryTable) syncCmd() tea.Cmd {
+	filter, seq := c.filter, c.searchSeq
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
@@ -254,13 +518,39 @@ func (c *EntryTable) syncCmd() tea.Cmd {
 			c.logger.Error("failed to sync entries", zap.Error(err))
 			merr = errors.Join(merr, err)
 		}
-		resp, err := c.entryUC.GetAll(ctx)
+		resp, err := c.entryUC.Search(ctx, filter)
 		if err != nil {
 			c.logger.Error("failed to get entries", zap.Error(err))
 			merr = errors.Join(merr, err)
-			return syncMsg{err: merr}
+			return syncMsg{err: merr, seq: seq}
 		}
-		return syncMsg{entries: resp.Entries, err: merr}
+		return syncMsg{entries: resp.Entries, err: merr, seq: seq}
+	}
+}
+
+func (c *EntryTable) loadCmd() tea.Cmd {
+	filter, seq := c.filter, c.searchSeq
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.entryUC.Search(ctx, filter)
+		if err != nil {
+			c.logger.Error("failed to get entries", zap.Error(err))
+			return syncMsg{err: err, seq: seq}
+		}
+		return syncMsg{entries: resp.Entries, seq: seq}
+	}
+}
+
+func (c *EntryTable) getCmd(id uuid.UUID, clip int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		entry, err := c.entryUC.Get(ctx, entities.GetEntryRequest{ID: id})
+		if err != nil {
+			c.logger.Error("failed to get entry", zap.Error(err))
+		}
+		return entryMsg{entry: entry, clip: clip, err: err}
 	}
 }
 
@@ -274,10 +564,18 @@ func (c *EntryTable) deleteCmd(id uuid.UUID) tea.Cmd {
 }
 
 func (c *EntryTable) reset() {
+	c.stopSearching()
 	c.table.Blur()
 	c.table.SetRows(nil)
 }
 
+// nextEntryType cycles the type filter: all and then the registered types
+func nextEntryType(typ core.EntryType) core.EntryType {
+	types := append([]core.EntryType{""}, core.EntryTypes()...)
+	idx := slices.Index(types, typ)
+	return types[(idx+1)%len(types)]
+}
+
 func (c *EntryTable) entryCreateSelector(
 	result base.UpdateResult,
 ) base.UpdateResult {
@@ -294,15 +592,10 @@ func (c *EntryTable) entryUpdate(
 	result base.UpdateResult,
 ) base.UpdateResult {
 	title := c.title + "/update"
-	switch entry.Type {
-	case core.EntryTypePassword:
-		result.Next = NewEntryUpdatePassword(title, c.logger, c, c.entryUC, entry)
-	case core.EntryTypeNote:
-		result.Next = NewEntryUpdateNote(title, c.logger, c, c.entryUC, entry)
-	case core.EntryTypeCard:
-		result.Next = NewEntryUpdateCard(title, c.logger, c, c.entryUC, entry)
-	case core.EntryTypeBinary:
-		result.Next = NewEntryUpdateBinary(title, c.logger, c, c.entryUC, entry)
+	if newEntryUpdate, ok := entryUpdates[entry.Type]; ok {
+		result.Next = newEntryUpdate(title, c.logger, c, c.entryUC, c.clipboard, entry)
+	} else if schema, ok := core.LookupEntrySchema(entry.Type); ok {
+		result.Next = NewEntryUpdateFields(title, c.logger, c, c.entryUC, c.clipboard, entry, schema)
 	}
 	return result
 }
@@ -0,0 +1,237 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*EntryTrash)(nil)
+
+type (
+	EntryTrash struct {
+		title   string
+		logger  *zap.Logger
+		back    base.Component
+		table   table.Model
+		entryUC EntryTrashUC
+		entries []entities.GetEntryResponse
+		syncing bool
+	}
+	EntryTrashUC interface {
+		GetTrash(ctx context.Context) (entities.GetEntriesResponse, error)
+		Undelete(ctx context.Context, request entities.UndeleteEntryRequest) error
+	}
+	trashMsg struct {
+		entries []entities.GetEntryResponse
+		err     error
+	}
+	undeleteMsg struct {
+		err error
+	}
+)
+
+// NewEntryTrash shows deleted entries that are not purged on the server yet
+func NewEntryTrash(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryTrashUC,
+) *EntryTrash {
+	c := &EntryTrash{
+		title:   title,
+		logger:  logger,
+		back:    back,
+		entryUC: entryUC,
+	}
+	c.table = c.newTable()
+	return c
+}
+
+func (c *EntryTrash) Title() string {
+	return c.title
+}
+
+func (c *EntryTrash) Init() (result base.InitResult) {
+	c.table.SetRows(nil)
+	c.syncing = true
+	result.Status = "loading trash..."
+	return result.AppendCmd(c.trashCmd())
+}
+
+func (c *EntryTrash) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case trashMsg:
+		return c.updateTrashMsg(msg, result)
+	case undeleteMsg:
+		return c.updateUndeleteMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
+			return result
+		}
+	}
+	var cmd tea.Cmd
+	c.table, cmd = c.table.Update(msg)
+	return result.AppendCmd(cmd)
+}
+
+func (c *EntryTrash) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(c.table.View())
+	sb.WriteByte('\n')
+	if idx := c.table.Cursor(); idx >= 0 && idx < len(c.entries) {
+		sb.WriteString(styles.SubtleStyle.Render(entryPreview(c.entries[idx])))
+	}
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("enter: undelete"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	return sb.String()
+}
+
+func (c *EntryTrash) newTable() table.Model {
+	columns := []table.Column{
+		{Title: "Key", Width: 15},
+		{Title: "Description", Width: 30},
+		{Title: "Deleted", Width: 25},
+	}
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(7),
+	)
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+	return t
+}
+
+func (c *EntryTrash) updateTrashMsg(
+	msg trashMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	c.entries = msg.entries
+	rows := make([]table.Row, len(c.entries))
+	for i, entry := range c.entries {
+		rows[i] = table.Row{
+			entry.Key,
+			entry.Meta["description"],
+			entry.UpdatedAt.Local().Format(time.DateTime),
+		}
+	}
+	c.table.SetRows(rows)
+	c.table.SetCursor(0)
+	result.Status = "trash loaded"
+	if len(c.entries) == 0 {
+		result.Status = "trash is empty 🤷"
+	}
+	return result
+}
+
+func (c *EntryTrash) updateUndeleteMsg(
+	msg undeleteMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	result.Status = "entry undeleted 🔥"
+	result.Prev = c.back
+	return result
+}
+
+func (c *EntryTrash) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "enter":
+		idx := c.table.Cursor()
+		if idx < 0 || idx >= len(c.entries) {
+			return result
+		}
+		c.syncing = true
+		result.Status = "undeleting..."
+		return result.AppendCmd(c.undeleteCmd(c.entries[idx].ID))
+	}
+	return result
+}
+
+func (c *EntryTrash) trashCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.entryUC.GetTrash(ctx)
+		if err != nil {
+			c.logger.Error("failed to get trash", zap.Error(err))
+			return trashMsg{err: err}
+		}
+		return trashMsg{entries: resp.Entries}
+	}
+}
+
+func (c *EntryTrash) undeleteCmd(id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.entryUC.Undelete(ctx, entities.UndeleteEntryRequest{ID: id})
+		if err != nil {
+			c.logger.Error("failed to undelete entry", zap.Error(err))
+		}
+		return undeleteMsg{err: err}
+	}
+}
+
+func (c *EntryTrash) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound),
+		errors.Is(err, entities.ErrUserVaultLocked):
+		return "trash is available after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrEntryExists):
+		return "entry key is taken, rename the entry first 🤷"
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return "entry is already purged 🤷"
+	default:
+		return "can't load trash 🤨: internal server error 💀"
+	}
+}
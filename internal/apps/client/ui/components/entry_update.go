@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -26,6 +27,11 @@ type (
 		syncing    bool
 
 		inputs    []input.Input
+		password  input.Input // the generated password input, nil for entries without passwords
+		clipboard Clipboard
+		clips     []clip
+		code      func() string // the live code view, nil for entries without codes
+		codeSeq   int
 		entryUC   EntryUpdateUC
 		entry     entities.GetEntryResponse
 		reseter   func(entities.GetEntryResponse, []input.Input)
@@ -33,7 +39,9 @@ type (
 		requester func([]input.Input) (entities.UpdateEntryRequest, error)
 	}
 	EntryUpdateUC interface {
+		EntryHistoryUC
 		Update(ctx context.Context, request entities.UpdateEntryRequest) error
+		DownloadBlob(ctx context.Context, request entities.DownloadBlobRequest) error
 	}
 	entryUpdateMsg struct {
 		id      uuid.UUID
@@ -46,37 +54,66 @@ type (
 	}
 )
 
+// entryUpdates are the forms of the built-in types, other types use the schema-driven form
+var entryUpdates = map[core.EntryType]func(
+	string,
+	*zap.Logger,
+	base.Component,
+	EntryUpdateUC,
+	Clipboard,
+	entities.GetEntryResponse,
+) *EntryUpdate{
+	core.EntryTypePassword: NewEntryUpdatePassword,
+	core.EntryTypeNote:     NewEntryUpdateNote,
+	core.EntryTypeCard:     NewEntryUpdateCard,
+	core.EntryTypeBinary:   NewEntryUpdateBinary,
+	core.EntryTypeTOTP:     NewEntryUpdateTOTP,
+}
+
 func NewEntryUpdatePassword(
 	title string,
 	logger *zap.Logger,
 	back base.Component,
 	entryUC EntryUpdateUC,
+	clipboard Clipboard,
 	entry entities.GetEntryResponse,
 ) *EntryUpdate {
 	const (
 		loginIndex = iota
 		passwordIndex
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[loginIndex] = input.NewText("login", 32)
-	inputs[passwordIndex] = input.NewText("password", 32)
+	inputs[passwordIndex] = input.NewText("password", 64)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryUpdate{
 		title:      title,
 		logger:     logger,
 		back:       back,
 		focusIndex: 0,
 
-		entry:   entry,
-		entryUC: entryUC,
-		inputs:  inputs,
+		entry:     entry,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		password:  inputs[passwordIndex],
+		clipboard: clipboard,
+		clips: []clip{
+			{name: "password", value: inputs[passwordIndex].Value},
+			{name: "login", value: inputs[loginIndex].Value},
+		},
 		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
 			passData := entry.Data.(entities.EntryDataPassword)
 			inputs[loginIndex].SetValue(passData.Login)
 			inputs[passwordIndex].SetValue(passData.Password)
 			inputs[descIndex].SetValue(entry.Meta["description"])
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
 		},
 		validator: func(inputs []input.Input) error {
 			if inputs[loginIndex].Value() == "" {
@@ -89,8 +126,10 @@ func NewEntryUpdatePassword(
 		},
 		requester: func(inputs []input.Input) (entities.UpdateEntryRequest, error) {
 			return entities.UpdateEntryRequest{
-				ID:   entry.ID,
-				Meta: map[string]string{"description": inputs[descIndex].Value()},
+				ID:     entry.ID,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
 				Data: entities.EntryDataPassword{
 					Login:    inputs[loginIndex].Value(),
 					Password: inputs[passwordIndex].Value(),
@@ -105,6 +144,7 @@ func NewEntryUpdateCard(
 	logger *zap.Logger,
 	back base.Component,
 	entryUC EntryUpdateUC,
+	clipboard Clipboard,
 	entry entities.GetEntryResponse,
 ) *EntryUpdate {
 	const (
@@ -113,6 +153,8 @@ func NewEntryUpdateCard(
 		expiresIndex
 		cvvIndex
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
@@ -121,15 +163,22 @@ func NewEntryUpdateCard(
 	inputs[expiresIndex] = input.NewText("expires", 32)
 	inputs[cvvIndex] = input.NewText("cvv", 32)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryUpdate{
 		title:      title,
 		logger:     logger,
 		back:       back,
 		focusIndex: 0,
 
-		entryUC: entryUC,
-		entry:   entry,
-		inputs:  inputs,
+		entryUC:   entryUC,
+		entry:     entry,
+		inputs:    inputs,
+		clipboard: clipboard,
+		clips: []clip{
+			{name: "number", value: inputs[numberIndex].Value},
+			{name: "cvc", value: inputs[cvvIndex].Value},
+		},
 		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
 			cardData := entry.Data.(entities.EntryDataCard)
 			inputs[numberIndex].SetValue(cardData.Number)
@@ -137,6 +186,8 @@ func NewEntryUpdateCard(
 			inputs[expiresIndex].SetValue(cardData.Expires)
 			inputs[cvvIndex].SetValue(cardData.Cvc)
 			inputs[descIndex].SetValue(entry.Meta["description"])
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
 		},
 		validator: func(inputs []input.Input) error {
 			if inputs[numberIndex].Value() == "" {
@@ -146,8 +197,10 @@ func NewEntryUpdateCard(
 		},
 		requester: func(inputs []input.Input) (entities.UpdateEntryRequest, error) {
 			return entities.UpdateEntryRequest{
-				ID:   entry.ID,
-				Meta: map[string]string{"description": inputs[descIndex].Value()},
+				ID:     entry.ID,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
 				Data: entities.EntryDataCard{
 					Number:  inputs[numberIndex].Value(),
 					Owner:   inputs[ownerIndex].Value(),
@@ -164,26 +217,34 @@ func NewEntryUpdateNote(
 	logger *zap.Logger,
 	back base.Component,
 	entryUC EntryUpdateUC,
+	clipboard Clipboard,
 	entry entities.GetEntryResponse,
 ) *EntryUpdate {
 	const (
 		noteIndex = iota
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[noteIndex] = input.NewArea("note", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryUpdate{
 		title:      title,
 		logger:     logger,
 		back:       back,
 		focusIndex: 0,
 
-		entryUC: entryUC,
-		entry:   entry,
-		inputs:  inputs,
+		entryUC:   entryUC,
+		entry:     entry,
+		inputs:    inputs,
+		clipboard: clipboard,
 		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
 			noteData := entry.Data.(entities.EntryDataNote)
 			inputs[noteIndex].SetValue(string(noteData))
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
 		},
 		validator: func(inputs []input.Input) error {
 			if inputs[noteIndex].Value() == "" {
@@ -193,9 +254,11 @@ func NewEntryUpdateNote(
 		},
 		requester: func(inputs []input.Input) (entities.UpdateEntryRequest, error) {
 			return entities.UpdateEntryRequest{
-				ID:   entry.ID,
-				Meta: nil,
-				Data: entities.EntryDataNote(inputs[noteIndex].Value()),
+				ID:     entry.ID,
+				Meta:   nil,
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   entities.EntryDataNote(inputs[noteIndex].Value()),
 			}, nil
 		},
 	}
@@ -206,25 +269,31 @@ func NewEntryUpdateBinary(
 	logger *zap.Logger,
 	back base.Component,
 	entryUC EntryUpdateUC,
+	clipboard Clipboard,
 	entry entities.GetEntryResponse,
 ) *EntryUpdate {
 	const (
 		pathIndex = iota
 		descIndex
+		folderIndex
+		tagsIndex
 		inputCount
 	)
 	inputs := make([]input.Input, inputCount)
 	inputs[pathIndex] = input.NewTextReadonly("filepath", 64)
 	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
 	return &EntryUpdate{
 		title:      title,
 		logger:     logger,
 		back:       back,
 		focusIndex: 0,
 
-		entry:   entry,
-		entryUC: entryUC,
-		inputs:  inputs,
+		entry:     entry,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		clipboard: clipboard,
 		validator: func(inputs []input.Input) error {
 			if inputs[pathIndex].Value() == "" {
 				return errors.New("path should not be empty")
@@ -234,15 +303,168 @@ func NewEntryUpdateBinary(
 		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
 			path := entry.Meta["filename"]
 			inputs[pathIndex].SetValue(path)
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
 		},
 		requester: func(inputs []input.Input) (request entities.UpdateEntryRequest, err error) {
+			meta := map[string]string{
+				"filename":    entry.Meta["filename"],
+				"description": inputs[descIndex].Value(),
+			}
+			if size, ok := entry.Meta[entities.EntryMetaBlobSize]; ok {
+				meta[entities.EntryMetaBlobSize] = size
+			}
 			return entities.UpdateEntryRequest{
-				ID: entry.ID,
-				Meta: map[string]string{
-					"filename":    entry.Meta["filename"],
-					"description": inputs[descIndex].Value(),
-				},
-				Data: entry.Data,
+				ID:     entry.ID,
+				Meta:   meta,
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   entry.Data,
+			}, nil
+		},
+	}
+}
+
+func NewEntryUpdateTOTP(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryUpdateUC,
+	clipboard Clipboard,
+	entry entities.GetEntryResponse,
+) *EntryUpdate {
+	const (
+		secretIndex = iota
+		algorithmIndex
+		digitsIndex
+		periodIndex
+		descIndex
+		folderIndex
+		tagsIndex
+		inputCount
+	)
+	inputs := make([]input.Input, inputCount)
+	inputs[secretIndex] = input.NewText("secret or otpauth:// uri", 256)
+	inputs[algorithmIndex] = input.NewText("algorithm (SHA1)", 8)
+	inputs[digitsIndex] = input.NewText("digits (6)", 1)
+	inputs[periodIndex] = input.NewText("period, sec (30)", 4)
+	inputs[descIndex] = input.NewArea("description", 280)
+	inputs[folderIndex] = input.NewText("folder", 64)
+	inputs[tagsIndex] = input.NewText("tags", 64)
+	data := func(inputs []input.Input) (entities.EntryDataTOTP, error) {
+		return totpData(
+			inputs[secretIndex].Value(),
+			inputs[algorithmIndex].Value(),
+			inputs[digitsIndex].Value(),
+			inputs[periodIndex].Value(),
+		)
+	}
+	totp := entry.Data.(entities.EntryDataTOTP)
+	return &EntryUpdate{
+		title:      title,
+		logger:     logger,
+		back:       back,
+		focusIndex: 0,
+
+		entry:     entry,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		clipboard: clipboard,
+		clips: []clip{
+			{name: "code", value: func() string { return totpCode(totp) }},
+		},
+		code: func() string { return totpView(totp) },
+		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
+			totpData := entry.Data.(entities.EntryDataTOTP)
+			inputs[secretIndex].SetValue(totpData.Secret)
+			inputs[algorithmIndex].SetValue(totpData.Algorithm)
+			if totpData.Digits != 0 {
+				inputs[digitsIndex].SetValue(strconv.Itoa(totpData.Digits))
+			}
+			if totpData.Period != 0 {
+				inputs[periodIndex].SetValue(strconv.Itoa(totpData.Period))
+			}
+			inputs[descIndex].SetValue(entry.Meta["description"])
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
+		},
+		validator: func(inputs []input.Input) error {
+			if inputs[secretIndex].Value() == "" {
+				return errors.New("secret should not be empty")
+			}
+			_, err := data(inputs)
+			return err
+		},
+		requester: func(inputs []input.Input) (entities.UpdateEntryRequest, error) {
+			totp, err := data(inputs)
+			if err != nil {
+				return entities.UpdateEntryRequest{}, err
+			}
+			return entities.UpdateEntryRequest{
+				ID:     entry.ID,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   totp,
+			}, nil
+		},
+	}
+}
+
+// NewEntryUpdateFields returns the form of the schema-driven type, the inputs are built from the schema fields
+func NewEntryUpdateFields(
+	title string,
+	logger *zap.Logger,
+	back base.Component,
+	entryUC EntryUpdateUC,
+	clipboard Clipboard,
+	entry entities.GetEntryResponse,
+	schema core.EntrySchema,
+) *EntryUpdate {
+	fieldCount := len(schema.Fields)
+	var (
+		descIndex   = fieldCount
+		folderIndex = fieldCount + 1
+		tagsIndex   = fieldCount + 2
+	)
+	inputs := fieldInputs(schema, true)
+	inputs = append(inputs,
+		input.NewArea("description", 280),
+		input.NewText("folder", 64),
+		input.NewText("tags", 64))
+	prev, _ := entry.Data.(entities.EntryDataFields)
+	return &EntryUpdate{
+		title:      title,
+		logger:     logger,
+		back:       back,
+		focusIndex: 0,
+
+		entry:     entry,
+		entryUC:   entryUC,
+		inputs:    inputs,
+		clipboard: clipboard,
+		clips:     fieldsClips(schema, func() entities.EntryDataFields { return prev }),
+		reseter: func(entry entities.GetEntryResponse, inputs []input.Input) {
+			resetFieldInputs(schema, inputs[:descIndex], prev)
+			inputs[descIndex].SetValue(entry.Meta["description"])
+			inputs[folderIndex].SetValue(entry.Folder)
+			inputs[tagsIndex].SetValue(strings.Join(entry.Tags, ", "))
+		},
+		validator: func(inputs []input.Input) error {
+			_, err := fieldsData(schema, inputs[:descIndex], prev)
+			return err
+		},
+		requester: func(inputs []input.Input) (entities.UpdateEntryRequest, error) {
+			data, err := fieldsData(schema, inputs[:descIndex], prev)
+			if err != nil {
+				return entities.UpdateEntryRequest{}, err
+			}
+			return entities.UpdateEntryRequest{
+				ID:     entry.ID,
+				Meta:   map[string]string{"description": inputs[descIndex].Value()},
+				Folder: inputs[folderIndex].Value(),
+				Tags:   inputTags(inputs[tagsIndex].Value()),
+				Data:   data,
 			}, nil
 		},
 	}
@@ -254,7 +476,12 @@ func (c *EntryUpdate) Title() string {
 
 func (c *EntryUpdate) Init() (result base.InitResult) {
 	c.syncing = false
-	return result.AppendCmd(c.reset())
+	result = result.AppendCmd(c.reset())
+	if c.code != nil {
+		c.codeSeq++
+		result = result.AppendCmd(totpTickCmd(c.codeSeq))
+	}
+	return result
 }
 
 func (c *EntryUpdate) Update(msg tea.Msg) (result base.UpdateResult) {
@@ -263,6 +490,11 @@ func (c *EntryUpdate) Update(msg tea.Msg) (result base.UpdateResult) {
 		return c.updateEntryMsg(msg, result)
 	case binaryDownloadMsg:
 		return c.updateBinaryDownloadMsg(msg, result)
+	case totpTickMsg:
+		if msg.seq != c.codeSeq {
+			return result
+		}
+		return result.AppendCmd(totpTickCmd(c.codeSeq))
 	case tea.KeyMsg:
 		if result = c.updateKeyMsg(msg, result); result.Cmd != nil {
 			return result
@@ -279,6 +511,15 @@ func (c *EntryUpdate) View() string {
 	sb := strings.Builder{}
 	for i := range c.inputs {
 		sb.WriteString(c.inputs[i].View())
+		if c.password != nil && c.inputs[i] == c.password {
+			sb.WriteString("  ")
+			sb.WriteString(passwordStrength(c.password))
+		}
+		sb.WriteByte('\n')
+	}
+	if c.code != nil {
+		sb.WriteByte('\n')
+		sb.WriteString(c.code())
 		sb.WriteByte('\n')
 	}
 	sb.WriteByte('\n')
@@ -292,6 +533,20 @@ func (c *EntryUpdate) View() string {
 	sb.WriteString(styles.SubtleStyle.Render("q: quit"))
 	sb.WriteString(styles.DotStyle)
 	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	if c.password != nil {
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("ctrl+g: generate password"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("ctrl+t: generate passphrase"))
+	}
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("ctrl+r: history"))
+	for i, key := range []string{"ctrl+y", "alt+y"} {
+		if i < len(c.clips) {
+			sb.WriteString(styles.DotStyle)
+			sb.WriteString(styles.SubtleStyle.Render(key + ": copy " + c.clips[i].name))
+		}
+	}
 	if c.entry.Type == core.EntryTypeBinary {
 		sb.WriteString(styles.DotStyle)
 		sb.WriteString(styles.SubtleStyle.Render("d: download file"))
@@ -333,6 +588,30 @@ func (c *EntryUpdate) updateKeyMsg(
 		}
 		result.Prev = c.back
 		return result
+	case "ctrl+g", "ctrl+t":
+		if c.password == nil {
+			return result
+		}
+		if err := generatePassword(c.password, k == "ctrl+t"); err != nil {
+			c.logger.Error("failed to generate password", zap.Error(err))
+			result.Status = "can't generate password 🤨"
+			return result
+		}
+		result.Status = "password generated 🎲"
+		return result.AppendCmd(func() tea.Msg { return nil })
+	case "ctrl+y", "alt+y":
+		idx := 0
+		if k == "alt+y" {
+			idx = 1
+		}
+		return copyClip(c.logger, c.clipboard, c.clips, idx, result).AppendCmd(func() tea.Msg { return nil })
+	case "ctrl+r":
+		if c.syncing {
+			result.Status = "🤔"
+			return result
+		}
+		result.Next = NewEntryHistory(c.title+"/history", c.logger, c, c.back, c.entryUC, c.entry)
+		return result
 	case "d":
 		if c.entry.Type != core.EntryTypeBinary {
 			return result
@@ -420,11 +699,26 @@ func (c *EntryUpdate) downloadBinaryCmd() tea.Cmd {
 			return binaryDownloadMsg{err: errors.New("filename is empty")}
 		}
 		filepath := "./downloads/" + filename
-		data := entry.Data.(entities.EntryDataBinary)
 		if err := os.MkdirAll("./downloads", 0755); err != nil {
 			c.logger.Error("failed to create downloads directory", zap.Error(err))
 			return binaryDownloadMsg{err: err}
 		}
+		if entry.HasBlob() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+			if err := c.entryUC.DownloadBlob(ctx, entities.DownloadBlobRequest{
+				ID:   entry.ID,
+				Path: filepath,
+			}); err != nil {
+				c.logger.Error("failed to download blob", zap.Error(err))
+				return binaryDownloadMsg{err: err}
+			}
+			return binaryDownloadMsg{
+				err:    nil,
+				status: "binary downloaded to " + filepath + " 🔥",
+			}
+		}
+		data := entry.Data.(entities.EntryDataBinary)
 		if err := os.WriteFile(filepath, data, 0644); err != nil {
 			c.logger.Error("failed to write file", zap.Error(err))
 			return binaryDownloadMsg{err: err}
@@ -160,6 +160,7 @@ func (c *Main) authCmd(pass string) tea.Cmd {
 			c.container.Logger.Error("failed to register dependencies", zap.Error(err))
 			return authMsg{err: fmt.Errorf("main: failed to auth user and register dependencies: %w", err)}
 		}
+		c.container.Watch()
 		return authMsg{err: nil}
 	}
 }
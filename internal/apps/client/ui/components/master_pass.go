@@ -0,0 +1,234 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*MasterPass)(nil)
+
+type (
+	MasterPass struct {
+		title        string
+		logger       *zap.Logger
+		back         base.Component
+		masterPassUC MasterPassUC
+		inputs       []textinput.Model
+		focusIndex   int
+		syncing      bool
+		changed      bool
+	}
+	MasterPassUC interface {
+		Change(ctx context.Context, request entities.ChangeMasterPassRequest) error
+	}
+	masterPassMsg struct {
+		err error
+	}
+)
+
+// NewMasterPass changes the master password, the client quits after that
+// because opened dependencies still hold the previous key.
+func NewMasterPass(
+	title string,
+	logger *zap.Logger,
+	masterPassUC MasterPassUC,
+) *MasterPass {
+	c := &MasterPass{
+		title:        title,
+		logger:       logger,
+		masterPassUC: masterPassUC,
+	}
+	c.inputs = []textinput.Model{
+		c.passwordInput("Current master password"),
+		c.passwordInput("New master password"),
+		c.passwordInput("Repeat new master password"),
+	}
+	return c
+}
+
+func (c *MasterPass) Title() string {
+	return c.title
+}
+
+func (c *MasterPass) Init() (result base.InitResult) {
+	c.syncing = false
+	c.changed = false
+	for i := range c.inputs {
+		c.inputs[i].SetValue("")
+	}
+	return result.AppendCmd(c.setFocus(0))
+}
+
+func (c *MasterPass) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case masterPassMsg:
+		return c.updateMasterPassMsg(msg, result)
+	case tea.KeyMsg:
+		if result = c.updateKeyMsg(msg, result); result.Cmd != nil || result.Prev != nil {
+			return result
+		}
+		if c.syncing {
+			return result
+		}
+	}
+	cmds := make([]tea.Cmd, len(c.inputs))
+	for i := range c.inputs {
+		c.inputs[i], cmds[i] = c.inputs[i].Update(msg)
+	}
+	return result.AppendCmd(cmds...)
+}
+
+func (c *MasterPass) View() string {
+	if c.changed {
+		return "Master password changed 🔐, start gophkeeper again with the new one."
+	}
+	sb := strings.Builder{}
+	for i := range c.inputs {
+		sb.WriteString(c.inputs[i].View())
+		sb.WriteRune('\n')
+	}
+	sb.WriteByte('\n')
+	if c.focusIndex == len(c.inputs) {
+		sb.WriteString(styles.FocusedButton)
+	} else {
+		sb.WriteString(styles.BlurredButton)
+	}
+	sb.WriteByte('\n')
+	sb.WriteByte('\n')
+	sb.WriteString(styles.SubtleStyle.Render("tab: next"))
+	sb.WriteString(styles.DotStyle)
+	sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	return sb.String()
+}
+
+func (c *MasterPass) SetPrev(back base.Component) {
+	c.back = back
+}
+
+func (c *MasterPass) updateMasterPassMsg(
+	msg masterPassMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	c.syncing = false
+	if msg.err != nil {
+		result.Status = c.errStatus(msg.err)
+		return result
+	}
+	c.changed = true
+	result.Status = "master password changed 🔐"
+	result.Quitting = true
+	return result.AppendCmd(tea.Quit)
+}
+
+func (c *MasterPass) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	k := msg.String()
+	switch k {
+	case "ctrl+c":
+		result.Quitting = true
+		return result.AppendCmd(tea.Quit)
+	case "esc":
+		result.Prev = c.back
+		return result
+	case "tab", "shift+tab", "up", "down", "enter":
+		if k == "enter" && c.focusIndex == len(c.inputs) {
+			if c.inputs[1].Value() != c.inputs[2].Value() {
+				result.Status = "new passwords don't match 🤨"
+				return result
+			}
+			c.syncing = true
+			result.Status = "re-encrypting vault..."
+			return result.AppendCmd(c.changeCmd(entities.ChangeMasterPassRequest{
+				Old: core.Pass(c.inputs[0].Value()),
+				New: core.Pass(c.inputs[1].Value()),
+			}))
+		}
+
+		focusIndex := c.focusIndex
+		if k == "up" || k == "shift+tab" {
+			focusIndex--
+		} else {
+			focusIndex++
+		}
+		if focusIndex > len(c.inputs) {
+			focusIndex = 0
+		} else if focusIndex < 0 {
+			focusIndex = len(c.inputs)
+		}
+		return result.AppendCmd(c.setFocus(focusIndex))
+	}
+	return result
+}
+
+func (c *MasterPass) setFocus(focusIndex int) tea.Cmd {
+	c.focusIndex = focusIndex
+	cmds := make([]tea.Cmd, len(c.inputs))
+	for i := range c.inputs {
+		if i == c.focusIndex {
+			cmds[i] = c.inputs[i].Focus()
+			c.inputs[i].PromptStyle = styles.FocusedStyle
+			c.inputs[i].TextStyle = styles.FocusedStyle
+			continue
+		}
+		c.inputs[i].Blur()
+		c.inputs[i].PromptStyle = styles.NoStyle
+		c.inputs[i].TextStyle = styles.NoStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+func (c *MasterPass) changeCmd(request entities.ChangeMasterPassRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		err := c.masterPassUC.Change(ctx, request)
+		if err != nil {
+			c.logger.Error("failed to change master password", zap.Error(err))
+		}
+		return masterPassMsg{err: err}
+	}
+}
+
+func (c *MasterPass) passwordInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 32
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
+func (c *MasterPass) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrUserMasterPassInvalid):
+		return "current master password is invalid 🤨"
+	case errors.Is(err, entities.ErrUserPasswordInvalid):
+		return "new master password should differ from the current one 🤔"
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable, the vault key can't be kept 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound),
+		errors.Is(err, entities.ErrUserVaultLocked):
+		return "master password could be changed after sign-in 🔒"
+	default:
+		return "can't change master password 🤨: internal error 💀"
+	}
+}
@@ -0,0 +1,46 @@
+package components
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/passgen"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/input"
+	"strings"
+)
+
+var strengthStyles = map[passgen.Score]lipgloss.Style{
+	passgen.ScoreVeryWeak:   lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+	passgen.ScoreWeak:       lipgloss.NewStyle().Foreground(lipgloss.Color("208")),
+	passgen.ScoreFair:       lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+	passgen.ScoreStrong:     lipgloss.NewStyle().Foreground(lipgloss.Color("112")),
+	passgen.ScoreVeryStrong: lipgloss.NewStyle().Foreground(lipgloss.Color("40")),
+}
+
+// generatePassword replaces the password input value with the generated password or passphrase
+func generatePassword(password input.Input, passphrase bool) (err error) {
+	var value string
+	if passphrase {
+		value, err = passgen.GeneratePassphrase(passgen.DefaultPassphrasePolicy)
+	} else {
+		value, err = passgen.Generate(passgen.DefaultPolicy)
+	}
+	if err != nil {
+		return err
+	}
+	password.SetValue(value)
+	return nil
+}
+
+// passwordStrength renders the estimated strength of the password input value
+func passwordStrength(password input.Input) string {
+	value := password.Value()
+	if value == "" {
+		return ""
+	}
+	strength := passgen.Estimate(value)
+	hint := fmt.Sprintf("%s, %.0f bits", strength.Score, strength.Entropy)
+	if len(strength.Patterns) != 0 {
+		hint += ": " + strings.Join(strength.Patterns, ", ")
+	}
+	return strengthStyles[strength.Score].Render(hint)
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"context"
+	"errors"
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,6 +18,9 @@ import (
 
 var _ base.Component = (*SignIn)(nil)
 
+// signInInputsCount is the number of login and password inputs, the two-factor code input follows them
+const signInInputsCount = 2
+
 type (
 	SignIn struct {
 		logger     *zap.Logger
@@ -108,7 +112,21 @@ func (c *SignIn) updateSignInMsg(
 	msg signInMsg,
 	result base.UpdateResult,
 ) base.UpdateResult {
-	if msg.err != nil {
+	switch {
+	// the account is protected with two-factor authentication, the code is asked and sent with the same creds
+	case errors.Is(msg.err, entities.ErrUserOTPRequired):
+		if len(c.inputs) == signInInputsCount {
+			c.inputs = append(c.inputs, c.codeInput())
+		}
+		result.Status = "enter two-factor code or recovery code 🔐"
+		return result.AppendCmd(c.setFocus(len(c.inputs) - 1))
+	case errors.Is(msg.err, entities.ErrUserOTPInvalid):
+		result.Status = "two-factor code is invalid 🤨"
+		return result
+	case errors.Is(msg.err, entities.ErrUserOTPLocked):
+		result.Status = "too many two-factor attempts, try again later ⏳"
+		return result
+	case msg.err != nil:
 		result.Status = msg.err.Error()
 		return result
 	}
@@ -133,41 +151,54 @@ func (c *SignIn) updateKeyMsg(
 
 	case "tab", "shift+tab", "up", "down", "enter":
 		if k == "enter" && c.focusIndex == len(c.inputs) && c.inputsValid() {
-			result.Cmd = c.signInCmd(c.inputs[0].Value(), c.inputs[1].Value())
+			request := entities.SignInUserRequest{
+				Login:    c.inputs[0].Value(),
+				Password: c.inputs[1].Value(),
+			}
+			if len(c.inputs) > signInInputsCount {
+				request.OTP = c.inputs[signInInputsCount].Value()
+			}
+			result.Cmd = c.signInCmd(request)
 			return result
 		}
 
+		focusIndex := c.focusIndex
 		if k == "up" || k == "shift+tab" {
-			c.focusIndex--
+			focusIndex--
 		} else {
-			c.focusIndex++
+			focusIndex++
 		}
-		if c.focusIndex > len(c.inputs) {
-			c.focusIndex = 0
-		} else if c.focusIndex < 0 {
-			c.focusIndex = len(c.inputs)
+		if focusIndex > len(c.inputs) {
+			focusIndex = 0
+		} else if focusIndex < 0 {
+			focusIndex = len(c.inputs)
 		}
+		return result.AppendCmd(c.setFocus(focusIndex))
+	}
+	return result
+}
 
-		cmds := make([]tea.Cmd, len(c.inputs))
-		for i := 0; i <= len(c.inputs)-1; i++ {
-			if i == c.focusIndex {
-				// Set focused state
-				cmds[i] = c.inputs[i].Focus()
-				c.inputs[i].PromptStyle = styles.FocusedStyle
-				c.inputs[i].TextStyle = styles.FocusedStyle
-				continue
-			}
-			// Remove focused state
-			c.inputs[i].Blur()
-			c.inputs[i].PromptStyle = styles.NoStyle
-			c.inputs[i].TextStyle = styles.NoStyle
+func (c *SignIn) setFocus(focusIndex int) tea.Cmd {
+	c.focusIndex = focusIndex
+	cmds := make([]tea.Cmd, len(c.inputs))
+	for i := 0; i <= len(c.inputs)-1; i++ {
+		if i == c.focusIndex {
+			// Set focused state
+			cmds[i] = c.inputs[i].Focus()
+			c.inputs[i].PromptStyle = styles.FocusedStyle
+			c.inputs[i].TextStyle = styles.FocusedStyle
+			continue
 		}
-		return result.AppendCmd(cmds...)
+		// Remove focused state
+		c.inputs[i].Blur()
+		c.inputs[i].PromptStyle = styles.NoStyle
+		c.inputs[i].TextStyle = styles.NoStyle
 	}
-	return result
+	return tea.Batch(cmds...)
 }
 
 func (c *SignIn) reset() {
+	c.inputs = c.inputs[:signInInputsCount]
 	c.focusIndex = 0
 	c.inputs[0].Focus()
 	c.inputs[0].PromptStyle = styles.FocusedStyle
@@ -182,14 +213,11 @@ func (c *SignIn) reset() {
 	}
 }
 
-func (c *SignIn) signInCmd(login, pass string) tea.Cmd {
+func (c *SignIn) signInCmd(request entities.SignInUserRequest) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		err := c.userUC.SignIn(ctx, entities.SignInUserRequest{
-			Login:    login,
-			Password: pass,
-		})
+		err := c.userUC.SignIn(ctx, request)
 		return signInMsg{err: err}
 	}
 }
@@ -212,6 +240,15 @@ func (c *SignIn) passwordInput() textinput.Model {
 	return ti
 }
 
+func (c *SignIn) codeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Two-factor code"
+	ti.CharLimit = 32
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	ti.Cursor.Style = styles.CursorStyle
+	return ti
+}
+
 func (c *SignIn) inputsValid() bool {
 	for i := range c.inputs {
 		if c.inputs[i].Value() == "" {
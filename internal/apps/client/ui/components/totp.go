@@ -0,0 +1,62 @@
+package components
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/totp"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// totpTickMsg refreshes the live code, the ticks of the previous Init are ignored by seq
+type totpTickMsg struct {
+	seq int
+}
+
+func totpTickCmd(seq int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return totpTickMsg{seq: seq} })
+}
+
+// totpData builds the authenticator key of the secret or otpauth:// uri input,
+// the algorithm, digits and period inputs are applied to the plain secret only
+func totpData(value, algorithm, digits, period string) (data entities.EntryDataTOTP, err error) {
+	if data, err = totp.Parse(value); err != nil {
+		return data, err
+	}
+	if totp.IsURI(value) {
+		return data, nil
+	}
+	data.Algorithm = strings.ToUpper(strings.TrimSpace(algorithm))
+	if digits = strings.TrimSpace(digits); digits != "" {
+		if data.Digits, err = strconv.Atoi(digits); err != nil {
+			return data, fmt.Errorf("%w: invalid digits %s", entities.ErrEntryTOTPInvalid, digits)
+		}
+	}
+	if period = strings.TrimSpace(period); period != "" {
+		if data.Period, err = strconv.Atoi(period); err != nil {
+			return data, fmt.Errorf("%w: invalid period %s", entities.ErrEntryTOTPInvalid, period)
+		}
+	}
+	return data, totp.Valid(data)
+}
+
+// totpCode returns the current code, empty for the invalid key
+func totpCode(data entities.EntryDataTOTP) string {
+	code, _, err := totp.Code(data, time.Now())
+	if err != nil {
+		return ""
+	}
+	return code
+}
+
+// totpView renders the current code and its countdown
+func totpView(data entities.EntryDataTOTP) string {
+	code, remaining, err := totp.Code(data, time.Now())
+	if err != nil {
+		return styles.SubtleStyle.Render("code: invalid key 🤨")
+	}
+	return fmt.Sprintf("code: %s %s", code, styles.SubtleStyle.Render(fmt.Sprintf("(%ds)", int(remaining/time.Second))))
+}
@@ -0,0 +1,301 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/ui/components/base/styles"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var _ base.Component = (*TwoFactor)(nil)
+
+type twoFactorMode int
+
+const (
+	twoFactorIdle twoFactorMode = iota
+	twoFactorConfirm
+	twoFactorDisable
+	twoFactorRecoveryCodes
+)
+
+type (
+	TwoFactor struct {
+		title         string
+		logger        *zap.Logger
+		back          base.Component
+		twoFactorUC   TwoFactorUC
+		mode          twoFactorMode
+		input         textinput.Model
+		enrolled      entities.EnrollOTPResponse
+		recoveryCodes []string
+		syncing       bool
+	}
+	TwoFactorUC interface {
+		Enroll(ctx context.Context) (entities.EnrollOTPResponse, error)
+		Confirm(ctx context.Context, request entities.ConfirmOTPRequest) (entities.ConfirmOTPResponse, error)
+		Disable(ctx context.Context, request entities.DisableOTPRequest) error
+	}
+	enrollMsg struct {
+		enrolled entities.EnrollOTPResponse
+		err      error
+	}
+	confirmMsg struct {
+		recoveryCodes []string
+		err           error
+	}
+	disableMsg struct {
+		err error
+	}
+)
+
+// NewTwoFactor enrolls and disables two-factor authentication of the server account
+func NewTwoFactor(
+	title string,
+	logger *zap.Logger,
+	twoFactorUC TwoFactorUC,
+) *TwoFactor {
+	c := &TwoFactor{
+		title:       title,
+		logger:      logger,
+		twoFactorUC: twoFactorUC,
+	}
+	c.input = c.codeInput()
+	return c
+}
+
+func (c *TwoFactor) Title() string {
+	return c.title
+}
+
+func (c *TwoFactor) Init() (result base.InitResult) {
+	c.setMode(twoFactorIdle)
+	c.enrolled = entities.EnrollOTPResponse{}
+	c.recoveryCodes = nil
+	c.syncing = false
+	return result
+}
+
+func (c *TwoFactor) Update(msg tea.Msg) (result base.UpdateResult) {
+	switch msg := msg.(type) {
+	case enrollMsg:
+		c.syncing = false
+		if msg.err != nil {
+			result.Status = c.errStatus(msg.err)
+			return result
+		}
+		c.enrolled = msg.enrolled
+		result.Status = "add the key to an authenticator app and enter the code"
+		return result.AppendCmd(c.setMode(twoFactorConfirm))
+	case confirmMsg:
+		c.syncing = false
+		if msg.err != nil {
+			result.Status = c.errStatus(msg.err)
+			return result
+		}
+		c.recoveryCodes = msg.recoveryCodes
+		c.setMode(twoFactorRecoveryCodes)
+		result.Status = "two-factor authentication enabled 🔐"
+		return result
+	case disableMsg:
+		c.syncing = false
+		if msg.err != nil {
+			result.Status = c.errStatus(msg.err)
+			return result
+		}
+		c.setMode(twoFactorIdle)
+		result.Status = "two-factor authentication disabled 🔓"
+		return result
+	case tea.KeyMsg:
+		return c.updateKeyMsg(msg, result)
+	}
+	if c.mode == twoFactorConfirm || c.mode == twoFactorDisable {
+		var cmd tea.Cmd
+		c.input, cmd = c.input.Update(msg)
+		return result.AppendCmd(cmd)
+	}
+	return result
+}
+
+func (c *TwoFactor) View() string {
+	sb := strings.Builder{}
+	switch c.mode {
+	case twoFactorIdle:
+		sb.WriteString("Two-factor authentication asks for a code from an authenticator app on sign-in.\n")
+		sb.WriteByte('\n')
+		sb.WriteString(styles.SubtleStyle.Render("e: enable"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("x: disable"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("q: quit"))
+	case twoFactorConfirm:
+		sb.WriteString("Key: " + c.enrolled.Secret + "\n")
+		sb.WriteString(styles.SubtleStyle.Render(c.enrolled.URI))
+		sb.WriteString("\n\n")
+		sb.WriteString(c.input.View())
+		sb.WriteString("\n\n")
+		sb.WriteString(styles.SubtleStyle.Render("enter: confirm"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("esc: cancel"))
+	case twoFactorDisable:
+		sb.WriteString(c.input.View())
+		sb.WriteString("\n\n")
+		sb.WriteString(styles.SubtleStyle.Render("enter: disable"))
+		sb.WriteString(styles.DotStyle)
+		sb.WriteString(styles.SubtleStyle.Render("esc: cancel"))
+	case twoFactorRecoveryCodes:
+		sb.WriteString("Recovery codes, each could be used once instead of the code. They won't be shown again:\n\n")
+		for _, code := range c.recoveryCodes {
+			sb.WriteString("  " + code + "\n")
+		}
+		sb.WriteByte('\n')
+		sb.WriteString(styles.SubtleStyle.Render("esc: back"))
+	}
+	return sb.String()
+}
+
+func (c *TwoFactor) SetPrev(back base.Component) {
+	c.back = back
+}
+
+func (c *TwoFactor) updateKeyMsg(
+	msg tea.KeyMsg,
+	result base.UpdateResult,
+) base.UpdateResult {
+	if c.syncing {
+		result.Status = "🤔"
+		return result
+	}
+	k := msg.String()
+	switch c.mode {
+	case twoFactorIdle:
+		switch k {
+		case "q", "ctrl+c":
+			result.Quitting = true
+			return result.AppendCmd(tea.Quit)
+		case "esc":
+			result.Prev = c.back
+			return result
+		case "e":
+			c.syncing = true
+			result.Status = "enrolling..."
+			return result.AppendCmd(c.enrollCmd())
+		case "x":
+			result.Status = "enter two-factor code or recovery code"
+			return result.AppendCmd(c.setMode(twoFactorDisable))
+		}
+	case twoFactorConfirm, twoFactorDisable:
+		switch k {
+		case "esc":
+			c.setMode(twoFactorIdle)
+			result.Status = "canceled"
+			return result
+		case "enter":
+			code := strings.TrimSpace(c.input.Value())
+			if code == "" {
+				return result
+			}
+			c.syncing = true
+			if c.mode == twoFactorConfirm {
+				result.Status = "confirming..."
+				return result.AppendCmd(c.confirmCmd(code))
+			}
+			result.Status = "disabling..."
+			return result.AppendCmd(c.disableCmd(code))
+		}
+		var cmd tea.Cmd
+		c.input, cmd = c.input.Update(msg)
+		return result.AppendCmd(cmd)
+	case twoFactorRecoveryCodes:
+		if k == "esc" || k == "enter" {
+			result.Prev = c.back
+			return result
+		}
+	}
+	return result
+}
+
+func (c *TwoFactor) setMode(mode twoFactorMode) tea.Cmd {
+	c.mode = mode
+	c.input.SetValue("")
+	if mode == twoFactorConfirm || mode == twoFactorDisable {
+		return c.input.Focus()
+	}
+	c.input.Blur()
+	return nil
+}
+
+func (c *TwoFactor) enrollCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.twoFactorUC.Enroll(ctx)
+		if err != nil {
+			c.logger.Error("failed to enroll two-factor authentication", zap.Error(err))
+		}
+		return enrollMsg{enrolled: resp, err: err}
+	}
+}
+
+func (c *TwoFactor) confirmCmd(code string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		resp, err := c.twoFactorUC.Confirm(ctx, entities.ConfirmOTPRequest{Code: code})
+		if err != nil {
+			c.logger.Error("failed to confirm two-factor authentication", zap.Error(err))
+		}
+		return confirmMsg{recoveryCodes: resp.RecoveryCodes, err: err}
+	}
+}
+
+func (c *TwoFactor) disableCmd(code string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err := c.twoFactorUC.Disable(ctx, entities.DisableOTPRequest{Code: code})
+		if err != nil {
+			c.logger.Error("failed to disable two-factor authentication", zap.Error(err))
+		}
+		return disableMsg{err: err}
+	}
+}
+
+func (c *TwoFactor) codeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Code"
+	ti.CharLimit = 32
+	ti.PromptStyle = styles.FocusedStyle
+	ti.TextStyle = styles.FocusedStyle
+	ti.Cursor.SetMode(cursor.CursorBlink)
+	return ti
+}
+
+func (c *TwoFactor) errStatus(err error) string {
+	switch {
+	case errors.Is(err, entities.ErrServerUnavailable):
+		return "server unavailable 🤨"
+	case errors.Is(err, entities.ErrUserTokenInvalid),
+		errors.Is(err, entities.ErrUserTokenNotFound):
+		return "two-factor authentication is available after sign-in/sign-up 🤔"
+	case errors.Is(err, entities.ErrUserOTPInvalid):
+		return "two-factor code is invalid 🤨"
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return "too many two-factor attempts, try again later ⏳"
+	case errors.Is(err, entities.ErrUserOTPStateInvalid):
+		if c.mode == twoFactorDisable {
+			return "two-factor authentication is not enabled 🤷"
+		}
+		return "two-factor authentication is already enabled 🤷"
+	default:
+		return "two-factor authentication failed 🤨: internal server error 💀"
+	}
+}
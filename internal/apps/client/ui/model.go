@@ -44,15 +44,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case base.UpdateStatusMsg:
 		m.status = msg.Status
 		return m, nil
+	case base.EntriesChangedMsg:
+		res := m.curr.Update(msg)
+		if res.Status != "" {
+			m.status = res.Status
+		}
+		return m, tea.Batch(res.Cmd, base.WaitEntriesChangedCmd(m.c.EntryChanges))
 	}
 	var cmds tea.Cmd
 	res := m.curr.Update(msg)
 	cmds = tea.Batch(cmds, res.Cmd)
 	if res.PassAccepted && !m.accepted {
 		m.accepted = true
-		table := components.NewEntryTable("gophkeeper/entries", m.c.Logger, m.c.EntryUC)
+		table := components.NewEntryTable("gophkeeper/entries", m.c.Logger, m.c.EntryUC, m.c.Clipboard)
 		signUp := components.NewSignUp("gophkeeper/sync/sign-up", m.c.Logger, m.c.UserUC, m.c.Memcache)
 		signIn := components.NewSignIn("gophkeeper/sync/sign-in", m.c.Logger, m.c.UserUC, m.c.Memcache)
+		devices := components.NewDevices("gophkeeper/sync/devices", m.c.Logger, m.c.SessionUC)
+		twoFactor := components.NewTwoFactor("gophkeeper/sync/two-factor", m.c.Logger, m.c.TwoFactorUC)
+		accountPassword := components.NewAccountPassword("gophkeeper/sync/password", m.c.Logger, m.c.UserUC)
+		accountDelete := components.NewAccountDelete("gophkeeper/sync/delete-account", m.c.Logger, m.c.UserUC)
+		masterPass := components.NewMasterPass("gophkeeper/master-password", m.c.Logger, m.c.MasterPassUC)
 		about := components.NewSettings("gophkeeper/about", components.BuildInfo{
 			Version: m.c.Config.BuildVersion,
 			Date:    m.c.Config.BuildDate,
@@ -62,17 +73,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			[]navlist.Item{
 				{Name: "Sign-up", Next: signUp},
 				{Name: "Sign-in", Next: signIn},
+				{Name: "Devices", Next: devices},
+				{Name: "Two-factor", Next: twoFactor},
+				{Name: "Account password", Next: accountPassword},
+				{Name: "Delete account", Next: accountDelete},
 				{Name: "Entries", Next: table},
+				{Name: "Master password", Next: masterPass},
 				{Name: "About", Next: about},
 			})
 		table.SetPrev(menu)
 		signUp.SetPrev(menu)
 		signIn.SetPrev(menu)
+		devices.SetPrev(menu)
+		twoFactor.SetPrev(menu)
+		accountPassword.SetPrev(menu)
+		accountDelete.SetPrev(menu)
+		masterPass.SetPrev(menu)
 		about.SetPrev(menu)
 		m.curr = menu
 		result := m.curr.Init()
 		m.status = result.Status
-		return m, result.Cmd
+		return m, tea.Batch(result.Cmd, base.WaitEntriesChangedCmd(m.c.EntryChanges))
 	}
 	if res.Status != "" {
 		m.status = res.Status
@@ -8,14 +8,15 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
 	"github.com/dlomanov/gophkeeper/internal/apps/shared/mapper"
-	sharedmd "github.com/dlomanov/gophkeeper/internal/apps/shared/md"
 	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -26,24 +27,37 @@ type (
 		entryClient   pb.EntryServiceClient
 		entryRepo     EntryRepo
 		entrySyncRepo EntrySyncRepo
+		conflictRepo  EntryConflictRepo
 		encrypter     Encrypter
+		vault         Encrypter
 		marshaler     Marshaler
 		mapper        mapper.EntryMapper
 		tx            trm.Manager
+		syncMu        sync.Mutex
 	}
 	EntryRepo interface {
 		Get(ctx context.Context, id uuid.UUID) (entities.Entry, error)
 		GetAll(ctx context.Context) ([]entities.Entry, error)
+		Search(ctx context.Context, request entities.SearchEntriesRequest) ([]entities.Entry, error)
+		GetFolders(ctx context.Context) ([]string, error)
+		GetTags(ctx context.Context) ([]string, error)
 		GetVersions(ctx context.Context) ([]core.EntryVersion, error)
 		Delete(ctx context.Context, id uuid.UUID) error
 		Create(ctx context.Context, entry entities.Entry) error
 		Update(ctx context.Context, entry entities.Entry) error
 	}
 	EntrySyncRepo interface {
+		Get(ctx context.Context, id uuid.UUID) (entities.EntrySync, error)
 		GetAll(ctx context.Context) ([]entities.EntrySync, error)
-		Delete(ctx context.Context, id uuid.UUID) error
+		Delete(ctx context.Context, id uuid.UUID, requestID string) error
 		Create(ctx context.Context, entrySync entities.EntrySync) error
 	}
+	EntryConflictRepo interface {
+		GetAll(ctx context.Context) ([]entities.EntryConflict, error)
+		Get(ctx context.Context, id uuid.UUID) (entities.EntryConflict, error)
+		Save(ctx context.Context, conflict entities.EntryConflict) error
+		Delete(ctx context.Context, id uuid.UUID) error
+	}
 	Encrypter interface {
 		Encrypt(data []byte) ([]byte, error)
 		Decrypt(data []byte) ([]byte, error)
@@ -59,7 +73,9 @@ func NewEntriesUC(
 	entryClient pb.EntryServiceClient,
 	entryRepo EntryRepo,
 	entrySyncRepo EntrySyncRepo,
+	conflictRepo EntryConflictRepo,
 	encrypter Encrypter,
+	vault Encrypter,
 	marshaler Marshaler,
 	cache *mem.Cache,
 	tx trm.Manager,
@@ -70,7 +86,9 @@ func NewEntriesUC(
 		entryClient:   entryClient,
 		entryRepo:     entryRepo,
 		entrySyncRepo: entrySyncRepo,
+		conflictRepo:  conflictRepo,
 		encrypter:     encrypter,
+		vault:         vault,
 		marshaler:     marshaler,
 		mapper:        mapper.EntryMapper{},
 		tx:            tx,
@@ -82,6 +100,71 @@ func (uc *EntryUC) GetAll(ctx context.Context) (response entities.GetEntriesResp
 	if err != nil {
 		return response, fmt.Errorf("entry_usecase: failed to get entries: %w", err)
 	}
+	return uc.toResponse(entries)
+}
+
+// Search returns entries matching the fuzzy query without their data, Get decrypts the chosen one
+func (uc *EntryUC) Search(
+	ctx context.Context,
+	request entities.SearchEntriesRequest,
+) (response entities.GetEntriesResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	entries, err := uc.entryRepo.Search(ctx, request)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to search entries: %w", err)
+	}
+	response.Entries = make([]entities.GetEntryResponse, len(entries))
+	for i, v := range entries {
+		response.Entries[i] = uc.toEntryResponse(v, nil)
+	}
+	return response, nil
+}
+
+func (uc *EntryUC) Get(
+	ctx context.Context,
+	request entities.GetEntryRequest,
+) (response entities.GetEntryResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	entry, err := uc.entryRepo.Get(ctx, request.ID)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	}
+	result, err := uc.toResponse([]entities.Entry{entry})
+	if err != nil {
+		return response, err
+	}
+	return result.Entries[0], nil
+}
+
+// GetFolders returns all entry folders with their parent folders, so the folder tree can be built
+func (uc *EntryUC) GetFolders(ctx context.Context) (response entities.GetEntryFoldersResponse, err error) {
+	folders, err := uc.entryRepo.GetFolders(ctx)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to get folders: %w", err)
+	}
+	for _, folder := range folders {
+		names := strings.Split(folder, core.FolderSeparator)
+		for i := range names {
+			response.Folders = append(response.Folders, strings.Join(names[:i+1], core.FolderSeparator))
+		}
+	}
+	slices.Sort(response.Folders)
+	response.Folders = slices.Compact(response.Folders)
+	return response, nil
+}
+
+func (uc *EntryUC) GetTags(ctx context.Context) (response entities.GetEntryTagsResponse, err error) {
+	if response.Tags, err = uc.entryRepo.GetTags(ctx); err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to get tags: %w", err)
+	}
+	return response, nil
+}
+
+func (uc *EntryUC) toResponse(entries []entities.Entry) (response entities.GetEntriesResponse, err error) {
 	var (
 		result    = make([]entities.GetEntryResponse, len(entries))
 		decrypted []byte
@@ -94,22 +177,28 @@ func (uc *EntryUC) GetAll(ctx context.Context) (response entities.GetEntriesResp
 		if data, err = uc.marshaler.Unmarshal(v.Type, decrypted); err != nil {
 			return response, fmt.Errorf("entry_usecase: failed to unmarshal entry: %w", err)
 		}
-		result[i] = entities.GetEntryResponse{
-			ID:            v.ID,
-			Key:           v.Key,
-			Type:          v.Type,
-			Data:          data,
-			Meta:          v.Meta,
-			Version:       v.Version,
-			GlobalVersion: v.GlobalVersion,
-			CreatedAt:     v.CreatedAt,
-			UpdatedAt:     v.UpdatedAt,
-		}
+		result[i] = uc.toEntryResponse(v, data)
 	}
 	response.Entries = result
 	return response, nil
 }
 
+func (uc *EntryUC) toEntryResponse(entry entities.Entry, data entities.EntryData) entities.GetEntryResponse {
+	return entities.GetEntryResponse{
+		ID:            entry.ID,
+		Key:           entry.Key,
+		Type:          entry.Type,
+		Data:          data,
+		Meta:          entry.Meta,
+		Folder:        entry.Folder,
+		Tags:          entry.Tags,
+		Version:       entry.Version,
+		GlobalVersion: entry.GlobalVersion,
+		CreatedAt:     entry.CreatedAt,
+		UpdatedAt:     entry.UpdatedAt,
+	}
+}
+
 func (uc *EntryUC) Create(
 	ctx context.Context,
 	request entities.CreateEntryRequest,
@@ -134,7 +223,12 @@ func (uc *EntryUC) Create(
 		uc.logger.Error("failed to encrypt entry data", zap.Error(err))
 		return response, fmt.Errorf("entry_usecase: %w", err)
 	}
+	if request.ID != uuid.Nil {
+		entry.ID = request.ID
+	}
 	entry.Meta = request.Meta
+	entry.Folder = core.NormalizeFolder(request.Folder)
+	entry.Tags = core.NormalizeTags(request.Tags)
 	entry.Data = encrypted
 	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
 		err = uc.entryRepo.Create(ctx, *entry)
@@ -171,6 +265,9 @@ func (uc *EntryUC) Update(
 		case err != nil:
 			return fmt.Errorf("entry_usecase: failed to get entry: %w", err)
 		}
+		if err = entities.ValidateEntryData(entry.Type, request.Data); err != nil {
+			return fmt.Errorf("entry_usecase: invalid request: %w", err)
+		}
 		var (
 			data      []byte
 			encrypted []byte
@@ -183,6 +280,8 @@ func (uc *EntryUC) Update(
 		}
 		if err = entry.Update(
 			entities.UpdateEntryMeta(request.Meta),
+			entities.UpdateEntryFolder(request.Folder),
+			entities.UpdateEntryTags(request.Tags),
 			entities.UpdateEntryData(data)); err != nil {
 			return fmt.Errorf("entry_usecase: %w", err)
 		}
@@ -190,10 +289,7 @@ func (uc *EntryUC) Update(
 		if err = uc.entryRepo.Update(ctx, entry); err != nil {
 			return fmt.Errorf("entry_usecase: failed to update entry in repo: %w", err)
 		}
-		if err = uc.entrySyncRepo.Create(ctx, entities.EntrySync{
-			ID:        entry.ID,
-			CreatedAt: time.Now().UTC(),
-		}); err != nil {
+		if err = uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(entry.ID)); err != nil {
 			return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
 		}
 		return nil
@@ -224,6 +320,10 @@ func (uc *EntryUC) Delete(
 }
 
 func (uc *EntryUC) Sync(ctx context.Context) (err error) {
+	// manual and background syncs must not push the same changes twice
+	uc.syncMu.Lock()
+	defer uc.syncMu.Unlock()
+
 	if ctx, err = uc.appendToken(ctx); err != nil {
 		return err
 	}
@@ -238,119 +338,38 @@ func (uc *EntryUC) Sync(ctx context.Context) (err error) {
 	return nil
 }
 
-func (uc *EntryUC) pushEntries(ctx context.Context) error {
-	result, err := uc.entrySyncRepo.GetAll(ctx)
-	if err != nil {
-		return fmt.Errorf("entry_usecase: failed to get entry syncs: %w", err)
-	}
-	for _, v := range result {
-		if err = uc.pushEntry(ctx, v.ID); err != nil {
-			uc.logger.Error("failed to push entry", zap.Error(err))
-			continue
-		}
-		if err = uc.entrySyncRepo.Delete(ctx, v.ID); err != nil {
-			uc.logger.Error("failed to delete entry sync", zap.Error(err))
-		}
-	}
-	return nil
-}
-
-func (uc *EntryUC) pushEntry(ctx context.Context, id uuid.UUID) error {
-	type pushType int
-	const (
-		pushTypeNone pushType = iota
-		pushTypeCreate
-		pushTypeUpdate
-		pushTypeDelete
-	)
-
-	getPushType := func(entry entities.Entry, err error) pushType {
-		switch {
-		case errors.Is(err, entities.ErrEntryNotFound):
-			return pushTypeDelete
-		case err != nil:
-			return pushTypeNone
-		case entry.GlobalVersion == 0:
-			return pushTypeCreate
-		default:
-			return pushTypeUpdate
-		}
-	}
-
-	entry, err := uc.entryRepo.Get(ctx, id)
+// fetch applies server changes since the stored cursor and falls back to the full diff
+// when the cursor is unknown or can't be served by the server anymore.
+// Entries that can't be decrypted are replaced on the server with the local copy,
+// the ones without a local copy are skipped and reported.
+func (uc *EntryUC) fetch(ctx context.Context) (err error) {
 	var (
-		typ       = getPushType(entry, err)
-		decrypted []byte
+		reset   = true
+		skipped int
 	)
-	switch typ {
-	case pushTypeCreate:
-		if decrypted, err = uc.encrypter.Decrypt(entry.Data); err != nil {
-			return fmt.Errorf("entry_usecase: failed to decrypt entry data: %w", err)
+	if cursor, ok := uc.getCursor(); ok {
+		if reset, skipped, err = uc.fetchChanges(ctx, cursor); err != nil {
+			return err
 		}
-		_, err = uc.entryClient.Create(ctx, &pb.CreateEntryRequest{
-			Key:  entry.Key,
-			Type: uc.mapper.ToAPIType(entry.Type),
-			Meta: entry.Meta,
-			Data: decrypted,
-		})
-		switch {
-		case status.Code(err) == codes.InvalidArgument:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrEntryInvalid, err)
-		case status.Code(err) == codes.AlreadyExists:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrEntryExists, err)
-		case status.Code(err) == codes.Unavailable:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrServerUnavailable, err)
-		case status.Code(err) == codes.Unauthenticated:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrUserTokenInvalid, err)
-		case err != nil:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w", err)
+		if reset {
+			uc.logger.Debug("entries cursor is reset, fetching full diff")
 		}
-	case pushTypeUpdate:
-		if decrypted, err = uc.encrypter.Decrypt(entry.Data); err != nil {
-			return fmt.Errorf("entry_usecase: failed to decrypt entry data: %w", err)
-		}
-		_, err = uc.entryClient.Update(ctx, &pb.UpdateEntryRequest{
-			Id:      id.String(),
-			Meta:    entry.Meta,
-			Data:    decrypted,
-			Version: entry.GlobalVersion,
-		})
-		switch {
-		case status.Code(err) == codes.InvalidArgument:
-			return fmt.Errorf("entry_usecase: failed to update entry: %w: %w", entities.ErrEntryInvalid, err)
-		case status.Code(err) == codes.NotFound:
-			return fmt.Errorf("entry_usecase: failed to update entry: %w: %w", entities.ErrEntryNotFound, err)
-		case status.Code(err) == codes.Unavailable:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrServerUnavailable, err)
-		case status.Code(err) == codes.Unauthenticated:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrUserTokenInvalid, err)
-		case err != nil:
-			return fmt.Errorf("entry_usecase: failed to update entry: %w", err)
-		}
-	case pushTypeDelete:
-		_, err = uc.entryClient.Delete(ctx, &pb.DeleteEntryRequest{Id: id.String()})
-		switch {
-		case status.Code(err) == codes.InvalidArgument:
-			return fmt.Errorf("entry_usecase: failed to delete entry: %w: %w", entities.ErrEntryInvalid, err)
-		case status.Code(err) == codes.NotFound:
-			return fmt.Errorf("entry_usecase: failed to delete entry: %w: %w", entities.ErrEntryNotFound, err)
-		case status.Code(err) == codes.Unavailable:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrServerUnavailable, err)
-		case status.Code(err) == codes.Unauthenticated:
-			return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrUserTokenInvalid, err)
-		case err != nil:
-			return fmt.Errorf("entry_usecase: failed to delete entry: %w", err)
+	}
+	if reset {
+		if skipped, err = uc.fetchDiff(ctx); err != nil {
+			return err
 		}
-	default:
-		return fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	}
+	if skipped != 0 {
+		return fmt.Errorf("entry_usecase: %w: %d entries skipped", entities.ErrEntryUndecryptable, skipped)
 	}
 	return nil
 }
 
-func (uc *EntryUC) fetch(ctx context.Context) error {
+func (uc *EntryUC) fetchDiff(ctx context.Context) (skipped int, err error) {
 	entries, err := uc.entryRepo.GetVersions(ctx)
 	if err != nil {
-		return fmt.Errorf("entry_usecase: failed to get entry versions: %w", err)
+		return 0, fmt.Errorf("entry_usecase: failed to get entry versions: %w", err)
 	}
 	versions := make([]*pb.EntryVersion, len(entries))
 	for i, v := range entries {
@@ -362,11 +381,11 @@ func (uc *EntryUC) fetch(ctx context.Context) error {
 	resp, err := uc.entryClient.GetDiff(ctx, &pb.GetEntriesDiffRequest{Versions: versions})
 	switch {
 	case status.Code(err) == codes.Unavailable:
-		return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrServerUnavailable, err)
+		return 0, fmt.Errorf("entry_usecase: failed to get diff: %w: %w", entities.ErrServerUnavailable, err)
 	case status.Code(err) == codes.Unauthenticated:
-		return fmt.Errorf("entry_usecase: failed to create entry: %w: %w", entities.ErrUserTokenInvalid, err)
+		return 0, fmt.Errorf("entry_usecase: failed to get diff: %w: %w", entities.ErrUserTokenInvalid, err)
 	case err != nil:
-		return fmt.Errorf("entry_usecase: failed to get diff: %w", err)
+		return 0, fmt.Errorf("entry_usecase: failed to get diff: %w", err)
 	}
 	entryMap := make(map[string]*pb.Entry)
 	for _, v := range resp.Entries {
@@ -374,87 +393,166 @@ func (uc *EntryUC) fetch(ctx context.Context) error {
 	}
 
 	for _, id := range resp.DeleteIds {
-		if err = uc.entryRepo.Delete(ctx, uuid.MustParse(id)); err != nil {
+		if err = uc.applyDelete(ctx, uuid.MustParse(id)); err != nil {
 			uc.logger.Error("failed to delete entry", zap.Error(err))
 		}
 	}
 
-	for _, v := range resp.CreateIds {
-		mentry, ok := entryMap[v]
+	for _, id := range slices.Concat(resp.CreateIds, resp.UpdateIds) {
+		mentry, ok := entryMap[id]
 		if !ok {
 			continue
 		}
-		now := time.Now().UTC()
-		encrypted, err := uc.encrypter.Encrypt(mentry.Data)
-		if err != nil {
-			return fmt.Errorf("entry_usecase: failed to encrypt entry data: %w", err)
-		}
-		entry := entities.Entry{
-			ID:            uuid.MustParse(mentry.Id),
-			Key:           mentry.Key,
-			Type:          uc.toEntityType(mentry.Type),
-			Meta:          mentry.Meta,
-			Data:          encrypted,
-			GlobalVersion: mentry.Version,
-			Version:       mentry.Version,
-			CreatedAt:     now,
-			UpdatedAt:     now,
-		}
-		if err = uc.entryRepo.Create(ctx, entry); err != nil {
-			return fmt.Errorf("entry_usecase: failed to create entry: %w", err)
+		err = uc.applyChange(ctx, mentry)
+		switch {
+		case errors.Is(err, entities.ErrEntryUndecryptable):
+			uc.logger.Warn("entry skipped", zap.String("entry_id", mentry.Id), zap.Error(err))
+			skipped++
+		case err != nil:
+			return 0, err
 		}
 	}
 
-	for _, v := range resp.UpdateIds {
-		mentry, ok := entryMap[v]
-		if !ok {
-			continue
+	uc.setCursor(resp.Cursor)
+	return skipped, nil
+}
+
+// toLocalEntry maps the server entry to the local one encrypted with the local key
+func (uc *EntryUC) toLocalEntry(mentry *pb.Entry) (entities.Entry, error) {
+	encrypted, err := uc.unseal(mentry.Data)
+	if err != nil {
+		return entities.Entry{}, err
+	}
+	now := time.Now().UTC()
+	return entities.Entry{
+		ID:            uuid.MustParse(mentry.Id),
+		Key:           mentry.Key,
+		Type:          uc.toEntityType(mentry.Type, mentry.TypeName),
+		Meta:          mentry.Meta,
+		Folder:        mentry.Folder,
+		Tags:          mentry.Tags,
+		Data:          encrypted,
+		GlobalVersion: mentry.Version,
+		Version:       mentry.Version,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// repushEntry queues the local copy to replace the server version that can't be decrypted,
+// e.g. sealed by the server before end-to-end encryption
+func (uc *EntryUC) repushEntry(ctx context.Context, local entities.Entry, version int64) error {
+	uc.logger.Warn("entry can't be decrypted, local copy is pushed", zap.String("entry_id", local.ID.String()))
+	local.GlobalVersion = version
+	if err := uc.entryRepo.Update(ctx, local); err != nil {
+		return fmt.Errorf("entry_usecase: failed to update entry: %w", err)
+	}
+	if err := uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(local.ID)); err != nil {
+		return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
+	}
+	return nil
+}
+
+// rekey records the server version of the created entry, the entry is replaced with
+// the one under the server ID if the server didn't keep the local one,
+// so incremental changes of the created entry are applied to it.
+// The entry changed during the push keeps its local changes for the next push.
+func (uc *EntryUC) rekey(ctx context.Context, pushed entities.Entry, serverID string, version int64) error {
+	id, err := uuid.Parse(serverID)
+	if err != nil {
+		return fmt.Errorf("entry_usecase: invalid created entry id: %w", err)
+	}
+	return uc.tx.Do(ctx, func(ctx context.Context) error {
+		entry, err := uc.entryRepo.Get(ctx, pushed.ID)
+		switch {
+		case errors.Is(err, entities.ErrEntryNotFound) && id == pushed.ID:
+			return nil
+		// the entry deleted during the push is deleted on the server under its server ID
+		case errors.Is(err, entities.ErrEntryNotFound):
+			if err = uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(id)); err != nil {
+				return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("entry_usecase: failed to get created entry: %w", err)
 		}
-		encrypted, err := uc.encrypter.Encrypt(mentry.Data)
-		if err != nil {
-			return fmt.Errorf("entry_usecase: failed to encrypt entry data: %w", err)
+		changed := entry.Version != pushed.Version
+		entry.GlobalVersion = version
+		if !changed {
+			entry.Version = version
 		}
-		now := time.Now().UTC()
-		entry := entities.Entry{
-			ID:            uuid.MustParse(mentry.Id),
-			Key:           mentry.Key,
-			Type:          uc.toEntityType(mentry.Type),
-			Meta:          mentry.Meta,
-			Data:          encrypted,
-			GlobalVersion: mentry.Version,
-			Version:       mentry.Version,
-			UpdatedAt:     now,
+		if id == entry.ID {
+			if err = uc.entryRepo.Update(ctx, entry); err != nil {
+				return fmt.Errorf("entry_usecase: failed to update created entry version: %w", err)
+			}
+			return nil
 		}
-		if err = uc.entryRepo.Update(ctx, entry); err != nil {
-			return fmt.Errorf("entry_usecase: failed to update entry: %w", err)
+		if err = uc.entryRepo.Delete(ctx, entry.ID); err != nil {
+			return fmt.Errorf("entry_usecase: failed to delete created entry: %w", err)
+		}
+		entry.ID = id
+		if err = uc.entryRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("entry_usecase: failed to create entry with server id: %w", err)
+		}
+		if !changed {
+			return nil
+		}
+		if err = uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(id)); err != nil {
+			return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
 		}
+		return nil
+	})
+}
+
+// seal re-encrypts locally stored data with the vault key, so the server never sees plaintext
+func (uc *EntryUC) seal(data []byte) ([]byte, error) {
+	decrypted, err := uc.encrypter.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("entry_usecase: failed to decrypt entry data: %w", err)
+	}
+	sealed, err := uc.vault.Encrypt(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("entry_usecase: failed to seal entry data: %w", err)
 	}
+	return sealed, nil
+}
 
-	return nil
+// unseal re-encrypts data received from the server with the local key
+func (uc *EntryUC) unseal(data []byte) ([]byte, error) {
+	decrypted, err := uc.vault.Decrypt(data)
+	switch {
+	case errors.Is(err, entities.ErrUserVaultLocked):
+		return nil, err
+	// the data is sealed with another vault key or by the server before end-to-end encryption
+	case err != nil:
+		return nil, fmt.Errorf("entry_usecase: failed to unseal entry data: %w: %w", entities.ErrEntryUndecryptable, err)
+	}
+	encrypted, err := uc.encrypter.Encrypt(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("entry_usecase: failed to encrypt entry data: %w", err)
+	}
+	return encrypted, nil
 }
 
 func (uc *EntryUC) appendToken(ctx context.Context) (context.Context, error) {
-	token, ok := uc.cache.GetString("token")
-	if !ok {
-		return nil, entities.ErrUserTokenNotFound
-	}
-	ctx = metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV(token)...)
-	return ctx, nil
+	return appendToken(ctx, uc.cache)
 }
 
 func (uc *EntryUC) toEntity(entry *pb.Entry) entities.Entry {
 	return entities.Entry{
 		ID:      uuid.MustParse(entry.Id),
 		Key:     entry.Key,
-		Type:    uc.toEntityType(entry.Type),
+		Type:    uc.toEntityType(entry.Type, entry.TypeName),
 		Meta:    entry.Meta,
+		Folder:  entry.Folder,
+		Tags:    entry.Tags,
 		Data:    entry.Data,
 		Version: entry.Version,
 	}
 }
 
-func (uc *EntryUC) toEntityType(t pb.EntryType) core.EntryType {
-	return uc.mapper.ToEntityType(t)
+func (uc *EntryUC) toEntityType(t pb.EntryType, name string) core.EntryType {
+	return uc.mapper.ToEntityType(t, name)
 }
 
 func (uc *EntryUC) toAPIType(t core.EntryType) pb.EntryType {
@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/backup"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ExportBackup writes all entries into the passphrase-encrypted backup archive,
+// files of the blob entries are downloaded from the server into the archive
+func (uc *EntryUC) ExportBackup(
+	ctx context.Context,
+	request entities.ExportBackupRequest,
+) (response entities.ExportBackupResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	entries, err := uc.GetAll(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	archive := backup.Archive{
+		CreatedAt: time.Now().UTC(),
+		Entries:   make([]backup.Entry, 0, len(entries.Entries)),
+	}
+	for _, v := range entries.Entries {
+		data, err := uc.marshaler.Marshal(v.Data)
+		if err != nil {
+			return response, fmt.Errorf("entry_usecase: failed to marshal entry data: %w", err)
+		}
+		entry := backup.Entry{
+			Key:       v.Key,
+			Type:      v.Type,
+			Meta:      v.Meta,
+			Folder:    v.Folder,
+			Tags:      v.Tags,
+			Data:      data,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		}
+		if v.HasBlob() {
+			if entry.Blob, err = uc.exportBlob(ctx, v); err != nil {
+				return response, err
+			}
+		}
+		archive.Entries = append(archive.Entries, entry)
+		response.Keys = append(response.Keys, v.Key)
+	}
+
+	file, err := os.OpenFile(filepath.Clean(request.Path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: %w: %w", entities.ErrBackupPathInvalid, err)
+	}
+	if err = backup.Write(file, request.Passphrase, archive); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		uc.logger.Error("failed to write backup", zap.Error(err))
+		return response, fmt.Errorf("entry_usecase: %w", err)
+	}
+	if err = file.Close(); err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to close backup: %w", err)
+	}
+	return response, nil
+}
+
+// RestoreBackup creates entries from the backup archive, the vault should be empty unless merge is requested.
+// On merge entries with existing keys are not changed, they are reported as unchanged or conflicting.
+func (uc *EntryUC) RestoreBackup(
+	ctx context.Context,
+	request entities.RestoreBackupRequest,
+) (response entities.RestoreBackupResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	content, err := os.ReadFile(filepath.Clean(request.Path))
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: %w: %w", entities.ErrBackupPathInvalid, err)
+	}
+	archive, err := backup.Read(bytes.NewReader(content), request.Passphrase)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: %w", err)
+	}
+	existing, err := uc.GetAll(ctx)
+	if err != nil {
+		return response, err
+	}
+	if len(existing.Entries) != 0 && !request.Merge {
+		return response, fmt.Errorf("entry_usecase: %w: %d entries", entities.ErrBackupVaultNotEmpty, len(existing.Entries))
+	}
+	byKey := make(map[string]entities.GetEntryResponse, len(existing.Entries))
+	for _, v := range existing.Entries {
+		byKey[v.Key] = v
+	}
+
+	for _, v := range archive.Entries {
+		if prev, ok := byKey[v.Key]; ok {
+			equal, err := uc.archivedEqual(prev, v)
+			switch {
+			case err != nil:
+				return response, err
+			case equal:
+				response.Unchanged = append(response.Unchanged, v.Key)
+			default:
+				response.Conflicts = append(response.Conflicts, v.Key)
+			}
+			continue
+		}
+		if err = uc.restoreEntry(ctx, v); err != nil {
+			uc.logger.Error("failed to restore entry", zap.String("key", v.Key), zap.Error(err))
+			return response, fmt.Errorf("entry_usecase: failed to restore entry %q: %w", v.Key, err)
+		}
+		response.Created = append(response.Created, v.Key)
+	}
+	return response, nil
+}
+
+func (uc *EntryUC) exportBlob(ctx context.Context, entry entities.GetEntryResponse) ([]byte, error) {
+	blob, err := entities.NewEntryBlob(entry.Data.(entities.EntryDataBinary))
+	if err != nil {
+		return nil, fmt.Errorf("entry_usecase: %w", err)
+	}
+	if ctx, err = uc.appendToken(ctx); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = uc.downloadBlob(ctx, blob, &buf); err != nil {
+		uc.logger.Error("failed to download blob", zap.String("key", entry.Key), zap.Error(err))
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// restoreEntry creates the archived entry, the blob file is uploaded as a new blob under the entry ID
+func (uc *EntryUC) restoreEntry(ctx context.Context, entry backup.Entry) (err error) {
+	id := uuid.New()
+	meta := maps.Clone(entry.Meta)
+	var data entities.EntryData
+	if entry.Blob == nil {
+		if data, err = uc.marshaler.Unmarshal(entry.Type, entry.Data); err != nil {
+			return fmt.Errorf("%w: %w", entities.ErrBackupInvalid, err)
+		}
+	} else {
+		if data, err = uc.restoreBlob(ctx, id, entry.Blob); err != nil {
+			return err
+		}
+		if meta == nil {
+			meta = make(map[string]string)
+		}
+		meta[entities.EntryMetaBlobSize] = strconv.Itoa(len(entry.Blob))
+	}
+	_, err = uc.Create(ctx, entities.CreateEntryRequest{
+		ID:     id,
+		Key:    entry.Key,
+		Type:   entry.Type,
+		Meta:   meta,
+		Folder: entry.Folder,
+		Tags:   entry.Tags,
+		Data:   data,
+	})
+	return err
+}
+
+func (uc *EntryUC) restoreBlob(ctx context.Context, id uuid.UUID, content []byte) (entities.EntryDataBinary, error) {
+	tokenCtx, err := uc.appendToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blob := entities.EntryBlob{
+		ID:  id,
+		Key: make([]byte, entities.EntryBlobKeySize),
+	}
+	if _, err = rand.Read(blob.Key); err != nil {
+		return nil, fmt.Errorf("entry_usecase: failed to generate blob key: %w", err)
+	}
+	if blob.Size, blob.Checksum, err = uc.uploadBlob(tokenCtx, blob, bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+	return blob.Data()
+}
+
+// archivedEqual compares the existing entry with the archived one,
+// blob entries are equal when their files have the same checksum
+func (uc *EntryUC) archivedEqual(prev entities.GetEntryResponse, entry backup.Entry) (bool, error) {
+	if prev.Type != entry.Type || prev.Folder != entry.Folder || !slices.Equal(prev.Tags, entry.Tags) {
+		return false, nil
+	}
+	if entry.Blob != nil {
+		if !prev.HasBlob() {
+			return false, nil
+		}
+		prevBlob, err := entities.NewEntryBlob(prev.Data.(entities.EntryDataBinary))
+		if err != nil {
+			return false, fmt.Errorf("entry_usecase: %w", err)
+		}
+		blob, err := entities.NewEntryBlob(entry.Data)
+		if err != nil {
+			return false, fmt.Errorf("entry_usecase: %w: %w", entities.ErrBackupInvalid, err)
+		}
+		return prevBlob.Checksum == blob.Checksum && maps.Equal(prev.Meta, entry.Meta), nil
+	}
+	data, err := uc.marshaler.Marshal(prev.Data)
+	if err != nil {
+		return false, fmt.Errorf("entry_usecase: failed to marshal entry data: %w", err)
+	}
+	return bytes.Equal(data, entry.Data) && maps.Equal(prev.Meta, entry.Meta), nil
+}
@@ -0,0 +1,138 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/backup"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestEntryUC_Backup(t *testing.T) {
+	ctx := context.Background()
+	source := newEntryEnv(t, "backup_source").newEntryUC(t, nil)
+	for _, v := range []entities.CreateEntryRequest{
+		{
+			Key:    "db",
+			Type:   core.EntryTypePassword,
+			Meta:   map[string]string{"env": "prod"},
+			Folder: "Work/Servers",
+			Tags:   []string{"prod"},
+			Data:   entities.EntryDataPassword{Login: "admin", Password: "1"},
+		},
+		{Key: "todo", Type: core.EntryTypeNote, Data: entities.EntryDataNote("buy milk")},
+		{Key: "key", Type: core.EntryTypeBinary, Data: entities.EntryDataBinary{0, 1, 2}},
+		{Key: "visa", Type: core.EntryTypeCard, Data: entities.EntryDataCard{Number: "4111"}},
+	} {
+		_, err := source.Create(ctx, v)
+		require.NoError(t, err, "failed to create entry")
+	}
+	path := filepath.Join(t.TempDir(), "vault.backup")
+	passphrase := core.Pass("passphrase")
+
+	_, err := source.ExportBackup(ctx, entities.ExportBackupRequest{Path: path})
+	require.ErrorIs(t, err, entities.ErrBackupPassInvalid)
+	exported, err := source.ExportBackup(ctx, entities.ExportBackupRequest{Path: path, Passphrase: passphrase})
+	require.NoError(t, err, "failed to export")
+	require.Len(t, exported.Keys, 4)
+
+	// restore into the vault with another key
+	env := newEntryEnv(t, "backup_target")
+	env.encrypter, env.vault = env.vault, env.encrypter
+	target := env.newEntryUC(t, nil)
+	_, err = target.RestoreBackup(ctx, entities.RestoreBackupRequest{Path: path, Passphrase: core.Pass("invalid")})
+	require.ErrorIs(t, err, entities.ErrBackupPassInvalid)
+	restored, err := target.RestoreBackup(ctx, entities.RestoreBackupRequest{Path: path, Passphrase: passphrase})
+	require.NoError(t, err, "failed to restore")
+	require.Len(t, restored.Created, 4)
+	require.Equal(t, entryValues(t, source), entryValues(t, target), "restored entries should be equal")
+
+	_, err = target.RestoreBackup(ctx, entities.RestoreBackupRequest{Path: path, Passphrase: passphrase})
+	require.ErrorIs(t, err, entities.ErrBackupVaultNotEmpty)
+
+	entries, err := target.GetAll(ctx)
+	require.NoError(t, err)
+	for _, v := range entries.Entries {
+		switch v.Key {
+		case "db":
+			require.NoError(t, target.Update(ctx, entities.UpdateEntryRequest{
+				ID:     v.ID,
+				Meta:   v.Meta,
+				Folder: v.Folder,
+				Tags:   v.Tags,
+				Data:   entities.EntryDataPassword{Login: "admin", Password: "2"},
+			}))
+		case "todo":
+			require.NoError(t, target.Delete(ctx, entities.DeleteEntryRequest{ID: v.ID}))
+		}
+	}
+	merged, err := target.RestoreBackup(ctx, entities.RestoreBackupRequest{Path: path, Passphrase: passphrase, Merge: true})
+	require.NoError(t, err, "failed to merge")
+	sort.Strings(merged.Unchanged)
+	require.Equal(t, []string{"todo"}, merged.Created)
+	require.Equal(t, []string{"key", "visa"}, merged.Unchanged)
+	require.Equal(t, []string{"db"}, merged.Conflicts)
+}
+
+func entryValues(t *testing.T, uc *usecases.EntryUC) map[string]entities.CreateEntryRequest {
+	entries, err := uc.GetAll(context.Background())
+	require.NoError(t, err)
+	result := make(map[string]entities.CreateEntryRequest, len(entries.Entries))
+	for _, v := range entries.Entries {
+		result[v.Key] = entities.CreateEntryRequest{
+			Key:    v.Key,
+			Type:   v.Type,
+			Meta:   v.Meta,
+			Folder: v.Folder,
+			Tags:   v.Tags,
+			Data:   v.Data,
+		}
+	}
+	return result
+}
+
+func TestEntryUC_RestoreBlob(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	path := filepath.Join(t.TempDir(), "vault.backup")
+	passphrase := core.Pass("passphrase")
+	file, err := os.Create(path)
+	require.NoError(t, err, "failed to create backup file")
+	require.NoError(t, backup.Write(file, passphrase, backup.Archive{
+		Entries: []backup.Entry{{Key: "blob", Type: core.EntryTypeBinary, Blob: []byte("content")}},
+	}), "failed to write backup")
+	require.NoError(t, file.Close())
+
+	var blobID string
+	upload := mocks.NewMockEntryService_UploadBlobClient(ctrl)
+	upload.EXPECT().Send(gomock.Any()).DoAndReturn(func(in *pb.UploadBlobRequest) error {
+		blobID = in.Id
+		return nil
+	})
+	upload.EXPECT().CloseAndRecv().Return(&pb.UploadBlobResponse{}, nil)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().UploadBlob(gomock.Any()).Return(upload, nil)
+
+	env := newEntryEnv(t, "backup_blob")
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	restored, err := sut.RestoreBackup(ctx, entities.RestoreBackupRequest{Path: path, Passphrase: passphrase})
+	require.NoError(t, err, "failed to restore")
+	require.Equal(t, []string{"blob"}, restored.Created)
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1)
+	require.True(t, entries.Entries[0].HasBlob(), "entry should reference blob")
+	require.Equal(t, entries.Entries[0].ID.String(), blobID, "blob should be stored under entry id")
+}
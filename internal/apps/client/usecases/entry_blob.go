@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// CreateBlob uploads the file to the server blob storage chunk by chunk
+// and creates a binary entry that references the uploaded blob under the same ID,
+// so the server removes the blob with the entry.
+// Every blob is encrypted with its own random key kept inside the entry data.
+func (uc *EntryUC) CreateBlob(
+	ctx context.Context,
+	request entities.CreateBlobEntryRequest,
+) (response entities.CreateEntryResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("upload_blob: invalid request: %w", err)
+	}
+	if ctx, err = uc.appendToken(ctx); err != nil {
+		return response, err
+	}
+	file, err := os.Open(request.Path)
+	if err != nil {
+		return response, fmt.Errorf("upload_blob: %w: %w", entities.ErrEntryBlobPathInvalid, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	blob := entities.EntryBlob{
+		ID:  uuid.New(),
+		Key: make([]byte, entities.EntryBlobKeySize),
+	}
+	if _, err = rand.Read(blob.Key); err != nil {
+		return response, fmt.Errorf("upload_blob: failed to generate blob key: %w", err)
+	}
+	if blob.Size, blob.Checksum, err = uc.uploadBlob(ctx, blob, file); err != nil {
+		uc.logger.Error("failed to upload blob", zap.Error(err))
+		return response, err
+	}
+
+	data, err := blob.Data()
+	if err != nil {
+		return response, fmt.Errorf("upload_blob: %w", err)
+	}
+	meta := maps.Clone(request.Meta)
+	if meta == nil {
+		meta = make(map[string]string)
+	}
+	meta[entities.EntryMetaBlobSize] = strconv.FormatInt(blob.Size, 10)
+	return uc.Create(ctx, entities.CreateEntryRequest{
+		ID:     blob.ID,
+		Key:    request.Key,
+		Type:   core.EntryTypeBinary,
+		Meta:   meta,
+		Folder: request.Folder,
+		Tags:   request.Tags,
+		Data:   data,
+	})
+}
+
+// DownloadBlob streams the blob referenced by the binary entry into the file at request path
+func (uc *EntryUC) DownloadBlob(
+	ctx context.Context,
+	request entities.DownloadBlobRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("download_blob: invalid request: %w", err)
+	}
+	blob, err := uc.getBlob(ctx, request.ID)
+	if err != nil {
+		return err
+	}
+	if ctx, err = uc.appendToken(ctx); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Clean(request.Path))
+	if err != nil {
+		return fmt.Errorf("download_blob: %w: %w", entities.ErrEntryBlobPathInvalid, err)
+	}
+	if err = uc.downloadBlob(ctx, blob, file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		uc.logger.Error("failed to download blob", zap.Error(err))
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("download_blob: failed to close file: %w", err)
+	}
+	return nil
+}
+
+func (uc *EntryUC) uploadBlob(
+	ctx context.Context,
+	blob entities.EntryBlob,
+	r io.Reader,
+) (size int64, checksum string, err error) {
+	encrypter, err := encrypto.NewEncrypter(blob.Key)
+	if err != nil {
+		return 0, "", fmt.Errorf("upload_blob: %w", err)
+	}
+	stream, err := uc.entryClient.UploadBlob(ctx)
+	if err != nil {
+		return 0, "", uc.blobError("upload_blob", err)
+	}
+
+	var (
+		hash  = sha256.New()
+		chunk = make([]byte, entities.EntryBlobChunkSize)
+		n     int
+	)
+	for {
+		n, err = io.ReadFull(r, chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, "", fmt.Errorf("upload_blob: failed to read file: %w", err)
+		}
+		hash.Write(chunk[:n])
+		size += int64(n)
+
+		encrypted, err := encrypter.Encrypt(chunk[:n])
+		if err != nil {
+			return 0, "", fmt.Errorf("upload_blob: failed to encrypt chunk: %w", err)
+		}
+		if err = stream.Send(&pb.UploadBlobRequest{
+			Id:    blob.ID.String(),
+			Chunk: encrypted,
+		}); err != nil {
+			// the actual error is returned by CloseAndRecv
+			break
+		}
+	}
+	if size == 0 {
+		return 0, "", fmt.Errorf("upload_blob: %w: empty file", entities.ErrEntryBlobPathInvalid)
+	}
+	if _, err = stream.CloseAndRecv(); err != nil {
+		return 0, "", uc.blobError("upload_blob", err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (uc *EntryUC) downloadBlob(
+	ctx context.Context,
+	blob entities.EntryBlob,
+	w io.Writer,
+) error {
+	encrypter, err := encrypto.NewEncrypter(blob.Key)
+	if err != nil {
+		return fmt.Errorf("download_blob: %w", err)
+	}
+	stream, err := uc.entryClient.DownloadBlob(ctx, &pb.DownloadBlobRequest{Id: blob.ID.String()})
+	if err != nil {
+		return uc.blobError("download_blob", err)
+	}
+
+	hash := sha256.New()
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return uc.blobError("download_blob", err)
+		}
+		chunk, err := encrypter.Decrypt(resp.Chunk)
+		if err != nil {
+			return fmt.Errorf("download_blob: failed to decrypt chunk: %w", err)
+		}
+		hash.Write(chunk)
+		if _, err = w.Write(chunk); err != nil {
+			return fmt.Errorf("download_blob: failed to write file: %w", err)
+		}
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != blob.Checksum {
+		return fmt.Errorf("download_blob: %w", entities.ErrEntryBlobCorrupted)
+	}
+	return nil
+}
+
+func (uc *EntryUC) getBlob(ctx context.Context, id uuid.UUID) (blob entities.EntryBlob, err error) {
+	entry, err := uc.entryRepo.Get(ctx, id)
+	switch {
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return blob, fmt.Errorf("download_blob: %w", err)
+	case err != nil:
+		return blob, fmt.Errorf("download_blob: failed to get entry: %w", err)
+	}
+	if entry.Type != core.EntryTypeBinary {
+		return blob, fmt.Errorf("download_blob: %w: %s", entities.ErrEntryTypeInvalid, entry.Type)
+	}
+	decrypted, err := uc.encrypter.Decrypt(entry.Data)
+	if err != nil {
+		return blob, fmt.Errorf("download_blob: failed to decrypt entry: %w", err)
+	}
+	if blob, err = entities.NewEntryBlob(decrypted); err != nil {
+		return blob, fmt.Errorf("download_blob: %w", err)
+	}
+	return blob, nil
+}
+
+func (uc *EntryUC) blobError(prefix string, err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("%s: %w: %w", prefix, entities.ErrEntryBlobInvalid, err)
+	case codes.NotFound:
+		return fmt.Errorf("%s: %w: %w", prefix, entities.ErrEntryBlobNotFound, err)
+	case codes.Aborted:
+		return fmt.Errorf("%s: %w: %w", prefix, entities.ErrEntryConflict, err)
+	case codes.Unavailable:
+		return fmt.Errorf("%s: %w: %w", prefix, entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%s: %w: %w", prefix, entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+}
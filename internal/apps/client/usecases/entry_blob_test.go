@@ -0,0 +1,97 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestEntryUC_Blob(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "blob_test")
+
+	dir := t.TempDir()
+	content := make([]byte, entities.EntryBlobChunkSize*2+100)
+	_, err := rand.Read(content)
+	require.NoError(t, err, "failed to generate content")
+	path := filepath.Join(dir, "blob.bin")
+	require.NoError(t, os.WriteFile(path, content, 0600), "failed to write file")
+
+	var (
+		chunks [][]byte
+		blobID string
+	)
+	upload := mocks.NewMockEntryService_UploadBlobClient(ctrl)
+	upload.EXPECT().Send(gomock.Any()).Times(3).DoAndReturn(func(in *pb.UploadBlobRequest) error {
+		require.False(t, bytes.Contains(content, in.Chunk[:64]), "chunk should be encrypted")
+		chunks = append(chunks, in.Chunk)
+		blobID = in.Id
+		return nil
+	})
+	upload.EXPECT().CloseAndRecv().Return(&pb.UploadBlobResponse{}, nil)
+
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().UploadBlob(gomock.Any()).Return(upload, nil)
+	client.EXPECT().DownloadBlob(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(context.Context, *pb.DownloadBlobRequest, ...grpc.CallOption) (pb.EntryService_DownloadBlobClient, error) {
+			download := mocks.NewMockEntryService_DownloadBlobClient(ctrl)
+			var calls []any
+			for _, v := range chunks {
+				calls = append(calls, download.EXPECT().Recv().Return(&pb.DownloadBlobResponse{Chunk: v}, nil))
+			}
+			calls = append(calls, download.EXPECT().Recv().Return(nil, io.EOF))
+			gomock.InOrder(calls...)
+			return download, nil
+		})
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	created, err := sut.CreateBlob(ctx, entities.CreateBlobEntryRequest{
+		Key:  "blob",
+		Meta: map[string]string{"filename": "blob.bin"},
+		Path: path,
+	})
+	require.NoError(t, err, "failed to create blob entry")
+	require.Len(t, chunks, 3, "unexpected chunk count")
+	require.Equal(t, created.ID.String(), blobID, "blob should be stored under entry id")
+
+	getAll, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, getAll.Entries, 1, "unexpected entries count")
+	entry := getAll.Entries[0]
+	require.Equal(t, created.ID, entry.ID, "entry id mismatch")
+	require.Equal(t, core.EntryTypeBinary, entry.Type, "entry type mismatch")
+	require.True(t, entry.HasBlob(), "entry should reference blob")
+	require.Equal(t, strconv.Itoa(len(content)), entry.Meta[entities.EntryMetaBlobSize], "blob size mismatch")
+
+	// download
+	downloaded := filepath.Join(dir, "downloaded.bin")
+	err = sut.DownloadBlob(ctx, entities.DownloadBlobRequest{ID: entry.ID, Path: downloaded})
+	require.NoError(t, err, "failed to download blob")
+	got, err := os.ReadFile(downloaded)
+	require.NoError(t, err, "failed to read downloaded file")
+	require.True(t, bytes.Equal(content, got), "downloaded content mismatch")
+
+	// corrupted
+	chunks = chunks[:2]
+	corrupted := filepath.Join(dir, "corrupted.bin")
+	err = sut.DownloadBlob(ctx, entities.DownloadBlobRequest{ID: entry.ID, Path: corrupted})
+	require.ErrorIs(t, err, entities.ErrEntryBlobCorrupted, "expected checksum mismatch")
+	require.NoFileExists(t, corrupted, "corrupted file should be removed")
+}
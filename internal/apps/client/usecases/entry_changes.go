@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+)
+
+const (
+	cacheKeyEntriesCursor = "entries_cursor"
+	entryChangesPageSize  = 100
+)
+
+// fetchChanges applies server changes page by page, the cursor is moved after every applied page.
+// reset reports that the cursor is stale and the full diff is required.
+func (uc *EntryUC) fetchChanges(ctx context.Context, cursor int64) (reset bool, skipped int, err error) {
+	for {
+		resp, err := uc.entryClient.GetChangesSince(ctx, &pb.GetChangesSinceRequest{
+			Cursor: cursor,
+			Limit:  entryChangesPageSize,
+		})
+		switch {
+		// server without change cursors
+		case status.Code(err) == codes.Unimplemented:
+			return true, skipped, nil
+		case status.Code(err) == codes.Unavailable:
+			return false, 0, fmt.Errorf("entry_usecase: failed to get changes: %w: %w", entities.ErrServerUnavailable, err)
+		case status.Code(err) == codes.Unauthenticated:
+			return false, 0, fmt.Errorf("entry_usecase: failed to get changes: %w: %w", entities.ErrUserTokenInvalid, err)
+		case err != nil:
+			return false, 0, fmt.Errorf("entry_usecase: failed to get changes: %w", err)
+		}
+		if resp.Reset_ {
+			return true, skipped, nil
+		}
+		if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+			for _, v := range resp.Entries {
+				err := uc.applyChange(ctx, v)
+				switch {
+				case errors.Is(err, entities.ErrEntryUndecryptable):
+					uc.logger.Warn("entry skipped", zap.String("entry_id", v.Id), zap.Error(err))
+					skipped++
+				case err != nil:
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return false, 0, err
+		}
+		cursor = resp.Cursor
+		uc.setCursor(cursor)
+		if !resp.HasMore {
+			return false, skipped, nil
+		}
+	}
+}
+
+// applyChange applies the server change to the local entry,
+// the pending local change of the entry is kept as the conflict or pushed over the server change.
+func (uc *EntryUC) applyChange(ctx context.Context, mentry *pb.Entry) error {
+	id, err := uuid.Parse(mentry.Id)
+	if err != nil {
+		return fmt.Errorf("entry_usecase: invalid changed entry id: %w", err)
+	}
+	if mentry.DeletedAt != 0 {
+		return uc.applyDelete(ctx, id)
+	}
+	sync, pending, err := uc.pendingSync(ctx, id)
+	if err != nil {
+		return err
+	}
+	local, err := uc.entryRepo.Get(ctx, id)
+	switch {
+	// the pending local delete is pushed over the server change
+	case errors.Is(err, entities.ErrEntryNotFound) && pending:
+		uc.logger.Debug("entry change skipped, entry is deleted locally", zap.String("entry_id", mentry.Id))
+	case errors.Is(err, entities.ErrEntryNotFound):
+		entry, err := uc.toLocalEntry(mentry)
+		if err != nil {
+			return err
+		}
+		if err = uc.entryRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("entry_usecase: failed to create entry: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	case local.GlobalVersion != mentry.Version:
+		entry, err := uc.toLocalEntry(mentry)
+		switch {
+		case errors.Is(err, entities.ErrEntryUndecryptable):
+			return uc.repushEntry(ctx, local, mentry.Version)
+		case err != nil:
+			return err
+		}
+		if pending {
+			if err = uc.saveFetchedConflict(ctx, local, entry, sync); err != nil {
+				return err
+			}
+		}
+		if err = uc.entryRepo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("entry_usecase: failed to update entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyDelete removes the entry deleted on the server,
+// the pending local change of the entry is pushed as a new entry instead.
+func (uc *EntryUC) applyDelete(ctx context.Context, id uuid.UUID) error {
+	_, pending, err := uc.pendingSync(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		err = uc.entryRepo.Delete(ctx, id)
+		if err != nil && !errors.Is(err, entities.ErrEntryNotFound) {
+			return fmt.Errorf("entry_usecase: failed to delete entry: %w", err)
+		}
+		return nil
+	}
+	local, err := uc.entryRepo.Get(ctx, id)
+	switch {
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	}
+	uc.logger.Debug("entry is deleted on server, local change is pushed as new entry", zap.String("entry_id", id.String()))
+	local.GlobalVersion = 0
+	if err = uc.entryRepo.Update(ctx, local); err != nil {
+		return fmt.Errorf("entry_usecase: failed to update entry: %w", err)
+	}
+	return nil
+}
+
+// pendingSync returns the local change of the entry that isn't pushed yet
+func (uc *EntryUC) pendingSync(ctx context.Context, id uuid.UUID) (sync entities.EntrySync, ok bool, err error) {
+	sync, err = uc.entrySyncRepo.Get(ctx, id)
+	switch {
+	case errors.Is(err, entities.ErrEntrySyncNotFound):
+		return sync, false, nil
+	case err != nil:
+		return sync, false, fmt.Errorf("entry_usecase: failed to get entry sync: %w", err)
+	}
+	return sync, true, nil
+}
+
+// getCursor returns the stored change cursor, it's kept in memcache and flushed to user_kv
+func (uc *EntryUC) getCursor() (int64, bool) {
+	value, ok := uc.cache.GetString(cacheKeyEntriesCursor)
+	if !ok || value == "" {
+		return 0, false
+	}
+	cursor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return cursor, true
+}
+
+func (uc *EntryUC) setCursor(cursor int64) {
+	uc.cache.SetString(cacheKeyEntriesCursor, strconv.FormatInt(cursor, 10))
+}
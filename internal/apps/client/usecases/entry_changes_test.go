@@ -0,0 +1,445 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/marshal"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestEntryUC_Changes(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "changes_test")
+
+	var (
+		createdID = uuid.NewString()
+		remoteID  = uuid.NewString()
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).
+		Return(&pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{
+			{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: createdID, Version: 1},
+		}}, nil)
+	gomock.InOrder(
+		client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{Cursor: 5}, nil),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 5, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{
+				Entries: []*pb.Entry{
+					{Id: createdID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note1_v2"), Version: 2},
+					{Id: remoteID, Key: "key2", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note2"), Version: 1},
+				},
+				Cursor:  7,
+				HasMore: true,
+			}, nil),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 7, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{
+				Entries: []*pb.Entry{
+					{Id: createdID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note1_v2"), Version: 3, DeletedAt: time.Now().Unix()},
+				},
+				Cursor: 8,
+			}, nil),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 8, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{Reset_: true}, nil),
+		client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{Cursor: 10}, nil),
+	)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	_, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "key1",
+		Type: core.EntryTypeNote,
+		Data: entities.EntryDataNote("note1"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 1)
+	require.Equal(t, createdID, entries.Entries[0].ID.String(), "created entry should get server id")
+	cursor, _ := env.cache.GetString("entries_cursor")
+	require.Equal(t, "5", cursor, "cursor should be taken from full diff")
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entries, err = sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 1, "deleted entry should be removed")
+	require.Equal(t, remoteID, entries.Entries[0].ID.String(), "remote entry should be created")
+	require.Equal(t, entities.EntryDataNote("note2"), entries.Entries[0].Data)
+	cursor, _ = env.cache.GetString("entries_cursor")
+	require.Equal(t, "8", cursor, "cursor should be moved by pages")
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	cursor, _ = env.cache.GetString("entries_cursor")
+	require.Equal(t, "10", cursor, "cursor should be reset by full diff")
+}
+
+func TestEntryUC_CreateClientID(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "client_id_test")
+
+	var (
+		takenID   = uuid.NewString()
+		createdID = uuid.NewString()
+		localID   string
+		sealed    []byte
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+			results := make([]*pb.EntryChangeResult, len(in.Changes))
+			for i, v := range in.Changes {
+				in := v.Create
+				require.NotNil(t, in, "create expected")
+				switch {
+				case in.Key == "key1":
+					localID, sealed = in.Id, in.Data
+					results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: in.Id, Version: 1}
+				case in.Id == takenID:
+					results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_ID_TAKEN}
+				default:
+					require.Empty(t, in.Id, "taken ID should not be sent again")
+					results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: createdID, Version: 1}
+				}
+			}
+			return &pb.ApplyChangesResponse{Results: results}, nil
+		})
+	gomock.InOrder(
+		client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{Cursor: 2}, nil),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 2, Limit: 100}).
+			DoAndReturn(func(_ context.Context, _ *pb.GetChangesSinceRequest, _ ...grpc.CallOption) (*pb.GetChangesSinceResponse, error) {
+				return &pb.GetChangesSinceResponse{
+					Entries: []*pb.Entry{{Id: localID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: sealed, Version: 1}},
+					Cursor:  3,
+				}, nil
+			}),
+	)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "key1",
+		Type: core.EntryTypeNote,
+		Data: entities.EntryDataNote("note1"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	taken, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "key2",
+		Type: core.EntryTypeNote,
+		Data: entities.EntryDataNote("note2"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	takenID = taken.ID.String()
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entry, err := env.entries.Get(ctx, created.ID)
+	require.NoError(t, err, "created entry should keep local id")
+	require.Equal(t, int64(1), entry.GlobalVersion, "created entry should get server version")
+	entry, err = env.entries.Get(ctx, uuid.MustParse(createdID))
+	require.NoError(t, err, "entry with taken id should get server id")
+	require.Equal(t, int64(1), entry.GlobalVersion, "rekeyed entry should get server version")
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 2, "created entries should not be duplicated")
+	conflicts, err := sut.GetConflicts(ctx)
+	require.NoError(t, err, "failed to get conflicts")
+	require.Empty(t, conflicts.Conflicts, "created entries should not conflict")
+}
+
+func TestEntryUC_PushBatches(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "push_test")
+
+	var (
+		batches    []int
+		requestIDs []string
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+			batches = append(batches, len(in.Changes))
+			results := make([]*pb.EntryChangeResult, len(in.Changes))
+			for i, v := range in.Changes {
+				results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: v.Create.Id, Version: 1}
+				require.NotEmpty(t, v.Create.RequestId, "change should carry request ID")
+				if v.Create.Key == "key0" {
+					requestIDs = append(requestIDs, v.Create.RequestId)
+					results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_INVALID, Error: "invalid"}
+				}
+			}
+			return &pb.ApplyChangesResponse{Results: results}, nil
+		})
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetEntriesDiffResponse{}, nil)
+	client.EXPECT().GetChangesSince(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetChangesSinceResponse{}, nil)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	for i := range 201 {
+		_, err := sut.Create(ctx, entities.CreateEntryRequest{
+			Key:  fmt.Sprintf("key%d", i),
+			Type: core.EntryTypeNote,
+			Data: entities.EntryDataNote("note"),
+		})
+		require.NoError(t, err, "failed to create entry")
+	}
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	require.Equal(t, []int{100, 100, 1}, batches, "changes should be pushed in batches")
+
+	batches = nil
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	require.Equal(t, []int{1}, batches, "only rejected change should be pushed again")
+	require.Len(t, requestIDs, 2)
+	require.Equal(t, requestIDs[0], requestIDs[1], "retried change should reuse request ID")
+}
+
+func TestEntryUC_PushConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "push_update_test")
+
+	var sut *usecases.EntryUC
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+				create := in.Changes[0].Create
+				require.NotNil(t, create, "create expected")
+				// the entry is changed locally while the create is in flight
+				require.NoError(t, sut.Update(ctx, entities.UpdateEntryRequest{
+					ID:   uuid.MustParse(create.Id),
+					Data: entities.EntryDataNote("note2"),
+				}), "failed to update entry")
+				return &pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{
+					{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: create.Id, Version: 1},
+				}}, nil
+			}),
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+				update := in.Changes[0].Update
+				require.NotNil(t, update, "update expected")
+				require.Equal(t, int64(1), update.Version, "update should be based on the created version")
+				data, err := env.vault.Decrypt(update.Data)
+				require.NoError(t, err, "failed to unseal data")
+				want, err := marshal.EntryMarshaler{}.Marshal(entities.EntryDataNote("note2"))
+				require.NoError(t, err, "failed to marshal data")
+				require.Equal(t, want, data, "changed data should be pushed")
+				return &pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{
+					{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: update.Id, Version: 2},
+				}}, nil
+			}),
+	)
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetEntriesDiffResponse{}, nil)
+	client.EXPECT().GetChangesSince(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetChangesSinceResponse{}, nil)
+
+	env.cache.SetString("token", "token-value")
+	sut = env.newEntryUC(t, client)
+
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "key1",
+		Type: core.EntryTypeNote,
+		Data: entities.EntryDataNote("note1"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entry, err := env.entries.Get(ctx, created.ID)
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, int64(1), entry.GlobalVersion, "created entry should get server version")
+	require.Equal(t, int64(2), entry.Version, "local change should be kept")
+
+	require.NoError(t, sut.Sync(ctx), "local change should be pushed as update")
+}
+
+func TestEntryUC_FetchUndecryptable(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "fetch_undecryptable_test")
+
+	otherVault, err := encrypto.NewEncrypter([]byte("0000000000000000"))
+	require.NoError(t, err, "failed to create other vault encrypter")
+	otherSealed, err := otherVault.Encrypt([]byte("note1"))
+	require.NoError(t, err, "failed to seal entry data")
+
+	var (
+		validID   = uuid.NewString()
+		invalidID = uuid.NewString()
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{
+			Entries: []*pb.Entry{
+				{Id: invalidID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: otherSealed, Version: 1},
+				{Id: validID, Key: "key2", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note2"), Version: 1},
+			},
+			CreateIds: []string{invalidID, validID},
+			Cursor:    3,
+		}, nil),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 3, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{
+				Entries: []*pb.Entry{
+					{Id: validID, Key: "key2", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: otherSealed, Version: 2},
+				},
+				Cursor: 4,
+			}, nil),
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+				require.Len(t, in.Changes, 1)
+				update := in.Changes[0].Update
+				require.NotNil(t, update, "local copy should be pushed as update")
+				require.Equal(t, validID, update.Id)
+				require.Equal(t, int64(2), update.Version, "update should be based on the undecryptable version")
+				data, err := env.vault.Decrypt(update.Data)
+				require.NoError(t, err, "local copy should be sealed with vault key")
+				want, err := marshal.EntryMarshaler{}.Marshal(entities.EntryDataNote("note2"))
+				require.NoError(t, err, "failed to marshal data")
+				require.Equal(t, want, data)
+				return &pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{
+					{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: validID, Version: 3},
+				}}, nil
+			}),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 4, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{Cursor: 4}, nil),
+	)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	err = sut.Sync(ctx)
+	require.ErrorIs(t, err, entities.ErrEntryUndecryptable, "entry without local copy should be reported")
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 1, "valid entry should be stored")
+	require.Equal(t, validID, entries.Entries[0].ID.String())
+	cursor, _ := env.cache.GetString("entries_cursor")
+	require.Equal(t, "3", cursor, "cursor should be kept")
+
+	require.NoError(t, sut.Sync(ctx), "undecryptable update should be replaced with local copy")
+	entries, err = sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Equal(t, entities.EntryDataNote("note2"), entries.Entries[0].Data, "local copy should be kept")
+	syncs, err := env.syncs.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, syncs, 1, "local copy should be queued for push")
+
+	require.NoError(t, sut.Sync(ctx), "local copy should be pushed")
+}
+
+func TestEntryUC_FetchPending(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "fetch_pending_test")
+
+	var (
+		updatedID = uuid.NewString()
+		deletedID = uuid.NewString()
+		removedID = uuid.NewString()
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{
+			Entries: []*pb.Entry{
+				{Id: updatedID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note1"), Version: 1},
+				{Id: deletedID, Key: "key2", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note2"), Version: 1},
+				{Id: removedID, Key: "key3", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note3"), Version: 1},
+			},
+			CreateIds: []string{updatedID, deletedID, removedID},
+			Cursor:    1,
+		}, nil),
+		// local changes are rejected, so they are still pending on fetch
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.InvalidArgument, "rejected")),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 1, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{
+				Entries: []*pb.Entry{
+					{Id: updatedID, Key: "key1", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note1_remote"), Version: 2},
+					{Id: deletedID, Key: "key2", Type: pb.EntryType_ENTRY_TYPE_NOTE, Version: 2, DeletedAt: time.Now().Unix()},
+					{Id: removedID, Key: "key3", Type: pb.EntryType_ENTRY_TYPE_NOTE, Data: env.seal(t, "note3_remote"), Version: 2},
+				},
+				Cursor: 2,
+			}, nil),
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+				require.Len(t, in.Changes, 2, "conflicted change should not be pushed")
+				results := make([]*pb.EntryChangeResult, len(in.Changes))
+				for i, v := range in.Changes {
+					switch {
+					case v.Create != nil:
+						require.Equal(t, "key2", v.Create.Key, "change of entry deleted on server should be pushed as new entry")
+						results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: v.Create.Id, Version: 1}
+					case v.Delete != nil:
+						require.Equal(t, removedID, v.Delete.Id, "local delete should be pushed")
+						results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK, Id: v.Delete.Id, Version: 3}
+					default:
+						require.Fail(t, "unexpected change", v.String())
+					}
+				}
+				return &pb.ApplyChangesResponse{Results: results}, nil
+			}),
+		client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 2, Limit: 100}).
+			Return(&pb.GetChangesSinceResponse{Cursor: 2}, nil),
+	)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	for id, data := range map[string]string{updatedID: "note1_local", deletedID: "note2_local"} {
+		require.NoError(t, sut.Update(ctx, entities.UpdateEntryRequest{
+			ID:   uuid.MustParse(id),
+			Data: entities.EntryDataNote(data),
+		}), "failed to update entry")
+	}
+	require.NoError(t, sut.Delete(ctx, entities.DeleteEntryRequest{ID: uuid.MustParse(removedID)}), "failed to delete entry")
+
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 2, "locally deleted entry should not be fetched again")
+	data := make(map[string]entities.EntryData, len(entries.Entries))
+	for _, v := range entries.Entries {
+		data[v.ID.String()] = v.Data
+	}
+	require.Equal(t, entities.EntryDataNote("note1_remote"), data[updatedID], "server version should be fetched")
+	require.Equal(t, entities.EntryDataNote("note2_local"), data[deletedID], "local change should be kept")
+	conflicts, err := sut.GetConflicts(ctx)
+	require.NoError(t, err, "failed to get conflicts")
+	require.Len(t, conflicts.Conflicts, 1, "overwritten local change should be kept as conflict")
+	require.Equal(t, updatedID, conflicts.Conflicts[0].ID.String())
+	require.Equal(t, entities.EntryDataNote("note1_local"), conflicts.Conflicts[0].Local.Data)
+	require.Equal(t, entities.EntryDataNote("note1_remote"), conflicts.Conflicts[0].Remote.Data)
+	_, err = env.syncs.Get(ctx, uuid.MustParse(updatedID))
+	require.ErrorIs(t, err, entities.ErrEntrySyncNotFound, "conflicted change should not be pushed")
+
+	require.NoError(t, sut.Sync(ctx), "pending changes should be pushed")
+}
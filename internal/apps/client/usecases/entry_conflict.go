@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+// GetConflicts returns entries rejected by the server that are waiting for resolution
+func (uc *EntryUC) GetConflicts(ctx context.Context) (response entities.GetEntryConflictsResponse, err error) {
+	conflicts, err := uc.conflictRepo.GetAll(ctx)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to get conflicts: %w", err)
+	}
+	response.Conflicts = make([]entities.GetEntryConflictResponse, len(conflicts))
+	for i, v := range conflicts {
+		conflict := entities.GetEntryConflictResponse{
+			ID:        v.ID,
+			Key:       v.Key,
+			CreatedAt: v.CreatedAt,
+		}
+		if conflict.Local, err = uc.toConflictEntry(v, v.Local); err != nil {
+			return response, err
+		}
+		if conflict.Remote, err = uc.toConflictEntry(v, v.Remote); err != nil {
+			return response, err
+		}
+		if v.Base != nil {
+			base, err := uc.toConflictEntry(v, *v.Base)
+			if err != nil {
+				return response, err
+			}
+			conflict.Base = &base
+		}
+		response.Conflicts[i] = conflict
+	}
+	return response, nil
+}
+
+// ResolveConflict applies the chosen version of the entry locally and schedules it for sync,
+// keeping the remote version only drops the conflict since the server version is already fetched.
+func (uc *EntryUC) ResolveConflict(
+	ctx context.Context,
+	request entities.ResolveEntryConflictRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		conflict, err := uc.conflictRepo.Get(ctx, request.ID)
+		switch {
+		case errors.Is(err, entities.ErrEntryConflictNotFound):
+			return fmt.Errorf("entry_usecase: %w", err)
+		case err != nil:
+			return fmt.Errorf("entry_usecase: failed to get conflict: %w", err)
+		}
+		switch request.Resolution {
+		case entities.EntryConflictKeepLocal:
+			err = uc.applyConflictVersion(ctx, conflict, conflict.Local.Meta, conflict.Local.Data)
+		case entities.EntryConflictMerge:
+			var data, encrypted []byte
+			if data, err = uc.marshaler.Marshal(request.Data); err != nil {
+				return fmt.Errorf("entry_usecase: failed to marshal entry data: %w", err)
+			}
+			if encrypted, err = uc.encrypter.Encrypt(data); err != nil {
+				return fmt.Errorf("entry_usecase: failed to encrypt entry data: %w", err)
+			}
+			err = uc.applyConflictVersion(ctx, conflict, request.Meta, encrypted)
+		}
+		if err != nil {
+			return err
+		}
+		if err = uc.conflictRepo.Delete(ctx, conflict.ID); err != nil {
+			return fmt.Errorf("entry_usecase: failed to delete conflict: %w", err)
+		}
+		return nil
+	}); err != nil {
+		uc.logger.Error("failed to resolve entry conflict", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// applyConflictVersion writes locally encrypted data with the local folder and tags over the fetched server version,
+// the entry is created again if it's gone or has another type.
+func (uc *EntryUC) applyConflictVersion(
+	ctx context.Context,
+	conflict entities.EntryConflict,
+	meta map[string]string,
+	data []byte,
+) error {
+	entry, err := uc.entryRepo.Get(ctx, conflict.ID)
+	switch {
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return uc.recreateConflictVersion(ctx, conflict.Key, conflict, meta, data)
+	case err != nil:
+		return fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	case entry.Type != conflict.Local.Type:
+		return uc.recreateConflictVersion(ctx, conflict.Key+" (mine)", conflict, meta, data)
+	}
+	if err = entry.Update(
+		entities.UpdateEntryMeta(meta),
+		entities.UpdateEntryFolder(conflict.Local.Folder),
+		entities.UpdateEntryTags(conflict.Local.Tags),
+		entities.UpdateEntryData(data)); err != nil {
+		return fmt.Errorf("entry_usecase: %w", err)
+	}
+	if err = uc.entryRepo.Update(ctx, entry); err != nil {
+		return fmt.Errorf("entry_usecase: failed to update entry in repo: %w", err)
+	}
+	if err = uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(entry.ID)); err != nil {
+		return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
+	}
+	return nil
+}
+
+func (uc *EntryUC) recreateConflictVersion(
+	ctx context.Context,
+	key string,
+	conflict entities.EntryConflict,
+	meta map[string]string,
+	data []byte,
+) error {
+	entry, err := entities.NewEntry(key, conflict.Local.Type, data)
+	if err != nil {
+		return fmt.Errorf("entry_usecase: %w", err)
+	}
+	entry.Meta = meta
+	entry.Folder = conflict.Local.Folder
+	entry.Tags = conflict.Local.Tags
+	err = uc.entryRepo.Create(ctx, *entry)
+	switch {
+	case errors.Is(err, entities.ErrEntryExists):
+		return fmt.Errorf("entry_usecase: %w", err)
+	case err != nil:
+		return fmt.Errorf("entry_usecase: failed to create entry in repo: %w", err)
+	}
+	if err = uc.entrySyncRepo.Create(ctx, *entities.NewEntrySync(entry.ID)); err != nil {
+		return fmt.Errorf("entry_usecase: failed to create entry sync in repo: %w", err)
+	}
+	return nil
+}
+
+// saveConflict stores versions from the rejected change details,
+// the local entry created offline is removed to free its key for the server version.
+func (uc *EntryUC) saveConflict(ctx context.Context, entry entities.Entry, details *pb.EntryConflict) error {
+	if details == nil || details.Server == nil {
+		return fmt.Errorf("entry_usecase: failed to push entry %s: %w: no details", entry.Key, entities.ErrEntryConflict)
+	}
+	var err error
+	conflict := entities.EntryConflict{
+		ID:  uuid.MustParse(details.Server.Id),
+		Key: entry.Key,
+		Local: entities.EntryConflictVersion{
+			Type:    entry.Type,
+			Meta:    entry.Meta,
+			Folder:  entry.Folder,
+			Tags:    entry.Tags,
+			Data:    entry.Data,
+			Version: entry.GlobalVersion,
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	if conflict.Remote, err = uc.toConflictVersion(details.Server); err != nil {
+		return err
+	}
+	if details.Base != nil {
+		base, err := uc.toConflictVersion(details.Base)
+		if err != nil {
+			return err
+		}
+		conflict.Base = &base
+	}
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err = uc.conflictRepo.Save(ctx, conflict); err != nil {
+			return fmt.Errorf("entry_usecase: failed to save conflict: %w", err)
+		}
+		if entry.ID == conflict.ID {
+			return nil
+		}
+		if err = uc.entryRepo.Delete(ctx, entry.ID); err != nil {
+			return fmt.Errorf("entry_usecase: failed to delete conflicted entry: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// saveFetchedConflict keeps the pending local change that the fetched server version replaces,
+// the change is pushed again only if it's chosen on resolution.
+func (uc *EntryUC) saveFetchedConflict(
+	ctx context.Context,
+	local entities.Entry,
+	fetched entities.Entry,
+	sync entities.EntrySync,
+) error {
+	conflict := entities.EntryConflict{
+		ID:  local.ID,
+		Key: local.Key,
+		Local: entities.EntryConflictVersion{
+			Type:    local.Type,
+			Meta:    local.Meta,
+			Folder:  local.Folder,
+			Tags:    local.Tags,
+			Data:    local.Data,
+			Version: local.GlobalVersion,
+		},
+		Remote: entities.EntryConflictVersion{
+			Type:    fetched.Type,
+			Meta:    fetched.Meta,
+			Folder:  fetched.Folder,
+			Tags:    fetched.Tags,
+			Data:    fetched.Data,
+			Version: fetched.GlobalVersion,
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err := uc.conflictRepo.Save(ctx, conflict); err != nil {
+			return fmt.Errorf("entry_usecase: failed to save conflict: %w", err)
+		}
+		if err := uc.entrySyncRepo.Delete(ctx, sync.ID, sync.RequestID); err != nil {
+			return fmt.Errorf("entry_usecase: failed to delete entry sync: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	uc.logger.Debug("entry conflict", zap.String("key", local.Key))
+	return nil
+}
+
+func (uc *EntryUC) toConflictVersion(entry *pb.Entry) (entities.EntryConflictVersion, error) {
+	encrypted, err := uc.unseal(entry.Data)
+	if err != nil {
+		return entities.EntryConflictVersion{}, err
+	}
+	return entities.EntryConflictVersion{
+		Type:    uc.toEntityType(entry.Type, entry.TypeName),
+		Meta:    entry.Meta,
+		Folder:  entry.Folder,
+		Tags:    entry.Tags,
+		Data:    encrypted,
+		Version: entry.Version,
+	}, nil
+}
+
+func (uc *EntryUC) toConflictEntry(
+	conflict entities.EntryConflict,
+	version entities.EntryConflictVersion,
+) (entities.GetEntryResponse, error) {
+	decrypted, err := uc.encrypter.Decrypt(version.Data)
+	if err != nil {
+		return entities.GetEntryResponse{}, fmt.Errorf("entry_usecase: failed to decrypt entry: %w", err)
+	}
+	data, err := uc.marshaler.Unmarshal(version.Type, decrypted)
+	if err != nil {
+		return entities.GetEntryResponse{}, fmt.Errorf("entry_usecase: failed to unmarshal entry: %w", err)
+	}
+	return entities.GetEntryResponse{
+		ID:            conflict.ID,
+		Key:           conflict.Key,
+		Type:          version.Type,
+		Data:          data,
+		Meta:          version.Meta,
+		Folder:        version.Folder,
+		Tags:          version.Tags,
+		Version:       version.Version,
+		GlobalVersion: version.Version,
+	}, nil
+}
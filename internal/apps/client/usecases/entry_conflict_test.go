@@ -0,0 +1,119 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/marshal"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"testing"
+)
+
+func TestEntryUC_Conflict(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "conflict_test")
+
+	marshaler := marshal.EntryMarshaler{}
+	remoteData, err := marshaler.Marshal(entities.EntryDataPassword{Login: "remote", Password: "remote_password"})
+	require.NoError(t, err, "failed to marshal remote data")
+	sealed, err := env.vault.Encrypt(remoteData)
+	require.NoError(t, err, "failed to seal remote data")
+
+	remote := &pb.Entry{
+		Id:      uuid.NewString(),
+		Key:     "key1",
+		Type:    pb.EntryType_ENTRY_TYPE_PASSWORD,
+		Meta:    map[string]string{"description": "remote"},
+		Data:    sealed,
+		Version: 1,
+	}
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).
+			Return(&pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{{
+				Status:   pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_CONFLICT,
+				Error:    "entry key conflict",
+				Conflict: &pb.EntryConflict{Server: remote},
+			}}}, nil),
+		client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, changes *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+				require.Len(t, changes.Changes, 1)
+				in := changes.Changes[0].Update
+				require.NotNil(t, in, "update expected")
+				require.Equal(t, remote.Id, in.Id, "merged entry should update server entry")
+				require.Equal(t, remote.Version, in.Version, "merged entry should be based on server version")
+				data, err := env.vault.Decrypt(in.Data)
+				require.NoError(t, err, "failed to unseal merged data")
+				merged, err := marshaler.Unmarshal(core.EntryTypePassword, data)
+				require.NoError(t, err, "failed to unmarshal merged data")
+				require.Equal(t, entities.EntryDataPassword{Login: "local", Password: "remote_password"}, merged)
+				return &pb.ApplyChangesResponse{Results: []*pb.EntryChangeResult{{
+					Status:  pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK,
+					Id:      in.Id,
+					Version: in.Version + 1,
+				}}}, nil
+			}),
+	)
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{
+		Entries:   []*pb.Entry{remote},
+		CreateIds: []string{remote.Id},
+		Cursor:    1,
+	}, nil)
+	client.EXPECT().GetChangesSince(gomock.Any(), &pb.GetChangesSinceRequest{Cursor: 1, Limit: 100}).
+		Return(&pb.GetChangesSinceResponse{Cursor: 1}, nil)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	_, err = sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "key1",
+		Type: core.EntryTypePassword,
+		Meta: map[string]string{"description": "local"},
+		Data: entities.EntryDataPassword{Login: "local", Password: "local_password"},
+	})
+	require.NoError(t, err, "failed to create entry")
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 1, "conflicted entry should be replaced with server one")
+	require.Equal(t, remote.Id, entries.Entries[0].ID.String(), "server entry expected")
+
+	conflicts, err := sut.GetConflicts(ctx)
+	require.NoError(t, err, "failed to get conflicts")
+	require.Len(t, conflicts.Conflicts, 1, "conflict expected")
+	conflict := conflicts.Conflicts[0]
+	require.Equal(t, entities.EntryDataPassword{Login: "local", Password: "local_password"}, conflict.Local.Data)
+	require.Equal(t, entities.EntryDataPassword{Login: "remote", Password: "remote_password"}, conflict.Remote.Data)
+	require.Nil(t, conflict.Base, "base is unknown for key conflict")
+	require.True(t, conflict.Mergeable(), "password entries should be mergeable")
+
+	meta, data, err := conflict.Merge(map[string]bool{entities.EntryFieldLogin: true})
+	require.NoError(t, err, "failed to merge conflict")
+	require.Equal(t, "remote", meta[entities.EntryFieldDescription], "remote description expected")
+	err = sut.ResolveConflict(ctx, entities.ResolveEntryConflictRequest{
+		ID:         conflict.ID,
+		Resolution: entities.EntryConflictMerge,
+		Meta:       meta,
+		Data:       data,
+	})
+	require.NoError(t, err, "failed to resolve conflict")
+	require.NoError(t, sut.Sync(ctx), "failed to sync entries")
+
+	conflicts, err = sut.GetConflicts(ctx)
+	require.NoError(t, err, "failed to get conflicts")
+	require.Empty(t, conflicts.Conflicts, "conflict should be resolved")
+	err = sut.ResolveConflict(ctx, entities.ResolveEntryConflictRequest{
+		ID:         conflict.ID,
+		Resolution: entities.EntryConflictKeepRemote,
+	})
+	require.ErrorIs(t, err, entities.ErrEntryConflictNotFound, "expected conflict not found")
+}
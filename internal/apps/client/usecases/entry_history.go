@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// GetHistory returns previous versions of the entry stored on the server, the latest first
+func (uc *EntryUC) GetHistory(
+	ctx context.Context,
+	request entities.GetEntryHistoryRequest,
+) (response entities.GetEntriesResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	if ctx, err = uc.appendToken(ctx); err != nil {
+		return response, err
+	}
+	resp, err := uc.entryClient.GetHistory(ctx, &pb.GetEntryHistoryRequest{Id: request.ID.String()})
+	if err != nil {
+		uc.logger.Error("failed to get entry history", zap.Error(err))
+		return response, uc.historyError("failed to get entry history", err)
+	}
+
+	if response.Entries, err = uc.toRemoteEntries(resp.Entries); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// Restore saves the entry version from the server history as a new version and syncs entries
+func (uc *EntryUC) Restore(
+	ctx context.Context,
+	request entities.RestoreEntryRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	tokenCtx, err := uc.appendToken(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = uc.entryClient.Restore(tokenCtx, &pb.RestoreEntryRequest{
+		Id:        request.ID.String(),
+		Version:   request.Version,
+		RequestId: uuid.NewString(),
+	}); err != nil {
+		uc.logger.Error("failed to restore entry", zap.Error(err))
+		return uc.historyError("failed to restore entry", err)
+	}
+	return uc.Sync(ctx)
+}
+
+// toRemoteEntries unseals entries received from the server without storing them locally
+func (uc *EntryUC) toRemoteEntries(entries []*pb.Entry) ([]entities.GetEntryResponse, error) {
+	result := make([]entities.GetEntryResponse, len(entries))
+	for i, v := range entries {
+		typ := uc.toEntityType(v.Type, v.TypeName)
+		decrypted, err := uc.vault.Decrypt(v.Data)
+		if err != nil {
+			return nil, fmt.Errorf("entry_usecase: failed to unseal entry data: %w", err)
+		}
+		data, err := uc.marshaler.Unmarshal(typ, decrypted)
+		if err != nil {
+			return nil, fmt.Errorf("entry_usecase: failed to unmarshal entry: %w", err)
+		}
+		result[i] = entities.GetEntryResponse{
+			ID:            uuid.MustParse(v.Id),
+			Key:           v.Key,
+			Type:          typ,
+			Meta:          v.Meta,
+			Folder:        v.Folder,
+			Tags:          v.Tags,
+			Data:          data,
+			GlobalVersion: v.Version,
+			Version:       v.Version,
+			UpdatedAt:     time.Unix(v.UpdatedAt, 0).UTC(),
+		}
+	}
+	return result, nil
+}
+
+func (uc *EntryUC) historyError(msg string, err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrEntryInvalid, err)
+	case codes.NotFound:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrEntryHistoryNotFound, err)
+	case codes.Unavailable:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("entry_usecase: %s: %w", msg, err)
+	}
+}
@@ -0,0 +1,76 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestEntryUC_History(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "history_test")
+
+	sealed, err := env.vault.Encrypt([]byte("note_v1"))
+	require.NoError(t, err, "failed to seal entry data")
+
+	var (
+		id        = uuid.New()
+		updatedAt = time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().GetHistory(gomock.Any(), &pb.GetEntryHistoryRequest{Id: id.String()}).Return(
+		&pb.GetEntryHistoryResponse{Entries: []*pb.Entry{{
+			Id:        id.String(),
+			Key:       "key1",
+			Type:      pb.EntryType_ENTRY_TYPE_NOTE,
+			Meta:      map[string]string{"description": "v1"},
+			Data:      sealed,
+			Version:   1,
+			UpdatedAt: updatedAt.Unix(),
+		}}}, nil)
+	gomock.InOrder(
+		client.EXPECT().Restore(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.RestoreEntryRequest, _ ...grpc.CallOption) (*pb.RestoreEntryResponse, error) {
+				require.Equal(t, id.String(), in.Id)
+				require.Equal(t, int64(1), in.Version)
+				require.NotEmpty(t, in.RequestId, "request ID expected")
+				return &pb.RestoreEntryResponse{Id: id.String(), Version: 3}, nil
+			}),
+		client.EXPECT().Restore(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.NotFound, "entry version not found")),
+	)
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{}, nil)
+
+	sut := env.newEntryUC(t, client)
+
+	_, err = sut.GetHistory(ctx, entities.GetEntryHistoryRequest{ID: id})
+	require.ErrorIs(t, err, entities.ErrUserTokenNotFound, "expected token required")
+	env.cache.SetString("token", "token-value")
+
+	history, err := sut.GetHistory(ctx, entities.GetEntryHistoryRequest{ID: id})
+	require.NoError(t, err, "failed to get entry history")
+	require.Len(t, history.Entries, 1, "unexpected history length")
+	require.Equal(t, core.EntryTypeNote, history.Entries[0].Type, "entry type mismatch")
+	require.Equal(t, entities.EntryDataNote("note_v1"), history.Entries[0].Data, "entry data mismatch")
+	require.Equal(t, updatedAt, history.Entries[0].UpdatedAt, "entry updated at mismatch")
+
+	err = sut.Restore(ctx, entities.RestoreEntryRequest{ID: id, Version: 1})
+	require.NoError(t, err, "failed to restore entry")
+	err = sut.Restore(ctx, entities.RestoreEntryRequest{ID: id, Version: 2})
+	require.ErrorIs(t, err, entities.ErrEntryHistoryNotFound, "expected version not found")
+	err = sut.Restore(ctx, entities.RestoreEntryRequest{ID: id})
+	require.ErrorIs(t, err, entities.ErrEntryVersionInvalid, "expected invalid version")
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/importer"
+	"go.uber.org/zap"
+)
+
+// Import creates entries from the export of another password manager, records with existing keys
+// are reported as duplicates and skipped, created entries are queued for sync
+func (uc *EntryUC) Import(
+	ctx context.Context,
+	request entities.ImportEntriesRequest,
+) (response entities.ImportEntriesResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	records, err := importer.Parse(request.Format, request.Content)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to parse import: %w", err)
+	}
+	existing, err := uc.entryRepo.GetAll(ctx)
+	if err != nil {
+		return response, fmt.Errorf("entry_usecase: failed to get entries: %w", err)
+	}
+	keys := make(map[string]struct{}, len(existing)+len(records))
+	for _, v := range existing {
+		keys[v.Key] = struct{}{}
+	}
+
+	for _, record := range records {
+		if _, ok := keys[record.Key]; ok {
+			response.Duplicates = append(response.Duplicates, record.Key)
+			continue
+		}
+		keys[record.Key] = struct{}{}
+		if request.DryRun {
+			if err = record.Validate(); err != nil {
+				return response, fmt.Errorf("entry_usecase: invalid entry %q: %w", record.Key, err)
+			}
+			response.Created = append(response.Created, record.Key)
+			continue
+		}
+		_, err = uc.Create(ctx, record)
+		switch {
+		case errors.Is(err, entities.ErrEntryExists):
+			response.Duplicates = append(response.Duplicates, record.Key)
+		case err != nil:
+			uc.logger.Error("failed to import entry", zap.String("key", record.Key), zap.Error(err))
+			return response, fmt.Errorf("entry_usecase: failed to import entry %q: %w", record.Key, err)
+		default:
+			response.Created = append(response.Created, record.Key)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,64 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEntryUC_Import(t *testing.T) {
+	ctx := context.Background()
+	env := newEntryEnv(t, "import_test")
+	sut := env.newEntryUC(t, nil)
+
+	_, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:  "mail",
+		Type: core.EntryTypePassword,
+		Data: entities.EntryDataPassword{Login: "old", Password: "old"},
+	})
+	require.NoError(t, err, "failed to create entry")
+	content := []byte("title,username,password,url,notes\n" +
+		"mail,user,pass,https://mail.example.com,\n" +
+		"db,admin,secret,,primary\n" +
+		"db,admin,other,,replica\n" +
+		"todo,,,,buy milk\n")
+
+	_, err = sut.Import(ctx, entities.ImportEntriesRequest{Format: "unknown", Content: content})
+	require.ErrorIs(t, err, entities.ErrImportFormatInvalid)
+	_, err = sut.Import(ctx, entities.ImportEntriesRequest{Format: entities.ImportFormatCSV, Content: []byte("a,b\n")})
+	require.ErrorIs(t, err, entities.ErrImportContentInvalid)
+
+	got, err := sut.Import(ctx, entities.ImportEntriesRequest{
+		Format:  entities.ImportFormatCSV,
+		Content: content,
+		DryRun:  true,
+	})
+	require.NoError(t, err, "failed to dry-run import")
+	require.Equal(t, []string{"db", "todo"}, got.Created)
+	require.Equal(t, []string{"mail", "db"}, got.Duplicates)
+	entries, err := sut.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 1, "dry-run shouldn't create entries")
+
+	got, err = sut.Import(ctx, entities.ImportEntriesRequest{Format: entities.ImportFormatCSV, Content: content})
+	require.NoError(t, err, "failed to import")
+	require.Equal(t, []string{"db", "todo"}, got.Created)
+	require.Equal(t, []string{"mail", "db"}, got.Duplicates)
+
+	entries, err = sut.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, 3)
+	imported := make(map[string]entities.GetEntryResponse)
+	for _, v := range entries.Entries {
+		imported[v.Key] = v
+	}
+	require.Equal(t, entities.EntryDataPassword{Login: "old", Password: "old"}, imported["mail"].Data, "existing entry shouldn't be changed")
+	require.Equal(t, entities.EntryDataPassword{Login: "admin", Password: "secret"}, imported["db"].Data)
+	require.Equal(t, map[string]string{"notes": "primary"}, imported["db"].Meta)
+	require.Equal(t, entities.EntryDataNote("buy milk"), imported["todo"].Data)
+	syncs, err := env.syncs.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, syncs, 3, "imported entries should be queued for sync")
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// entryPushBatchSize matches the server limit of changes applied in one transaction
+	entryPushBatchSize = 100
+	// entryPushBatchBytes keeps the batch under the default gRPC message size limit
+	entryPushBatchBytes = 2 * 1024 * 1024
+)
+
+// pushChange is the pending local change of the entry
+type pushChange struct {
+	sync    entities.EntrySync
+	entry   entities.Entry
+	request *pb.EntryChangeRequest
+}
+
+// pushEntries sends pending local changes in batches, the sync rows are cleared
+// only for the changes accepted by the server: applied, conflicted or already deleted ones
+func (uc *EntryUC) pushEntries(ctx context.Context) error {
+	syncs, err := uc.entrySyncRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("entry_usecase: failed to get entry syncs: %w", err)
+	}
+	changes := make([]pushChange, 0, len(syncs))
+	for _, v := range syncs {
+		change, err := uc.newPushChange(ctx, v)
+		switch {
+		case errors.Is(err, entities.ErrUserVaultLocked):
+			return err
+		case err != nil:
+			uc.logger.Error("failed to prepare entry change", zap.Error(err))
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for len(changes) > 0 {
+		n := pushBatchLen(changes)
+		retry, err := uc.pushBatch(ctx, changes[:n])
+		switch {
+		// the rejected batch is kept for the next push, so it doesn't block the fetch
+		case errors.Is(err, entities.ErrEntryInvalid):
+			uc.logger.Error("entry changes rejected", zap.Int("changes", n), zap.Error(err))
+		case err != nil:
+			return err
+		}
+		changes = append(changes[n:], retry...)
+	}
+	return nil
+}
+
+// newPushChange creates the entry if it has never been pushed,
+// the missing local entry is deleted on the server
+func (uc *EntryUC) newPushChange(ctx context.Context, sync entities.EntrySync) (pushChange, error) {
+	entry, err := uc.entryRepo.Get(ctx, sync.ID)
+	switch {
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return pushChange{
+			sync: sync,
+			request: &pb.EntryChangeRequest{Delete: &pb.DeleteEntryRequest{
+				Id:        sync.ID.String(),
+				RequestId: sync.RequestID,
+			}},
+		}, nil
+	case err != nil:
+		return pushChange{}, fmt.Errorf("entry_usecase: failed to get entry: %w", err)
+	}
+	sealed, err := uc.seal(entry.Data)
+	if err != nil {
+		return pushChange{}, err
+	}
+	change := pushChange{sync: sync, entry: entry}
+	if entry.GlobalVersion == 0 {
+		change.request = &pb.EntryChangeRequest{Create: &pb.CreateEntryRequest{
+			Id:        entry.ID.String(),
+			Key:       entry.Key,
+			Type:      uc.mapper.ToAPIType(entry.Type),
+			TypeName:  string(entry.Type),
+			Meta:      entry.Meta,
+			Folder:    entry.Folder,
+			Tags:      entry.Tags,
+			Data:      sealed,
+			RequestId: sync.RequestID,
+		}}
+		return change, nil
+	}
+	change.request = &pb.EntryChangeRequest{Update: &pb.UpdateEntryRequest{
+		Id:        entry.ID.String(),
+		Meta:      entry.Meta,
+		Folder:    entry.Folder,
+		Tags:      entry.Tags,
+		Data:      sealed,
+		Version:   entry.GlobalVersion,
+		RequestId: sync.RequestID,
+	}}
+	return change, nil
+}
+
+// pushBatch applies the batch on the server and returns the creates to retry
+// without the local ID, it's taken on the server
+func (uc *EntryUC) pushBatch(ctx context.Context, batch []pushChange) (retry []pushChange, err error) {
+	requests := make([]*pb.EntryChangeRequest, len(batch))
+	for i, v := range batch {
+		requests[i] = v.request
+	}
+	resp, err := uc.entryClient.ApplyChanges(ctx, &pb.ApplyChangesRequest{Changes: requests})
+	switch {
+	case status.Code(err) == codes.InvalidArgument:
+		return nil, fmt.Errorf("entry_usecase: failed to push entries: %w: %w", entities.ErrEntryInvalid, err)
+	case status.Code(err) == codes.Unavailable:
+		return nil, fmt.Errorf("entry_usecase: failed to push entries: %w: %w", entities.ErrServerUnavailable, err)
+	case status.Code(err) == codes.Unauthenticated:
+		return nil, fmt.Errorf("entry_usecase: failed to push entries: %w: %w", entities.ErrUserTokenInvalid, err)
+	case err != nil:
+		return nil, fmt.Errorf("entry_usecase: failed to push entries: %w", err)
+	}
+	if len(resp.Results) != len(batch) {
+		return nil, fmt.Errorf("entry_usecase: failed to push entries: got %d results for %d changes",
+			len(resp.Results), len(batch))
+	}
+
+	for i, result := range resp.Results {
+		change := batch[i]
+		switch result.Status {
+		case pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK:
+			if change.request.Create == nil {
+				break
+			}
+			if err = uc.rekey(ctx, change.entry, result.Id, result.Version); err != nil {
+				uc.logger.Error("failed to save created entry", zap.Error(err))
+				continue
+			}
+		// conflict is kept until it's resolved, the next fetch brings the server version
+		case pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_CONFLICT:
+			if err = uc.saveConflict(ctx, change.entry, result.Conflict); err != nil {
+				uc.logger.Error("failed to save entry conflict", zap.Error(err))
+				continue
+			}
+			uc.logger.Debug("entry conflict", zap.String("key", change.entry.Key))
+		// entry is already deleted on the server, the next fetch removes it locally
+		case pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_NOT_FOUND:
+			uc.logger.Debug("entry not found on server", zap.String("entry_id", change.sync.ID.String()))
+		case pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_ID_TAKEN:
+			if create := change.request.Create; create != nil && create.Id != "" {
+				create.Id = ""
+				retry = append(retry, change)
+				continue
+			}
+			uc.logger.Error("entry id is taken", zap.String("entry_id", change.sync.ID.String()))
+			continue
+		default:
+			uc.logger.Error("entry change rejected",
+				zap.String("entry_id", change.sync.ID.String()),
+				zap.String("status", result.Status.String()),
+				zap.String("error", result.Error))
+			continue
+		}
+		if err = uc.entrySyncRepo.Delete(ctx, change.sync.ID, change.sync.RequestID); err != nil {
+			uc.logger.Error("failed to delete entry sync", zap.Error(err))
+		}
+	}
+	return retry, nil
+}
+
+// pushBatchLen returns the number of changes fitting the batch limits, at least one
+func pushBatchLen(changes []pushChange) int {
+	size := 0
+	for i, v := range changes {
+		size += proto.Size(v.request)
+		if i == entryPushBatchSize || i > 0 && size > entryPushBatchBytes {
+			return i
+		}
+	}
+	return len(changes)
+}
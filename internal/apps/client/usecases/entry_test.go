@@ -22,30 +22,18 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
 	"testing"
 	"time"
 )
 
 type TestEntryUC struct {
 	suite.Suite
-	logger *zap.Logger
-	db     *sqlx.DB
+	env *entryEnv
 }
 
 func (s *TestEntryUC) SetupSuite() {
-	var err error
-	s.logger = zaptest.NewLogger(s.T(), zaptest.Level(zap.DebugLevel))
-	s.db, err = sqlx.Open("sqlite3", "file:test.db?cache=shared&mode=memory")
-	require.NoError(s.T(), err, "failed to open database")
-	ms, err := migrations.GetMigrations()
-	require.NoError(s.T(), err, "failed to get migrations")
-	err = migrator.Migrate(s.logger.Sugar(), s.db.DB, ms)
-	require.NoError(s.T(), err, "failed to up migrations")
-}
-
-func (s *TestEntryUC) TearDownSuite() {
-	err := s.db.Close()
-	require.NoError(s.T(), err, "failed to close database")
+	s.env = newEntryEnv(s.T(), "entry_test")
 }
 
 func TestEntryUCRun(t *testing.T) {
@@ -57,29 +45,30 @@ func (s *TestEntryUC) TestMethods() {
 	ctrl := gomock.NewController(s.T())
 	defer ctrl.Finish()
 
+	pushed := 0
 	client := mocks.NewMockEntryServiceClient(ctrl)
-	client.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
-	client.EXPECT().Update(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
-	client.EXPECT().Delete(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	client.EXPECT().ApplyChanges(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, in *pb.ApplyChangesRequest, _ ...grpc.CallOption) (*pb.ApplyChangesResponse, error) {
+			results := make([]*pb.EntryChangeResult, len(in.Changes))
+			for i, v := range in.Changes {
+				results[i] = &pb.EntryChangeResult{Status: pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK}
+				if v.Create == nil {
+					continue
+				}
+				_, err := s.env.vault.Decrypt(v.Create.Data)
+				require.NoError(s.T(), err, "entry data should be sealed with vault key")
+				_, err = s.env.encrypter.Decrypt(v.Create.Data)
+				require.Error(s.T(), err, "entry data should not be sealed with local key")
+				pushed++
+				results[i].Id = uuid.NewString()
+				results[i].Version = 1
+			}
+			return &pb.ApplyChangesResponse{Results: results}, nil
+		})
 	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetEntriesDiffResponse{}, nil)
+	client.EXPECT().GetChangesSince(gomock.Any(), gomock.Any()).AnyTimes().Return(&pb.GetChangesSinceResponse{}, nil)
 
-	encrypter, err := encrypto.NewEncrypter([]byte("1234567890123456"))
-	require.NoError(s.T(), err, "failed to create encrypter")
-	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
-	entrySyncRepo := repo.NewEntrySyncRepo(s.db, trmsqlx.DefaultCtxGetter)
-	memcache := mem.NewCache()
-	trm, err := manager.New(trmsqlx.NewDefaultFactory(s.db))
-	require.NoError(s.T(), err, "failed to create transaction manager")
-	sut := usecases.NewEntriesUC(
-		s.logger,
-		client,
-		entryRepo,
-		entrySyncRepo,
-		encrypter,
-		marshal.EntryMarshaler{},
-		memcache,
-		trm,
-	)
+	sut := s.env.newEntryUC(s.T(), client)
 
 	// create
 	createEntries := map[string]entities.CreateEntryRequest{
@@ -177,7 +166,114 @@ func (s *TestEntryUC) TestMethods() {
 	require.Len(s.T(), getAll.Entries, len(entries)-1, "unexpected entries count after deletion")
 
 	// sync
-	memcache.SetString("token", "token-value")
+	s.env.cache.SetString("token", "token-value")
 	err = sut.Sync(ctx)
 	require.NoError(s.T(), err, "failed to sync entries")
+	require.NotZero(s.T(), pushed, "expected created entries to be pushed")
+}
+
+func TestEntryUC_Folders(t *testing.T) {
+	ctx := context.Background()
+	sut := newEntryEnv(t, "entry_folders").newEntryUC(t, nil)
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "db",
+		Type:   core.EntryTypeNote,
+		Folder: "Work/ Servers /DB/",
+		Tags:   []string{"prod", " prod", ""},
+		Data:   entities.EntryDataNote("note"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	_, err = sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "mail",
+		Type:   core.EntryTypeNote,
+		Folder: "Personal",
+		Tags:   []string{"mail"},
+		Data:   entities.EntryDataNote("note"),
+	})
+	require.NoError(t, err, "failed to create entry")
+
+	folders, err := sut.GetFolders(ctx)
+	require.NoError(t, err, "failed to get folders")
+	require.Equal(t, []string{"Personal", "Work", "Work/Servers", "Work/Servers/DB"}, folders.Folders,
+		"parent folders should be included")
+	tags, err := sut.GetTags(ctx)
+	require.NoError(t, err, "failed to get tags")
+	require.Equal(t, []string{"mail", "prod"}, tags.Tags)
+
+	require.NoError(t, sut.Update(ctx, entities.UpdateEntryRequest{
+		ID:     created.ID,
+		Folder: "Work",
+		Tags:   []string{"prod", "db"},
+		Data:   entities.EntryDataNote("note"),
+	}), "failed to update entry")
+	found, err := sut.Search(ctx, entities.SearchEntriesRequest{Folder: "Work"})
+	require.NoError(t, err, "failed to search entries")
+	require.Len(t, found.Entries, 1)
+	require.Equal(t, "Work", found.Entries[0].Folder)
+	require.Equal(t, []string{"db", "prod"}, found.Entries[0].Tags, "tags should be sorted")
+	require.Nil(t, found.Entries[0].Data, "found entries should be listed without data")
+	got, err := sut.Get(ctx, entities.GetEntryRequest{ID: found.Entries[0].ID})
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, entities.EntryDataNote("note"), got.Data)
+	_, err = sut.Get(ctx, entities.GetEntryRequest{ID: uuid.New()})
+	require.ErrorIs(t, err, entities.ErrEntryNotFound)
+}
+
+// entryEnv is an in-memory database with the keys and the cache shared by entry usecase tests.
+type entryEnv struct {
+	logger    *zap.Logger
+	db        *sqlx.DB
+	entries   *repo.EntryRepo
+	syncs     *repo.EntrySyncRepo
+	encrypter *encrypto.Encrypter
+	vault     *encrypto.Encrypter
+	cache     *mem.Cache
+}
+
+func newEntryEnv(t *testing.T, name string) *entryEnv {
+	logger := zaptest.NewLogger(t)
+	db, err := sqlx.Open("sqlite3", "file:"+name+".db?cache=shared&mode=memory")
+	require.NoError(t, err, "failed to open database")
+	t.Cleanup(func() { _ = db.Close() })
+	ms, err := migrations.GetMigrations()
+	require.NoError(t, err, "failed to get migrations")
+	require.NoError(t, migrator.Migrate(logger.Sugar(), db.DB, ms), "failed to up migrations")
+	encrypter, err := encrypto.NewEncrypter([]byte("1234567890123456"))
+	require.NoError(t, err, "failed to create encrypter")
+	vault, err := encrypto.NewEncrypter([]byte("6543210987654321"))
+	require.NoError(t, err, "failed to create vault encrypter")
+
+	return &entryEnv{
+		logger:    logger,
+		db:        db,
+		entries:   repo.NewEntryRepo(db, trmsqlx.DefaultCtxGetter),
+		syncs:     repo.NewEntrySyncRepo(db, trmsqlx.DefaultCtxGetter),
+		encrypter: encrypter,
+		vault:     vault,
+		cache:     mem.NewCache(),
+	}
+}
+
+func (e *entryEnv) newEntryUC(t *testing.T, client pb.EntryServiceClient) *usecases.EntryUC {
+	trm, err := manager.New(trmsqlx.NewDefaultFactory(e.db))
+	require.NoError(t, err, "failed to create transaction manager")
+
+	return usecases.NewEntriesUC(
+		e.logger,
+		client,
+		e.entries,
+		e.syncs,
+		repo.NewEntryConflictRepo(e.db, trmsqlx.DefaultCtxGetter),
+		e.encrypter,
+		e.vault,
+		marshal.EntryMarshaler{},
+		e.cache,
+		trm,
+	)
+}
+
+func (e *entryEnv) seal(t *testing.T, data string) []byte {
+	sealed, err := e.vault.Encrypt([]byte(data))
+	require.NoError(t, err, "failed to seal entry data")
+	return sealed
 }
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetTrash returns entries deleted on the server that are not purged yet, the latest deleted first
+func (uc *EntryUC) GetTrash(ctx context.Context) (response entities.GetEntriesResponse, err error) {
+	if ctx, err = uc.appendToken(ctx); err != nil {
+		return response, err
+	}
+	resp, err := uc.entryClient.GetTrash(ctx, &pb.GetTrashRequest{})
+	if err != nil {
+		uc.logger.Error("failed to get trash", zap.Error(err))
+		return response, uc.trashError("failed to get trash", err)
+	}
+	if response.Entries, err = uc.toRemoteEntries(resp.Entries); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// Undelete restores the deleted entry on the server and syncs entries
+func (uc *EntryUC) Undelete(
+	ctx context.Context,
+	request entities.UndeleteEntryRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("entry_usecase: invalid request: %w", err)
+	}
+	tokenCtx, err := uc.appendToken(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = uc.entryClient.Undelete(tokenCtx, &pb.UndeleteEntryRequest{
+		Id:        request.ID.String(),
+		RequestId: uuid.NewString(),
+	}); err != nil {
+		uc.logger.Error("failed to undelete entry", zap.Error(err))
+		return uc.trashError("failed to undelete entry", err)
+	}
+	return uc.Sync(ctx)
+}
+
+func (uc *EntryUC) trashError(msg string, err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrEntryInvalid, err)
+	case codes.AlreadyExists:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrEntryExists, err)
+	case codes.NotFound:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrEntryNotFound, err)
+	case codes.Unavailable:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("entry_usecase: %s: %w: %w", msg, entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("entry_usecase: %s: %w", msg, err)
+	}
+}
@@ -0,0 +1,75 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestEntryUC_Trash(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "trash_test")
+
+	sealed, err := env.vault.Encrypt([]byte("note_v1"))
+	require.NoError(t, err, "failed to seal entry data")
+
+	var (
+		id        = uuid.New()
+		deletedAt = time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().GetTrash(gomock.Any(), &pb.GetTrashRequest{}).Return(
+		&pb.GetTrashResponse{Entries: []*pb.Entry{{
+			Id:        id.String(),
+			Key:       "key1",
+			Type:      pb.EntryType_ENTRY_TYPE_NOTE,
+			Meta:      map[string]string{"description": "v1"},
+			Data:      sealed,
+			Version:   2,
+			UpdatedAt: deletedAt.Unix(),
+			DeletedAt: deletedAt.Unix(),
+		}}}, nil)
+	gomock.InOrder(
+		client.EXPECT().Undelete(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, in *pb.UndeleteEntryRequest, _ ...grpc.CallOption) (*pb.UndeleteEntryResponse, error) {
+				require.Equal(t, id.String(), in.Id)
+				require.NotEmpty(t, in.RequestId, "request ID expected")
+				return &pb.UndeleteEntryResponse{Id: id.String(), Version: 3}, nil
+			}),
+		client.EXPECT().Undelete(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.AlreadyExists, "entry already exists")),
+	)
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{}, nil)
+
+	sut := env.newEntryUC(t, client)
+
+	_, err = sut.GetTrash(ctx)
+	require.ErrorIs(t, err, entities.ErrUserTokenNotFound, "expected token required")
+	env.cache.SetString("token", "token-value")
+
+	trash, err := sut.GetTrash(ctx)
+	require.NoError(t, err, "failed to get trash")
+	require.Len(t, trash.Entries, 1, "unexpected trash length")
+	require.Equal(t, id, trash.Entries[0].ID, "entry id mismatch")
+	require.Equal(t, entities.EntryDataNote("note_v1"), trash.Entries[0].Data, "entry data mismatch")
+	require.Equal(t, deletedAt, trash.Entries[0].UpdatedAt, "entry deleted at mismatch")
+
+	err = sut.Undelete(ctx, entities.UndeleteEntryRequest{ID: id})
+	require.NoError(t, err, "failed to undelete entry")
+	err = sut.Undelete(ctx, entities.UndeleteEntryRequest{ID: id})
+	require.ErrorIs(t, err, entities.ErrEntryExists, "expected entry key taken")
+	err = sut.Undelete(ctx, entities.UndeleteEntryRequest{})
+	require.ErrorIs(t, err, entities.ErrEntryIDInvalid, "expected invalid id")
+}
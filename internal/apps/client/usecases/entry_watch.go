@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const entryWatchRetryDelay = 5 * time.Second
+
+// Watch subscribes to the server entry changes until ctx is done and syncs entries on every change.
+// The subscription is restored after connection loss or sign-in, synced is called after every sync.
+func (uc *EntryUC) Watch(ctx context.Context, synced func()) {
+	for {
+		err := uc.watch(ctx, synced)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, entities.ErrUserTokenNotFound):
+		case err != nil:
+			uc.logger.Debug("entries watch stopped", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(entryWatchRetryDelay):
+		}
+	}
+}
+
+func (uc *EntryUC) watch(ctx context.Context, synced func()) error {
+	watchCtx, err := uc.appendToken(ctx)
+	if err != nil {
+		return err
+	}
+	stream, err := uc.entryClient.Watch(watchCtx, &pb.WatchEntriesRequest{})
+	if err != nil {
+		return uc.watchError(err)
+	}
+	// changes made while the subscription was down
+	uc.syncChanges(ctx, synced)
+	for {
+		if _, err = stream.Recv(); err != nil {
+			return uc.watchError(err)
+		}
+		uc.syncChanges(ctx, synced)
+	}
+}
+
+func (uc *EntryUC) syncChanges(ctx context.Context, synced func()) {
+	if err := uc.Sync(ctx); err != nil {
+		uc.logger.Debug("failed to sync entries on change", zap.Error(err))
+		return
+	}
+	synced()
+}
+
+func (uc *EntryUC) watchError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return fmt.Errorf("entry_usecase: failed to watch entries: %w: %w", entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("entry_usecase: failed to watch entries: %w: %w", entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("entry_usecase: failed to watch entries: %w", err)
+	}
+}
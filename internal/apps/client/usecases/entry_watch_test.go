@@ -0,0 +1,59 @@
+package usecases_test
+
+import (
+	"context"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestEntryUC_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := newEntryEnv(t, "watch_test")
+
+	stream := mocks.NewMockEntryService_WatchClient(ctrl)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&pb.WatchEntriesResponse{}, nil),
+		stream.EXPECT().Recv().DoAndReturn(func() (*pb.WatchEntriesResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+	)
+	client := mocks.NewMockEntryServiceClient(ctrl)
+	client.EXPECT().Watch(gomock.Any(), gomock.Any()).Return(stream, nil)
+	// initial full sync and incremental sync on change
+	client.EXPECT().GetDiff(gomock.Any(), gomock.Any()).Return(&pb.GetEntriesDiffResponse{}, nil)
+	client.EXPECT().GetChangesSince(gomock.Any(), gomock.Any()).Return(&pb.GetChangesSinceResponse{}, nil)
+
+	env.cache.SetString("token", "token-value")
+	sut := env.newEntryUC(t, client)
+
+	var (
+		synced = make(chan struct{}, 2)
+		done   = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		sut.Watch(ctx, func() { synced <- struct{}{} })
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-synced:
+		case <-time.After(time.Second):
+			require.Fail(t, "entries were not synced")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "watch was not stopped")
+	}
+}
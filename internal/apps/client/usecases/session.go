@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type SessionUC struct {
+	logger        *zap.Logger
+	cache         *mem.Cache
+	sessionClient pb.SessionServiceClient
+}
+
+func NewSessionUC(
+	logger *zap.Logger,
+	cache *mem.Cache,
+	sessionClient pb.SessionServiceClient,
+) *SessionUC {
+	return &SessionUC{
+		logger:        logger,
+		cache:         cache,
+		sessionClient: sessionClient,
+	}
+}
+
+// GetAll returns devices signed in to the server account, recently seen first
+func (uc *SessionUC) GetAll(ctx context.Context) (response entities.GetSessionsResponse, err error) {
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return response, err
+	}
+	resp, err := uc.sessionClient.GetAll(ctx, &pb.GetSessionsRequest{})
+	if err != nil {
+		uc.logger.Error("failed to get sessions", zap.Error(err))
+		return response, uc.sessionError("failed to get sessions", err)
+	}
+	response.Sessions = make([]entities.Session, 0, len(resp.Sessions))
+	for _, v := range resp.Sessions {
+		id, err := uuid.Parse(v.Id)
+		if err != nil {
+			return response, fmt.Errorf("session_usecase: invalid session id: %w", err)
+		}
+		response.Sessions = append(response.Sessions, entities.Session{
+			ID:         id,
+			Device:     v.Device,
+			IP:         v.Ip,
+			CreatedAt:  time.Unix(v.CreatedAt, 0).UTC(),
+			LastSeenAt: time.Unix(v.LastSeenAt, 0).UTC(),
+			Current:    v.Current,
+		})
+	}
+	return response, nil
+}
+
+// Revoke signs the device out, its tokens are rejected by the server from now on
+func (uc *SessionUC) Revoke(ctx context.Context, request entities.RevokeSessionRequest) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("session_usecase: invalid request: %w", err)
+	}
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return err
+	}
+	if _, err = uc.sessionClient.Revoke(ctx, &pb.RevokeSessionRequest{Id: request.ID.String()}); err != nil {
+		uc.logger.Error("failed to revoke session", zap.Error(err))
+		return uc.sessionError("failed to revoke session", err)
+	}
+	return nil
+}
+
+// RevokeOthers signs out all devices except the current one
+func (uc *SessionUC) RevokeOthers(ctx context.Context) (response entities.RevokeOtherSessionsResponse, err error) {
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return response, err
+	}
+	resp, err := uc.sessionClient.RevokeOthers(ctx, &pb.RevokeOtherSessionsRequest{})
+	if err != nil {
+		uc.logger.Error("failed to revoke other sessions", zap.Error(err))
+		return response, uc.sessionError("failed to revoke other sessions", err)
+	}
+	response.Revoked = resp.Revoked
+	return response, nil
+}
+
+func (uc *SessionUC) sessionError(msg string, err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("session_usecase: %s: %w: %w", msg, entities.ErrSessionIDInvalid, err)
+	case codes.NotFound:
+		return fmt.Errorf("session_usecase: %s: %w: %w", msg, entities.ErrSessionNotFound, err)
+	case codes.Unavailable:
+		return fmt.Errorf("session_usecase: %s: %w: %w", msg, entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("session_usecase: %s: %w: %w", msg, entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("session_usecase: %s: %w", msg, err)
+	}
+}
@@ -0,0 +1,66 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestSessionUC(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := uuid.New()
+	other := uuid.New()
+	seen := time.Now().Add(-time.Hour).Unix()
+	client := mocks.NewMockSessionServiceClient(ctrl)
+	client.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(&pb.GetSessionsResponse{
+		Sessions: []*pb.Session{
+			{Id: current.String(), Device: "laptop", Ip: "10.0.0.1", CreatedAt: seen, LastSeenAt: seen, Current: true},
+			{Id: other.String(), Device: "phone", Ip: "10.0.0.2", CreatedAt: seen, LastSeenAt: seen},
+		},
+	}, nil)
+	client.EXPECT().Revoke(gomock.Any(), &pb.RevokeSessionRequest{Id: other.String()}).
+		Return(&pb.RevokeSessionResponse{}, nil)
+	client.EXPECT().Revoke(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.NotFound, "session not found"))
+	client.EXPECT().RevokeOthers(gomock.Any(), gomock.Any()).
+		Return(&pb.RevokeOtherSessionsResponse{Revoked: 2}, nil)
+
+	cache := mem.NewCache()
+	sut := usecases.NewSessionUC(zaptest.NewLogger(t), cache, client)
+
+	_, err := sut.GetAll(ctx)
+	require.ErrorIs(t, err, entities.ErrUserTokenNotFound, "token is required")
+	cache.SetString("token", "token-value")
+
+	got, err := sut.GetAll(ctx)
+	require.NoError(t, err, "failed to get sessions")
+	require.Len(t, got.Sessions, 2, "all sessions expected")
+	require.Equal(t, current, got.Sessions[0].ID)
+	require.True(t, got.Sessions[0].Current, "current session should be marked")
+	require.Equal(t, "phone", got.Sessions[1].Device)
+	require.Equal(t, seen, got.Sessions[1].LastSeenAt.Unix())
+
+	err = sut.Revoke(ctx, entities.RevokeSessionRequest{})
+	require.ErrorIs(t, err, entities.ErrSessionIDInvalid, "session ID is required")
+	require.NoError(t, sut.Revoke(ctx, entities.RevokeSessionRequest{ID: other}), "failed to revoke session")
+	err = sut.Revoke(ctx, entities.RevokeSessionRequest{ID: other})
+	require.ErrorIs(t, err, entities.ErrSessionNotFound, "revoked session should not be found")
+
+	revoked, err := sut.RevokeOthers(ctx)
+	require.NoError(t, err, "failed to revoke other sessions")
+	require.Equal(t, int64(2), revoked.Revoked)
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type TwoFactorUC struct {
+	logger          *zap.Logger
+	cache           *mem.Cache
+	twoFactorClient pb.TwoFactorServiceClient
+}
+
+func NewTwoFactorUC(
+	logger *zap.Logger,
+	cache *mem.Cache,
+	twoFactorClient pb.TwoFactorServiceClient,
+) *TwoFactorUC {
+	return &TwoFactorUC{
+		logger:          logger,
+		cache:           cache,
+		twoFactorClient: twoFactorClient,
+	}
+}
+
+// Enroll returns a new secret to be added to an authenticator app, it's enabled only after Confirm
+func (uc *TwoFactorUC) Enroll(ctx context.Context) (response entities.EnrollOTPResponse, err error) {
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return response, err
+	}
+	resp, err := uc.twoFactorClient.Enroll(ctx, &pb.EnrollTwoFactorRequest{})
+	if err != nil {
+		uc.logger.Error("failed to enroll two-factor authentication", zap.Error(err))
+		return response, uc.twoFactorError("failed to enroll", err)
+	}
+	response.Secret = resp.Secret
+	response.URI = resp.Uri
+	return response, nil
+}
+
+// Confirm enables two-factor authentication, recovery codes are returned only once
+func (uc *TwoFactorUC) Confirm(
+	ctx context.Context,
+	request entities.ConfirmOTPRequest,
+) (response entities.ConfirmOTPResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("two_factor_usecase: invalid request: %w", err)
+	}
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return response, err
+	}
+	resp, err := uc.twoFactorClient.Confirm(ctx, &pb.ConfirmTwoFactorRequest{Code: request.Code})
+	if err != nil {
+		uc.logger.Error("failed to confirm two-factor authentication", zap.Error(err))
+		return response, uc.twoFactorError("failed to confirm", err)
+	}
+	response.RecoveryCodes = resp.RecoveryCodes
+	return response, nil
+}
+
+// Disable turns two-factor authentication off, the code could be a recovery code
+func (uc *TwoFactorUC) Disable(ctx context.Context, request entities.DisableOTPRequest) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("two_factor_usecase: invalid request: %w", err)
+	}
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return err
+	}
+	if _, err = uc.twoFactorClient.Disable(ctx, &pb.DisableTwoFactorRequest{Code: request.Code}); err != nil {
+		uc.logger.Error("failed to disable two-factor authentication", zap.Error(err))
+		return uc.twoFactorError("failed to disable", err)
+	}
+	return nil
+}
+
+func (uc *TwoFactorUC) twoFactorError(msg string, err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("two_factor_usecase: %s: %w: %w", msg, entities.ErrUserOTPInvalid, err)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("two_factor_usecase: %s: %w: %w", msg, entities.ErrUserOTPStateInvalid, err)
+	case codes.ResourceExhausted:
+		return fmt.Errorf("two_factor_usecase: %s: %w: %w", msg, entities.ErrUserOTPLocked, err)
+	case codes.Unavailable:
+		return fmt.Errorf("two_factor_usecase: %s: %w: %w", msg, entities.ErrServerUnavailable, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("two_factor_usecase: %s: %w: %w", msg, entities.ErrUserTokenInvalid, err)
+	default:
+		return fmt.Errorf("two_factor_usecase: %s: %w", msg, err)
+	}
+}
@@ -0,0 +1,60 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestTwoFactorUC(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockTwoFactorServiceClient(ctrl)
+	client.EXPECT().Enroll(gomock.Any(), gomock.Any()).Return(&pb.EnrollTwoFactorResponse{
+		Secret: "JBSWY3DPEHPK3PXP",
+		Uri:    "otpauth://totp/gophkeeper:login?secret=JBSWY3DPEHPK3PXP",
+	}, nil)
+	client.EXPECT().Confirm(gomock.Any(), &pb.ConfirmTwoFactorRequest{Code: "000000"}).
+		Return(nil, status.Error(codes.InvalidArgument, "invalid two-factor code"))
+	client.EXPECT().Confirm(gomock.Any(), &pb.ConfirmTwoFactorRequest{Code: "123456"}).
+		Return(&pb.ConfirmTwoFactorResponse{RecoveryCodes: []string{"aaaa-bbbb", "cccc-dddd"}}, nil)
+	client.EXPECT().Disable(gomock.Any(), &pb.DisableTwoFactorRequest{Code: "aaaa-bbbb"}).
+		Return(&pb.DisableTwoFactorResponse{}, nil)
+	client.EXPECT().Disable(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.FailedPrecondition, "two-factor authentication not enabled"))
+
+	cache := mem.NewCache()
+	sut := usecases.NewTwoFactorUC(zaptest.NewLogger(t), cache, client)
+
+	_, err := sut.Enroll(ctx)
+	require.ErrorIs(t, err, entities.ErrUserTokenNotFound, "token is required")
+	cache.SetString("token", "token-value")
+
+	enrolled, err := sut.Enroll(ctx)
+	require.NoError(t, err, "failed to enroll")
+	require.Equal(t, "JBSWY3DPEHPK3PXP", enrolled.Secret)
+	require.NotEmpty(t, enrolled.URI, "provisioning uri expected")
+
+	_, err = sut.Confirm(ctx, entities.ConfirmOTPRequest{})
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "code is required")
+	_, err = sut.Confirm(ctx, entities.ConfirmOTPRequest{Code: "000000"})
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "invalid code expected")
+	confirmed, err := sut.Confirm(ctx, entities.ConfirmOTPRequest{Code: "123456"})
+	require.NoError(t, err, "failed to confirm")
+	require.Equal(t, []string{"aaaa-bbbb", "cccc-dddd"}, confirmed.RecoveryCodes)
+
+	require.NoError(t, sut.Disable(ctx, entities.DisableOTPRequest{Code: "aaaa-bbbb"}), "failed to disable")
+	err = sut.Disable(ctx, entities.DisableOTPRequest{Code: "cccc-dddd"})
+	require.ErrorIs(t, err, entities.ErrUserOTPStateInvalid, "disabled two-factor expected")
+}
@@ -1,21 +1,43 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	sharedmd "github.com/dlomanov/gophkeeper/internal/apps/shared/md"
 	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/core"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	cacheKeyVaultSalt = "vault_salt"
+	// cacheKeyVaultKey keeps the vault key wrapped with the key derived from the master password,
+	// the vault key can't be derived from the changed master password anymore.
+	cacheKeyVaultKey = "vault_key"
+	// vaultCheck is sealed with the vault key by the first device of the account
+	vaultCheck = "gophkeeper_vault_check"
+)
+
 type (
 	UserUC struct {
 		logger     *zap.Logger
 		cache      *mem.Cache
 		userClient pb.UserServiceClient
+		keyring    Keyring
+		device     string
+	}
+	Keyring interface {
+		Unlock(salt core.Salt) error
+		Restore(key []byte) error
+		Encrypt(data []byte) ([]byte, error)
+		Decrypt(data []byte) ([]byte, error)
 	}
 )
 
@@ -23,11 +45,15 @@ func NewUserUC(
 	logger *zap.Logger,
 	cache *mem.Cache,
 	userClient pb.UserServiceClient,
+	keyring Keyring,
+	device string,
 ) *UserUC {
 	return &UserUC{
 		logger:     logger,
 		cache:      cache,
 		userClient: userClient,
+		keyring:    keyring,
+		device:     device,
 	}
 }
 
@@ -38,6 +64,7 @@ func (uc *UserUC) SignUp(
 	resp, err := uc.userClient.SignUp(ctx, &pb.SignUpUserRequest{
 		Login:    request.Login,
 		Password: request.Password,
+		Device:   uc.device,
 	})
 	switch {
 	case status.Code(err) == codes.AlreadyExists:
@@ -47,8 +74,12 @@ func (uc *UserUC) SignUp(
 	case err != nil:
 		return fmt.Errorf("user_sign_up: %w: %w", entities.ErrServerInternal, err)
 	}
-	uc.cache.SetString("login", request.Login)
+	if err = uc.openVault(ctx, resp.Token, resp.VaultSalt, nil, nil); err != nil {
+		return fmt.Errorf("user_sign_up: %w", err)
+	}
+	uc.setLogin(request.Login)
 	uc.cache.SetString("token", resp.Token)
+	uc.cache.SetString("refresh_token", resp.RefreshToken)
 	return nil
 }
 
@@ -59,8 +90,16 @@ func (uc *UserUC) SignIn(
 	resp, err := uc.userClient.SignIn(ctx, &pb.SignInUserRequest{
 		Login:    request.Login,
 		Password: request.Password,
+		Device:   uc.device,
+		Otp:      request.OTP,
 	})
 	switch {
+	case status.Code(err) == codes.FailedPrecondition:
+		return fmt.Errorf("user_sign_in: %w: %w", entities.ErrUserOTPRequired, err)
+	case status.Code(err) == codes.PermissionDenied:
+		return fmt.Errorf("user_sign_in: %w: %w", entities.ErrUserOTPInvalid, err)
+	case status.Code(err) == codes.ResourceExhausted:
+		return fmt.Errorf("user_sign_in: %w: %w", entities.ErrUserOTPLocked, err)
 	case status.Code(err) == codes.InvalidArgument:
 		return fmt.Errorf("user_sign_in: %w: %w", entities.ErrUserCredsInvalid, err)
 	case status.Code(err) == codes.Unauthenticated:
@@ -68,7 +107,190 @@ func (uc *UserUC) SignIn(
 	case err != nil:
 		return fmt.Errorf("user_sign_in: %w: %w", entities.ErrServerInternal, err)
 	}
-	uc.cache.SetString("login", request.Login)
+	if err = uc.openVault(ctx, resp.Token, resp.VaultSalt, resp.VaultKey, resp.VaultCheck); err != nil {
+		return fmt.Errorf("user_sign_in: %w", err)
+	}
+	uc.setLogin(request.Login)
+	uc.cache.SetString("token", resp.Token)
+	uc.cache.SetString("refresh_token", resp.RefreshToken)
+	return nil
+}
+
+// ChangePassword changes the server account password, the server signs out other devices
+// and issues new tokens for this one.
+func (uc *UserUC) ChangePassword(
+	ctx context.Context,
+	request entities.ChangePasswordRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("user_change_password: invalid request: %w", err)
+	}
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return fmt.Errorf("user_change_password: %w", err)
+	}
+	resp, err := uc.userClient.ChangePassword(ctx, &pb.ChangePasswordRequest{
+		OldPassword: request.Old,
+		NewPassword: request.New,
+		Otp:         request.OTP,
+	})
+	if err != nil {
+		uc.logger.Error("failed to change password", zap.Error(err))
+		return uc.accountError("user_change_password", err)
+	}
 	uc.cache.SetString("token", resp.Token)
+	uc.cache.SetString("refresh_token", resp.RefreshToken)
 	return nil
 }
+
+// DeleteAccount deletes the server account with all synced entries, local entries are kept
+func (uc *UserUC) DeleteAccount(
+	ctx context.Context,
+	request entities.DeleteAccountRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("user_delete_account: invalid request: %w", err)
+	}
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return fmt.Errorf("user_delete_account: %w", err)
+	}
+	_, err = uc.userClient.DeleteAccount(ctx, &pb.DeleteAccountRequest{
+		Password: request.Password,
+		Otp:      request.OTP,
+	})
+	if err != nil {
+		uc.logger.Error("failed to delete account", zap.Error(err))
+		return uc.accountError("user_delete_account", err)
+	}
+	uc.cache.SetString("token", "")
+	uc.cache.SetString("refresh_token", "")
+	uc.cache.SetString("login", "")
+	uc.cache.SetString(cacheKeyEntriesCursor, "")
+	return nil
+}
+
+func (uc *UserUC) UnlockVault() error {
+	saltBase64, ok := uc.cache.GetString(cacheKeyVaultSalt)
+	if !ok {
+		return nil
+	}
+	salt, err := core.NewSalt(saltBase64)
+	if err != nil {
+		return fmt.Errorf("user_unlock_vault: %w: %w", entities.ErrUserVaultSaltInvalid, err)
+	}
+	keyBase64, _ := uc.cache.GetString(cacheKeyVaultKey)
+	wrapped, err := base64.StdEncoding.DecodeString(keyBase64)
+	if err != nil {
+		return fmt.Errorf("user_unlock_vault: %w: %w", entities.ErrUserVaultKeyInvalid, err)
+	}
+	if err = uc.unlock(salt, wrapped); err != nil {
+		return fmt.Errorf("user_unlock_vault: %w", err)
+	}
+	return nil
+}
+
+// SetVaultKey stores the vault key wrapped with the changed master password on the server,
+// the empty key drops the stored one
+func (uc *UserUC) SetVaultKey(ctx context.Context, wrapped []byte) (err error) {
+	if ctx, err = appendToken(ctx, uc.cache); err != nil {
+		return fmt.Errorf("user_set_vault_key: %w", err)
+	}
+	if _, err = uc.userClient.SetVaultKey(ctx, &pb.SetVaultKeyRequest{VaultKey: wrapped}); err != nil {
+		uc.logger.Error("failed to set vault key", zap.Error(err))
+		return uc.accountError("user_set_vault_key", err)
+	}
+	return nil
+}
+
+// setLogin drops the entries cursor of the previous user, so entries of the new one are fully synced
+func (uc *UserUC) setLogin(login string) {
+	if prev, ok := uc.cache.GetString("login"); ok && prev != login {
+		uc.cache.SetString(cacheKeyEntriesCursor, "")
+	}
+	uc.cache.SetString("login", login)
+}
+
+// openVault unlocks the vault of the signed in account, the vault key must match the account vault check,
+// so entries synced by every device of the account are sealed with one key.
+// The vault of the previous account is unlocked back on failure.
+func (uc *UserUC) openVault(ctx context.Context, token string, salt core.Salt, wrapped []byte, check []byte) error {
+	err := uc.unlock(salt, wrapped)
+	if err == nil {
+		err = uc.checkVault(ctx, token, check)
+	}
+	if err != nil {
+		uc.logger.Error("failed to unlock vault", zap.Error(err))
+		if rerr := uc.UnlockVault(); rerr != nil {
+			uc.logger.Error("failed to unlock previous vault", zap.Error(rerr))
+		}
+		return err
+	}
+	uc.cache.SetString(cacheKeyVaultKey, base64.StdEncoding.EncodeToString(wrapped))
+	uc.cache.SetString(cacheKeyVaultSalt, salt.Base64String())
+	return nil
+}
+
+// checkVault sets the account vault check if it's not set yet and verifies it with the unlocked vault key
+func (uc *UserUC) checkVault(ctx context.Context, token string, check []byte) error {
+	if len(check) == 0 {
+		sealed, err := uc.keyring.Encrypt([]byte(vaultCheck))
+		if err != nil {
+			return fmt.Errorf("failed to seal vault check: %w", err)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV(token)...)
+		resp, err := uc.userClient.SetVaultCheck(ctx, &pb.SetVaultCheckRequest{VaultCheck: sealed})
+		if err != nil {
+			return uc.accountError("user_vault_check", err)
+		}
+		check = resp.VaultCheck
+	}
+	data, err := uc.keyring.Decrypt(check)
+	if err != nil || !bytes.Equal(data, []byte(vaultCheck)) {
+		return entities.ErrUserVaultKeyMismatch
+	}
+	return nil
+}
+
+// unlock derives the key from the master password and unwraps the vault key with it.
+// The key wrapped with another master password is ignored, the derived key is checked against the vault check then.
+func (uc *UserUC) unlock(salt core.Salt, wrapped []byte) error {
+	if err := uc.keyring.Unlock(salt); err != nil {
+		return err
+	}
+	if len(wrapped) == 0 {
+		return nil
+	}
+	key, err := uc.keyring.Decrypt(wrapped)
+	if err != nil {
+		uc.logger.Debug("vault key is wrapped with another master password", zap.Error(err))
+		return nil
+	}
+	return uc.keyring.Restore(key)
+}
+
+func (uc *UserUC) accountError(op string, err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrUserCredsInvalid, err)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrUserOTPRequired, err)
+	case codes.ResourceExhausted:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrUserOTPLocked, err)
+	case codes.InvalidArgument:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrUserPasswordInvalid, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrUserTokenInvalid, err)
+	case codes.Unavailable:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrServerUnavailable, err)
+	default:
+		return fmt.Errorf("%s: %w: %w", op, entities.ErrServerInternal, err)
+	}
+}
+
+func appendToken(ctx context.Context, cache *mem.Cache) (context.Context, error) {
+	token, ok := cache.GetString("token")
+	if !ok {
+		return nil, entities.ErrUserTokenNotFound
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, sharedmd.NewTokenKV(token)...)
+	return ctx, nil
+}
@@ -0,0 +1,249 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"go.uber.org/zap"
+)
+
+type (
+	MasterPassUC struct {
+		logger       *zap.Logger
+		userAuthUC   *UserAuthUC
+		userUC       *UserUC
+		hasher       Hasher
+		storage      Storage
+		entryRepo    EntryRepo
+		conflictRepo EntryConflictRepo
+		dbKeyRepo    DBKeyRepo
+		keyring      VaultKeyring
+		cache        *mem.Cache
+		tx           *manager.Manager
+	}
+	DBKeyRepo interface {
+		Rekey(ctx context.Context, key core.Pass) error
+	}
+	VaultKeyring interface {
+		Key() ([]byte, error)
+	}
+	masterKey struct {
+		hash core.PassHash
+		salt core.Salt
+	}
+)
+
+func NewMasterPassUC(
+	logger *zap.Logger,
+	userAuthUC *UserAuthUC,
+	userUC *UserUC,
+	hasher Hasher,
+	storage Storage,
+	entryRepo EntryRepo,
+	conflictRepo EntryConflictRepo,
+	dbKeyRepo DBKeyRepo,
+	keyring VaultKeyring,
+	cache *mem.Cache,
+	tx *manager.Manager,
+) *MasterPassUC {
+	return &MasterPassUC{
+		logger:       logger,
+		userAuthUC:   userAuthUC,
+		userUC:       userUC,
+		hasher:       hasher,
+		storage:      storage,
+		entryRepo:    entryRepo,
+		conflictRepo: conflictRepo,
+		dbKeyRepo:    dbKeyRepo,
+		keyring:      keyring,
+		cache:        cache,
+		tx:           tx,
+	}
+}
+
+// Change re-encrypts the local vault with the key derived from the new master password.
+// Entries and conflicts are re-encrypted in one transaction, then the database is re-keyed.
+// The vault key is wrapped with the new master password and stored on the server first,
+// so the change is refused while the vault is locked or the server is unreachable.
+// Dependencies hold the previous key, so the client should be restarted after that.
+func (uc *MasterPassUC) Change(ctx context.Context, request entities.ChangeMasterPassRequest) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("master_pass_usecase: invalid request: %w", err)
+	}
+	hash, err := uc.userAuthUC.Auth(ctx, request.Old)
+	if err != nil {
+		return fmt.Errorf("master_pass_usecase: %w", err)
+	}
+	salt, err := uc.storage.Get(ctx, storageKeyUserSalt)
+	if err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to get salt: %w", err)
+	}
+	prev := masterKey{hash: hash}
+	if prev.salt, err = core.NewSalt(salt); err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to create salt from base64: %w", err)
+	}
+	next := masterKey{}
+	if next.salt, err = uc.hasher.GenerateSalt(); err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to generate salt: %w", err)
+	}
+	if next.hash, err = uc.hasher.Hash(request.New, next.salt); err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to hash pass: %w", err)
+	}
+
+	wrapped, err := uc.wrapVaultKey(request.New)
+	if err != nil {
+		return err
+	}
+	prevWrapped, _ := uc.cache.GetString(cacheKeyVaultKey)
+	if wrapped != nil {
+		if err = uc.userUC.SetVaultKey(ctx, wrapped); err != nil {
+			return fmt.Errorf("master_pass_usecase: %w", err)
+		}
+	}
+
+	if err = uc.reencrypt(ctx, prev, next, base64.StdEncoding.EncodeToString(wrapped)); err != nil {
+		uc.logger.Error("failed to re-encrypt vault", zap.Error(err))
+		return errors.Join(err, uc.restoreVaultKey(ctx, wrapped, prevWrapped))
+	}
+	if err = uc.dbKeyRepo.Rekey(ctx, request.New); err != nil {
+		uc.logger.Error("failed to rekey database", zap.Error(err))
+		if rerr := uc.reencrypt(ctx, next, prev, prevWrapped); rerr != nil {
+			uc.logger.Error("failed to restore vault encryption", zap.Error(rerr))
+			err = errors.Join(err, rerr)
+		}
+		return fmt.Errorf("master_pass_usecase: %w", errors.Join(err, uc.restoreVaultKey(ctx, wrapped, prevWrapped)))
+	}
+	return nil
+}
+
+// wrapVaultKey seals the vault key with the key derived from the new master password and the vault salt,
+// nil is returned if the client has never been signed in and has no vault.
+func (uc *MasterPassUC) wrapVaultKey(pass core.Pass) ([]byte, error) {
+	saltBase64, ok := uc.cache.GetString(cacheKeyVaultSalt)
+	if !ok || saltBase64 == "" {
+		return nil, nil
+	}
+	// the vault key can't be derived from the new master password, so the locked one would be lost
+	key, err := uc.keyring.Key()
+	if err != nil {
+		return nil, fmt.Errorf("master_pass_usecase: failed to get vault key: %w", err)
+	}
+	salt, err := core.NewSalt(saltBase64)
+	if err != nil {
+		return nil, fmt.Errorf("master_pass_usecase: %w: %w", entities.ErrUserVaultSaltInvalid, err)
+	}
+	hash, err := uc.hasher.Hash(pass, salt)
+	if err != nil {
+		return nil, fmt.Errorf("master_pass_usecase: failed to hash pass: %w", err)
+	}
+	encrypter, err := encrypto.NewEncrypter(hash)
+	if err != nil {
+		return nil, fmt.Errorf("master_pass_usecase: failed to create encrypter: %w", err)
+	}
+	wrapped, err := encrypter.Encrypt(key)
+	if err != nil {
+		return nil, fmt.Errorf("master_pass_usecase: failed to wrap vault key: %w", err)
+	}
+	return wrapped, nil
+}
+
+// restoreVaultKey puts the previous wrapped vault key back on the server after the failed change
+func (uc *MasterPassUC) restoreVaultKey(ctx context.Context, wrapped []byte, prevBase64 string) error {
+	if wrapped == nil {
+		return nil
+	}
+	prev, err := base64.StdEncoding.DecodeString(prevBase64)
+	if err != nil {
+		return fmt.Errorf("master_pass_usecase: %w: %w", entities.ErrUserVaultKeyInvalid, err)
+	}
+	if err = uc.userUC.SetVaultKey(ctx, prev); err != nil {
+		uc.logger.Error("failed to restore vault key", zap.Error(err))
+		return fmt.Errorf("master_pass_usecase: %w", err)
+	}
+	return nil
+}
+
+func (uc *MasterPassUC) reencrypt(
+	ctx context.Context,
+	from masterKey,
+	to masterKey,
+	wrappedKey string,
+) error {
+	decrypter, err := encrypto.NewEncrypter(from.hash)
+	if err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to create encrypter: %w", err)
+	}
+	encrypter, err := encrypto.NewEncrypter(to.hash)
+	if err != nil {
+		return fmt.Errorf("master_pass_usecase: failed to create encrypter: %w", err)
+	}
+	reencrypt := func(data []byte) ([]byte, error) {
+		decrypted, err := decrypter.Decrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("master_pass_usecase: failed to decrypt data: %w", err)
+		}
+		return encrypter.Encrypt(decrypted)
+	}
+
+	values := map[string]string{
+		storageKeyUserPassHash: to.hash.Base64String(),
+		storageKeyUserSalt:     to.salt.Base64String(),
+		cacheKeyVaultKey:       wrappedKey,
+	}
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		entries, err := uc.entryRepo.GetAll(ctx)
+		if err != nil {
+			return fmt.Errorf("master_pass_usecase: failed to get entries: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.Data, err = reencrypt(entry.Data); err != nil {
+				return err
+			}
+			if err = uc.entryRepo.Update(ctx, entry); err != nil {
+				return fmt.Errorf("master_pass_usecase: failed to update entry: %w", err)
+			}
+		}
+
+		conflicts, err := uc.conflictRepo.GetAll(ctx)
+		if err != nil {
+			return fmt.Errorf("master_pass_usecase: failed to get conflicts: %w", err)
+		}
+		for _, conflict := range conflicts {
+			if conflict.Local.Data, err = reencrypt(conflict.Local.Data); err != nil {
+				return err
+			}
+			if conflict.Remote.Data, err = reencrypt(conflict.Remote.Data); err != nil {
+				return err
+			}
+			if conflict.Base != nil {
+				if conflict.Base.Data, err = reencrypt(conflict.Base.Data); err != nil {
+					return err
+				}
+			}
+			if err = uc.conflictRepo.Save(ctx, conflict); err != nil {
+				return fmt.Errorf("master_pass_usecase: failed to save conflict: %w", err)
+			}
+		}
+
+		for k, v := range values {
+			if err = uc.storage.Set(ctx, k, v); err != nil {
+				return fmt.Errorf("master_pass_usecase: failed to set %s: %w", k, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// memcache is flushed on exit, previous values would be written back otherwise
+	for k, v := range values {
+		uc.cache.SetString(k, v)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/base64"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/repo"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/pass"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/vault"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/migrations"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
+	"github.com/dlomanov/gophkeeper/internal/infra/migrator"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"testing"
+	"time"
+)
+
+func TestMasterPassUC(t *testing.T) {
+	ctx := context.Background()
+	logger := zaptest.NewLogger(t)
+	db, err := sqlx.Open("sqlite3", "file:master_pass_test.db?cache=shared&mode=memory")
+	require.NoError(t, err, "failed to open database")
+	defer func() { _ = db.Close() }()
+	ms, err := migrations.GetMigrations()
+	require.NoError(t, err, "failed to get migrations")
+	require.NoError(t, migrator.Migrate(logger.Sugar(), db.DB, ms), "failed to up migrations")
+	trm, err := manager.New(trmsqlx.NewDefaultFactory(db))
+	require.NoError(t, err, "failed to create transaction manager")
+
+	kvRepo := repo.NewKVPairRepo(db, trmsqlx.DefaultCtxGetter, trm)
+	entryRepo := repo.NewEntryRepo(db, trmsqlx.DefaultCtxGetter)
+	conflictRepo := repo.NewEntryConflictRepo(db, trmsqlx.DefaultCtxGetter)
+	userAuthUC := usecases.NewUserAuthUC(&pass.Hasher{}, kvRepo, trm)
+	hash, err := userAuthUC.Auth(ctx, core.Pass("old"))
+	require.NoError(t, err, "failed to auth user")
+
+	encrypter, err := encrypto.NewEncrypter(hash)
+	require.NoError(t, err, "failed to create encrypter")
+	encrypt := func(data string) []byte {
+		encrypted, err := encrypter.Encrypt([]byte(data))
+		require.NoError(t, err, "failed to encrypt data")
+		return encrypted
+	}
+	now := time.Now().UTC()
+	entry := entities.Entry{
+		ID:        uuid.New(),
+		Key:       "key1",
+		Type:      core.EntryTypeNote,
+		Data:      encrypt("note"),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, entryRepo.Create(ctx, entry), "failed to create entry")
+	conflict := entities.EntryConflict{
+		ID:        uuid.New(),
+		Key:       "key2",
+		Local:     entities.EntryConflictVersion{Type: core.EntryTypeNote, Data: encrypt("local"), Version: 1},
+		Remote:    entities.EntryConflictVersion{Type: core.EntryTypeNote, Data: encrypt("remote"), Version: 2},
+		CreatedAt: now,
+	}
+	require.NoError(t, conflictRepo.Save(ctx, conflict), "failed to save conflict")
+
+	salt := core.Salt("1234567890123456")
+	keyring := vault.NewKeyring(pass.Hasher{}, core.Pass("old"))
+	require.NoError(t, keyring.Unlock(salt), "failed to unlock vault")
+	sealed, err := keyring.Encrypt([]byte("synced"))
+	require.NoError(t, err, "failed to seal data")
+
+	cache := mem.NewCache()
+	cache.SetString("vault_salt", salt.Base64String())
+	cache.SetString("token", "token-value")
+	var stored []byte
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockUserServiceClient(ctrl)
+	client.EXPECT().SetVaultKey(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *pb.SetVaultKeyRequest, _ ...grpc.CallOption) (*pb.SetVaultKeyResponse, error) {
+			stored = in.VaultKey
+			return &pb.SetVaultKeyResponse{}, nil
+		})
+	newSUT := func(keyring *vault.Keyring) *usecases.MasterPassUC {
+		return usecases.NewMasterPassUC(
+			logger,
+			userAuthUC,
+			usecases.NewUserUC(logger, cache, client, keyring, "laptop"),
+			&pass.Hasher{},
+			kvRepo,
+			entryRepo,
+			conflictRepo,
+			repo.NewDBKeyRepo(db),
+			keyring,
+			cache,
+			trm,
+		)
+	}
+	sut := newSUT(keyring)
+
+	err = newSUT(vault.NewKeyring(pass.Hasher{}, core.Pass("old"))).Change(ctx, entities.ChangeMasterPassRequest{
+		Old: core.Pass("old"),
+		New: core.Pass("new"),
+	})
+	require.ErrorIs(t, err, entities.ErrUserVaultLocked, "change should be refused while vault is locked")
+	err = sut.Change(ctx, entities.ChangeMasterPassRequest{Old: core.Pass("wrong"), New: core.Pass("new")})
+	require.ErrorIs(t, err, entities.ErrUserMasterPassInvalid, "current password should be checked")
+	err = sut.Change(ctx, entities.ChangeMasterPassRequest{Old: core.Pass("old"), New: core.Pass("old")})
+	require.ErrorIs(t, err, entities.ErrUserPasswordInvalid, "new password should differ")
+	require.NoError(t, sut.Change(ctx, entities.ChangeMasterPassRequest{
+		Old: core.Pass("old"),
+		New: core.Pass("new"),
+	}), "failed to change master password")
+
+	_, err = userAuthUC.Auth(ctx, core.Pass("old"))
+	require.ErrorIs(t, err, entities.ErrUserMasterPassInvalid, "old password should be rejected")
+	newHash, err := userAuthUC.Auth(ctx, core.Pass("new"))
+	require.NoError(t, err, "new password should be accepted")
+	cached, _ := cache.GetString("user_pass_hash")
+	require.Equal(t, newHash.Base64String(), cached, "memcache should not flush the old hash")
+
+	decrypter, err := encrypto.NewEncrypter(newHash)
+	require.NoError(t, err, "failed to create encrypter")
+	got, err := entryRepo.Get(ctx, entry.ID)
+	require.NoError(t, err, "failed to get entry")
+	data, err := decrypter.Decrypt(got.Data)
+	require.NoError(t, err, "entry should be re-encrypted")
+	require.Equal(t, "note", string(data))
+	gotConflict, err := conflictRepo.Get(ctx, conflict.ID)
+	require.NoError(t, err, "failed to get conflict")
+	data, err = decrypter.Decrypt(gotConflict.Remote.Data)
+	require.NoError(t, err, "conflict should be re-encrypted")
+	require.Equal(t, "remote", string(data))
+
+	cached, _ = cache.GetString("vault_key")
+	require.Equal(t, base64.StdEncoding.EncodeToString(stored), cached, "wrapped vault key should be stored on server")
+	key, err := keyring.Key()
+	require.NoError(t, err, "failed to get vault key")
+	device := vault.NewKeyring(pass.Hasher{}, core.Pass("new"))
+	require.NoError(t, device.Unlock(salt), "failed to unlock vault")
+	unwrapped, err := device.Decrypt(stored)
+	require.NoError(t, err, "vault key should be wrapped with new master password")
+	require.Equal(t, key, unwrapped)
+	restarted := vault.NewKeyring(pass.Hasher{}, core.Pass("new"))
+	userUC := usecases.NewUserUC(logger, cache, nil, restarted, "laptop")
+	require.NoError(t, userUC.UnlockVault(), "failed to unlock vault")
+	data, err = restarted.Decrypt(sealed)
+	require.NoError(t, err, "synced data should be readable after restart")
+	require.Equal(t, "synced", string(data))
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/mem"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/pass"
+	"github.com/dlomanov/gophkeeper/internal/apps/client/infra/services/vault"
 	"github.com/dlomanov/gophkeeper/internal/apps/client/usecases"
 	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
 	"github.com/dlomanov/gophkeeper/internal/apps/shared/proto/mocks"
+	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"testing"
@@ -46,7 +50,7 @@ func TestUserUC_SignUp(t *testing.T) {
 		},
 		{
 			name:        "ok",
-			response:    &proto.SignUpUserResponse{Token: "token"},
+			response:    &proto.SignUpUserResponse{Token: "token", RefreshToken: "refresh_token", VaultSalt: []byte("salt")},
 			responseErr: nil,
 			wantErr:     require.NoError,
 			wantCache: func(t require.TestingT, value any, i ...any) {
@@ -57,6 +61,11 @@ func TestUserUC_SignUp(t *testing.T) {
 				token, ok := c.GetString("token")
 				require.True(t, ok, "token should be in cache")
 				require.Equal(t, "token", token)
+				refreshToken, ok := c.GetString("refresh_token")
+				require.True(t, ok, "refresh token should be in cache")
+				require.Equal(t, "refresh_token", refreshToken)
+				_, ok = c.GetString("vault_salt")
+				require.True(t, ok, "vault salt should be in cache")
 			},
 		},
 	}
@@ -69,17 +78,24 @@ func TestUserUC_SignUp(t *testing.T) {
 			client.EXPECT().SignUp(
 				gomock.Any(),
 				gomock.Any()).Return(tt.response, tt.responseErr)
+			client.EXPECT().SetVaultCheck(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(_ context.Context, in *proto.SetVaultCheckRequest, _ ...grpc.CallOption) (*proto.SetVaultCheckResponse, error) {
+					return &proto.SetVaultCheckResponse{VaultCheck: in.VaultCheck}, nil
+				})
 			cache := mem.NewCache()
 			sut := usecases.NewUserUC(
 				zaptest.NewLogger(t),
 				cache,
-				client)
+				client,
+				vault.NewKeyring(pass.Hasher{}, core.Pass("master")),
+				"test device")
 
 			err := sut.SignUp(context.Background(), entities.SignUpUserRequest{
 				Login:    "login",
 				Password: "password",
 			})
 			tt.wantErr(t, err)
+			tt.wantCache(t, cache)
 		})
 	}
 }
@@ -106,6 +122,20 @@ func TestUserUC_SignIn(t *testing.T) {
 			wantErr:     exactErr(entities.ErrUserCredsInvalid),
 			wantCache:   nothing,
 		},
+		{
+			name:        "two-factor code required error",
+			response:    &proto.SignInUserResponse{},
+			responseErr: status.Error(codes.FailedPrecondition, ""),
+			wantErr:     exactErr(entities.ErrUserOTPRequired),
+			wantCache:   nothing,
+		},
+		{
+			name:        "invalid two-factor code error",
+			response:    &proto.SignInUserResponse{},
+			responseErr: status.Error(codes.PermissionDenied, ""),
+			wantErr:     exactErr(entities.ErrUserOTPInvalid),
+			wantCache:   nothing,
+		},
 		{
 			name:        "internal server error",
 			response:    &proto.SignInUserResponse{},
@@ -115,7 +145,7 @@ func TestUserUC_SignIn(t *testing.T) {
 		},
 		{
 			name:        "ok",
-			response:    &proto.SignInUserResponse{Token: "token"},
+			response:    &proto.SignInUserResponse{Token: "token", RefreshToken: "refresh_token", VaultSalt: []byte("salt")},
 			responseErr: nil,
 			wantErr:     require.NoError,
 			wantCache: func(t require.TestingT, value any, i ...any) {
@@ -126,6 +156,60 @@ func TestUserUC_SignIn(t *testing.T) {
 				token, ok := c.GetString("token")
 				require.True(t, ok, "token should be in cache")
 				require.Equal(t, "token", token)
+				refreshToken, ok := c.GetString("refresh_token")
+				require.True(t, ok, "refresh token should be in cache")
+				require.Equal(t, "refresh_token", refreshToken)
+				_, ok = c.GetString("vault_salt")
+				require.True(t, ok, "vault salt should be in cache")
+			},
+		},
+		{
+			name:        "vault check ok",
+			response:    &proto.SignInUserResponse{Token: "token", VaultSalt: []byte("salt"), VaultCheck: sealVaultCheck(t, "master")},
+			responseErr: nil,
+			wantErr:     require.NoError,
+			wantCache:   nothing,
+		},
+		{
+			name: "wrapped vault key ok",
+			response: &proto.SignInUserResponse{
+				Token:      "token",
+				VaultSalt:  []byte("salt"),
+				VaultKey:   wrapVaultKey(t, "master", "other"),
+				VaultCheck: sealVaultCheck(t, "other"),
+			},
+			responseErr: nil,
+			wantErr:     require.NoError,
+			wantCache: func(t require.TestingT, value any, i ...any) {
+				c := value.(*mem.Cache)
+				key, ok := c.GetString("vault_key")
+				require.True(t, ok, "wrapped vault key should be in cache")
+				require.NotEmpty(t, key)
+			},
+		},
+		{
+			name: "vault key wrapped with other master password ok",
+			response: &proto.SignInUserResponse{
+				Token:      "token",
+				VaultSalt:  []byte("salt"),
+				VaultKey:   wrapVaultKey(t, "other", "master"),
+				VaultCheck: sealVaultCheck(t, "master"),
+			},
+			responseErr: nil,
+			wantErr:     require.NoError,
+			wantCache:   nothing,
+		},
+		{
+			name:        "vault key mismatch error",
+			response:    &proto.SignInUserResponse{Token: "token", VaultSalt: []byte("salt"), VaultCheck: sealVaultCheck(t, "other")},
+			responseErr: nil,
+			wantErr:     exactErr(entities.ErrUserVaultKeyMismatch),
+			wantCache: func(t require.TestingT, value any, i ...any) {
+				c := value.(*mem.Cache)
+				_, ok := c.GetString("token")
+				require.False(t, ok, "token shouldn't be in cache")
+				_, ok = c.GetString("vault_salt")
+				require.False(t, ok, "vault salt shouldn't be in cache")
 			},
 		},
 	}
@@ -138,21 +222,109 @@ func TestUserUC_SignIn(t *testing.T) {
 			client.EXPECT().SignIn(
 				gomock.Any(),
 				gomock.Any()).Return(tt.response, tt.responseErr)
+			client.EXPECT().SetVaultCheck(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(_ context.Context, in *proto.SetVaultCheckRequest, _ ...grpc.CallOption) (*proto.SetVaultCheckResponse, error) {
+					return &proto.SetVaultCheckResponse{VaultCheck: in.VaultCheck}, nil
+				})
 			cache := mem.NewCache()
 			sut := usecases.NewUserUC(
 				zaptest.NewLogger(t),
 				cache,
-				client)
+				client,
+				vault.NewKeyring(pass.Hasher{}, core.Pass("master")),
+				"test device")
 
 			err := sut.SignIn(context.Background(), entities.SignInUserRequest{
 				Login:    "login",
 				Password: "password",
 			})
 			tt.wantErr(t, err)
+			tt.wantCache(t, cache)
 		})
 	}
 }
 
+func TestUserUC_Account(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockUserServiceClient(ctrl)
+	cache := mem.NewCache()
+	sut := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		cache,
+		client,
+		vault.NewKeyring(pass.Hasher{}, core.Pass("master")),
+		"test device")
+
+	request := entities.ChangePasswordRequest{Old: "old", New: "new"}
+	err := sut.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserTokenNotFound, "token required")
+	cache.SetString("token", "token")
+	cache.SetString("login", "login")
+	err = sut.ChangePassword(ctx, entities.ChangePasswordRequest{Old: "old", New: "old"})
+	require.ErrorIs(t, err, entities.ErrUserPasswordInvalid, "new password should differ")
+
+	gomock.InOrder(
+		client.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.PermissionDenied, "")),
+		client.EXPECT().ChangePassword(gomock.Any(), &proto.ChangePasswordRequest{OldPassword: "old", NewPassword: "new"}).
+			Return(&proto.ChangePasswordResponse{Token: "token2", RefreshToken: "refresh_token2"}, nil),
+		client.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.PermissionDenied, "")),
+		client.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{Password: "new"}).
+			Return(nil, status.Error(codes.FailedPrecondition, "")),
+		client.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{Password: "new", Otp: "000000"}).
+			Return(nil, status.Error(codes.ResourceExhausted, "")),
+		client.EXPECT().DeleteAccount(gomock.Any(), &proto.DeleteAccountRequest{Password: "new", Otp: "123456"}).
+			Return(&proto.DeleteAccountResponse{}, nil),
+	)
+	err = sut.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "invalid current password")
+	require.NoError(t, sut.ChangePassword(ctx, request), "failed to change password")
+	token, _ := cache.GetString("token")
+	require.Equal(t, "token2", token, "token should be replaced")
+	refreshToken, _ := cache.GetString("refresh_token")
+	require.Equal(t, "refresh_token2", refreshToken, "refresh token should be replaced")
+
+	err = sut.DeleteAccount(ctx, entities.DeleteAccountRequest{})
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "password required")
+	err = sut.DeleteAccount(ctx, entities.DeleteAccountRequest{Password: "old"})
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "invalid password")
+	err = sut.DeleteAccount(ctx, entities.DeleteAccountRequest{Password: "new"})
+	require.ErrorIs(t, err, entities.ErrUserOTPRequired, "two-factor code required")
+	err = sut.DeleteAccount(ctx, entities.DeleteAccountRequest{Password: "new", OTP: "000000"})
+	require.ErrorIs(t, err, entities.ErrUserOTPLocked, "two-factor attempts exceeded")
+	err = sut.DeleteAccount(ctx, entities.DeleteAccountRequest{Password: "new", OTP: "123456"})
+	require.NoError(t, err, "failed to delete account")
+	token, _ = cache.GetString("token")
+	require.Empty(t, token, "token should be dropped")
+	login, _ := cache.GetString("login")
+	require.Empty(t, login, "login should be dropped")
+}
+
+// sealVaultCheck seals the vault check with the vault key of the master password and the test salt
+func sealVaultCheck(t *testing.T, master string) []byte {
+	keyring := vault.NewKeyring(pass.Hasher{}, core.Pass(master))
+	require.NoError(t, keyring.Unlock(core.Salt("salt")), "failed to unlock vault")
+	sealed, err := keyring.Encrypt([]byte("gophkeeper_vault_check"))
+	require.NoError(t, err, "failed to seal vault check")
+	return sealed
+}
+
+// wrapVaultKey wraps the vault key of the owner master password with the key of the master password
+func wrapVaultKey(t *testing.T, master string, owner string) []byte {
+	keyring := vault.NewKeyring(pass.Hasher{}, core.Pass(owner))
+	require.NoError(t, keyring.Unlock(core.Salt("salt")), "failed to unlock vault")
+	key, err := keyring.Key()
+	require.NoError(t, err, "failed to get vault key")
+	wrapper := vault.NewKeyring(pass.Hasher{}, core.Pass(master))
+	require.NoError(t, wrapper.Unlock(core.Salt("salt")), "failed to unlock vault")
+	wrapped, err := wrapper.Encrypt(key)
+	require.NoError(t, err, "failed to wrap vault key")
+	return wrapped
+}
+
 func exactErr(err error) require.ErrorAssertionFunc {
 	return func(t require.TestingT, err2 error, i ...any) {
 		require.ErrorIs(t, err2, err, i)
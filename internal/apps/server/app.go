@@ -16,6 +16,12 @@ import (
 	"time"
 )
 
+const (
+	trashPurgeInterval   = time.Hour
+	requestPurgeInterval = time.Hour
+	blobPurgeInterval    = time.Hour
+)
+
 func Run(ctx context.Context, config *config.Config) error {
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid config: %w", err)
@@ -38,6 +44,12 @@ func Run(ctx context.Context, config *config.Config) error {
 	}
 	defer closeContainer(c)
 
+	purgeCtx, stopPurge := context.WithCancel(ctx)
+	defer stopPurge()
+	go purgeTrash(purgeCtx, c)
+	go purgeRequests(purgeCtx, c)
+	go purgeBlobs(purgeCtx, c)
+
 	grpcsrv := startGRPC(ctx, c)
 	wait(ctx, c, grpcsrv)
 	shutdownGRPC(c, grpcsrv)
@@ -51,6 +63,61 @@ func closeContainer(c *deps.Container) {
 	}
 }
 
+// purgeTrash periodically removes entries deleted longer than the trash retention
+func purgeTrash(ctx context.Context, c *deps.Container) {
+	if c.Config.TrashRetention == 0 {
+		c.Logger.Debug("trash retention is not set, deleted entries are kept forever")
+		return
+	}
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := c.EntryUC.Purge(ctx, c.Config.TrashRetention)
+		if err == nil && purged > 0 {
+			c.Logger.Info("deleted entries purged", zap.Int64("count", purged))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeBlobs periodically removes uploaded blobs which entries were never pushed
+func purgeBlobs(ctx context.Context, c *deps.Container) {
+	ticker := time.NewTicker(blobPurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := c.BlobUC.PurgeOrphans(ctx)
+		if err == nil && purged > 0 {
+			c.Logger.Info("orphan blobs purged", zap.Int64("count", purged))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeRequests periodically removes request IDs kept for replaying retries
+func purgeRequests(ctx context.Context, c *deps.Container) {
+	ticker := time.NewTicker(requestPurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := c.EntryUC.PurgeRequests(ctx)
+		if err == nil && purged > 0 {
+			c.Logger.Debug("entry requests purged", zap.Int64("count", purged))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func startGRPC(ctx context.Context, c *deps.Container) *grpcserver.Server {
 	opts := []grpcserver.Option{
 		grpcserver.Addr(c.Config.Address),
@@ -2,7 +2,6 @@ package config
 
 import (
 	"errors"
-	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
 	"time"
 )
 
@@ -13,9 +12,10 @@ type (
 		PassHashCost   int           // Password hash cost
 		TokenSecretKey []byte        // Token secret key
 		TokenExpires   time.Duration // Token expires
+		RefreshExpires time.Duration // Refresh token expires
+		TrashRetention time.Duration // Deleted entries retention, 0 keeps them forever
 		LogLevel       string        // Log level
 		LogType        string        // Log type
-		DataSecretKey  []byte        // Data secret key
 		Cert           []byte
 		CertKey        []byte
 	}
@@ -38,15 +38,18 @@ func (c Config) Validate() error {
 	if c.TokenExpires <= 0 {
 		errs = append(errs, errors.New("token expires should be specified"))
 	}
+	if c.RefreshExpires <= 0 {
+		errs = append(errs, errors.New("refresh token expires should be specified"))
+	}
+	if c.TrashRetention < 0 {
+		errs = append(errs, errors.New("trash retention should not be negative"))
+	}
 	if c.LogLevel == "" {
 		errs = append(errs, errors.New("log level should be specified"))
 	}
 	if c.LogType == "" {
 		errs = append(errs, errors.New("log type should be specified"))
 	}
-	if !encrypto.KeyValid(c.DataSecretKey) {
-		errs = append(errs, errors.New("data secret key should be specified"))
-	}
 	if len(c.Cert) == 0 || len(c.CertKey) == 0 {
 		errs = append(errs, errors.New("TLS certificate should be specified"))
 	}
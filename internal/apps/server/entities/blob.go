@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// BlobChunkMaxSize leaves room for the client-side encryption overhead
+	BlobChunkMaxSize = 512*1024 + 1024
+	BlobMaxSize      = 1024 * 1024 * 1024
+	// BlobOrphanRetention is how long the uploaded blob waits for its entry to be pushed
+	BlobOrphanRetention = 7 * 24 * time.Hour
+)
+
+type (
+	BlobChunk struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		Index     int
+		Data      []byte
+		CreatedAt time.Time
+	}
+	UploadBlobRequest struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+		// Version is the version of the entry the blob replaces, zero for the entry that isn't pushed yet
+		Version int64
+		Next    func() ([]byte, error)
+	}
+	UploadBlobResponse struct {
+		ID   uuid.UUID
+		Size int64
+	}
+	DownloadBlobRequest struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+		Send   func(chunk []byte) error
+	}
+)
+
+func NewBlobChunk(
+	id uuid.UUID,
+	userID uuid.UUID,
+	index int,
+	data []byte,
+) (*BlobChunk, error) {
+	var err error
+	if id == uuid.Nil {
+		err = errors.Join(err, ErrBlobIDInvalid)
+	}
+	if userID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if len(data) == 0 {
+		err = errors.Join(err, ErrBlobChunkEmpty)
+	}
+	if len(data) > BlobChunkMaxSize {
+		err = errors.Join(err, ErrBlobChunkSizeExceeded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlobChunk{
+		ID:        id,
+		UserID:    userID,
+		Index:     index,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (r UploadBlobRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrBlobIDInvalid)
+	}
+	if r.Version < 0 {
+		err = errors.Join(err, ErrEntryVersionInvalid)
+	}
+	if r.Next == nil {
+		err = errors.Join(err, ErrBlobChunkEmpty)
+	}
+	return err
+}
+
+func (r DownloadBlobRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrBlobIDInvalid)
+	}
+	return err
+}
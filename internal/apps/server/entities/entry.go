@@ -1,16 +1,28 @@
 package entities
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/dlomanov/gophkeeper/internal/core"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const (
-	EntryMaxDataSize = 1024 * 1024
+	// EntryMaxDataSize leaves room for the client-side encryption overhead
+	EntryMaxDataSize = 1024*1024 + 1024
+	// EntryChangesDefaultLimit is the page size of changes when the client doesn't set it
+	EntryChangesDefaultLimit = 100
+	EntryChangesMaxLimit     = 1000
+	// EntryChangesMaxBatch is the max number of changes applied in one transaction
+	EntryChangesMaxBatch = 100
+	// EntryRequestRetention is how long applied request IDs are kept for retries
+	EntryRequestRetention = 24 * time.Hour
+	EntryRequestIDMaxLen  = 64
 )
 
 type (
@@ -20,10 +32,14 @@ type (
 		Key       string
 		Type      core.EntryType
 		Meta      map[string]string
+		Folder    string
+		Tags      []string
 		Data      []byte
 		Version   int64
 		CreatedAt time.Time
 		UpdatedAt time.Time
+		DeletedAt *time.Time
+		Seq       int64 // position in the user change sequence, assigned by the storage on every write
 	}
 	EntryUpdateOption func(e *Entry) error
 )
@@ -88,6 +104,33 @@ func (e *Entry) Update(version int64, opts ...EntryUpdateOption) error {
 	return nil
 }
 
+// Delete marks the entry as deleted, the tombstone is synced to other devices like any other version
+func (e *Entry) Delete() {
+	utcNow := time.Now().UTC()
+	e.DeletedAt = &utcNow
+	e.UpdatedAt = utcNow
+	e.Version++
+}
+
+func (e *Entry) Undelete() {
+	e.DeletedAt = nil
+	e.UpdatedAt = time.Now().UTC()
+	e.Version++
+}
+
+func (e *Entry) Deleted() bool {
+	return e.DeletedAt != nil
+}
+
+// SameContent reports whether entries have the same type, meta, folder, tags and data
+func (e *Entry) SameContent(other *Entry) bool {
+	return e.Type == other.Type &&
+		e.Folder == other.Folder &&
+		maps.Equal(e.Meta, other.Meta) &&
+		slices.Equal(e.Tags, other.Tags) &&
+		bytes.Equal(e.Data, other.Data)
+}
+
 func UpdateEntryData(data []byte) EntryUpdateOption {
 	return func(e *Entry) error {
 		if len(data) == 0 {
@@ -108,6 +151,27 @@ func UpdateEntryMeta(meta map[string]string) EntryUpdateOption {
 	}
 }
 
+func UpdateEntryFolder(folder string) EntryUpdateOption {
+	return func(e *Entry) error {
+		e.Folder = core.NormalizeFolder(folder)
+		return nil
+	}
+}
+
+func UpdateEntryTags(tags []string) EntryUpdateOption {
+	return func(e *Entry) error {
+		e.Tags = core.NormalizeTags(tags)
+		return nil
+	}
+}
+
+func UndeleteEntry() EntryUpdateOption {
+	return func(e *Entry) error {
+		e.DeletedAt = nil
+		return nil
+	}
+}
+
 type (
 	GetEntryRequest struct {
 		UserID uuid.UUID
@@ -122,6 +186,7 @@ type (
 		CreateIDs []uuid.UUID
 		UpdateIDs []uuid.UUID
 		DeleteIDs []uuid.UUID
+		Cursor    int64 // change seq the diff is consistent with
 	}
 	GetEntryResponse struct {
 		Entry *Entry
@@ -133,35 +198,152 @@ type (
 		Entries []Entry
 	}
 	CreateEntryRequest struct {
-		Key    string
-		UserID uuid.UUID
-		Type   core.EntryType
-		Meta   map[string]string
-		Data   []byte
+		ID        uuid.UUID // client-assigned, generated if nil
+		Key       string
+		UserID    uuid.UUID
+		Type      core.EntryType
+		Meta      map[string]string
+		Folder    string
+		Tags      []string
+		Data      []byte
+		RequestID string
 	}
 	CreateEntryResponse struct {
+		ID       uuid.UUID
+		Version  int64
+		Conflict *EntryConflict
+	}
+	UpdateEntryRequest struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		Meta      map[string]string
+		Folder    string
+		Tags      []string
+		Data      []byte
+		Version   int64
+		RequestID string
+	}
+	UpdateEntryResponse struct {
+		ID       uuid.UUID
+		Version  int64
+		Conflict *EntryConflict
+	}
+	// EntryConflict describes the rejected client change, it's resolved by the client
+	EntryConflict struct {
+		Server *Entry // current server version
+		Client *Entry // rejected client version
+		Base   *Entry // version the client change is based on, nil if unknown
+	}
+	DeleteEntryRequest struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		RequestID string
+	}
+	DeleteEntryResponse struct {
 		ID      uuid.UUID
 		Version int64
 	}
-	UpdateEntryRequest struct {
+	GetEntryHistoryRequest struct {
+		UserID uuid.UUID
+		ID     uuid.UUID
+	}
+	// GetEntryHistoryResponse contains previous versions of the entry, the latest first
+	GetEntryHistoryResponse struct {
+		Entries []Entry
+	}
+	RestoreEntryRequest struct {
+		UserID    uuid.UUID
+		ID        uuid.UUID
+		Version   int64
+		RequestID string
+	}
+	RestoreEntryResponse struct {
 		ID      uuid.UUID
-		UserID  uuid.UUID
-		Meta    map[string]string
-		Data    []byte
 		Version int64
 	}
-	UpdateEntryResponse struct {
+	GetTrashRequest struct {
+		UserID uuid.UUID
+	}
+	GetTrashResponse struct {
+		Entries []Entry
+	}
+	UndeleteEntryRequest struct {
+		UserID    uuid.UUID
+		ID        uuid.UUID
+		RequestID string
+	}
+	UndeleteEntryResponse struct {
 		ID      uuid.UUID
 		Version int64
 	}
-	DeleteEntryRequest struct {
-		ID     uuid.UUID
+	// EntryChangeSeq tracks user changes: Current is the last assigned seq,
+	// Purged is the seq of the last purged tombstone.
+	EntryChangeSeq struct {
+		Current int64
+		Purged  int64
+	}
+	GetChangesSinceRequest struct {
 		UserID uuid.UUID
+		Cursor int64
+		Limit  int
 	}
-	DeleteEntryResponse struct {
+	GetChangesSinceResponse struct {
+		Entries []Entry
+		Cursor  int64
+		HasMore bool
+		Reset   bool // cursor is stale, the client must fall back to the full diff
+	}
+	WatchEntriesRequest struct {
+		UserID uuid.UUID
+		Send   func(change EntryChange) error
+	}
+	// EntryChange notifies user devices that the entry was created, updated or deleted
+	EntryChange struct {
 		ID      uuid.UUID
 		Version int64
 	}
+	// AppliedEntryRequest is the response of the applied request,
+	// it's replayed when the request is retried with the same ID
+	AppliedEntryRequest struct {
+		UserID    uuid.UUID
+		RequestID string
+		EntryID   uuid.UUID
+		Version   int64
+		CreatedAt time.Time
+	}
+	// ApplyEntryChangesRequest contains client changes applied in one transaction,
+	// the user ID of the batch overrides the one of the changes
+	ApplyEntryChangesRequest struct {
+		UserID  uuid.UUID
+		Changes []EntryChangeRequest
+	}
+	// EntryChangeRequest contains exactly one operation
+	EntryChangeRequest struct {
+		Create *CreateEntryRequest
+		Update *UpdateEntryRequest
+		Delete *DeleteEntryRequest
+		Err    error // the change is rejected as malformed, the other changes are applied
+	}
+	// ApplyEntryChangesResponse contains the results in the order of the changes
+	ApplyEntryChangesResponse struct {
+		Results []EntryChangeResult
+	}
+	EntryChangeResult struct {
+		Status   EntryChangeStatus
+		ID       uuid.UUID
+		Version  int64
+		Err      error // rejection reason, nil if applied
+		Conflict *EntryConflict
+	}
+	EntryChangeStatus int
+)
+
+const (
+	EntryChangeOK EntryChangeStatus = iota + 1
+	EntryChangeConflict
+	EntryChangeInvalid
+	EntryChangeNotFound
+	EntryChangeIDTaken
 )
 
 func (r GetEntryRequest) Validate() error {
@@ -206,6 +388,9 @@ func (r CreateEntryRequest) Validate() error {
 	if len(r.Data) > EntryMaxDataSize {
 		err = errors.Join(err, ErrEntryDataSizeExceeded)
 	}
+	if len(r.RequestID) > EntryRequestIDMaxLen {
+		err = errors.Join(err, ErrEntryRequestIDInvalid)
+	}
 	return err
 }
 
@@ -226,6 +411,9 @@ func (r UpdateEntryRequest) Validate() error {
 	if r.Version == 0 {
 		err = errors.Join(err, ErrEntryVersionInvalid)
 	}
+	if len(r.RequestID) > EntryRequestIDMaxLen {
+		err = errors.Join(err, ErrEntryRequestIDInvalid)
+	}
 	return err
 }
 
@@ -237,5 +425,109 @@ func (r DeleteEntryRequest) Validate() error {
 	if r.ID == uuid.Nil {
 		err = errors.Join(err, ErrEntryIDInvalid)
 	}
+	if len(r.RequestID) > EntryRequestIDMaxLen {
+		err = errors.Join(err, ErrEntryRequestIDInvalid)
+	}
+	return err
+}
+
+func (r GetEntryHistoryRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	return err
+}
+
+func (r RestoreEntryRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	if r.Version <= 0 {
+		err = errors.Join(err, ErrEntryVersionInvalid)
+	}
+	if len(r.RequestID) > EntryRequestIDMaxLen {
+		err = errors.Join(err, ErrEntryRequestIDInvalid)
+	}
+	return err
+}
+
+func (r GetTrashRequest) Validate() error {
+	if r.UserID == uuid.Nil {
+		return ErrUserIDInvalid
+	}
+	return nil
+}
+
+func (r GetChangesSinceRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.Cursor < 0 {
+		err = errors.Join(err, fmt.Errorf("%w: %d", ErrEntryCursorInvalid, r.Cursor))
+	}
+	if r.Limit < 0 || r.Limit > EntryChangesMaxLimit {
+		err = errors.Join(err, fmt.Errorf("%w: %d", ErrEntryChangesLimitInvalid, r.Limit))
+	}
+	return err
+}
+
+func (r ApplyEntryChangesRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if len(r.Changes) == 0 || len(r.Changes) > EntryChangesMaxBatch {
+		err = errors.Join(err, fmt.Errorf("%w: %d", ErrEntryChangesBatchInvalid, len(r.Changes)))
+	}
+	return err
+}
+
+func (r EntryChangeRequest) Validate() error {
+	if r.Err != nil {
+		return r.Err
+	}
+	n := 0
+	for _, set := range []bool{r.Create != nil, r.Update != nil, r.Delete != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return ErrEntryChangeInvalid
+	}
+	return nil
+}
+
+func (r UndeleteEntryRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.ID == uuid.Nil {
+		err = errors.Join(err, ErrEntryIDInvalid)
+	}
+	if len(r.RequestID) > EntryRequestIDMaxLen {
+		err = errors.Join(err, ErrEntryRequestIDInvalid)
+	}
+	return err
+}
+
+func (r WatchEntriesRequest) Validate() error {
+	var err error
+	if r.UserID == uuid.Nil {
+		err = errors.Join(err, ErrUserIDInvalid)
+	}
+	if r.Send == nil {
+		err = errors.Join(err, ErrEntryWatchInvalid)
+	}
 	return err
 }
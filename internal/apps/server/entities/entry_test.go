@@ -206,6 +206,23 @@ func TestUpdateVersion_invalidOptions(t *testing.T) {
 	require.ErrorIs(t, errs, entities.ErrEntryDataSizeExceeded, "want data size exceeded error")
 }
 
+func TestUpdateVersion_folderAndTags(t *testing.T) {
+	entry, err := entities.NewEntry("key", uuid.New(), core.EntryTypePassword, []byte("test"))
+	require.NoError(t, err, "failed to create entry")
+	err = entry.Update(entry.Version,
+		entities.UpdateEntryFolder(" Work//Servers/ "),
+		entities.UpdateEntryTags([]string{"prod", " db", "", "prod"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Work/Servers", entry.Folder, "folder should be normalized")
+	assert.Equal(t, []string{"db", "prod"}, entry.Tags, "tags should be normalized")
+
+	err = entry.Update(entry.Version, entities.UpdateEntryFolder("/"), entities.UpdateEntryTags(nil))
+	require.NoError(t, err)
+	assert.Empty(t, entry.Folder, "folder should be root")
+	assert.Nil(t, entry.Tags, "tags should be removed")
+}
+
 func TestUpdateVersion_versionConflict(t *testing.T) {
 	entry, err := entities.NewEntry("key", uuid.New(), core.EntryTypePassword, []byte("test"))
 	require.NoError(t, err, "failed to create entry")
@@ -3,20 +3,54 @@ package entities
 import "github.com/dlomanov/gophkeeper/internal/core/apperrors"
 
 var (
-	ErrEntryKeyInvalid       = apperrors.NewInvalid("invalid entry key")
-	ErrEntryIsNil            = apperrors.NewInvalid("entry is nil")
-	ErrEntryIDInvalid        = apperrors.NewInvalid("invalid entry ID")
-	ErrEntryTypeInvalid      = apperrors.NewInvalid("invalid entry type")
-	ErrEntryVersionConflict  = apperrors.NewConflict("entry version conflict")
-	ErrEntryVersionInvalid   = apperrors.NewInvalid("entry version invalid")
-	ErrEntryDataEmpty        = apperrors.NewInvalid("empty entry data")
-	ErrEntryDataSizeExceeded = apperrors.NewInvalid("entry data size exceeded")
-	ErrEntryExists           = apperrors.NewInvalid("entry already exists")
-	ErrEntryNotFound         = apperrors.NewNotFound("entry not found")
-	ErrUserIDInvalid         = apperrors.NewInvalid("user ID is invalid")
-	ErrUserExists            = apperrors.NewInvalid("user already exists")
-	ErrUserNotFound          = apperrors.NewNotFound("user not found")
-	ErrUserCredsInvalid      = apperrors.NewInvalid("user credentials are invalid")
-	ErrUserTokenInvalid      = apperrors.NewInvalid("invalid token")
-	ErrUserTokenExpired      = apperrors.NewInvalid("token expired")
+	ErrEntryKeyInvalid          = apperrors.NewInvalid("invalid entry key")
+	ErrEntryIsNil               = apperrors.NewInvalid("entry is nil")
+	ErrEntryIDInvalid           = apperrors.NewInvalid("invalid entry ID")
+	ErrEntryTypeInvalid         = apperrors.NewInvalid("invalid entry type")
+	ErrEntryVersionConflict     = apperrors.NewConflict("entry version conflict")
+	ErrEntryKeyConflict         = apperrors.NewConflict("entry key conflict")
+	ErrEntryIDTaken             = apperrors.NewConflict("entry ID is taken")
+	ErrEntryVersionInvalid      = apperrors.NewInvalid("entry version invalid")
+	ErrEntryDataEmpty           = apperrors.NewInvalid("empty entry data")
+	ErrEntryDataSizeExceeded    = apperrors.NewInvalid("entry data size exceeded")
+	ErrEntryExists              = apperrors.NewInvalid("entry already exists")
+	ErrEntryNotFound            = apperrors.NewNotFound("entry not found")
+	ErrEntryWatchInvalid        = apperrors.NewInvalid("invalid entry watch request")
+	ErrEntryHistoryNotFound     = apperrors.NewNotFound("entry version not found in history")
+	ErrEntryCursorInvalid       = apperrors.NewInvalid("invalid entry change cursor")
+	ErrEntryChangesLimitInvalid = apperrors.NewInvalid("invalid entry changes limit")
+	ErrEntryChangesBatchInvalid = apperrors.NewInvalid("invalid entry changes batch size")
+	ErrEntryChangeInvalid       = apperrors.NewInvalid("entry change must contain exactly one operation")
+	ErrEntryRequestIDInvalid    = apperrors.NewInvalid("invalid request ID")
+	ErrEntryRequestIDReused     = apperrors.NewInvalid("request ID is reused for another entry")
+	ErrEntryRequestNotFound     = apperrors.NewNotFound("applied request not found")
+	ErrBlobIDInvalid            = apperrors.NewInvalid("invalid blob ID")
+	ErrBlobChunkEmpty           = apperrors.NewInvalid("empty blob chunk")
+	ErrBlobChunkSizeExceeded    = apperrors.NewInvalid("blob chunk size exceeded")
+	ErrBlobSizeExceeded         = apperrors.NewInvalid("blob size exceeded")
+	ErrBlobEntryInvalid         = apperrors.NewInvalid("blob entry must be an active binary entry")
+	ErrBlobNotFound             = apperrors.NewNotFound("blob not found")
+	ErrBlobVersionConflict      = apperrors.NewConflict("blob entry version conflict")
+	ErrUserIDInvalid            = apperrors.NewInvalid("user ID is invalid")
+	ErrUserExists               = apperrors.NewInvalid("user already exists")
+	ErrUserNotFound             = apperrors.NewNotFound("user not found")
+	ErrUserCredsInvalid         = apperrors.NewInvalid("user credentials are invalid")
+	ErrUserPassInvalid          = apperrors.NewInvalid("new password is invalid")
+	ErrUserTokenInvalid         = apperrors.NewInvalid("invalid token")
+	ErrUserTokenExpired         = apperrors.NewInvalid("token expired")
+	ErrUserRefreshInvalid       = apperrors.NewInvalid("invalid refresh token")
+	ErrUserRefreshExpired       = apperrors.NewInvalid("refresh token expired")
+	ErrUserRefreshNotFound      = apperrors.NewNotFound("refresh token not found")
+	ErrSessionIDInvalid         = apperrors.NewInvalid("invalid session ID")
+	ErrSessionNotFound          = apperrors.NewNotFound("session not found")
+	ErrSessionRevoked           = apperrors.NewInvalid("session revoked")
+	ErrUserOTPRequired          = apperrors.NewInvalid("two-factor code required")
+	ErrUserOTPInvalid           = apperrors.NewInvalid("invalid two-factor code")
+	ErrUserOTPLocked            = apperrors.NewInvalid("too many two-factor attempts, try again later")
+	ErrUserOTPEnabled           = apperrors.NewInvalid("two-factor authentication already enabled")
+	ErrUserOTPNotEnrolled       = apperrors.NewInvalid("two-factor authentication not enrolled")
+	ErrUserOTPNotEnabled        = apperrors.NewInvalid("two-factor authentication not enabled")
+	ErrUserRecoveryCodeNotFound = apperrors.NewNotFound("recovery code not found")
+	ErrUserVaultCheckInvalid    = apperrors.NewInvalid("vault check is invalid")
+	ErrUserVaultKeyInvalid      = apperrors.NewInvalid("wrapped vault key is invalid")
 )
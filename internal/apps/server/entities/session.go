@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// SessionSeenInterval limits last seen updates made on every authenticated call
+	SessionSeenInterval = time.Minute
+	SessionDeviceMaxLen = 128
+)
+
+type (
+	Session struct {
+		ID         uuid.UUID
+		UserID     uuid.UUID
+		Device     string
+		IP         string
+		CreatedAt  time.Time
+		LastSeenAt time.Time
+		RevokedAt  *time.Time
+	}
+	// Device describes the client the session is opened from
+	Device struct {
+		Name string
+		IP   string
+	}
+	TokenClaims struct {
+		UserID    uuid.UUID
+		SessionID uuid.UUID
+	}
+	GetSessionsRequest struct {
+		UserID    uuid.UUID
+		SessionID uuid.UUID
+	}
+	GetSessionsResponse struct {
+		Sessions []GetSessionResponse
+	}
+	GetSessionResponse struct {
+		Session
+		Current bool
+	}
+	RevokeSessionRequest struct {
+		UserID uuid.UUID
+		ID     uuid.UUID
+	}
+	RevokeOtherSessionsRequest struct {
+		UserID    uuid.UUID
+		SessionID uuid.UUID
+	}
+	RevokeOtherSessionsResponse struct {
+		Revoked int64
+	}
+)
+
+func NewSession(userID uuid.UUID, device Device) *Session {
+	name := device.Name
+	if name == "" {
+		name = "unknown device"
+	}
+	if len(name) > SessionDeviceMaxLen {
+		name = name[:SessionDeviceMaxLen]
+	}
+	now := time.Now().UTC()
+	return &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Device:     name,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+}
+
+func (s Session) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+// Stale reports that the last seen time should be updated
+func (s Session) Stale(now time.Time) bool {
+	return now.Sub(s.LastSeenAt) >= SessionSeenInterval
+}
+
+func (c TokenClaims) Valid() bool {
+	return c.UserID != uuid.Nil && c.SessionID != uuid.Nil
+}
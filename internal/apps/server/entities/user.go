@@ -1,16 +1,30 @@
 package entities
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	UserVaultSaltSize     = 16
+	UserVaultCheckMaxSize = 1024
+	UserVaultKeyMaxSize   = 1024
+	UserRefreshTokenSize  = 32
+)
+
 type (
 	Creds struct {
 		Login Login
 		Pass  core.Pass
+		// OTP is a two-factor code or a recovery code, required only when two-factor authentication is enabled
+		OTP string
 	}
 	HashCreds struct {
 		Login    Login
@@ -24,10 +38,57 @@ type (
 	User struct {
 		ID uuid.UUID
 		HashCreds
+		VaultSalt core.Salt
+		// VaultCheck is a value sealed by the first device with the vault key,
+		// other devices check that they derive the same key
+		VaultCheck []byte
+		// VaultKey is the vault key wrapped with the key derived from the changed master password,
+		// so devices signed in with the new master password unwrap the same key
+		VaultKey  []byte
+		OTP       UserOTP
 		CreatedAt time.Time
 		UpdatedAt time.Time
 	}
+	UserAuth struct {
+		Token        Token
+		RefreshToken Token
+		VaultSalt    core.Salt
+		VaultCheck   []byte
+		VaultKey     []byte
+	}
+	// RefreshToken is stored by hash, the token itself is known only to the client
+	RefreshToken struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		SessionID uuid.UUID
+		Hash      string
+		ExpiresAt time.Time
+		CreatedAt time.Time
+		RevokedAt *time.Time
+	}
 	Token string
+	// ChangePasswordRequest is made from the session, the session is replaced with a new one
+	ChangePasswordRequest struct {
+		UserID    uuid.UUID
+		SessionID uuid.UUID
+		Old       core.Pass
+		New       core.Pass
+		OTP       string
+	}
+	DeleteAccountRequest struct {
+		UserID uuid.UUID
+		Pass   core.Pass
+		OTP    string
+	}
+	SetVaultCheckRequest struct {
+		UserID     uuid.UUID
+		VaultCheck []byte
+	}
+	// SetVaultKeyRequest replaces the wrapped vault key, the empty one drops it
+	SetVaultKeyRequest struct {
+		UserID   uuid.UUID
+		VaultKey []byte
+	}
 )
 
 func NewUser(creds HashCreds) (*User, error) {
@@ -35,15 +96,48 @@ func NewUser(creds HashCreds) (*User, error) {
 		return nil, ErrUserCredsInvalid
 	}
 
+	salt := make(core.Salt, UserVaultSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("user: failed to generate vault salt: %w", err)
+	}
+
 	now := time.Now().UTC()
 	return &User{
 		ID:        uuid.New(),
 		HashCreds: creds,
+		VaultSalt: salt,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
+// NewRefreshToken generates an opaque refresh token for the user session
+func NewRefreshToken(claims TokenClaims, expires time.Duration) (Token, *RefreshToken, error) {
+	value := make([]byte, UserRefreshTokenSize)
+	if _, err := rand.Read(value); err != nil {
+		return "", nil, fmt.Errorf("user: failed to generate refresh token: %w", err)
+	}
+	token := Token(base64.RawURLEncoding.EncodeToString(value))
+
+	now := time.Now().UTC()
+	return token, &RefreshToken{
+		ID:        uuid.New(),
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Hash:      token.Hash(),
+		ExpiresAt: now.Add(expires),
+		CreatedAt: now,
+	}, nil
+}
+
+func (t RefreshToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.After(now)
+}
+
+func (t RefreshToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
 func (c Creds) Valid() bool {
 	return len(c.Login) != 0 && len(c.Pass) != 0
 }
@@ -55,3 +149,8 @@ func (c HashCreds) Valid() bool {
 func (t Token) Valid() bool {
 	return t != ""
 }
+
+func (t Token) Hash() string {
+	sum := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const (
+	UserOTPIssuer            = "gophkeeper"
+	UserOTPMaxAttempts       = 5
+	UserOTPLockout           = 15 * time.Minute
+	UserRecoveryCodesCount   = 10
+	userRecoveryCodeSize     = 10
+	userRecoveryCodeGroupLen = 8
+)
+
+type (
+	// UserOTP is the user two-factor state, the secret is kept pending until it's confirmed with a code
+	UserOTP struct {
+		Secret   string
+		Enabled  bool
+		LastStep int64
+	}
+	// RecoveryCode is stored by hash and could be used once instead of the two-factor code
+	RecoveryCode struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		Hash      string
+		CreatedAt time.Time
+		UsedAt    *time.Time
+	}
+	EnrollOTPRequest struct {
+		UserID uuid.UUID
+	}
+	EnrollOTPResponse struct {
+		Secret string
+		URI    string
+	}
+	ConfirmOTPRequest struct {
+		UserID uuid.UUID
+		Code   string
+	}
+	ConfirmOTPResponse struct {
+		RecoveryCodes []string
+	}
+	DisableOTPRequest struct {
+		UserID uuid.UUID
+		Code   string
+	}
+)
+
+// NewRecoveryCodes generates recovery codes, plain codes are shown to the user once
+func NewRecoveryCodes(userID uuid.UUID) ([]string, []RecoveryCode, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	now := time.Now().UTC()
+	codes := make([]string, UserRecoveryCodesCount)
+	stored := make([]RecoveryCode, UserRecoveryCodesCount)
+	for i := range codes {
+		value := make([]byte, userRecoveryCodeSize)
+		if _, err := rand.Read(value); err != nil {
+			return nil, nil, fmt.Errorf("user: failed to generate recovery code: %w", err)
+		}
+		code := strings.ToLower(encoding.EncodeToString(value))
+		codes[i] = code[:userRecoveryCodeGroupLen] + "-" + code[userRecoveryCodeGroupLen:]
+		stored[i] = RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Hash:      RecoveryCodeHash(codes[i]),
+			CreatedAt: now,
+		}
+	}
+	return codes, stored, nil
+}
+
+// RecoveryCodeHash normalizes the code the way it could be typed by the user
+func RecoveryCodeHash(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return Token(code).Hash()
+}
@@ -16,7 +16,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const UserIDKey ContextKey = "server_user_id"
+const (
+	UserIDKey    ContextKey = "server_user_id"
+	SessionIDKey ContextKey = "server_session_id"
+)
 
 type ContextKey string
 
@@ -24,6 +27,10 @@ func Logger(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	return logging.UnaryServerInterceptor(interceptorLogger(logger))
 }
 
+func StreamLogger(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return logging.StreamServerInterceptor(interceptorLogger(logger))
+}
+
 func interceptorLogger(logger *zap.Logger) logging.Logger {
 	return logging.LoggerFunc(func(_ context.Context, lvl logging.Level, msg string, fields ...any) {
 		values := make(map[string]any, len(fields)/2+1)
@@ -49,25 +56,42 @@ func interceptorLogger(logger *zap.Logger) logging.Logger {
 }
 
 func Recovery(logger *zap.Logger) grpc.UnaryServerInterceptor {
-	return recovery.UnaryServerInterceptor(recovery.WithRecoveryHandler(func(p any) (err error) {
+	return recovery.UnaryServerInterceptor(recoveryHandler(logger))
+}
+
+func StreamRecovery(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return recovery.StreamServerInterceptor(recoveryHandler(logger))
+}
+
+func recoveryHandler(logger *zap.Logger) recovery.Option {
+	return recovery.WithRecoveryHandler(func(p any) (err error) {
 		logger.Error("cached panic", zap.Any("panic", p))
 		return status.Error(codes.Internal, "internal server error")
-	}))
+	})
 }
 
 type Tokener interface {
-	GetUserID(ctx context.Context, token entities.Token) (uuid.UUID, error)
+	Authenticate(ctx context.Context, token entities.Token) (entities.TokenClaims, error)
 }
 
 func Auth(logger *zap.Logger, tokener Tokener) grpc.UnaryServerInterceptor {
-	return auth.UnaryServerInterceptor(func(ctx context.Context) (context.Context, error) {
+	return auth.UnaryServerInterceptor(AuthFunc(logger, tokener))
+}
+
+func StreamAuth(logger *zap.Logger, tokener Tokener) grpc.StreamServerInterceptor {
+	return auth.StreamServerInterceptor(AuthFunc(logger, tokener))
+}
+
+// AuthFunc authenticates the call by the token, claims are put into the context
+func AuthFunc(logger *zap.Logger, tokener Tokener) auth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
 		t, err := auth.AuthFromMD(ctx, sharedmd.Schema)
 		if err != nil {
 			logger.Debug("failed to get token from metadata", zap.Error(err))
 			return ctx, status.Error(codes.Unauthenticated, err.Error())
 		}
 		token := entities.Token(t)
-		userID, err := tokener.GetUserID(ctx, token)
+		claims, err := tokener.Authenticate(ctx, token)
 		switch {
 		case errors.Is(err, entities.ErrUserTokenInvalid):
 			logger.Debug("invalid token", zap.Error(err))
@@ -75,12 +99,16 @@ func Auth(logger *zap.Logger, tokener Tokener) grpc.UnaryServerInterceptor {
 		case errors.Is(err, entities.ErrUserTokenExpired):
 			logger.Debug("token expired", zap.Error(err))
 			return ctx, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, entities.ErrSessionRevoked):
+			logger.Debug("session revoked", zap.Error(err))
+			return ctx, status.Error(codes.Unauthenticated, err.Error())
 		case err != nil:
-			logger.Error("failed to get user ID from token", zap.Error(err))
+			logger.Error("failed to authenticate token", zap.Error(err))
 			return ctx, status.Error(codes.Internal, "internal server error")
 		}
-		return context.WithValue(ctx, UserIDKey, userID), nil
-	})
+		ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+		return context.WithValue(ctx, SessionIDKey, claims.SessionID), nil
+	}
 }
 
 func GetUserID(ctx context.Context) (uuid.UUID, bool) {
@@ -89,3 +117,10 @@ func GetUserID(ctx context.Context) (uuid.UUID, bool) {
 	}
 	return uuid.Nil, false
 }
+
+func GetSessionID(ctx context.Context) (uuid.UUID, bool) {
+	if v, ok := ctx.Value(SessionIDKey).(uuid.UUID); ok {
+		return v, true
+	}
+	return uuid.Nil, false
+}
@@ -11,13 +11,22 @@ import (
 
 func UseServices(s *grpcserver2.Server, c *deps.Container) {
 	pb.RegisterUserServiceServer(s.Server, services.NewUserService(c.Logger, c.UserUC))
-	pb.RegisterEntryServiceServer(s.Server, services.NewEntryService(c.Logger, c.EntryUC))
+	pb.RegisterTwoFactorServiceServer(s.Server, services.NewTwoFactorService(c.Logger, c.UserUC))
+	pb.RegisterSessionServiceServer(s.Server, services.NewSessionService(c.Logger, c.SessionUC))
+	pb.RegisterEntryServiceServer(s.Server, services.NewEntryService(c.Logger, c.EntryUC, c.BlobUC))
 }
 
 func GetOptions(c *deps.Container) grpcserver2.Option {
-	return grpcserver2.ServerOptions(grpc.ChainUnaryInterceptor(
-		interceptor.Auth(c.Logger, c.UserUC),
-		interceptor.Logger(c.Logger),
-		interceptor.Recovery(c.Logger),
-	))
+	return grpcserver2.ServerOptions(
+		grpc.ChainUnaryInterceptor(
+			interceptor.Auth(c.Logger, c.UserUC),
+			interceptor.Logger(c.Logger),
+			interceptor.Recovery(c.Logger),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamAuth(c.Logger, c.UserUC),
+			interceptor.StreamLogger(c.Logger),
+			interceptor.StreamRecovery(c.Logger),
+		),
+	)
 }
@@ -22,16 +22,19 @@ type EntryService struct {
 	pb.UnimplementedEntryServiceServer
 	logger  *zap.Logger
 	entryUC *usecases.EntryUC
+	blobUC  *usecases.BlobUC
 	mapper  mapper.EntryMapper
 }
 
 func NewEntryService(
 	logger *zap.Logger,
 	entryUC *usecases.EntryUC,
+	blobUC *usecases.BlobUC,
 ) *EntryService {
 	return &EntryService{
 		logger:  logger,
 		entryUC: entryUC,
+		blobUC:  blobUC,
 		mapper:  mapper.EntryMapper{},
 	}
 }
@@ -158,6 +161,45 @@ func (s *EntryService) GetDiff(
 		CreateIds: createIDs,
 		UpdateIds: updateIDs,
 		DeleteIds: deleteIDs,
+		Cursor:    got.Cursor,
+	}, nil
+}
+
+func (s *EntryService) GetChangesSince(
+	ctx context.Context,
+	request *pb.GetChangesSinceRequest,
+) (*pb.GetChangesSinceResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	changes, err := s.entryUC.GetChangesSince(ctx, entities.GetChangesSinceRequest{
+		UserID: userID,
+		Cursor: request.Cursor,
+		Limit:  int(request.Limit),
+	})
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Error("failed to get changes",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	entries := make([]*pb.Entry, len(changes.Entries))
+	for i, v := range changes.Entries {
+		entries[i] = s.toAPIEntry(v)
+	}
+	return &pb.GetChangesSinceResponse{
+		Entries: entries,
+		Cursor:  changes.Cursor,
+		HasMore: changes.HasMore,
+		Reset_:  changes.Reset,
 	}, nil
 }
 
@@ -171,27 +213,37 @@ func (s *EntryService) Create(
 		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
 	}
 
+	var id uuid.UUID
+	if request.Id != "" {
+		if id = s.parseUUID(request.Id); id == uuid.Nil {
+			return nil, status.Error(codes.InvalidArgument, entities.ErrEntryIDInvalid.Error())
+		}
+	}
+
 	created, err := s.entryUC.Create(ctx, entities.CreateEntryRequest{
-		Key:    request.Key,
-		UserID: userID,
-		Type:   s.toEntityType(request.Type),
-		Meta:   request.Meta,
-		Data:   request.Data,
+		ID:        id,
+		Key:       request.Key,
+		UserID:    userID,
+		Type:      s.toEntityType(request.Type, request.TypeName),
+		Meta:      request.Meta,
+		Folder:    request.Folder,
+		Tags:      request.Tags,
+		Data:      request.Data,
+		RequestID: request.RequestId,
 	})
 	if err != nil {
 		s.logger.Debug("failed to create entry",
 			zap.Error(err),
 			zap.String("user_id", userID.String()))
 	}
-	var (
-		invalid  *apperrors.AppErrorInvalid
-		conflict *apperrors.AppErrorConflict
-	)
+	var invalid *apperrors.AppErrorInvalid
 	switch {
+	case errors.Is(err, entities.ErrEntryIDTaken):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
 	case errors.As(err, &invalid):
 		return nil, status.Error(codes.InvalidArgument, err.Error())
-	case errors.As(err, &conflict):
-		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case created.Conflict != nil:
+		return nil, s.conflictError(err, created.Conflict)
 	case err != nil:
 		s.logger.Error("failed to create entry",
 			zap.String("user_id", userID.String()),
@@ -216,11 +268,14 @@ func (s *EntryService) Update(
 	}
 
 	updated, err := s.entryUC.Update(ctx, entities.UpdateEntryRequest{
-		ID:      s.parseUUID(request.Id),
-		UserID:  userID,
-		Meta:    request.Meta,
-		Data:    request.Data,
-		Version: request.Version,
+		ID:        s.parseUUID(request.Id),
+		UserID:    userID,
+		Meta:      request.Meta,
+		Folder:    request.Folder,
+		Tags:      request.Tags,
+		Data:      request.Data,
+		Version:   request.Version,
+		RequestID: request.RequestId,
 	})
 	if err != nil {
 		s.logger.Debug("failed to update entry",
@@ -237,6 +292,8 @@ func (s *EntryService) Update(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	case errors.As(err, &notFound):
 		return nil, status.Error(codes.NotFound, err.Error())
+	case updated.Conflict != nil:
+		return nil, s.conflictError(err, updated.Conflict)
 	case err != nil:
 		s.logger.Error("failed to update entry",
 			zap.String("user_id", userID.String()),
@@ -262,8 +319,9 @@ func (s *EntryService) Delete(
 	}
 
 	deleted, err := s.entryUC.Delete(ctx, entities.DeleteEntryRequest{
-		ID:     s.parseUUID(request.Id),
-		UserID: userID,
+		ID:        s.parseUUID(request.Id),
+		UserID:    userID,
+		RequestID: request.RequestId,
 	})
 	if err != nil {
 		s.logger.Debug("failed to update entry",
@@ -294,14 +352,444 @@ func (s *EntryService) Delete(
 	}, nil
 }
 
+func (s *EntryService) UploadBlob(stream pb.EntryService_UploadBlobServer) error {
+	ctx := stream.Context()
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		s.logger.Debug("failed to receive first blob chunk", zap.Error(err))
+		return status.Error(codes.InvalidArgument, entities.ErrBlobChunkEmpty.Error())
+	}
+	id, err := uuid.Parse(first.Id)
+	if err != nil {
+		s.logger.Debug("invalid blob id", zap.Error(err))
+		return status.Error(codes.InvalidArgument, entities.ErrBlobIDInvalid.Error())
+	}
+
+	pending := first.Chunk
+	uploaded, err := s.blobUC.Upload(ctx, entities.UploadBlobRequest{
+		ID:      id,
+		UserID:  userID,
+		Version: first.Version,
+		Next: func() ([]byte, error) {
+			if len(pending) != 0 {
+				chunk := pending
+				pending = nil
+				return chunk, nil
+			}
+			msg, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return msg.Chunk, nil
+		},
+	})
+	if err != nil {
+		s.logger.Debug("failed to upload blob",
+			zap.String("user_id", userID.String()),
+			zap.String("blob_id", id.String()),
+			zap.Error(err))
+	}
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		conflict *apperrors.AppErrorConflict
+	)
+	switch {
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &conflict):
+		return status.Error(codes.Aborted, err.Error())
+	case status.Code(err) == codes.Canceled:
+		return err
+	case err != nil:
+		s.logger.Error("failed to upload blob",
+			zap.String("user_id", userID.String()),
+			zap.String("blob_id", id.String()),
+			zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	return stream.SendAndClose(&pb.UploadBlobResponse{
+		Id:   uploaded.ID.String(),
+		Size: uploaded.Size,
+	})
+}
+
+func (s *EntryService) DownloadBlob(
+	request *pb.DownloadBlobRequest,
+	stream pb.EntryService_DownloadBlobServer,
+) error {
+	ctx := stream.Context()
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+	id, err := uuid.Parse(request.Id)
+	if err != nil {
+		s.logger.Debug("invalid blob id", zap.Error(err))
+		return status.Error(codes.InvalidArgument, entities.ErrBlobIDInvalid.Error())
+	}
+
+	err = s.blobUC.Download(ctx, entities.DownloadBlobRequest{
+		ID:     id,
+		UserID: userID,
+		Send: func(chunk []byte) error {
+			return stream.Send(&pb.DownloadBlobResponse{Chunk: chunk})
+		},
+	})
+	if err != nil {
+		s.logger.Debug("failed to download blob",
+			zap.String("user_id", userID.String()),
+			zap.String("blob_id", id.String()),
+			zap.Error(err))
+	}
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, err.Error())
+	case status.Code(err) == codes.Canceled:
+		return err
+	case err != nil:
+		s.logger.Error("failed to download blob",
+			zap.String("user_id", userID.String()),
+			zap.String("blob_id", id.String()),
+			zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+	return nil
+}
+
+func (s *EntryService) GetHistory(
+	ctx context.Context,
+	request *pb.GetEntryHistoryRequest,
+) (*pb.GetEntryHistoryResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	history, err := s.entryUC.GetHistory(ctx, entities.GetEntryHistoryRequest{
+		UserID: userID,
+		ID:     s.parseUUID(request.Id),
+	})
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Error("failed to get entry history",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", request.Id),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	entries := make([]*pb.Entry, len(history.Entries))
+	for i, v := range history.Entries {
+		entries[i] = s.toAPIEntry(v)
+	}
+	return &pb.GetEntryHistoryResponse{Entries: entries}, nil
+}
+
+func (s *EntryService) Restore(
+	ctx context.Context,
+	request *pb.RestoreEntryRequest,
+) (*pb.RestoreEntryResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	restored, err := s.entryUC.Restore(ctx, entities.RestoreEntryRequest{
+		UserID:    userID,
+		ID:        s.parseUUID(request.Id),
+		Version:   request.Version,
+		RequestID: request.RequestId,
+	})
+	if err != nil {
+		s.logger.Debug("failed to restore entry",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", request.Id),
+			zap.Error(err))
+	}
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		s.logger.Error("failed to restore entry",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", request.Id),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.RestoreEntryResponse{
+		Id:      restored.ID.String(),
+		Version: restored.Version,
+	}, nil
+}
+
+func (s *EntryService) GetTrash(
+	ctx context.Context,
+	_ *pb.GetTrashRequest,
+) (*pb.GetTrashResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	trash, err := s.entryUC.GetTrash(ctx, entities.GetTrashRequest{UserID: userID})
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Error("failed to get trash",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	entries := make([]*pb.Entry, len(trash.Entries))
+	for i, v := range trash.Entries {
+		entries[i] = s.toAPIEntry(v)
+	}
+	return &pb.GetTrashResponse{Entries: entries}, nil
+}
+
+func (s *EntryService) Undelete(
+	ctx context.Context,
+	request *pb.UndeleteEntryRequest,
+) (*pb.UndeleteEntryResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	undeleted, err := s.entryUC.Undelete(ctx, entities.UndeleteEntryRequest{
+		UserID:    userID,
+		ID:        s.parseUUID(request.Id),
+		RequestID: request.RequestId,
+	})
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case errors.Is(err, entities.ErrEntryExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		s.logger.Error("failed to undelete entry",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", request.Id),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.UndeleteEntryResponse{
+		Id:      undeleted.ID.String(),
+		Version: undeleted.Version,
+	}, nil
+}
+
+func (s *EntryService) Watch(
+	_ *pb.WatchEntriesRequest,
+	stream pb.EntryService_WatchServer,
+) error {
+	ctx := stream.Context()
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	err := s.entryUC.Watch(ctx, entities.WatchEntriesRequest{
+		UserID: userID,
+		Send: func(change entities.EntryChange) error {
+			return stream.Send(&pb.WatchEntriesResponse{
+				Id:      change.ID.String(),
+				Version: change.Version,
+			})
+		},
+	})
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Debug("entries watch stopped",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return status.Error(codes.Unavailable, "entries watch stopped")
+	}
+	return nil
+}
+
 func (s *EntryService) toAPIEntry(entry entities.Entry) *pb.Entry {
-	return &pb.Entry{
-		Id:      entry.ID.String(),
-		Key:     entry.Key,
-		Type:    s.toAPIType(entry.Type),
-		Meta:    entry.Meta,
-		Data:    entry.Data,
-		Version: entry.Version,
+	result := &pb.Entry{
+		Id:        entry.ID.String(),
+		Key:       entry.Key,
+		Type:      s.toAPIType(entry.Type),
+		TypeName:  string(entry.Type),
+		Meta:      entry.Meta,
+		Folder:    entry.Folder,
+		Tags:      entry.Tags,
+		Data:      entry.Data,
+		Version:   entry.Version,
+		UpdatedAt: entry.UpdatedAt.Unix(),
+	}
+	if entry.DeletedAt != nil {
+		result.DeletedAt = entry.DeletedAt.Unix()
+	}
+	return result
+}
+
+// ApplyChanges applies the batch of changes in one transaction,
+// rejected changes are reported in the results
+func (s *EntryService) ApplyChanges(
+	ctx context.Context,
+	request *pb.ApplyChangesRequest,
+) (*pb.ApplyChangesResponse, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+
+	changes := make([]entities.EntryChangeRequest, len(request.Changes))
+	for i, v := range request.Changes {
+		if v.Create != nil {
+			var id uuid.UUID
+			if v.Create.Id != "" {
+				if id = s.parseUUID(v.Create.Id); id == uuid.Nil {
+					changes[i].Err = entities.ErrEntryIDInvalid
+					continue
+				}
+			}
+			changes[i].Create = &entities.CreateEntryRequest{
+				ID:        id,
+				Key:       v.Create.Key,
+				Type:      s.toEntityType(v.Create.Type, v.Create.TypeName),
+				Meta:      v.Create.Meta,
+				Folder:    v.Create.Folder,
+				Tags:      v.Create.Tags,
+				Data:      v.Create.Data,
+				RequestID: v.Create.RequestId,
+			}
+		}
+		if v.Update != nil {
+			changes[i].Update = &entities.UpdateEntryRequest{
+				ID:        s.parseUUID(v.Update.Id),
+				Meta:      v.Update.Meta,
+				Folder:    v.Update.Folder,
+				Tags:      v.Update.Tags,
+				Data:      v.Update.Data,
+				Version:   v.Update.Version,
+				RequestID: v.Update.RequestId,
+			}
+		}
+		if v.Delete != nil {
+			changes[i].Delete = &entities.DeleteEntryRequest{
+				ID:        s.parseUUID(v.Delete.Id),
+				RequestID: v.Delete.RequestId,
+			}
+		}
+	}
+
+	applied, err := s.entryUC.ApplyChanges(ctx, entities.ApplyEntryChangesRequest{
+		UserID:  userID,
+		Changes: changes,
+	})
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Error("failed to apply entry changes",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	results := make([]*pb.EntryChangeResult, len(applied.Results))
+	for i, v := range applied.Results {
+		result := &pb.EntryChangeResult{
+			Status:  s.toAPIChangeStatus(v.Status),
+			Version: v.Version,
+		}
+		if v.ID != uuid.Nil {
+			result.Id = v.ID.String()
+		}
+		if v.Err != nil {
+			result.Error = v.Err.Error()
+		}
+		if v.Conflict != nil {
+			result.Conflict = s.toAPIConflict(v.Conflict)
+		}
+		results[i] = result
+	}
+	return &pb.ApplyChangesResponse{Results: results}, nil
+}
+
+// conflictError returns the aborted status with both versions of the entry in details
+func (s *EntryService) conflictError(err error, conflict *entities.EntryConflict) error {
+	st, derr := status.New(codes.Aborted, err.Error()).WithDetails(s.toAPIConflict(conflict))
+	if derr != nil {
+		s.logger.Error("failed to attach entry conflict details", zap.Error(derr))
+		return status.Error(codes.Internal, "internal server error")
+	}
+	return st.Err()
+}
+
+func (s *EntryService) toAPIConflict(conflict *entities.EntryConflict) *pb.EntryConflict {
+	result := &pb.EntryConflict{
+		Server: s.toAPIEntry(*conflict.Server),
+		Client: s.toAPIEntry(*conflict.Client),
+	}
+	if conflict.Base != nil {
+		result.Base = s.toAPIEntry(*conflict.Base)
+	}
+	return result
+}
+
+func (s *EntryService) toAPIChangeStatus(value entities.EntryChangeStatus) pb.EntryChangeStatus {
+	switch value {
+	case entities.EntryChangeOK:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_OK
+	case entities.EntryChangeConflict:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_CONFLICT
+	case entities.EntryChangeInvalid:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_INVALID
+	case entities.EntryChangeNotFound:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_NOT_FOUND
+	case entities.EntryChangeIDTaken:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_ID_TAKEN
+	default:
+		return pb.EntryChangeStatus_ENTRY_CHANGE_STATUS_UNSPECIFIED
 	}
 }
 
@@ -309,8 +797,8 @@ func (s *EntryService) toAPIType(typ core.EntryType) pb.EntryType {
 	return s.mapper.ToAPIType(typ)
 }
 
-func (s *EntryService) toEntityType(t pb.EntryType) core.EntryType {
-	return s.mapper.ToEntityType(t)
+func (s *EntryService) toEntityType(t pb.EntryType, name string) core.EntryType {
+	return s.mapper.ToEntityType(t, name)
 }
 
 func (s *EntryService) toEntityVersions(versions []*pb.EntryVersion) []core.EntryVersion {
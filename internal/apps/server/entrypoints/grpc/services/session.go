@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entrypoints/grpc/interceptor"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/core/apperrors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ pb.SessionServiceServer = (*SessionService)(nil)
+
+type SessionService struct {
+	pb.UnimplementedSessionServiceServer
+	logger    *zap.Logger
+	sessionUC *usecases.SessionUC
+}
+
+func NewSessionService(
+	logger *zap.Logger,
+	sessionUC *usecases.SessionUC,
+) *SessionService {
+	return &SessionService{
+		logger:    logger,
+		sessionUC: sessionUC,
+	}
+}
+
+func (s *SessionService) GetAll(
+	ctx context.Context,
+	_ *pb.GetSessionsRequest,
+) (*pb.GetSessionsResponse, error) {
+	userID, sessionID, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := s.sessionUC.GetAll(ctx, entities.GetSessionsRequest{
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		s.logger.Error("failed to get sessions",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	sessions := make([]*pb.Session, len(got.Sessions))
+	for i, v := range got.Sessions {
+		sessions[i] = &pb.Session{
+			Id:         v.ID.String(),
+			Device:     v.Device,
+			Ip:         v.IP,
+			CreatedAt:  v.CreatedAt.Unix(),
+			LastSeenAt: v.LastSeenAt.Unix(),
+			Current:    v.Current,
+		}
+	}
+	return &pb.GetSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s *SessionService) Revoke(
+	ctx context.Context,
+	request *pb.RevokeSessionRequest,
+) (*pb.RevokeSessionResponse, error) {
+	userID, _, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(request.Id)
+	if err != nil {
+		s.logger.Debug("invalid session id", zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, entities.ErrSessionIDInvalid.Error())
+	}
+
+	err = s.sessionUC.Revoke(ctx, entities.RevokeSessionRequest{UserID: userID, ID: id})
+	if err != nil {
+		s.logger.Debug("failed to revoke session",
+			zap.String("user_id", userID.String()),
+			zap.String("session_id", request.Id),
+			zap.Error(err))
+	}
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+func (s *SessionService) RevokeOthers(
+	ctx context.Context,
+	_ *pb.RevokeOtherSessionsRequest,
+) (*pb.RevokeOtherSessionsResponse, error) {
+	userID, sessionID, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := s.sessionUC.RevokeOthers(ctx, entities.RevokeOtherSessionsRequest{
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		s.logger.Error("failed to revoke other sessions",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.RevokeOtherSessionsResponse{Revoked: got.Revoked}, nil
+}
+
+func (s *SessionService) getSession(ctx context.Context) (userID uuid.UUID, sessionID uuid.UUID, err error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return uuid.Nil, uuid.Nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+	sessionID, ok = interceptor.GetSessionID(ctx)
+	if !ok {
+		s.logger.Debug("session id not found in context")
+		return uuid.Nil, uuid.Nil, status.Error(codes.Unauthenticated, entities.ErrSessionIDInvalid.Error())
+	}
+	return userID, sessionID, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entrypoints/grpc/interceptor"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
+	"github.com/dlomanov/gophkeeper/internal/core/apperrors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ pb.TwoFactorServiceServer = (*TwoFactorService)(nil)
+
+type TwoFactorService struct {
+	pb.UnimplementedTwoFactorServiceServer
+	logger *zap.Logger
+	userUC *usecases.UserUC
+}
+
+func NewTwoFactorService(
+	logger *zap.Logger,
+	userUC *usecases.UserUC,
+) *TwoFactorService {
+	return &TwoFactorService{
+		logger: logger,
+		userUC: userUC,
+	}
+}
+
+func (s *TwoFactorService) Enroll(
+	ctx context.Context,
+	_ *pb.EnrollTwoFactorRequest,
+) (*pb.EnrollTwoFactorResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := s.userUC.EnrollOTP(ctx, entities.EnrollOTPRequest{UserID: userID})
+	if err != nil {
+		s.logger.Debug("failed to enroll two-factor authentication",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.EnrollTwoFactorResponse{
+		Secret: got.Secret,
+		Uri:    got.URI,
+	}, nil
+}
+
+func (s *TwoFactorService) Confirm(
+	ctx context.Context,
+	request *pb.ConfirmTwoFactorRequest,
+) (*pb.ConfirmTwoFactorResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := s.userUC.ConfirmOTP(ctx, entities.ConfirmOTPRequest{
+		UserID: userID,
+		Code:   request.Code,
+	})
+	if err != nil {
+		s.logger.Debug("failed to confirm two-factor authentication",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.ConfirmTwoFactorResponse{RecoveryCodes: got.RecoveryCodes}, nil
+}
+
+func (s *TwoFactorService) Disable(
+	ctx context.Context,
+	request *pb.DisableTwoFactorRequest,
+) (*pb.DisableTwoFactorResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userUC.DisableOTP(ctx, entities.DisableOTPRequest{
+		UserID: userID,
+		Code:   request.Code,
+	})
+	if err != nil {
+		s.logger.Debug("failed to disable two-factor authentication",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return nil, s.toStatus(err)
+	}
+
+	return &pb.DisableTwoFactorResponse{}, nil
+}
+
+func (s *TwoFactorService) getUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return uuid.Nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+	return userID, nil
+}
+
+func (*TwoFactorService) toStatus(err error) error {
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case errors.Is(err, entities.ErrUserOTPEnabled),
+		errors.Is(err, entities.ErrUserOTPNotEnrolled),
+		errors.Is(err, entities.ErrUserOTPNotEnabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &notFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
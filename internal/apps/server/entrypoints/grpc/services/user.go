@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entrypoints/grpc/interceptor"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
 	pb "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/dlomanov/gophkeeper/internal/core/apperrors"
+	"github.com/google/uuid"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"net"
 )
 
 var (
@@ -44,7 +48,7 @@ func (s *UserService) SignUp(
 		Pass:  core.Pass(request.Password),
 	}
 
-	token, err := s.userUC.SignUp(ctx, creds)
+	auth, err := s.userUC.SignUp(ctx, creds, entities.Device{Name: request.Device, IP: peerIP(ctx)})
 	if err != nil {
 		s.logger.Debug("failed to sign up", zap.Error(err))
 	}
@@ -58,16 +62,21 @@ func (s *UserService) SignUp(
 		return nil, status.Error(codes.Internal, "internal server error")
 	}
 
-	return &pb.SignUpUserResponse{Token: string(token)}, nil
+	return &pb.SignUpUserResponse{
+		Token:        string(auth.Token),
+		RefreshToken: string(auth.RefreshToken),
+		VaultSalt:    auth.VaultSalt,
+	}, nil
 }
 
 func (s *UserService) SignIn(ctx context.Context, request *pb.SignInUserRequest) (*pb.SignInUserResponse, error) {
 	creds := entities.Creds{
 		Login: entities.Login(request.Login),
 		Pass:  core.Pass(request.Password),
+		OTP:   request.Otp,
 	}
 
-	token, err := s.userUC.SignIn(ctx, creds)
+	auth, err := s.userUC.SignIn(ctx, creds, entities.Device{Name: request.Device, IP: peerIP(ctx)})
 	if err != nil {
 		s.logger.Debug("failed to sign in", zap.Error(err))
 	}
@@ -76,6 +85,13 @@ func (s *UserService) SignIn(ctx context.Context, request *pb.SignInUserRequest)
 		notFound *apperrors.AppErrorNotFound
 	)
 	switch {
+	// the client asks for the code and signs in again
+	case errors.Is(err, entities.ErrUserOTPRequired):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entities.ErrUserOTPInvalid):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
 	case errors.As(err, &invalid):
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	case errors.As(err, &notFound):
@@ -84,9 +100,211 @@ func (s *UserService) SignIn(ctx context.Context, request *pb.SignInUserRequest)
 		return nil, status.Error(codes.Internal, "internal server error")
 	}
 
-	return &pb.SignInUserResponse{Token: string(token)}, nil
+	return &pb.SignInUserResponse{
+		Token:        string(auth.Token),
+		RefreshToken: string(auth.RefreshToken),
+		VaultSalt:    auth.VaultSalt,
+		VaultCheck:   auth.VaultCheck,
+		VaultKey:     auth.VaultKey,
+	}, nil
 }
 
-func (s *UserService) AuthFuncOverride(ctx context.Context, _ string) (context.Context, error) {
+func (s *UserService) Refresh(ctx context.Context, request *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	auth, err := s.userUC.Refresh(ctx, entities.Token(request.RefreshToken), peerIP(ctx))
+	if err != nil {
+		s.logger.Debug("failed to refresh token", zap.Error(err))
+	}
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.RefreshTokenResponse{
+		Token:        string(auth.Token),
+		RefreshToken: string(auth.RefreshToken),
+	}, nil
+}
+
+func (s *UserService) ChangePassword(
+	ctx context.Context,
+	request *pb.ChangePasswordRequest,
+) (*pb.ChangePasswordResponse, error) {
+	userID, sessionID, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := s.userUC.ChangePassword(ctx, entities.ChangePasswordRequest{
+		UserID:    userID,
+		SessionID: sessionID,
+		Old:       core.Pass(request.OldPassword),
+		New:       core.Pass(request.NewPassword),
+		OTP:       request.Otp,
+	})
+	if err != nil {
+		s.logger.Debug("failed to change password",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.Is(err, entities.ErrUserCredsInvalid),
+		errors.Is(err, entities.ErrUserOTPInvalid):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, entities.ErrUserOTPRequired):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, entities.ErrSessionRevoked),
+		errors.Is(err, entities.ErrUserNotFound):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.ChangePasswordResponse{
+		Token:        string(auth.Token),
+		RefreshToken: string(auth.RefreshToken),
+	}, nil
+}
+
+func (s *UserService) DeleteAccount(
+	ctx context.Context,
+	request *pb.DeleteAccountRequest,
+) (*pb.DeleteAccountResponse, error) {
+	userID, _, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userUC.DeleteAccount(ctx, entities.DeleteAccountRequest{
+		UserID: userID,
+		Pass:   core.Pass(request.Password),
+		OTP:    request.Otp,
+	})
+	if err != nil {
+		s.logger.Debug("failed to delete account",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+	switch {
+	case errors.Is(err, entities.ErrUserCredsInvalid),
+		errors.Is(err, entities.ErrUserOTPInvalid):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, entities.ErrUserOTPRequired):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, entities.ErrUserNotFound):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func (s *UserService) SetVaultCheck(
+	ctx context.Context,
+	request *pb.SetVaultCheckRequest,
+) (*pb.SetVaultCheckResponse, error) {
+	userID, _, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	check, err := s.userUC.SetVaultCheck(ctx, entities.SetVaultCheckRequest{
+		UserID:     userID,
+		VaultCheck: request.VaultCheck,
+	})
+	if err != nil {
+		s.logger.Debug("failed to set vault check",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.Is(err, entities.ErrUserNotFound):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.SetVaultCheckResponse{VaultCheck: check}, nil
+}
+
+func (s *UserService) SetVaultKey(
+	ctx context.Context,
+	request *pb.SetVaultKeyRequest,
+) (*pb.SetVaultKeyResponse, error) {
+	userID, _, err := s.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userUC.SetVaultKey(ctx, entities.SetVaultKeyRequest{
+		UserID:   userID,
+		VaultKey: request.VaultKey,
+	})
+	if err != nil {
+		s.logger.Debug("failed to set vault key",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+	}
+	var invalid *apperrors.AppErrorInvalid
+	switch {
+	case errors.Is(err, entities.ErrUserNotFound):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.As(err, &invalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &pb.SetVaultKeyResponse{}, nil
+}
+
+// AuthFuncOverride authenticates only account methods, sign-up, sign-in and refresh are anonymous
+func (s *UserService) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	switch fullMethodName {
+	case pb.UserService_ChangePassword_FullMethodName,
+		pb.UserService_DeleteAccount_FullMethodName,
+		pb.UserService_SetVaultCheck_FullMethodName,
+		pb.UserService_SetVaultKey_FullMethodName:
+		return interceptor.AuthFunc(s.logger, s.userUC)(ctx)
+	}
 	return ctx, nil
 }
+
+func (s *UserService) getSession(ctx context.Context) (userID uuid.UUID, sessionID uuid.UUID, err error) {
+	userID, ok := interceptor.GetUserID(ctx)
+	if !ok {
+		s.logger.Debug("user id not found in context")
+		return uuid.Nil, uuid.Nil, status.Error(codes.Unauthenticated, entities.ErrUserIDInvalid.Error())
+	}
+	sessionID, ok = interceptor.GetSessionID(ctx)
+	if !ok {
+		s.logger.Debug("session id not found in context")
+		return uuid.Nil, uuid.Nil, status.Error(codes.Unauthenticated, entities.ErrSessionIDInvalid.Error())
+	}
+	return userID, sessionID, nil
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -9,10 +9,10 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/pass"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/repo"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/diff"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/notify"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/token"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/migrations"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
-	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
 	"github.com/dlomanov/gophkeeper/internal/infra/migrator"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
@@ -23,12 +23,14 @@ import (
 var _ io.Closer = (*Container)(nil)
 
 type Container struct {
-	Logger  *zap.Logger
-	Config  *config.Config
-	DB      *sqlx.DB
-	Tx      *manager.Manager
-	UserUC  *usecases.UserUC
-	EntryUC *usecases.EntryUC
+	Logger    *zap.Logger
+	Config    *config.Config
+	DB        *sqlx.DB
+	Tx        *manager.Manager
+	UserUC    *usecases.UserUC
+	SessionUC *usecases.SessionUC
+	EntryUC   *usecases.EntryUC
+	BlobUC    *usecases.BlobUC
 }
 
 func NewContainer(
@@ -50,32 +52,35 @@ func NewContainer(
 	// repos
 	getter := trmsqlx.DefaultCtxGetter
 	userRepo := repo.NewUserRepo(db, getter)
+	sessionRepo := repo.NewSessionRepo(db, getter)
 
 	// services
 	hasher := pass.NewHasher(config.PassHashCost)
-	tokener := token.NewJWT(config.TokenSecretKey, config.TokenExpires)
+	tokener := token.NewJWT(config.TokenSecretKey, config.TokenExpires, config.RefreshExpires)
 	merger := diff.NewEntry()
-	encrypter, err := encrypto.NewEncrypter(config.DataSecretKey)
-	if err != nil {
-		return nil, fmt.Errorf("container: failed to create encrypter: %w", err)
-	}
+	notifier := notify.NewHub()
 
 	// usecases
-	userUC := usecases.NewUserUC(logger, userRepo, hasher, tokener)
+	userUC := usecases.NewUserUC(logger, userRepo, hasher, tokener, sessionRepo, trm)
+	sessionUC := usecases.NewSessionUC(logger, sessionRepo)
+	entryRepo := repo.NewEntryRepo(db, getter)
 	entryUC := usecases.NewEntryUC(
 		logger,
-		repo.NewEntryRepo(db, getter),
+		entryRepo,
 		merger,
-		encrypter,
+		notifier,
 		trm)
+	blobUC := usecases.NewBlobUC(logger, repo.NewBlobRepo(db, getter), entryRepo, trm)
 
 	return &Container{
-		Logger:  logger,
-		Config:  config,
-		DB:      db,
-		Tx:      trm,
-		UserUC:  userUC,
-		EntryUC: entryUC,
+		Logger:    logger,
+		Config:    config,
+		DB:        db,
+		Tx:        trm,
+		UserUC:    userUC,
+		SessionUC: sessionUC,
+		EntryUC:   entryUC,
+		BlobUC:    blobUC,
 	}, nil
 }
 
@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var _ usecases.BlobRepo = (*BlobRepo)(nil)
+
+type (
+	BlobRepo struct {
+		db     *sqlx.DB
+		getter *trmsqlx.CtxGetter
+	}
+	blobChunkRow struct {
+		ID         uuid.UUID `db:"id"`
+		UserID     uuid.UUID `db:"user_id"`
+		ChunkIndex int       `db:"chunk_index"`
+		Data       []byte    `db:"data"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+)
+
+func NewBlobRepo(
+	db *sqlx.DB,
+	getter *trmsqlx.CtxGetter,
+) *BlobRepo {
+	return &BlobRepo{
+		db:     db,
+		getter: getter,
+	}
+}
+
+func (r *BlobRepo) GetChunk(
+	ctx context.Context,
+	userID uuid.UUID,
+	id uuid.UUID,
+	index int,
+) (entities.BlobChunk, error) {
+	row := blobChunkRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT id, user_id, chunk_index, data, created_at
+		FROM blobs
+		WHERE id = $1 AND user_id = $2 AND chunk_index = $3;`, id, userID, index)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entities.BlobChunk{}, fmt.Errorf("blob_repo: %w", entities.ErrBlobNotFound)
+	case err != nil:
+		return entities.BlobChunk{}, fmt.Errorf("blob_repo: failed to get blob chunk: %w", err)
+	}
+	return r.toEntity(row), nil
+}
+
+func (r *BlobRepo) CreateChunk(ctx context.Context, chunk entities.BlobChunk) error {
+	_, err := r.getDB(ctx).NamedExecContext(ctx, `
+		INSERT INTO blobs (id, user_id, chunk_index, data, created_at)
+		VALUES (:id, :user_id, :chunk_index, :data, :created_at)
+		ON CONFLICT (id, user_id, chunk_index) DO UPDATE SET data = excluded.data`,
+		r.toRow(chunk))
+	if err != nil {
+		return fmt.Errorf("blob_repo: failed to create blob chunk: %w", err)
+	}
+	return nil
+}
+
+func (r *BlobRepo) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	result, err := r.getDB(ctx).ExecContext(ctx, `
+		DELETE FROM blobs
+		WHERE id = $1 AND user_id = $2;`, id, userID)
+	if err != nil {
+		return fmt.Errorf("blob_repo: failed to delete blob: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("blob_repo: failed to delete blob: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("blob_repo: %w", entities.ErrBlobNotFound)
+	}
+	return nil
+}
+
+// Move moves the blob chunks to another ID of the same user
+func (r *BlobRepo) Move(ctx context.Context, userID uuid.UUID, from uuid.UUID, to uuid.UUID) error {
+	result, err := r.getDB(ctx).ExecContext(ctx, `
+		UPDATE blobs
+		SET id = $3
+		WHERE id = $1 AND user_id = $2;`, from, userID, to)
+	if err != nil {
+		return fmt.Errorf("blob_repo: failed to move blob: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("blob_repo: failed to move blob: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("blob_repo: %w", entities.ErrBlobNotFound)
+	}
+	return nil
+}
+
+// PurgeOrphans removes blobs created before createdBefore that have no entry
+func (r *BlobRepo) PurgeOrphans(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := r.getDB(ctx).ExecContext(ctx, `
+		DELETE FROM blobs b
+		WHERE NOT b.legacy
+		  AND b.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM entries e WHERE e.id = b.id AND e.user_id = b.user_id);`, createdBefore)
+	if err != nil {
+		return 0, fmt.Errorf("blob_repo: failed to purge orphan blobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *BlobRepo) getDB(ctx context.Context) trmsqlx.Tr {
+	return r.getter.DefaultTrOrDB(ctx, r.db)
+}
+
+func (*BlobRepo) toRow(chunk entities.BlobChunk) blobChunkRow {
+	return blobChunkRow{
+		ID:         chunk.ID,
+		UserID:     chunk.UserID,
+		ChunkIndex: chunk.Index,
+		Data:       chunk.Data,
+		CreatedAt:  chunk.CreatedAt,
+	}
+}
+
+func (*BlobRepo) toEntity(row blobChunkRow) entities.BlobChunk {
+	return entities.BlobChunk{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Index:     row.ChunkIndex,
+		Data:      row.Data,
+		CreatedAt: row.CreatedAt,
+	}
+}
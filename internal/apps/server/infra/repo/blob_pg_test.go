@@ -0,0 +1,92 @@
+package repo_test
+
+import (
+	"context"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/repo"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (s *EntryTestSuit) TestBlobRepo() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := entities.NewUser(entities.HashCreds{
+		Login:    "test_blob_user",
+		PassHash: []byte("test_password_hash"),
+	})
+	require.NoError(s.T(), err, "no error expected when creating user")
+	err = repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user)
+	require.NoError(s.T(), err, "no error expected when creating user in storage")
+
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entry, err := entities.NewEntry("blob", user.ID, core.EntryTypeBinary, []byte("descriptor"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	err = entryRepo.Create(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when creating entry in storage")
+
+	blobRepo := repo.NewBlobRepo(s.db, trmsqlx.DefaultCtxGetter)
+	_, err = blobRepo.GetChunk(ctx, user.ID, entry.ID, 0)
+	require.ErrorIs(s.T(), err, entities.ErrBlobNotFound, "expected blob not found error")
+
+	chunks := [][]byte{[]byte("chunk_0"), []byte("chunk_1")}
+	for i, data := range chunks {
+		chunk, err := entities.NewBlobChunk(entry.ID, user.ID, i, data)
+		require.NoError(s.T(), err, "no error expected when creating chunk")
+		err = blobRepo.CreateChunk(ctx, *chunk)
+		require.NoError(s.T(), err, "no error expected when creating chunk in storage")
+	}
+	for i, data := range chunks {
+		chunk, err := blobRepo.GetChunk(ctx, user.ID, entry.ID, i)
+		require.NoError(s.T(), err, "no error expected when getting chunk")
+		require.Equal(s.T(), data, chunk.Data, "expected same chunk data")
+	}
+
+	staged, err := entities.NewBlobChunk(uuid.New(), user.ID, 0, []byte("staged"))
+	require.NoError(s.T(), err, "no error expected when creating chunk")
+	err = blobRepo.CreateChunk(ctx, *staged)
+	require.NoError(s.T(), err, "no error expected when creating chunk in storage")
+	err = blobRepo.Delete(ctx, user.ID, entry.ID)
+	require.NoError(s.T(), err, "no error expected when deleting blob")
+	err = blobRepo.Move(ctx, user.ID, staged.ID, entry.ID)
+	require.NoError(s.T(), err, "no error expected when moving blob")
+	chunk, err := blobRepo.GetChunk(ctx, user.ID, entry.ID, 0)
+	require.NoError(s.T(), err, "no error expected when getting moved chunk")
+	require.Equal(s.T(), staged.Data, chunk.Data, "expected moved chunk data")
+	err = blobRepo.Move(ctx, user.ID, staged.ID, entry.ID)
+	require.ErrorIs(s.T(), err, entities.ErrBlobNotFound, "expected staged blob not found error")
+
+	entry.Delete()
+	err = entryRepo.Update(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when deleting entry")
+	_, err = blobRepo.GetChunk(ctx, user.ID, entry.ID, 0)
+	require.NoError(s.T(), err, "expected blob kept until entry is purged")
+	_, err = entryRepo.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(s.T(), err, "no error expected when purging entries")
+	_, err = blobRepo.GetChunk(ctx, user.ID, entry.ID, 0)
+	require.ErrorIs(s.T(), err, entities.ErrBlobNotFound, "expected blob purged with entry")
+	err = blobRepo.Delete(ctx, user.ID, entry.ID)
+	require.ErrorIs(s.T(), err, entities.ErrBlobNotFound, "expected blob not found error")
+
+	orphan, err := entities.NewBlobChunk(uuid.New(), user.ID, 0, []byte("orphan"))
+	require.NoError(s.T(), err, "no error expected when creating chunk")
+	orphan.CreatedAt = orphan.CreatedAt.Add(-time.Hour)
+	legacy := *orphan
+	legacy.ID = uuid.New()
+	for _, chunk := range []entities.BlobChunk{*orphan, legacy} {
+		err = blobRepo.CreateChunk(ctx, chunk)
+		require.NoError(s.T(), err, "no error expected when creating chunk in storage")
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE blobs SET legacy = true WHERE id = $1;`, legacy.ID)
+	require.NoError(s.T(), err, "no error expected when marking blob as legacy")
+	_, err = blobRepo.PurgeOrphans(ctx, time.Now().UTC())
+	require.NoError(s.T(), err, "no error expected when purging orphan blobs")
+	_, err = blobRepo.GetChunk(ctx, user.ID, orphan.ID, 0)
+	require.ErrorIs(s.T(), err, entities.ErrBlobNotFound, "expected orphan blob purged")
+	_, err = blobRepo.GetChunk(ctx, user.ID, legacy.ID, 0)
+	require.NoError(s.T(), err, "expected legacy blob kept")
+}
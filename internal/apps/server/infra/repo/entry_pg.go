@@ -10,11 +10,14 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"time"
 )
 
+const pgUniqueViolation = "23505"
+
 type (
 	EntryRepo struct {
 		db     *sqlx.DB
@@ -26,14 +29,35 @@ type (
 		Key       string         `db:"key"`
 		Type      string         `db:"type"`
 		Meta      sql.NullString `db:"meta"`
+		Folder    string         `db:"folder"`
+		Tags      sql.NullString `db:"tags"`
 		Data      []byte         `db:"data"`
 		Version   int64          `db:"version"`
 		CreatedAt time.Time      `db:"created_at"`
 		UpdatedAt time.Time      `db:"updated_at"`
+		DeletedAt sql.NullTime   `db:"deleted_at"`
+		Seq       int64          `db:"seq"`
 	}
 	entryVersionRow struct {
-		ID      uuid.UUID `db:"id"`
-		Version int64     `db:"version"`
+		ID        uuid.UUID    `db:"id"`
+		Version   int64        `db:"version"`
+		DeletedAt sql.NullTime `db:"deleted_at"`
+	}
+	entryKeyRow struct {
+		ID     uuid.UUID `db:"id"`
+		UserID uuid.UUID `db:"user_id"`
+		Seq    int64     `db:"seq"`
+	}
+	entryChangeSeqRow struct {
+		Current int64 `db:"change_seq"`
+		Purged  int64 `db:"purged_seq"`
+	}
+	entryRequestRow struct {
+		UserID    uuid.UUID `db:"user_id"`
+		RequestID string    `db:"request_id"`
+		EntryID   uuid.UUID `db:"entry_id"`
+		Version   int64     `db:"version"`
+		CreatedAt time.Time `db:"created_at"`
 	}
 )
 
@@ -50,7 +74,7 @@ func NewEntryRepo(
 func (r *EntryRepo) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entities.Entry, error) {
 	row := entryRow{}
 	err := r.getDB(ctx).GetContext(ctx, &row, `
-		SELECT id, user_id, key, type, meta, data, version, created_at, updated_at
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
 		FROM entries
 		WHERE id = $1 AND user_id = $2;`, id, userID)
 	switch {
@@ -62,12 +86,27 @@ func (r *EntryRepo) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*e
 	return r.toEntity(row)
 }
 
+func (r *EntryRepo) GetByKey(ctx context.Context, userID uuid.UUID, key string) (*entities.Entry, error) {
+	row := entryRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
+		FROM entries
+		WHERE key = $1 AND user_id = $2 AND deleted_at IS NULL;`, key, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("entry_repo: %w", entities.ErrEntryNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get entry by key: %w", err)
+	}
+	return r.toEntity(row)
+}
+
 func (r *EntryRepo) GetAll(ctx context.Context, userID uuid.UUID) ([]entities.Entry, error) {
 	var rows []entryRow
 	err := r.getDB(ctx).SelectContext(ctx, &rows, `
-		SELECT id, user_id, key, type, meta, data, version, created_at, updated_at
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
 		FROM entries
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at;`, userID)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -81,7 +120,7 @@ func (r *EntryRepo) GetAll(ctx context.Context, userID uuid.UUID) ([]entities.En
 func (r *EntryRepo) GetVersions(ctx context.Context, userID uuid.UUID) ([]core.EntryVersion, error) {
 	var rows []entryVersionRow
 	err := r.getDB(ctx).SelectContext(ctx, &rows, `
-		SELECT id, version FROM entries WHERE user_id = $1;`, userID)
+		SELECT id, version, deleted_at FROM entries WHERE user_id = $1;`, userID)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
@@ -101,9 +140,9 @@ func (r *EntryRepo) GetByIDs(
 	}
 	var rows []entryRow
 	err := r.getDB(ctx).SelectContext(ctx, &rows, `
-		SELECT id, user_id, key, type, meta, data, version, created_at, updated_at
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
 		FROM entries
-		WHERE user_id = $1 AND id = ANY($2)
+		WHERE user_id = $1 AND id = ANY($2) AND deleted_at IS NULL
 		ORDER BY created_at;`, userID, pq.Array(entryIds))
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -114,14 +153,54 @@ func (r *EntryRepo) GetByIDs(
 	return r.toEntities(rows)
 }
 
+func (r *EntryRepo) GetHistory(ctx context.Context, userID uuid.UUID, id uuid.UUID) ([]entities.Entry, error) {
+	var rows []entryRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at
+		FROM entry_versions
+		WHERE id = $1 AND user_id = $2
+		ORDER BY version DESC;`, id, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get entry history: %w", err)
+	}
+	return r.toEntities(rows)
+}
+
+func (r *EntryRepo) GetHistoryEntry(
+	ctx context.Context,
+	userID uuid.UUID,
+	id uuid.UUID,
+	version int64,
+) (*entities.Entry, error) {
+	row := entryRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at
+		FROM entry_versions
+		WHERE id = $1 AND user_id = $2 AND version = $3;`, id, userID, version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("entry_repo: %w", entities.ErrEntryHistoryNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get entry history: %w", err)
+	}
+	return r.toEntity(row)
+}
+
 func (r *EntryRepo) Create(ctx context.Context, e *entities.Entry) error {
 	row, err := r.toRow(e)
 	if err != nil {
 		return err
 	}
 	result, err := r.getDB(ctx).NamedExecContext(ctx, `
-		INSERT INTO entries (id, user_id, key, type, meta, data, version, created_at, updated_at)
-		VALUES (:id, :user_id, :key, :type, :meta, :data, :version, :created_at, :updated_at)
+		WITH seq AS (
+		    UPDATE users SET change_seq = change_seq + 1 WHERE id = :user_id RETURNING change_seq
+		)
+		INSERT INTO entries (id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq)
+		SELECT :id, :user_id, :key, :type, :meta, :folder, :tags, :data, :version, :created_at, :updated_at, :deleted_at, seq.change_seq
+		FROM seq
 		ON CONFLICT DO NOTHING
 	`, row)
 	if err != nil {
@@ -142,15 +221,30 @@ func (r *EntryRepo) Update(ctx context.Context, e *entities.Entry) error {
 	if err != nil {
 		return err
 	}
+	if err = r.archive(ctx, e.UserID, e.ID); err != nil {
+		return err
+	}
 	result, err := r.getDB(ctx).NamedExecContext(ctx, `
+		WITH seq AS (
+		    UPDATE users SET change_seq = change_seq + 1 WHERE id = :user_id RETURNING change_seq
+		)
 		UPDATE entries
 		SET meta = :meta,
+		    folder = :folder,
+		    tags = :tags,
 		    data = :data,
 		    version = :version,
-		    updated_at = :updated_at
+		    updated_at = :updated_at,
+		    deleted_at = :deleted_at,
+		    seq = (SELECT change_seq FROM seq)
 		WHERE id = :id AND user_id = :user_id
 	`, row)
-	if err != nil {
+	var pgErr *pgconn.PgError
+	switch {
+	// restored entry key is taken by another entry
+	case errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation:
+		return fmt.Errorf("entry_repo: %w", entities.ErrEntryExists)
+	case err != nil:
 		return fmt.Errorf("entry_repo: failed to update entry: %w", err)
 	}
 	affected, err := result.RowsAffected()
@@ -163,19 +257,152 @@ func (r *EntryRepo) Update(ctx context.Context, e *entities.Entry) error {
 	return nil
 }
 
-func (r *EntryRepo) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
-	result, err := r.getDB(ctx).ExecContext(ctx, `
+func (r *EntryRepo) GetDeleted(ctx context.Context, userID uuid.UUID) ([]entities.Entry, error) {
+	var rows []entryRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
+		FROM entries
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC;`, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get deleted entries: %w", err)
+	}
+	return r.toEntities(rows)
+}
+
+// GetChangesSince returns entries including tombstones changed after the seq in the change order
+func (r *EntryRepo) GetChangesSince(
+	ctx context.Context,
+	userID uuid.UUID,
+	seq int64,
+	limit int,
+) ([]entities.Entry, error) {
+	var rows []entryRow
+	err := r.getDB(ctx).SelectContext(ctx, &rows, `
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at, seq
+		FROM entries
+		WHERE user_id = $1 AND seq > $2
+		ORDER BY seq
+		LIMIT $3;`, userID, seq, limit)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get entry changes: %w", err)
+	}
+	return r.toEntities(rows)
+}
+
+// GetChangeSeq returns the last assigned and the last purged seq of the user changes
+func (r *EntryRepo) GetChangeSeq(ctx context.Context, userID uuid.UUID) (entities.EntryChangeSeq, error) {
+	row := entryChangeSeqRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT change_seq, purged_seq
+		FROM users
+		WHERE id = $1;`, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entities.EntryChangeSeq{}, fmt.Errorf("entry_repo: %w", entities.ErrUserNotFound)
+	case err != nil:
+		return entities.EntryChangeSeq{}, fmt.Errorf("entry_repo: failed to get change seq: %w", err)
+	}
+	return entities.EntryChangeSeq{Current: row.Current, Purged: row.Purged}, nil
+}
+
+// Purge permanently removes entries deleted before deletedBefore with their history and blobs
+func (r *EntryRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var rows []entryKeyRow
+	if err := r.getDB(ctx).SelectContext(ctx, &rows, `
 		DELETE FROM entries
-		WHERE id = $1 AND user_id = $2;`, id, userID)
-	if err != nil {
-		return fmt.Errorf("entry_repo: failed to delete entry: %w", err)
+		WHERE deleted_at < $1
+		RETURNING id, user_id, seq;`, deletedBefore); err != nil {
+		return 0, fmt.Errorf("entry_repo: failed to purge entries: %w", err)
 	}
-	affected, err := result.RowsAffected()
+	for _, row := range rows {
+		if _, err := r.getDB(ctx).ExecContext(ctx, `
+			DELETE FROM entry_versions
+			WHERE id = $1 AND user_id = $2;`, row.ID, row.UserID); err != nil {
+			return 0, fmt.Errorf("entry_repo: failed to purge entry history: %w", err)
+		}
+		if _, err := r.getDB(ctx).ExecContext(ctx, `
+			DELETE FROM blobs
+			WHERE id = $1 AND user_id = $2;`, row.ID, row.UserID); err != nil {
+			return 0, fmt.Errorf("entry_repo: failed to purge entry blob: %w", err)
+		}
+		// clients behind the purged tombstone can't see the deletion anymore
+		if _, err := r.getDB(ctx).ExecContext(ctx, `
+			UPDATE users
+			SET purged_seq = greatest(purged_seq, $2)
+			WHERE id = $1;`, row.UserID, row.Seq); err != nil {
+			return 0, fmt.Errorf("entry_repo: failed to update purged seq: %w", err)
+		}
+	}
+	return int64(len(rows)), nil
+}
+
+func (r *EntryRepo) GetRequest(
+	ctx context.Context,
+	userID uuid.UUID,
+	requestID string,
+) (*entities.AppliedEntryRequest, error) {
+	row := entryRequestRow{}
+	err := r.getDB(ctx).GetContext(ctx, &row, `
+		SELECT user_id, request_id, entry_id, version, created_at
+		FROM entry_requests
+		WHERE user_id = $1 AND request_id = $2;`, userID, requestID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("entry_repo: %w", entities.ErrEntryRequestNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("entry_repo: failed to get entry request: %w", err)
+	}
+	return &entities.AppliedEntryRequest{
+		UserID:    row.UserID,
+		RequestID: row.RequestID,
+		EntryID:   row.EntryID,
+		Version:   row.Version,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (r *EntryRepo) SaveRequest(ctx context.Context, request entities.AppliedEntryRequest) error {
+	if _, err := r.getDB(ctx).ExecContext(ctx, `
+		INSERT INTO entry_requests (user_id, request_id, entry_id, version, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING;`,
+		request.UserID,
+		request.RequestID,
+		request.EntryID,
+		request.Version,
+		request.CreatedAt); err != nil {
+		return fmt.Errorf("entry_repo: failed to save entry request: %w", err)
+	}
+	return nil
+}
+
+// PurgeRequests removes request IDs applied before createdBefore, their retries aren't replayed anymore
+func (r *EntryRepo) PurgeRequests(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := r.getDB(ctx).ExecContext(ctx, `
+		DELETE FROM entry_requests
+		WHERE created_at < $1;`, createdBefore)
 	if err != nil {
-		return fmt.Errorf("entry_repo: failed to delete entry: %w", err)
+		return 0, fmt.Errorf("entry_repo: failed to purge entry requests: %w", err)
 	}
-	if affected == 0 {
-		return fmt.Errorf("entry_repo: %w", entities.ErrEntryNotFound)
+	return result.RowsAffected()
+}
+
+// archive copies the current entry version to the history before it is overwritten
+func (r *EntryRepo) archive(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if _, err := r.getDB(ctx).ExecContext(ctx, `
+		INSERT INTO entry_versions (id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at)
+		SELECT id, user_id, key, type, meta, folder, tags, data, version, created_at, updated_at, deleted_at
+		FROM entries
+		WHERE id = $1 AND user_id = $2
+		ON CONFLICT DO NOTHING;`, id, userID); err != nil {
+		return fmt.Errorf("entry_repo: failed to archive entry version: %w", err)
 	}
 	return nil
 }
@@ -195,11 +422,16 @@ func (*EntryRepo) toRow(e *entities.Entry) (entryRow, error) {
 		Key:       e.Key,
 		Type:      string(e.Type),
 		Meta:      sql.NullString{},
+		Folder:    e.Folder,
+		Tags:      sql.NullString{},
 		Data:      e.Data,
 		Version:   e.Version,
 		CreatedAt: e.CreatedAt,
 		UpdatedAt: e.UpdatedAt,
 	}
+	if e.DeletedAt != nil {
+		row.DeletedAt = sql.NullTime{Valid: true, Time: *e.DeletedAt}
+	}
 
 	if e.Meta != nil {
 		meta, err := json.Marshal(e.Meta)
@@ -208,6 +440,13 @@ func (*EntryRepo) toRow(e *entities.Entry) (entryRow, error) {
 		}
 		row.Meta = sql.NullString{Valid: true, String: string(meta)}
 	}
+	if e.Tags != nil {
+		tags, err := json.Marshal(e.Tags)
+		if err != nil {
+			return entryRow{}, fmt.Errorf("entry_repo: failed to marshal entry tags: %w", err)
+		}
+		row.Tags = sql.NullString{Valid: true, String: string(tags)}
+	}
 
 	return row, nil
 }
@@ -231,10 +470,17 @@ func (*EntryRepo) toEntity(row entryRow) (*entities.Entry, error) {
 		Key:       row.Key,
 		Type:      "",
 		Meta:      nil,
+		Folder:    row.Folder,
+		Tags:      nil,
 		Data:      row.Data,
 		Version:   row.Version,
 		CreatedAt: row.CreatedAt,
 		UpdatedAt: row.UpdatedAt,
+		Seq:       row.Seq,
+	}
+	if row.DeletedAt.Valid {
+		deletedAt := row.DeletedAt.Time
+		entry.DeletedAt = &deletedAt
 	}
 
 	typ := core.EntryType(row.Type)
@@ -250,6 +496,11 @@ func (*EntryRepo) toEntity(row entryRow) (*entities.Entry, error) {
 		}
 		entry.Meta = meta
 	}
+	if row.Tags.Valid {
+		if err := json.Unmarshal([]byte(row.Tags.String), &entry.Tags); err != nil {
+			return nil, fmt.Errorf("entry_repo: failed to unmarshal entry tags: %w", err)
+		}
+	}
 
 	return entry, nil
 }
@@ -260,6 +511,7 @@ func (r *EntryRepo) toEntryVersions(rows []entryVersionRow) ([]core.EntryVersion
 		versions = append(versions, core.EntryVersion{
 			ID:      row.ID,
 			Version: row.Version,
+			Deleted: row.DeletedAt.Valid,
 		})
 	}
 	return versions, nil
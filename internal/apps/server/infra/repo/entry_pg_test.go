@@ -88,6 +88,8 @@ func (s *EntryTestSuit) TestEntryRepo() {
 	entries := make([]*entities.Entry, 3)
 	entries[0], err = entities.NewEntry("key1", user.ID, core.EntryTypePassword, []byte("test_data_1"))
 	entries[0].Meta = map[string]string{"key1": "value1", "key2": "value2"}
+	entries[0].Folder = "Work"
+	entries[0].Tags = []string{"prod"}
 	require.NoError(s.T(), err, "no error expected when creating entry")
 	entries[1], err = entities.NewEntry("key2", user.ID, core.EntryTypeBinary, []byte("test_data_2"))
 	require.NoError(s.T(), err, "no error expected when creating entry")
@@ -109,6 +111,8 @@ func (s *EntryTestSuit) TestEntryRepo() {
 	}
 
 	entries[0].Meta["test_key"] = "test_value"
+	entries[0].Folder = "Work/Servers"
+	entries[0].Tags = append(entries[0].Tags, "db")
 	err = entryRepo.Update(ctx, entries[0])
 	require.NoError(s.T(), err, "no error expected when updating entry in storage")
 
@@ -148,11 +152,188 @@ func (s *EntryTestSuit) TestEntryRepo() {
 	require.Equal(s.T(), len(entryIds), compared, "expected same number of entries and versions")
 }
 
+func (s *EntryTestSuit) TestEntryHistory() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := entities.NewUser(entities.HashCreds{
+		Login:    "test_history_user",
+		PassHash: []byte("test_password_hash"),
+	})
+	require.NoError(s.T(), err, "no error expected when creating user")
+	err = repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user)
+	require.NoError(s.T(), err, "no error expected when creating user in storage")
+
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entry, err := entities.NewEntry("key1", user.ID, core.EntryTypeNote, []byte("test_data_1"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	err = entryRepo.Create(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when creating entry in storage")
+	history, err := entryRepo.GetHistory(ctx, user.ID, entry.ID)
+	require.NoError(s.T(), err, "no error expected when getting entry history")
+	require.Empty(s.T(), history, "expected empty history")
+
+	v1 := *entry
+	err = entry.Update(entry.Version, entities.UpdateEntryData([]byte("test_data_2")))
+	require.NoError(s.T(), err, "no error expected when updating entry")
+	err = entryRepo.Update(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when updating entry in storage")
+	v2 := *entry
+	entry.Delete()
+	err = entryRepo.Update(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when deleting entry in storage")
+
+	history, err = entryRepo.GetHistory(ctx, user.ID, entry.ID)
+	require.NoError(s.T(), err, "no error expected when getting entry history")
+	require.Len(s.T(), history, 2, "expected 2 versions in history")
+	s.assertEquals(s.T(), &v2, &history[0])
+	s.assertEquals(s.T(), &v1, &history[1])
+
+	got, err := entryRepo.GetHistoryEntry(ctx, user.ID, entry.ID, v1.Version)
+	require.NoError(s.T(), err, "no error expected when getting entry version")
+	s.assertEquals(s.T(), &v1, got)
+	_, err = entryRepo.GetHistoryEntry(ctx, user.ID, entry.ID, v2.Version+1)
+	require.ErrorIs(s.T(), err, entities.ErrEntryHistoryNotFound, "expected entry version not found")
+}
+
+func (s *EntryTestSuit) TestEntryTombstone() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := entities.NewUser(entities.HashCreds{
+		Login:    "test_tombstone_user",
+		PassHash: []byte("test_password_hash"),
+	})
+	require.NoError(s.T(), err, "no error expected when creating user")
+	err = repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user)
+	require.NoError(s.T(), err, "no error expected when creating user in storage")
+
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entry, err := entities.NewEntry("key1", user.ID, core.EntryTypeNote, []byte("test_data_1"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	err = entryRepo.Create(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when creating entry in storage")
+	entry.Delete()
+	err = entryRepo.Update(ctx, entry)
+	require.NoError(s.T(), err, "no error expected when deleting entry in storage")
+
+	getAll, err := entryRepo.GetAll(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected when getting entries")
+	require.Empty(s.T(), getAll, "expected deleted entry excluded")
+	versions, err := entryRepo.GetVersions(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected when getting entry versions")
+	require.Len(s.T(), versions, 1, "expected tombstone version")
+	require.True(s.T(), versions[0].Deleted, "expected tombstone version")
+	trash, err := entryRepo.GetDeleted(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected when getting deleted entries")
+	require.Len(s.T(), trash, 1, "expected deleted entry in trash")
+	s.assertEquals(s.T(), entry, &trash[0])
+
+	// key of deleted entry is free
+	reused, err := entities.NewEntry("key1", user.ID, core.EntryTypeNote, []byte("test_data_2"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	err = entryRepo.Create(ctx, reused)
+	require.NoError(s.T(), err, "no error expected when reusing deleted entry key")
+	entry.Undelete()
+	err = entryRepo.Update(ctx, entry)
+	require.ErrorIs(s.T(), err, entities.ErrEntryExists, "expected entry key is taken")
+
+	purged, err := entryRepo.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(s.T(), err, "no error expected when purging entries")
+	require.GreaterOrEqual(s.T(), purged, int64(1), "expected deleted entry purged")
+	_, err = entryRepo.Get(ctx, user.ID, entry.ID)
+	require.ErrorIs(s.T(), err, entities.ErrEntryNotFound, "expected purged entry not found")
+	history, err := entryRepo.GetHistory(ctx, user.ID, entry.ID)
+	require.NoError(s.T(), err, "no error expected when getting entry history")
+	require.Empty(s.T(), history, "expected history purged with entry")
+}
+
+func (s *EntryTestSuit) TestEntryChanges() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := entities.NewUser(entities.HashCreds{
+		Login:    "test_changes_user",
+		PassHash: []byte("test_password_hash"),
+	})
+	require.NoError(s.T(), err, "no error expected when creating user")
+	err = repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user)
+	require.NoError(s.T(), err, "no error expected when creating user in storage")
+
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entry1, err := entities.NewEntry("key1", user.ID, core.EntryTypeNote, []byte("test_data_1"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	require.NoError(s.T(), entryRepo.Create(ctx, entry1), "no error expected when creating entry in storage")
+	entry2, err := entities.NewEntry("key2", user.ID, core.EntryTypeNote, []byte("test_data_2"))
+	require.NoError(s.T(), err, "no error expected when creating entry")
+	require.NoError(s.T(), entryRepo.Create(ctx, entry2), "no error expected when creating entry in storage")
+	entry1.Delete()
+	require.NoError(s.T(), entryRepo.Update(ctx, entry1), "no error expected when deleting entry in storage")
+
+	seq, err := entryRepo.GetChangeSeq(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected when getting change seq")
+	require.Equal(s.T(), entities.EntryChangeSeq{Current: 3}, seq, "expected seq per write")
+	changes, err := entryRepo.GetChangesSince(ctx, user.ID, 0, 10)
+	require.NoError(s.T(), err, "no error expected when getting changes")
+	require.Len(s.T(), changes, 2, "expected latest change per entry")
+	require.Equal(s.T(), entry2.ID, changes[0].ID, "expected changes in seq order")
+	require.Equal(s.T(), entry1.ID, changes[1].ID, "expected changes in seq order")
+	require.True(s.T(), changes[1].Deleted(), "expected tombstone in changes")
+	changes, err = entryRepo.GetChangesSince(ctx, user.ID, 2, 10)
+	require.NoError(s.T(), err, "no error expected when getting changes")
+	require.Len(s.T(), changes, 1, "expected changes after seq")
+
+	_, err = entryRepo.Purge(ctx, time.Now().Add(time.Second))
+	require.NoError(s.T(), err, "no error expected when purging entries")
+	seq, err = entryRepo.GetChangeSeq(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected when getting change seq")
+	require.Equal(s.T(), entities.EntryChangeSeq{Current: 3, Purged: 3}, seq, "expected purged tombstone seq")
+}
+
+func (s *EntryTestSuit) TestEntryRequests() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := entities.NewUser(entities.HashCreds{
+		Login:    "test_requests_user",
+		PassHash: []byte("test_password_hash"),
+	})
+	require.NoError(s.T(), err, "no error expected when creating user")
+	err = repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user)
+	require.NoError(s.T(), err, "no error expected when creating user in storage")
+
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	_, err = entryRepo.GetRequest(ctx, user.ID, "request1")
+	require.ErrorIs(s.T(), err, entities.ErrEntryRequestNotFound, "not found error expected")
+
+	request := entities.AppliedEntryRequest{
+		UserID:    user.ID,
+		RequestID: "request1",
+		EntryID:   uuid.New(),
+		Version:   2,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	require.NoError(s.T(), entryRepo.SaveRequest(ctx, request), "no error expected when saving request")
+	require.NoError(s.T(), entryRepo.SaveRequest(ctx, request), "saving the same request should be no-op")
+	got, err := entryRepo.GetRequest(ctx, user.ID, "request1")
+	require.NoError(s.T(), err, "no error expected when getting request")
+	require.Equal(s.T(), request.EntryID, got.EntryID)
+	require.Equal(s.T(), request.Version, got.Version)
+
+	purged, err := entryRepo.PurgeRequests(ctx, time.Now().UTC().Add(time.Second))
+	require.NoError(s.T(), err, "no error expected when purging requests")
+	require.Equal(s.T(), int64(1), purged, "expected purged request")
+	_, err = entryRepo.GetRequest(ctx, user.ID, "request1")
+	require.ErrorIs(s.T(), err, entities.ErrEntryRequestNotFound, "purged request should not be found")
+}
+
 func (s *EntryTestSuit) assertEquals(t *testing.T, expected *entities.Entry, actual *entities.Entry) {
 	assert.Equal(t, expected.ID.String(), actual.ID.String(), "expected same entry IDs")
 	assert.Equal(t, expected.UserID.String(), actual.UserID.String(), "expected same user IDs")
 	assert.Equal(t, expected.Type, actual.Type, "expected same entry types")
 	assert.True(t, reflect.DeepEqual(expected.Meta, actual.Meta), "expected same entry meta")
+	assert.Equal(t, expected.Folder, actual.Folder, "expected same entry folder")
+	assert.Equal(t, expected.Tags, actual.Tags, "expected same entry tags")
 	assert.Equal(t, expected.Data, actual.Data, "expected same entry data")
 	assert.Equal(t, expected.CreatedAt.Format("2006-01-02 15:04:05.000"), actual.CreatedAt.Format("2006-01-02 15:04:05.000"), "expected same entry created at")
 	assert.Equal(t, expected.UpdatedAt.Format("2006-01-02 15:04:05.000"), actual.UpdatedAt.Format("2006-01-02 15:04:05.000"), "expected same entry updated at")
@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var _ usecases.SessionRepo = (*SessionRepo)(nil)
+
+type (
+	SessionRepo struct {
+		db     *sqlx.DB
+		getter *trmsqlx.CtxGetter
+	}
+	sessionRow struct {
+		ID         uuid.UUID    `db:"id"`
+		UserID     uuid.UUID    `db:"user_id"`
+		Device     string       `db:"device"`
+		IP         string       `db:"ip"`
+		CreatedAt  time.Time    `db:"created_at"`
+		LastSeenAt time.Time    `db:"last_seen_at"`
+		RevokedAt  sql.NullTime `db:"revoked_at"`
+	}
+)
+
+func NewSessionRepo(
+	db *sqlx.DB,
+	getter *trmsqlx.CtxGetter,
+) *SessionRepo {
+	return &SessionRepo{
+		db:     db,
+		getter: getter,
+	}
+}
+
+func (r *SessionRepo) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (session entities.Session, err error) {
+	db := r.getDB(ctx)
+	row := sessionRow{}
+
+	err = db.GetContext(ctx, &row, `
+		SELECT id, user_id, device, ip, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE id = $1 AND user_id = $2;`,
+		id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return session, entities.ErrSessionNotFound
+		default:
+			return session, err
+		}
+	}
+
+	return r.toEntity(row), nil
+}
+
+// GetAll returns active sessions, recently seen first
+func (r *SessionRepo) GetAll(ctx context.Context, userID uuid.UUID) ([]entities.Session, error) {
+	db := r.getDB(ctx)
+
+	var rows []sessionRow
+	err := db.SelectContext(ctx, &rows, `
+		SELECT id, user_id, device, ip, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC;`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]entities.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = r.toEntity(row)
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepo) Create(ctx context.Context, session entities.Session) error {
+	db := r.getDB(ctx)
+	row := r.toRow(session)
+
+	_, err := db.NamedExecContext(ctx, `
+		INSERT INTO sessions (id, user_id, device, ip, created_at, last_seen_at, revoked_at)
+		VALUES (:id, :user_id, :device, :ip, :created_at, :last_seen_at, :revoked_at);`,
+		row)
+	return err
+}
+
+// Touch updates the last seen time, the IP is kept when empty
+func (r *SessionRepo) Touch(ctx context.Context, id uuid.UUID, seenAt time.Time, ip string) error {
+	db := r.getDB(ctx)
+
+	_, err := db.ExecContext(ctx, `
+		UPDATE sessions SET last_seen_at = $2, ip = coalesce(nullif($3, ''), ip)
+		WHERE id = $1;`,
+		id, seenAt, ip)
+	return err
+}
+
+func (r *SessionRepo) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID, revokedAt time.Time) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE sessions SET revoked_at = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;`,
+		id, userID, revokedAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) RevokeOthers(
+	ctx context.Context,
+	userID uuid.UUID,
+	keepID uuid.UUID,
+	revokedAt time.Time,
+) (int64, error) {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE sessions SET revoked_at = $3
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;`,
+		userID, keepID, revokedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *SessionRepo) getDB(ctx context.Context) trmsqlx.Tr {
+	return r.getter.DefaultTrOrDB(ctx, r.db)
+}
+
+func (*SessionRepo) toRow(session entities.Session) sessionRow {
+	row := sessionRow{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		Device:     session.Device,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+	if session.RevokedAt != nil {
+		row.RevokedAt = sql.NullTime{Time: *session.RevokedAt, Valid: true}
+	}
+	return row
+}
+
+func (*SessionRepo) toEntity(row sessionRow) entities.Session {
+	session := entities.Session{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Device:     row.Device,
+		IP:         row.IP,
+		CreatedAt:  row.CreatedAt,
+		LastSeenAt: row.LastSeenAt,
+	}
+	if row.RevokedAt.Valid {
+		session.RevokedAt = &row.RevokedAt.Time
+	}
+	return session
+}
@@ -0,0 +1,64 @@
+package repo_test
+
+import (
+	"context"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/repo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (s *UserTestSuit) TestSessionRepo() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	r := repo.NewSessionRepo(s.db, trmsqlx.DefaultCtxGetter)
+	user := must(s.T(), func() (*entities.User, error) {
+		return entities.NewUser(entities.HashCreds{
+			Login:    "testSessionUser",
+			PassHash: []byte("hash"),
+		})
+	})
+	require.NoError(s.T(), repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *user), "no error expected")
+
+	session1 := entities.NewSession(user.ID, entities.Device{Name: "laptop", IP: "10.0.0.1"})
+	session2 := entities.NewSession(user.ID, entities.Device{Name: "phone", IP: "10.0.0.2"})
+	session3 := entities.NewSession(user.ID, entities.Device{Name: "tablet", IP: "10.0.0.3"})
+	for _, v := range []*entities.Session{session1, session2, session3} {
+		require.NoError(s.T(), r.Create(ctx, *v), "no error expected")
+	}
+
+	_, err := r.Get(ctx, uuid.New(), session1.ID)
+	require.ErrorIs(s.T(), err, entities.ErrSessionNotFound, "expected session of another user not found")
+	got, err := r.Get(ctx, user.ID, session1.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), session1.Device, got.Device, "expected same device")
+	require.Equal(s.T(), session1.IP, got.IP, "expected same IP")
+
+	seenAt := time.Now().UTC().Add(time.Minute)
+	require.NoError(s.T(), r.Touch(ctx, session1.ID, seenAt, ""), "no error expected")
+	got, err = r.Get(ctx, user.ID, session1.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), session1.IP, got.IP, "expected IP kept")
+	require.Equal(s.T(), seenAt.Format(time.DateTime), got.LastSeenAt.Format(time.DateTime), "expected last seen updated")
+
+	sessions, err := r.GetAll(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Len(s.T(), sessions, 3, "expected all sessions")
+	require.Equal(s.T(), session1.ID, sessions[0].ID, "expected recently seen session first")
+
+	now := time.Now().UTC()
+	require.NoError(s.T(), r.Revoke(ctx, user.ID, session2.ID, now), "no error expected")
+	err = r.Revoke(ctx, user.ID, session2.ID, now)
+	require.ErrorIs(s.T(), err, entities.ErrSessionNotFound, "expected revoked session not found")
+
+	revoked, err := r.RevokeOthers(ctx, user.ID, session1.ID, now)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), int64(1), revoked, "expected one active session revoked")
+	sessions, err = r.GetAll(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Len(s.T(), sessions, 1, "expected only current session")
+	require.Equal(s.T(), session1.ID, sessions[0].ID, "expected current session kept")
+}
@@ -21,11 +21,33 @@ type (
 		getter *trmsqlx.CtxGetter
 	}
 	userRow struct {
-		ID        uuid.UUID `db:"id"`
-		Login     string    `db:"login"`
-		PassHash  string    `db:"pass_hash"`
-		CreatedAt time.Time `db:"created_at"`
-		UpdatedAt time.Time `db:"updated_at"`
+		ID          uuid.UUID `db:"id"`
+		Login       string    `db:"login"`
+		PassHash    string    `db:"pass_hash"`
+		VaultSalt   []byte    `db:"vault_salt"`
+		VaultCheck  []byte    `db:"vault_check"`
+		VaultKey    []byte    `db:"vault_key"`
+		OTPSecret   string    `db:"otp_secret"`
+		OTPEnabled  bool      `db:"otp_enabled"`
+		OTPLastStep int64     `db:"otp_last_step"`
+		CreatedAt   time.Time `db:"created_at"`
+		UpdatedAt   time.Time `db:"updated_at"`
+	}
+	refreshTokenRow struct {
+		ID        uuid.UUID    `db:"id"`
+		UserID    uuid.UUID    `db:"user_id"`
+		SessionID uuid.UUID    `db:"session_id"`
+		TokenHash string       `db:"token_hash"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		CreatedAt time.Time    `db:"created_at"`
+		RevokedAt sql.NullTime `db:"revoked_at"`
+	}
+	recoveryCodeRow struct {
+		ID        uuid.UUID    `db:"id"`
+		UserID    uuid.UUID    `db:"user_id"`
+		CodeHash  string       `db:"code_hash"`
+		CreatedAt time.Time    `db:"created_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
 	}
 )
 
@@ -56,7 +78,32 @@ func (r *UserRepo) Get(ctx context.Context, login entities.Login) (user entities
 	db := r.getDB(ctx)
 	row := userRow{}
 
-	err = db.GetContext(ctx, &row, `SELECT id, login, pass_hash, created_at, updated_at FROM users WHERE login = $1;`, login)
+	err = db.GetContext(ctx, &row, `
+		SELECT id, login, pass_hash, vault_salt, vault_check, vault_key, otp_secret, otp_enabled, otp_last_step, created_at, updated_at
+		FROM users
+		WHERE login = $1;`,
+		login)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return user, entities.ErrUserNotFound
+		default:
+			return user, err
+		}
+	}
+
+	return r.toEntity(row), nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (user entities.User, err error) {
+	db := r.getDB(ctx)
+	row := userRow{}
+
+	err = db.GetContext(ctx, &row, `
+		SELECT id, login, pass_hash, vault_salt, vault_check, vault_key, otp_secret, otp_enabled, otp_last_step, created_at, updated_at
+		FROM users
+		WHERE id = $1;`,
+		id)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -74,8 +121,8 @@ func (r *UserRepo) Create(ctx context.Context, user entities.User) error {
 	row := r.toRow(user)
 
 	result, err := db.NamedExecContext(ctx, `
-		INSERT INTO users (id, login, pass_hash, created_at, updated_at)
-		VALUES (:id, :login, :pass_hash, :created_at, :updated_at)
+		INSERT INTO users (id, login, pass_hash, vault_salt, created_at, updated_at)
+		VALUES (:id, :login, :pass_hash, :vault_salt, :created_at, :updated_at)
 		ON CONFLICT (id) DO NOTHING;`,
 		row)
 	if err != nil {
@@ -93,17 +140,308 @@ func (r *UserRepo) Create(ctx context.Context, user entities.User) error {
 	return nil
 }
 
+func (r *UserRepo) UpdatePass(ctx context.Context, id uuid.UUID, hash core.PassHash) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE users SET pass_hash = $2, updated_at = $3
+		WHERE id = $1;`,
+		id, string(hash), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetVaultCheck stores the vault check only if it's not set yet, the stored one is returned
+func (r *UserRepo) SetVaultCheck(ctx context.Context, id uuid.UUID, check []byte) ([]byte, error) {
+	db := r.getDB(ctx)
+
+	var stored []byte
+	err := db.GetContext(ctx, &stored, `
+		UPDATE users SET vault_check = coalesce(vault_check, $2)
+		WHERE id = $1
+		RETURNING vault_check;`,
+		id, check)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, entities.ErrUserNotFound
+	case err != nil:
+		return nil, err
+	}
+	return stored, nil
+}
+
+// SetVaultKey replaces the wrapped vault key, the empty key is stored as null
+func (r *UserRepo) SetVaultKey(ctx context.Context, id uuid.UUID, key []byte) error {
+	db := r.getDB(ctx)
+
+	if len(key) == 0 {
+		key = nil
+	}
+	result, err := db.ExecContext(ctx, `
+		UPDATE users SET vault_key = $2
+		WHERE id = $1;`,
+		id, key)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the user with all rows referencing it, it should be called in a transaction
+func (r *UserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	db := r.getDB(ctx)
+
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE user_id = $1;`,
+		`DELETE FROM sessions WHERE user_id = $1;`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1;`,
+		`DELETE FROM blobs WHERE user_id = $1;`,
+		`DELETE FROM entry_versions WHERE user_id = $1;`,
+		`DELETE FROM entry_requests WHERE user_id = $1;`,
+		`DELETE FROM entries WHERE user_id = $1;`,
+	} {
+		if _, err := db.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	result, err := db.ExecContext(ctx, `DELETE FROM users WHERE id = $1;`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetRefreshToken(ctx context.Context, hash string) (token entities.RefreshToken, err error) {
+	db := r.getDB(ctx)
+	row := refreshTokenRow{}
+
+	err = db.GetContext(ctx, &row, `
+		SELECT id, user_id, session_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1;`,
+		hash)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return token, entities.ErrUserRefreshNotFound
+		default:
+			return token, err
+		}
+	}
+
+	return r.toRefreshTokenEntity(row), nil
+}
+
+func (r *UserRepo) CreateRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	db := r.getDB(ctx)
+	row := r.toRefreshTokenRow(token)
+
+	_, err := db.NamedExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, session_id, token_hash, expires_at, created_at, revoked_at)
+		VALUES (:id, :user_id, :session_id, :token_hash, :expires_at, :created_at, :revoked_at);`,
+		row)
+	return err
+}
+
+func (r *UserRepo) RevokeRefreshToken(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE refresh_tokens SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL;`,
+		id, revokedAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserRefreshNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepo) UpdateOTP(ctx context.Context, id uuid.UUID, otp entities.UserOTP) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE users SET otp_secret = $2, otp_enabled = $3, otp_last_step = $4, updated_at = $5
+		WHERE id = $1;`,
+		id, otp.Secret, otp.Enabled, otp.LastStep, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UseOTPStep moves the last used two-factor step forward, a step that isn't newer than the last one is rejected
+func (r *UserRepo) UseOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE users SET otp_last_step = $2
+		WHERE id = $1 AND otp_last_step < $2;`,
+		id, step)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserOTPInvalid
+	}
+
+	return nil
+}
+
+// TakeOTPAttempt counts the two-factor attempt, the last allowed attempt locks the user out until lockedUntil.
+// The attempt is rejected while the user is locked out.
+func (r *UserRepo) TakeOTPAttempt(
+	ctx context.Context,
+	id uuid.UUID,
+	maxAttempts int,
+	lockedUntil time.Time,
+	now time.Time,
+) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE users SET
+			otp_attempts = CASE WHEN otp_attempts + 1 >= $2 THEN 0 ELSE otp_attempts + 1 END,
+			otp_locked_until = CASE WHEN otp_attempts + 1 >= $2 THEN $3 ELSE otp_locked_until END
+		WHERE id = $1 AND (otp_locked_until IS NULL OR otp_locked_until <= $4);`,
+		id, maxAttempts, lockedUntil, now)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserOTPLocked
+	}
+
+	return nil
+}
+
+func (r *UserRepo) ResetOTPAttempts(ctx context.Context, id uuid.UUID) error {
+	db := r.getDB(ctx)
+
+	_, err := db.ExecContext(ctx, `
+		UPDATE users SET otp_attempts = 0, otp_locked_until = NULL
+		WHERE id = $1;`,
+		id)
+	return err
+}
+
+// ReplaceRecoveryCodes drops all recovery codes of the user and saves the new ones
+func (r *UserRepo) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entities.RecoveryCode) error {
+	db := r.getDB(ctx)
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userID); err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	rows := make([]recoveryCodeRow, len(codes))
+	for i, code := range codes {
+		rows[i] = r.toRecoveryCodeRow(code)
+	}
+	_, err := db.NamedExecContext(ctx, `
+		INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at, used_at)
+		VALUES (:id, :user_id, :code_hash, :created_at, :used_at);`,
+		rows)
+	return err
+}
+
+func (r *UserRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, usedAt time.Time) error {
+	db := r.getDB(ctx)
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE user_recovery_codes SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;`,
+		userID, hash, usedAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrUserRecoveryCodeNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepo) getDB(ctx context.Context) trmsqlx.Tr {
 	return r.getter.DefaultTrOrDB(ctx, r.db)
 }
 
 func (*UserRepo) toRow(user entities.User) userRow {
 	return userRow{
-		ID:        user.ID,
-		Login:     string(user.Login),
-		PassHash:  string(user.PassHash),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:          user.ID,
+		Login:       string(user.Login),
+		PassHash:    string(user.PassHash),
+		VaultSalt:   user.VaultSalt,
+		VaultCheck:  user.VaultCheck,
+		OTPSecret:   user.OTP.Secret,
+		OTPEnabled:  user.OTP.Enabled,
+		OTPLastStep: user.OTP.LastStep,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
 	}
 }
 
@@ -114,7 +452,58 @@ func (*UserRepo) toEntity(row userRow) entities.User {
 			Login:    entities.Login(row.Login),
 			PassHash: core.PassHash(row.PassHash),
 		},
+		VaultSalt:  row.VaultSalt,
+		VaultCheck: row.VaultCheck,
+		VaultKey:   row.VaultKey,
+		OTP: entities.UserOTP{
+			Secret:   row.OTPSecret,
+			Enabled:  row.OTPEnabled,
+			LastStep: row.OTPLastStep,
+		},
 		CreatedAt: row.CreatedAt,
 		UpdatedAt: row.UpdatedAt,
 	}
 }
+
+func (*UserRepo) toRefreshTokenRow(token entities.RefreshToken) refreshTokenRow {
+	row := refreshTokenRow{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		SessionID: token.SessionID,
+		TokenHash: token.Hash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if token.RevokedAt != nil {
+		row.RevokedAt = sql.NullTime{Time: *token.RevokedAt, Valid: true}
+	}
+	return row
+}
+
+func (*UserRepo) toRefreshTokenEntity(row refreshTokenRow) entities.RefreshToken {
+	token := entities.RefreshToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		SessionID: row.SessionID,
+		Hash:      row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.RevokedAt.Valid {
+		token.RevokedAt = &row.RevokedAt.Time
+	}
+	return token
+}
+
+func (*UserRepo) toRecoveryCodeRow(code entities.RecoveryCode) recoveryCodeRow {
+	row := recoveryCodeRow{
+		ID:        code.ID,
+		UserID:    code.UserID,
+		CodeHash:  code.Hash,
+		CreatedAt: code.CreatedAt,
+	}
+	if code.UsedAt != nil {
+		row.UsedAt = sql.NullTime{Time: *code.UsedAt, Valid: true}
+	}
+	return row
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/repo"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/migrations"
+	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/dlomanov/gophkeeper/internal/infra/migrator"
 	testcont2 "github.com/dlomanov/gophkeeper/internal/infra/testcont"
+	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
@@ -99,12 +101,156 @@ func (s *UserTestSuit) TestUserRepo() {
 
 	require.Equal(s.T(), user.ID, user1.ID, "expected same user IDs")
 	require.Equal(s.T(), user.HashCreds, user1.HashCreds, "expected same user creds")
+	require.Equal(s.T(), user.VaultSalt, user1.VaultSalt, "expected same user vault salt")
 	require.Equal(s.T(), user.CreatedAt.Format("2006-01-02 15:04:05.000"), user1.CreatedAt.Format("2006-01-02 15:04:05.000"), "expected same user created at")
 	require.Equal(s.T(), user.UpdatedAt.Format("2006-01-02 15:04:05.000"), user1.UpdatedAt.Format("2006-01-02 15:04:05.000"), "expected same user updated at")
 }
 
+func (s *UserTestSuit) TestRefreshTokens() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	r := repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter)
+	user := must(s.T(), func() (*entities.User, error) {
+		return entities.NewUser(entities.HashCreds{
+			Login:    "testRefreshUser",
+			PassHash: []byte("hash"),
+		})
+	})
+	require.NoError(s.T(), r.Create(ctx, *user), "no error expected")
+
+	session := entities.NewSession(user.ID, entities.Device{Name: "test device"})
+	require.NoError(s.T(), repo.NewSessionRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *session), "no error expected")
+	claims := entities.TokenClaims{UserID: user.ID, SessionID: session.ID}
+
+	_, err := r.GetRefreshToken(ctx, entities.Token("unknown").Hash())
+	require.ErrorIs(s.T(), err, entities.ErrUserRefreshNotFound, "expected refresh token not found error")
+
+	token, stored, err := entities.NewRefreshToken(claims, time.Hour)
+	require.NoError(s.T(), err, "no error expected")
+	require.NoError(s.T(), r.CreateRefreshToken(ctx, *stored), "no error expected")
+
+	got, err := r.GetRefreshToken(ctx, token.Hash())
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), stored.ID, got.ID, "expected same refresh token IDs")
+	require.Equal(s.T(), claims.UserID, got.UserID, "expected same user IDs")
+	require.Equal(s.T(), claims.SessionID, got.SessionID, "expected same session IDs")
+	require.False(s.T(), got.Revoked(), "expected active refresh token")
+
+	now := time.Now().UTC()
+	require.NoError(s.T(), r.RevokeRefreshToken(ctx, stored.ID, now), "no error expected")
+	err = r.RevokeRefreshToken(ctx, stored.ID, now)
+	require.ErrorIs(s.T(), err, entities.ErrUserRefreshNotFound, "expected revoked refresh token not found")
+	got, err = r.GetRefreshToken(ctx, token.Hash())
+	require.NoError(s.T(), err, "no error expected")
+	require.True(s.T(), got.Revoked(), "expected revoked refresh token")
+}
+
 func must[T any](t *testing.T, fn func() (T, error)) T {
 	v, err := fn()
 	require.NoError(t, err, "must not return error")
 	return v
 }
+
+func (s *UserTestSuit) TestUserOTP() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	r := repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter)
+	user := must(s.T(), func() (*entities.User, error) {
+		return entities.NewUser(entities.HashCreds{
+			Login:    "testOTPUser",
+			PassHash: []byte("hash"),
+		})
+	})
+	require.NoError(s.T(), r.Create(ctx, *user), "no error expected")
+
+	otp := entities.UserOTP{Secret: "JBSWY3DPEHPK3PXP", Enabled: true, LastStep: 10}
+	require.NoError(s.T(), r.UpdateOTP(ctx, user.ID, otp), "no error expected")
+	got, err := r.GetByID(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), otp, got.OTP, "expected same otp state")
+
+	require.ErrorIs(s.T(), r.UseOTPStep(ctx, user.ID, 10), entities.ErrUserOTPInvalid, "expected used step rejected")
+	require.NoError(s.T(), r.UseOTPStep(ctx, user.ID, 11), "no error expected")
+
+	now := time.Now().UTC()
+	lockedUntil := now.Add(time.Minute)
+	require.NoError(s.T(), r.TakeOTPAttempt(ctx, user.ID, 2, lockedUntil, now), "no error expected")
+	require.NoError(s.T(), r.ResetOTPAttempts(ctx, user.ID), "no error expected")
+	require.NoError(s.T(), r.TakeOTPAttempt(ctx, user.ID, 2, lockedUntil, now), "no error expected")
+	require.NoError(s.T(), r.TakeOTPAttempt(ctx, user.ID, 2, lockedUntil, now), "expected last attempt taken")
+	err = r.TakeOTPAttempt(ctx, user.ID, 2, lockedUntil, now)
+	require.ErrorIs(s.T(), err, entities.ErrUserOTPLocked, "expected user locked out")
+	require.NoError(s.T(), r.TakeOTPAttempt(ctx, user.ID, 2, lockedUntil, lockedUntil), "expected lockout expired")
+
+	codes, stored, err := entities.NewRecoveryCodes(user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.NoError(s.T(), r.ReplaceRecoveryCodes(ctx, user.ID, stored), "no error expected")
+	hash := entities.RecoveryCodeHash(codes[0])
+	require.NoError(s.T(), r.UseRecoveryCode(ctx, user.ID, hash, time.Now().UTC()), "no error expected")
+	err = r.UseRecoveryCode(ctx, user.ID, hash, time.Now().UTC())
+	require.ErrorIs(s.T(), err, entities.ErrUserRecoveryCodeNotFound, "expected used code rejected")
+
+	require.NoError(s.T(), r.ReplaceRecoveryCodes(ctx, user.ID, nil), "no error expected")
+	err = r.UseRecoveryCode(ctx, user.ID, entities.RecoveryCodeHash(codes[1]), time.Now().UTC())
+	require.ErrorIs(s.T(), err, entities.ErrUserRecoveryCodeNotFound, "expected codes dropped")
+}
+
+func (s *UserTestSuit) TestUserAccount() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	r := repo.NewUserRepo(s.db, trmsqlx.DefaultCtxGetter)
+	entryRepo := repo.NewEntryRepo(s.db, trmsqlx.DefaultCtxGetter)
+	user := must(s.T(), func() (*entities.User, error) {
+		return entities.NewUser(entities.HashCreds{
+			Login:    "testAccountUser",
+			PassHash: []byte("hash"),
+		})
+	})
+	require.NoError(s.T(), r.Create(ctx, *user), "no error expected")
+
+	require.NoError(s.T(), r.UpdatePass(ctx, user.ID, []byte("new_hash")), "no error expected")
+	got, err := r.GetByID(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), "new_hash", string(got.PassHash), "expected password updated")
+
+	check, err := r.SetVaultCheck(ctx, user.ID, []byte("check1"))
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), []byte("check1"), check, "expected vault check set")
+	check, err = r.SetVaultCheck(ctx, user.ID, []byte("check2"))
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), []byte("check1"), check, "expected vault check set once")
+	got, err = r.GetByID(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), []byte("check1"), got.VaultCheck, "expected vault check stored")
+
+	require.NoError(s.T(), r.SetVaultKey(ctx, user.ID, []byte("key1")), "no error expected")
+	got, err = r.GetByID(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Equal(s.T(), []byte("key1"), got.VaultKey, "expected vault key stored")
+	require.NoError(s.T(), r.SetVaultKey(ctx, user.ID, nil), "no error expected")
+	got, err = r.GetByID(ctx, user.ID)
+	require.NoError(s.T(), err, "no error expected")
+	require.Empty(s.T(), got.VaultKey, "expected vault key dropped")
+	require.ErrorIs(s.T(), r.SetVaultKey(ctx, uuid.New(), []byte("key1")), entities.ErrUserNotFound)
+
+	session := entities.NewSession(user.ID, entities.Device{Name: "laptop"})
+	require.NoError(s.T(), repo.NewSessionRepo(s.db, trmsqlx.DefaultCtxGetter).Create(ctx, *session), "no error expected")
+	_, stored, err := entities.NewRefreshToken(entities.TokenClaims{UserID: user.ID, SessionID: session.ID}, time.Hour)
+	require.NoError(s.T(), err, "no error expected")
+	require.NoError(s.T(), r.CreateRefreshToken(ctx, *stored), "no error expected")
+	entry, err := entities.NewEntry("key1", user.ID, core.EntryTypeNote, []byte("test_data_1"))
+	require.NoError(s.T(), err, "no error expected")
+	require.NoError(s.T(), entryRepo.Create(ctx, entry), "no error expected")
+
+	require.NoError(s.T(), r.Delete(ctx, user.ID), "no error expected")
+	_, err = r.GetByID(ctx, user.ID)
+	require.ErrorIs(s.T(), err, entities.ErrUserNotFound, "expected user deleted")
+	_, err = entryRepo.Get(ctx, user.ID, entry.ID)
+	require.ErrorIs(s.T(), err, entities.ErrEntryNotFound, "expected entries deleted")
+	_, err = r.GetRefreshToken(ctx, stored.Hash)
+	require.ErrorIs(s.T(), err, entities.ErrUserRefreshNotFound, "expected refresh tokens deleted")
+	require.ErrorIs(s.T(), r.Delete(ctx, user.ID), entities.ErrUserNotFound, "expected deleted user not found")
+}
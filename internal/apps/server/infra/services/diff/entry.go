@@ -37,6 +37,13 @@ func (e Entry) GetDiff(
 	}
 
 	for _, s := range server {
+		// entry deleted on the server, tombstone removes it from client
+		if s.Deleted {
+			if _, ok := clientMap[s.ID]; ok {
+				deleteIDs = append(deleteIDs, s.ID)
+			}
+			continue
+		}
 		// server and client have the same entry
 		if c, ok := clientMap[s.ID]; ok {
 			// but different version
@@ -50,7 +57,11 @@ func (e Entry) GetDiff(
 	}
 
 	for _, c := range clientVersions {
-		// client have deleted entry
+		// client entry is not pushed yet
+		if c.Version == 0 {
+			continue
+		}
+		// client have purged entry
 		if _, ok := serverMap[c.ID]; !ok {
 			deleteIDs = append(deleteIDs, c.ID)
 		}
@@ -57,6 +57,30 @@ func TestEntry_GetDiff(t *testing.T) {
 			wantUpdateIDs: []uuid.UUID{uuid1},
 			wantDeleteIDs: nil,
 		},
+		{
+			name:          "deleted on server",
+			server:        []core.EntryVersion{{ID: uuid1, Version: 2, Deleted: true}},
+			client:        []core.EntryVersion{{ID: uuid1, Version: 1}},
+			wantCreateIDs: nil,
+			wantUpdateIDs: nil,
+			wantDeleteIDs: []uuid.UUID{uuid1},
+		},
+		{
+			name:          "deleted on server before client sync",
+			server:        []core.EntryVersion{{ID: uuid1, Version: 2, Deleted: true}},
+			client:        nil,
+			wantCreateIDs: nil,
+			wantUpdateIDs: nil,
+			wantDeleteIDs: nil,
+		},
+		{
+			name:          "not pushed by client",
+			server:        nil,
+			client:        []core.EntryVersion{{ID: uuid1, Version: 0}},
+			wantCreateIDs: nil,
+			wantUpdateIDs: nil,
+			wantDeleteIDs: nil,
+		},
 		{
 			name: "mixed changes",
 			server: []core.EntryVersion{
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/google/uuid"
+	"sync"
+)
+
+var _ usecases.EntryNotifier = (*Hub)(nil)
+
+const watcherBufferSize = 16
+
+// Hub delivers entry changes to the watchers of the same user.
+// Delivery is best-effort: watchers pull the actual diff on notification,
+// so a change is dropped when the watcher buffer is full.
+type (
+	Hub struct {
+		mu       sync.RWMutex
+		watchers map[uuid.UUID]map[*watcher]struct{}
+	}
+	watcher struct {
+		ch chan entities.EntryChange
+	}
+)
+
+func NewHub() *Hub {
+	return &Hub{
+		watchers: make(map[uuid.UUID]map[*watcher]struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan entities.EntryChange, func()) {
+	w := &watcher{ch: make(chan entities.EntryChange, watcherBufferSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.watchers[userID]; !ok {
+		h.watchers[userID] = make(map[*watcher]struct{})
+	}
+	h.watchers[userID][w] = struct{}{}
+
+	once := sync.Once{}
+	return w.ch, func() {
+		once.Do(func() { h.unsubscribe(userID, w) })
+	}
+}
+
+func (h *Hub) Notify(userID uuid.UUID, change entities.EntryChange) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for w := range h.watchers[userID] {
+		select {
+		case w.ch <- change:
+		default:
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(userID uuid.UUID, w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.watchers[userID], w)
+	if len(h.watchers[userID]) == 0 {
+		delete(h.watchers, userID)
+	}
+	close(w.ch)
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHub(t *testing.T) {
+	var (
+		hub     = NewHub()
+		userID1 = uuid.New()
+		userID2 = uuid.New()
+		change  = entities.EntryChange{ID: uuid.New(), Version: 1}
+	)
+
+	ch1, unsubscribe1 := hub.Subscribe(userID1)
+	ch2, unsubscribe2 := hub.Subscribe(userID1)
+	ch3, unsubscribe3 := hub.Subscribe(userID2)
+	defer unsubscribe2()
+	defer unsubscribe3()
+
+	hub.Notify(userID1, change)
+	require.Equal(t, change, <-ch1)
+	require.Equal(t, change, <-ch2)
+	require.Empty(t, ch3, "other user should not be notified")
+
+	// full buffer does not block
+	for i := 0; i < watcherBufferSize+1; i++ {
+		hub.Notify(userID2, change)
+	}
+	require.Len(t, ch3, watcherBufferSize)
+
+	unsubscribe1()
+	unsubscribe1()
+	_, ok := <-ch1
+	require.False(t, ok, "channel should be closed after unsubscribe")
+	hub.Notify(userID1, change)
+	require.Equal(t, change, <-ch2)
+}
@@ -18,28 +18,32 @@ var (
 
 type (
 	JWTTokener struct {
-		secret  []byte
-		expires time.Duration
+		secret         []byte
+		expires        time.Duration
+		refreshExpires time.Duration
 	}
 	Claims struct {
 		jwt.RegisteredClaims
-		UserID string `json:"user_id"`
+		UserID    string `json:"user_id"`
+		SessionID string `json:"session_id"`
 	}
 )
 
-func NewJWT(secret []byte, expires time.Duration) JWTTokener {
+func NewJWT(secret []byte, expires time.Duration, refreshExpires time.Duration) JWTTokener {
 	return JWTTokener{
-		secret:  secret,
-		expires: expires,
+		secret:         secret,
+		expires:        expires,
+		refreshExpires: refreshExpires,
 	}
 }
 
-func (t JWTTokener) Create(id uuid.UUID) (entities.Token, error) {
+func (t JWTTokener) Create(claims entities.TokenClaims) (entities.Token, error) {
 	token := jwt.NewWithClaims(method, Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(t.expires)),
 		},
-		UserID: id.String(),
+		UserID:    claims.UserID.String(),
+		SessionID: claims.SessionID.String(),
 	})
 	tokenString, err := token.SignedString(t.secret)
 	if err != nil {
@@ -48,7 +52,11 @@ func (t JWTTokener) Create(id uuid.UUID) (entities.Token, error) {
 	return entities.Token(tokenString), nil
 }
 
-func (t JWTTokener) GetUserID(token entities.Token) (uuid.UUID, error) {
+func (t JWTTokener) CreateRefresh(claims entities.TokenClaims) (entities.Token, *entities.RefreshToken, error) {
+	return entities.NewRefreshToken(claims, t.refreshExpires)
+}
+
+func (t JWTTokener) GetClaims(token entities.Token) (entities.TokenClaims, error) {
 	c := new(Claims)
 
 	value, err := jwt.ParseWithClaims(string(token), c, func(token *jwt.Token) (any, error) {
@@ -59,26 +67,31 @@ func (t JWTTokener) GetUserID(token entities.Token) (uuid.UUID, error) {
 	})
 	switch {
 	case errors.Is(err, jwt.ErrTokenExpired):
-		return uuid.Nil, entities.ErrUserTokenExpired
+		return entities.TokenClaims{}, entities.ErrUserTokenExpired
 	case errors.Is(err, jwt.ErrTokenExpired):
-		return uuid.Nil, entities.ErrUserTokenExpired
+		return entities.TokenClaims{}, entities.ErrUserTokenExpired
 	case err != nil:
-		return uuid.Nil, fmt.Errorf("%w: %w", entities.ErrUserTokenInvalid, err)
+		return entities.TokenClaims{}, fmt.Errorf("%w: %w", entities.ErrUserTokenInvalid, err)
 	}
 	if !value.Valid {
-		return uuid.Nil, entities.ErrUserTokenInvalid
+		return entities.TokenClaims{}, entities.ErrUserTokenInvalid
 	}
 
 	expires := c.ExpiresAt.UTC()
 	now := time.Now().UTC()
 	if expires.Compare(now) == -1 {
-		return uuid.Nil, entities.ErrUserTokenExpired
+		return entities.TokenClaims{}, entities.ErrUserTokenExpired
 	}
 
-	id, err := uuid.Parse(c.UserID)
+	userID, err := uuid.Parse(c.UserID)
+	if err != nil {
+		return entities.TokenClaims{}, err
+	}
+	// tokens issued before sessions have no session ID
+	sessionID, err := uuid.Parse(c.SessionID)
 	if err != nil {
-		return uuid.Nil, err
+		return entities.TokenClaims{}, fmt.Errorf("%w: %w", entities.ErrUserTokenInvalid, err)
 	}
 
-	return id, nil
+	return entities.TokenClaims{UserID: userID, SessionID: sessionID}, nil
 }
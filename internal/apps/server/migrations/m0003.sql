@@ -0,0 +1,4 @@
+alter table if exists users
+    add column if not exists vault_salt bytea not null default decode(md5(random()::text || clock_timestamp()::text), 'hex');
+alter table if exists users
+    alter column vault_salt drop default;
\ No newline at end of file
@@ -0,0 +1,9 @@
+create table if not exists blobs
+(
+    id          uuid      not null,
+    user_id     uuid      not null references users,
+    chunk_index int4      not null,
+    data        bytea     not null,
+    created_at  timestamp not null,
+    primary key (id, user_id, chunk_index)
+);
\ No newline at end of file
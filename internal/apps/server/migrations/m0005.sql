@@ -0,0 +1,13 @@
+create table if not exists entry_versions
+(
+    id         uuid      not null,
+    user_id    uuid      not null references users,
+    key        text      not null,
+    type       text      not null,
+    meta       json,
+    data       bytea     not null,
+    version    int8      not null,
+    created_at timestamp not null,
+    updated_at timestamp not null,
+    primary key (id, user_id, version)
+);
\ No newline at end of file
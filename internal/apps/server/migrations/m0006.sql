@@ -0,0 +1,9 @@
+alter table if exists entries
+    add column if not exists deleted_at timestamp;
+alter table if exists entry_versions
+    add column if not exists deleted_at timestamp;
+
+alter table if exists entries
+    drop constraint if exists entries_key_unique;
+create unique index if not exists entries_key_unique_idx on entries (key, user_id) where deleted_at is null;
+create index if not exists entries_deleted_at_idx on entries (deleted_at) where deleted_at is not null;
\ No newline at end of file
@@ -0,0 +1,8 @@
+alter table if exists users
+    add column if not exists change_seq int8 not null default 0;
+alter table if exists users
+    add column if not exists purged_seq int8 not null default 0;
+alter table if exists entries
+    add column if not exists seq int8 not null default 0;
+
+create index if not exists entries_user_id_seq_idx on entries (user_id, seq);
@@ -0,0 +1,12 @@
+create table if not exists refresh_tokens
+(
+    id         uuid primary key,
+    user_id    uuid      not null references users,
+    token_hash text      not null,
+    expires_at timestamp not null,
+    created_at timestamp not null,
+    revoked_at timestamp
+);
+
+create unique index if not exists refresh_tokens_token_hash_idx on refresh_tokens (token_hash);
+create index if not exists refresh_tokens_user_id_idx on refresh_tokens (user_id);
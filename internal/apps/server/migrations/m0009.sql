@@ -0,0 +1,19 @@
+create table if not exists sessions
+(
+    id           uuid primary key,
+    user_id      uuid      not null references users,
+    device       text      not null,
+    ip           text      not null,
+    created_at   timestamp not null,
+    last_seen_at timestamp not null,
+    revoked_at   timestamp
+);
+
+create index if not exists sessions_user_id_idx on sessions (user_id);
+
+alter table if exists refresh_tokens
+    add column if not exists session_id uuid references sessions;
+-- refresh tokens issued before sessions can't be bound to a device, such clients sign in again
+delete from refresh_tokens where session_id is null;
+alter table if exists refresh_tokens
+    alter column session_id set not null;
@@ -0,0 +1,15 @@
+alter table if exists users
+    add column if not exists otp_secret    text    not null default '',
+    add column if not exists otp_enabled   boolean not null default false,
+    add column if not exists otp_last_step bigint  not null default 0;
+
+create table if not exists user_recovery_codes
+(
+    id         uuid primary key,
+    user_id    uuid      not null references users,
+    code_hash  text      not null,
+    created_at timestamp not null,
+    used_at    timestamp
+);
+
+create index if not exists user_recovery_codes_user_id_idx on user_recovery_codes (user_id);
@@ -0,0 +1,7 @@
+alter table if exists entries
+    add column if not exists folder text not null default '',
+    add column if not exists tags   json;
+
+alter table if exists entry_versions
+    add column if not exists folder text not null default '',
+    add column if not exists tags   json;
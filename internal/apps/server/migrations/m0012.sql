@@ -0,0 +1,11 @@
+create table if not exists entry_requests
+(
+    user_id    uuid      not null references users,
+    request_id text      not null,
+    entry_id   uuid      not null,
+    version    bigint    not null,
+    created_at timestamp not null,
+    primary key (user_id, request_id)
+);
+
+create index if not exists entry_requests_created_at_idx on entry_requests (created_at);
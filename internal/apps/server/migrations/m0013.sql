@@ -0,0 +1,4 @@
+-- blobs uploaded before they were stored under the entry ID can't be matched to their entries,
+-- they are never purged as orphans
+alter table blobs add column if not exists legacy boolean not null default true;
+alter table blobs alter column legacy set default false;
@@ -0,0 +1,2 @@
+-- the vault check is set by the first device signed in after the update
+alter table users add column if not exists vault_check bytea;
@@ -0,0 +1,3 @@
+alter table if exists users
+    add column if not exists otp_attempts     integer not null default 0,
+    add column if not exists otp_locked_until timestamp;
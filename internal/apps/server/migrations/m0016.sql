@@ -0,0 +1,2 @@
+-- the vault key is wrapped by the device that changed its master password
+alter table users add column if not exists vault_key bytea;
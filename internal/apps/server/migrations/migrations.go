@@ -11,6 +11,20 @@ var fs embed.FS
 var files = []file{
 	{Name: "m0001.sql", Title: "M0001: Users table", NoTx: false},
 	{Name: "m0002.sql", Title: "M0002: Entries table", NoTx: false},
+	{Name: "m0003.sql", Title: "M0003: Users vault salt", NoTx: false},
+	{Name: "m0004.sql", Title: "M0004: Blobs table", NoTx: false},
+	{Name: "m0005.sql", Title: "M0005: Entry versions table", NoTx: false},
+	{Name: "m0006.sql", Title: "M0006: Entries tombstones", NoTx: false},
+	{Name: "m0007.sql", Title: "M0007: Entries change sequence", NoTx: false},
+	{Name: "m0008.sql", Title: "M0008: Refresh tokens table", NoTx: false},
+	{Name: "m0009.sql", Title: "M0009: Sessions table", NoTx: false},
+	{Name: "m0010.sql", Title: "M0010: Users two-factor authentication", NoTx: false},
+	{Name: "m0011.sql", Title: "M0011: Entries folders and tags", NoTx: false},
+	{Name: "m0012.sql", Title: "M0012: Entry requests idempotency keys", NoTx: false},
+	{Name: "m0013.sql", Title: "M0013: Blobs keyed by entry ID", NoTx: false},
+	{Name: "m0014.sql", Title: "M0014: Users vault check", NoTx: false},
+	{Name: "m0015.sql", Title: "M0015: Users two-factor attempts", NoTx: false},
+	{Name: "m0016.sql", Title: "M0016: Users wrapped vault key", NoTx: false},
 }
 
 type file struct {
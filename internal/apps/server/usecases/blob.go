@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+type (
+	// BlobUC stores blobs under the ID of the binary entry that references them
+	BlobUC struct {
+		logger    *zap.Logger
+		blobRepo  BlobRepo
+		entryRepo EntryRepo
+		tx        trm.Manager
+	}
+	BlobRepo interface {
+		GetChunk(ctx context.Context, userID uuid.UUID, id uuid.UUID, index int) (entities.BlobChunk, error)
+		CreateChunk(ctx context.Context, chunk entities.BlobChunk) error
+		Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+		Move(ctx context.Context, userID uuid.UUID, from uuid.UUID, to uuid.UUID) error
+		PurgeOrphans(ctx context.Context, createdBefore time.Time) (int64, error)
+	}
+)
+
+func NewBlobUC(
+	logger *zap.Logger,
+	blobRepo BlobRepo,
+	entryRepo EntryRepo,
+	tx trm.Manager,
+) *BlobUC {
+	return &BlobUC{
+		logger:    logger,
+		blobRepo:  blobRepo,
+		entryRepo: entryRepo,
+		tx:        tx,
+	}
+}
+
+// Upload stages the received chunks under a temporary ID without holding a transaction,
+// the staged blob replaces the current one only when it's fully received.
+func (uc *BlobUC) Upload(
+	ctx context.Context,
+	request entities.UploadBlobRequest,
+) (response entities.UploadBlobResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("upload_blob: invalid request: %w", err)
+	}
+	userID := request.UserID
+	id := request.ID
+	staged := uuid.New()
+
+	var size int64
+	if err = uc.checkEntry(ctx, request); err == nil {
+		size, err = uc.stage(ctx, userID, staged, request.Next)
+	}
+	if err == nil {
+		err = uc.tx.Do(ctx, func(ctx context.Context) error {
+			if err := uc.checkEntry(ctx, request); err != nil {
+				return err
+			}
+			if err := uc.blobRepo.Delete(ctx, userID, id); err != nil && !errors.Is(err, entities.ErrBlobNotFound) {
+				return fmt.Errorf("upload_blob: failed to delete previous blob from storage: %w", err)
+			}
+			if err := uc.blobRepo.Move(ctx, userID, staged, id); err != nil {
+				return fmt.Errorf("upload_blob: failed to move staged blob in storage: %w", err)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		// the staged blob left on failure is purged as orphan
+		if derr := uc.blobRepo.Delete(ctx, userID, staged); derr != nil && !errors.Is(derr, entities.ErrBlobNotFound) {
+			uc.logger.Warn("failed to delete staged blob",
+				zap.String("user_id", userID.String()),
+				zap.String("blob_id", staged.String()),
+				zap.Error(derr))
+		}
+		uc.logger.Error("failed to upload blob",
+			zap.String("user_id", userID.String()),
+			zap.String("blob_id", id.String()),
+			zap.Error(err))
+		return response, err
+	}
+	response.ID = id
+	response.Size = size
+
+	return response, nil
+}
+
+// checkEntry allows the blob of the binary entry only if it's uploaded along with the update of the entry version
+func (uc *BlobUC) checkEntry(ctx context.Context, request entities.UploadBlobRequest) error {
+	entry, err := uc.entryRepo.Get(ctx, request.UserID, request.ID)
+	switch {
+	// the blob is uploaded before the entry is pushed, so the missing entry is expected
+	case errors.Is(err, entities.ErrEntryNotFound) && request.Version == 0:
+		return nil
+	case errors.Is(err, entities.ErrEntryNotFound):
+		return fmt.Errorf("upload_blob: %w: %d", entities.ErrBlobVersionConflict, request.Version)
+	case err != nil:
+		return fmt.Errorf("upload_blob: failed to get blob entry from storage: %w", err)
+	case entry.Type != core.EntryTypeBinary || entry.Deleted():
+		return fmt.Errorf("upload_blob: %w: %s", entities.ErrBlobEntryInvalid, entry.Type)
+	case entry.Version != request.Version:
+		return fmt.Errorf("upload_blob: %w: %d", entities.ErrBlobVersionConflict, request.Version)
+	}
+	return nil
+}
+
+// stage saves the received chunks under the staged ID, the blob size is checked before every chunk is saved
+func (uc *BlobUC) stage(
+	ctx context.Context,
+	userID uuid.UUID,
+	staged uuid.UUID,
+	next func() ([]byte, error),
+) (size int64, err error) {
+	for index := 0; ; index++ {
+		data, err := next()
+		switch {
+		case errors.Is(err, io.EOF) && index == 0:
+			return 0, fmt.Errorf("upload_blob: %w", entities.ErrBlobChunkEmpty)
+		case errors.Is(err, io.EOF):
+			return size, nil
+		case err != nil:
+			return 0, fmt.Errorf("upload_blob: failed to receive chunk: %w", err)
+		}
+		if size += int64(len(data)); size > entities.BlobMaxSize {
+			return 0, fmt.Errorf("upload_blob: %w: %d", entities.ErrBlobSizeExceeded, size)
+		}
+		chunk, err := entities.NewBlobChunk(staged, userID, index, data)
+		if err != nil {
+			return 0, fmt.Errorf("upload_blob: invalid chunk: %w", err)
+		}
+		if err = uc.blobRepo.CreateChunk(ctx, *chunk); err != nil {
+			return 0, fmt.Errorf("upload_blob: failed to create chunk in storage: %w", err)
+		}
+	}
+}
+
+// PurgeOrphans removes blobs which entries weren't pushed within entities.BlobOrphanRetention
+func (uc *BlobUC) PurgeOrphans(ctx context.Context) (int64, error) {
+	purged, err := uc.blobRepo.PurgeOrphans(ctx, time.Now().UTC().Add(-entities.BlobOrphanRetention))
+	if err != nil {
+		uc.logger.Error("failed to purge orphan blobs", zap.Error(err))
+		return 0, fmt.Errorf("purge_blobs: %w", err)
+	}
+	return purged, nil
+}
+
+func (uc *BlobUC) Download(
+	ctx context.Context,
+	request entities.DownloadBlobRequest,
+) (err error) {
+	if err = request.Validate(); err != nil {
+		return fmt.Errorf("download_blob: invalid request: %w", err)
+	}
+	userID := request.UserID
+	id := request.ID
+
+	for index := 0; ; index++ {
+		chunk, err := uc.blobRepo.GetChunk(ctx, userID, id, index)
+		switch {
+		case errors.Is(err, entities.ErrBlobNotFound) && index == 0:
+			uc.logger.Debug("blob not found",
+				zap.String("user_id", userID.String()),
+				zap.String("blob_id", id.String()))
+			return fmt.Errorf("download_blob: %w", err)
+		case errors.Is(err, entities.ErrBlobNotFound):
+			return nil
+		case err != nil:
+			uc.logger.Error("failed to get blob chunk",
+				zap.String("user_id", userID.String()),
+				zap.String("blob_id", id.String()),
+				zap.Int("index", index),
+				zap.Error(err))
+			return fmt.Errorf("download_blob: failed to get chunk from storage: %w", err)
+		}
+		if err = request.Send(chunk.Data); err != nil {
+			return fmt.Errorf("download_blob: failed to send chunk: %w", err)
+		}
+	}
+}
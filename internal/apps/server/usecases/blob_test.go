@@ -0,0 +1,202 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/dlomanov/gophkeeper/internal/core"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBlobUC(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		userID    = uuid.New()
+		id        = uuid.New()
+		entryRepo = NewMockEntryRepo()
+		blobRepo  = NewMockBlobRepo(entryRepo)
+		sut       = usecases.NewBlobUC(
+			zaptest.NewLogger(t, zaptest.Level(zap.FatalLevel)),
+			blobRepo,
+			entryRepo,
+			NewMockTrmManager())
+	)
+	upload := func(id uuid.UUID, version int64, chunks ...[]byte) (entities.UploadBlobResponse, error) {
+		return sut.Upload(ctx, entities.UploadBlobRequest{
+			ID:      id,
+			UserID:  userID,
+			Version: version,
+			Next: func() ([]byte, error) {
+				if len(chunks) == 0 {
+					return nil, io.EOF
+				}
+				chunk := chunks[0]
+				chunks = chunks[1:]
+				return chunk, nil
+			},
+		})
+	}
+	download := func(id uuid.UUID) ([]byte, error) {
+		buf := bytes.Buffer{}
+		err := sut.Download(ctx, entities.DownloadBlobRequest{
+			ID:     id,
+			UserID: userID,
+			Send: func(chunk []byte) error {
+				_, err := buf.Write(chunk)
+				return err
+			},
+		})
+		return buf.Bytes(), err
+	}
+
+	_, err := upload(uuid.Nil, 0, []byte("chunk"))
+	require.ErrorIs(t, err, entities.ErrBlobIDInvalid)
+	_, err = upload(id, 0)
+	require.ErrorIs(t, err, entities.ErrBlobChunkEmpty)
+	_, err = upload(id, 0, make([]byte, entities.BlobChunkMaxSize+1))
+	require.ErrorIs(t, err, entities.ErrBlobChunkSizeExceeded)
+	_, err = download(id)
+	require.ErrorIs(t, err, entities.ErrBlobNotFound)
+
+	resp, err := upload(id, 0, []byte("chunk_1"), []byte("chunk_2"))
+	require.NoError(t, err)
+	require.Equal(t, id, resp.ID)
+	require.Equal(t, int64(14), resp.Size)
+	data, err := download(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("chunk_1chunk_2"), data)
+
+	_, err = upload(id, 0, []byte("chunk_3"))
+	require.NoError(t, err, "re-upload should replace blob")
+	data, err = download(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("chunk_3"), data)
+
+	note, err := entities.NewEntry("note", userID, core.EntryTypeNote, []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, entryRepo.Create(ctx, note))
+	_, err = upload(note.ID, note.Version, []byte("chunk"))
+	require.ErrorIs(t, err, entities.ErrBlobEntryInvalid, "blob should belong to binary entry")
+
+	chunk := make([]byte, entities.BlobChunkMaxSize)
+	chunks := make([][]byte, entities.BlobMaxSize/len(chunk)+1)
+	for i := range chunks {
+		chunks[i] = chunk
+	}
+	_, err = upload(id, 0, chunks...)
+	require.ErrorIs(t, err, entities.ErrBlobSizeExceeded)
+	data, err = download(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("chunk_3"), data, "failed upload should keep previous blob")
+	require.Len(t, blobRepo.storage, 1, "failed upload should leave no staged chunks")
+
+	binary, err := entities.NewEntry("binary", userID, core.EntryTypeBinary, []byte("descriptor"))
+	require.NoError(t, err)
+	require.NoError(t, entryRepo.Create(ctx, binary))
+	_, err = upload(binary.ID, binary.Version-1, []byte("chunk"))
+	require.ErrorIs(t, err, entities.ErrBlobVersionConflict, "blob of pushed entry should be replaced with entry update only")
+	_, err = upload(uuid.New(), 1, []byte("chunk"))
+	require.ErrorIs(t, err, entities.ErrBlobVersionConflict, "blob of missing entry should be uploaded without version")
+	_, err = upload(binary.ID, binary.Version, []byte("chunk_4"))
+	require.NoError(t, err)
+	data, err = download(binary.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("chunk_4"), data)
+	require.Len(t, blobRepo.storage, 2, "rejected uploads should leave no staged chunks")
+}
+
+func TestBlobUC_UploadInterrupted(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		userID    = uuid.New()
+		id        = uuid.New()
+		entryRepo = NewMockEntryRepo()
+		blobRepo  = NewMockBlobRepo(entryRepo)
+		sut       = usecases.NewBlobUC(
+			zaptest.NewLogger(t, zaptest.Level(zap.FatalLevel)),
+			blobRepo,
+			entryRepo,
+			NewMockTrmManager())
+	)
+	chunk, err := entities.NewBlobChunk(id, userID, 0, []byte("previous"))
+	require.NoError(t, err)
+	require.NoError(t, blobRepo.CreateChunk(ctx, *chunk))
+
+	sent := false
+	_, err = sut.Upload(ctx, entities.UploadBlobRequest{
+		ID:     id,
+		UserID: userID,
+		Next: func() ([]byte, error) {
+			if sent {
+				return nil, context.Canceled
+			}
+			sent = true
+			return []byte("chunk"), nil
+		},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	got, err := blobRepo.GetChunk(ctx, userID, id, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("previous"), got.Data, "interrupted upload should keep previous blob")
+	_, err = blobRepo.GetChunk(ctx, userID, id, 1)
+	require.ErrorIs(t, err, entities.ErrBlobNotFound)
+	require.Len(t, blobRepo.storage, 1, "interrupted upload should leave no staged chunks")
+}
+
+func TestBlobUC_PurgeOrphans(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		userID    = uuid.New()
+		entryRepo = NewMockEntryRepo()
+		blobRepo  = NewMockBlobRepo(entryRepo)
+		sut       = usecases.NewBlobUC(
+			zaptest.NewLogger(t, zaptest.Level(zap.FatalLevel)),
+			blobRepo,
+			entryRepo,
+			NewMockTrmManager())
+	)
+	upload := func(id uuid.UUID, version int64) {
+		sent := false
+		_, err := sut.Upload(ctx, entities.UploadBlobRequest{
+			ID:      id,
+			UserID:  userID,
+			Version: version,
+			Next: func() ([]byte, error) {
+				if sent {
+					return nil, io.EOF
+				}
+				sent = true
+				return []byte("chunk"), nil
+			},
+		})
+		require.NoError(t, err)
+	}
+	entry, err := entities.NewEntry("binary", userID, core.EntryTypeBinary, []byte("descriptor"))
+	require.NoError(t, err)
+	require.NoError(t, entryRepo.Create(ctx, entry))
+	orphanID := uuid.New()
+	upload(entry.ID, entry.Version)
+	upload(orphanID, 0)
+
+	purged, err := sut.PurgeOrphans(ctx)
+	require.NoError(t, err)
+	require.Zero(t, purged, "recent blobs should wait for their entries")
+
+	for _, chunks := range blobRepo.storage {
+		chunks[0].CreatedAt = chunks[0].CreatedAt.Add(-entities.BlobOrphanRetention - time.Minute)
+	}
+	purged, err = sut.PurgeOrphans(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged, "only orphan blob should be purged")
+	_, err = blobRepo.GetChunk(ctx, userID, entry.ID, 0)
+	require.NoError(t, err, "blob with entry should be kept")
+	_, err = blobRepo.GetChunk(ctx, userID, orphanID, 0)
+	require.ErrorIs(t, err, entities.ErrBlobNotFound)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"time"
 )
 
 type (
@@ -16,17 +17,26 @@ type (
 		logger      *zap.Logger
 		entryRepo   EntryRepo
 		entryDiffer EntryDiffer
-		encrypter   Encrypter
+		notifier    EntryNotifier
 		tx          trm.Manager
 	}
 	EntryRepo interface {
 		Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entities.Entry, error)
+		GetByKey(ctx context.Context, userID uuid.UUID, key string) (*entities.Entry, error)
 		GetAll(ctx context.Context, userID uuid.UUID) ([]entities.Entry, error)
 		GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]entities.Entry, error)
 		GetVersions(ctx context.Context, userID uuid.UUID) ([]core.EntryVersion, error)
+		GetChangesSince(ctx context.Context, userID uuid.UUID, seq int64, limit int) ([]entities.Entry, error)
+		GetChangeSeq(ctx context.Context, userID uuid.UUID) (entities.EntryChangeSeq, error)
+		GetHistory(ctx context.Context, userID uuid.UUID, id uuid.UUID) ([]entities.Entry, error)
+		GetHistoryEntry(ctx context.Context, userID uuid.UUID, id uuid.UUID, version int64) (*entities.Entry, error)
 		Create(ctx context.Context, entry *entities.Entry) error
 		Update(ctx context.Context, entry *entities.Entry) error
-		Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+		GetDeleted(ctx context.Context, userID uuid.UUID) ([]entities.Entry, error)
+		Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+		GetRequest(ctx context.Context, userID uuid.UUID, requestID string) (*entities.AppliedEntryRequest, error)
+		SaveRequest(ctx context.Context, request entities.AppliedEntryRequest) error
+		PurgeRequests(ctx context.Context, createdBefore time.Time) (int64, error)
 	}
 	EntryDiffer interface {
 		GetDiff(
@@ -40,9 +50,9 @@ type (
 			err error,
 		)
 	}
-	Encrypter interface {
-		Encrypt(data []byte) ([]byte, error)
-		Decrypt(data []byte) ([]byte, error)
+	EntryNotifier interface {
+		Subscribe(userID uuid.UUID) (<-chan entities.EntryChange, func())
+		Notify(userID uuid.UUID, change entities.EntryChange)
 	}
 )
 
@@ -50,14 +60,14 @@ func NewEntryUC(
 	logger *zap.Logger,
 	entryRepo EntryRepo,
 	entryDiffer EntryDiffer,
-	encrypter Encrypter,
+	notifier EntryNotifier,
 	tx trm.Manager,
 ) *EntryUC {
 	return &EntryUC{
 		logger:      logger,
 		entryRepo:   entryRepo,
 		entryDiffer: entryDiffer,
-		encrypter:   encrypter,
+		notifier:    notifier,
 		tx:          tx,
 	}
 }
@@ -73,6 +83,9 @@ func (uc *EntryUC) Get(
 	id := request.ID
 
 	entry, err := uc.entryRepo.Get(ctx, userID, id)
+	if err == nil && entry.Deleted() {
+		err = fmt.Errorf("get_entry: entry deleted: %w", entities.ErrEntryNotFound)
+	}
 	switch {
 	case errors.Is(err, entities.ErrEntryNotFound):
 		uc.logger.Debug("entry not found",
@@ -87,15 +100,6 @@ func (uc *EntryUC) Get(
 			zap.Error(err))
 		return response, err
 	}
-	decrypted, err := uc.encrypter.Decrypt(entry.Data)
-	if err != nil {
-		uc.logger.Error("failed to decrypt entry",
-			zap.String("user_id", userID.String()),
-			zap.String("entry_id", id.String()),
-			zap.Error(err))
-		return response, fmt.Errorf("get entry: failed to decrypt entry: %w", err)
-	}
-	entry.Data = decrypted
 	response.Entry = entry
 
 	return response, nil
@@ -117,16 +121,6 @@ func (uc *EntryUC) GetEntries(
 			zap.Error(err))
 		return response, err
 	}
-	for i := range entries {
-		decrypted, err := uc.encrypter.Decrypt(entries[i].Data)
-		if err != nil {
-			uc.logger.Error("failed to decrypt entry",
-				zap.String("user_id", userID.String()),
-				zap.Error(err))
-			return response, fmt.Errorf("get all entries: failed to decrypt entry: %w", err)
-		}
-		entries[i].Data = decrypted
-	}
 	response.Entries = entries
 
 	return response, nil
@@ -145,8 +139,14 @@ func (uc *EntryUC) GetEntriesDiff(
 		updateIDs []uuid.UUID
 		deleteIDs []uuid.UUID
 		entries   []entities.Entry
+		seq       entities.EntryChangeSeq
 	)
 	if err := uc.tx.Do(ctx, func(ctx context.Context) error {
+		// seq is read first, so changes made during the diff are fetched again by the cursor
+		seq, err = uc.entryRepo.GetChangeSeq(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get_entries_diff: failed to get change seq from storage: %w", err)
+		}
 		versions, err := uc.entryRepo.GetVersions(ctx, userID)
 		if err != nil {
 			return fmt.Errorf("get_entries_diff: failed to get versions from storage: %w", err)
@@ -169,102 +169,179 @@ func (uc *EntryUC) GetEntriesDiff(
 			zap.Error(err))
 		return response, err
 	}
-	for i := range entries {
-		decrypted, err := uc.encrypter.Decrypt(entries[i].Data)
-		if err != nil {
-			uc.logger.Error("failed to decrypt entry",
-				zap.String("user_id", userID.String()),
-				zap.Error(err))
-			return response, fmt.Errorf("get all entries: failed to decrypt entry: %w", err)
-		}
-		entries[i].Data = decrypted
-	}
 	response.CreateIDs = createIDs
 	response.UpdateIDs = updateIDs
 	response.DeleteIDs = deleteIDs
 	response.Entries = entries
+	response.Cursor = seq.Current
 
 	return response, nil
 }
 
+// GetChangesSince returns a page of entries changed after the cursor including tombstones,
+// Reset is set when the cursor can't be served and the client must use the full diff.
+func (uc *EntryUC) GetChangesSince(
+	ctx context.Context,
+	request entities.GetChangesSinceRequest,
+) (response entities.GetChangesSinceResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("get_changes_since: invalid request: %w", err)
+	}
+	var (
+		userID = request.UserID
+		limit  = request.Limit
+	)
+	if limit == 0 {
+		limit = entities.EntryChangesDefaultLimit
+	}
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		seq, err := uc.entryRepo.GetChangeSeq(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get_changes_since: failed to get change seq from storage: %w", err)
+		}
+		if request.Cursor < seq.Purged || request.Cursor > seq.Current {
+			response.Reset = true
+			return nil
+		}
+		entries, err := uc.entryRepo.GetChangesSince(ctx, userID, request.Cursor, limit+1)
+		if err != nil {
+			return fmt.Errorf("get_changes_since: failed to get changes from storage: %w", err)
+		}
+		if response.HasMore = len(entries) > limit; response.HasMore {
+			entries = entries[:limit]
+		}
+		response.Entries = entries
+		response.Cursor = request.Cursor
+		if len(entries) != 0 {
+			response.Cursor = entries[len(entries)-1].Seq
+		}
+		return nil
+	}); err != nil {
+		uc.logger.Error("failed to get changes",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		return response, err
+	}
+	return response, nil
+}
+
 func (uc *EntryUC) Create(
 	ctx context.Context,
 	request entities.CreateEntryRequest,
 ) (response entities.CreateEntryResponse, err error) {
+	response, replayed, err := uc.createEntry(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if !replayed {
+		uc.notifier.Notify(request.UserID, entities.EntryChange{ID: response.ID, Version: response.Version})
+	}
+	return response, nil
+}
+
+// createEntry creates the entry in the current transaction,
+// the replayed create of the existing entry returns it as created
+func (uc *EntryUC) createEntry(
+	ctx context.Context,
+	request entities.CreateEntryRequest,
+) (response entities.CreateEntryResponse, replayed bool, err error) {
 	if err = request.Validate(); err != nil {
-		return response, fmt.Errorf("create_entry: invalid request: %w", err)
+		return response, false, fmt.Errorf("create_entry: invalid request: %w", err)
 	}
 	userID := request.UserID
 
 	entry, err := entities.NewEntry(request.Key, userID, request.Type, request.Data)
 	if err != nil {
-		return response, fmt.Errorf("create_entry: failed to create_entry: %w", err)
+		return response, false, fmt.Errorf("create_entry: failed to create_entry: %w", err)
 	}
-	entry.Meta = request.Meta
-	encrypted, err := uc.encrypter.Encrypt(request.Data)
-	if err != nil {
-		uc.logger.Error("failed to encrypt entry",
-			zap.String("user_id", userID.String()),
-			zap.Error(err))
-		return response, fmt.Errorf("create_entry: failed to encrypt entry: %w", err)
+	if request.ID != uuid.Nil {
+		entry.ID = request.ID
 	}
-	entry.Data = encrypted
+	entry.Meta = request.Meta
+	entry.Folder = core.NormalizeFolder(request.Folder)
+	entry.Tags = core.NormalizeTags(request.Tags)
 	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if applied, err := uc.replay(ctx, userID, request.RequestID, request.ID); err != nil || applied != nil {
+			entry, replayed = applied, applied != nil
+			return err
+		}
 		err = uc.entryRepo.Create(ctx, entry)
-		switch {
-		case errors.Is(err, entities.ErrEntryExists):
-			conflictKey := uc.newConflictKey(entry.Key, entry.Version)
-			conflictEntry, err := entities.NewEntry(conflictKey, entry.UserID, entry.Type, entry.Data)
+		if !errors.Is(err, entities.ErrEntryExists) {
 			if err != nil {
-				return fmt.Errorf("create_entry: failed to request conflict entry: %w: %w", err, entities.ErrEntryExists)
+				return fmt.Errorf("create_entry: failed to request entry in repo: %w", err)
 			}
-			conflictEntry.Meta = request.Meta
-			conflictEntry.Data = encrypted
-			if err = uc.entryRepo.Create(ctx, conflictEntry); err != nil {
-				return fmt.Errorf("create_entry: failed to request conflict entry in repo: %w: %w", err, entities.ErrEntryExists)
-			}
-			entry = conflictEntry
+			return uc.remember(ctx, request.RequestID, entry)
+		}
+		existing, err := uc.entryRepo.GetByKey(ctx, userID, entry.Key)
+		switch {
+		// the ID belongs to an entry of another user or under another key,
+		// the owner isn't disclosed
+		case errors.Is(err, entities.ErrEntryNotFound):
+			return fmt.Errorf("create_entry: %w", entities.ErrEntryIDTaken)
 		case err != nil:
-			return fmt.Errorf("create_entry: failed to request entry in repo: %w", err)
+			return fmt.Errorf("create_entry: failed to get conflict entry from storage: %w", err)
+		// the create is retried under another request ID, the lost response isn't replayed
+		// since the client could change the entry after the first try
+		case existing.ID == entry.ID:
+			if replayed, response.Conflict, err = uc.recreateEntry(ctx, existing, entry); err != nil {
+				return err
+			}
+			entry = existing
+			return uc.remember(ctx, request.RequestID, entry)
+		// the key is taken by another entry, the client resolves the conflict
+		default:
+			entry.ID = uuid.Nil
+			response.Conflict = &entities.EntryConflict{Server: existing, Client: entry}
+			return fmt.Errorf("create_entry: %w", entities.ErrEntryKeyConflict)
 		}
-		return nil
 	}); err != nil {
-		uc.logger.Error("failed to request entry",
+		uc.logError("failed to create entry", err,
 			zap.String("user_id", userID.String()),
-			zap.String("key", request.Key),
-			zap.Error(err))
-		return response, err
+			zap.String("key", request.Key))
+		return response, false, err
 	}
 	response.ID = entry.ID
 	response.Version = entry.Version
 
-	return response, nil
+	return response, replayed, nil
 }
 
 func (uc *EntryUC) Update(
 	ctx context.Context,
 	request entities.UpdateEntryRequest,
 ) (response entities.UpdateEntryResponse, err error) {
+	response, replayed, err := uc.updateEntry(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if !replayed {
+		uc.notifier.Notify(request.UserID, entities.EntryChange{ID: response.ID, Version: response.Version})
+	}
+	return response, nil
+}
+
+func (uc *EntryUC) updateEntry(
+	ctx context.Context,
+	request entities.UpdateEntryRequest,
+) (response entities.UpdateEntryResponse, replayed bool, err error) {
 	if err = request.Validate(); err != nil {
-		return response, fmt.Errorf("update entry: invalid request: %w", err)
+		return response, false, fmt.Errorf("update entry: invalid request: %w", err)
 	}
 	userID := request.UserID
 	id := request.ID
 	version := request.Version
 
-	encrypted, err := uc.encrypter.Encrypt(request.Data)
-	if err != nil {
-		uc.logger.Error("failed to encrypt entry",
-			zap.String("user_id", userID.String()),
-			zap.Error(err))
-		return response, fmt.Errorf("update_entry: failed to encrypt entry: %w", err)
-	}
 	var entry *entities.Entry
 	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
 		var err error
+		if entry, err = uc.replay(ctx, userID, request.RequestID, id); err != nil || entry != nil {
+			replayed = entry != nil
+			return err
+		}
 		entry, err = uc.entryRepo.Get(ctx, userID, id)
 		switch {
-		case errors.Is(err, entities.ErrEntryNotFound):
+		case errors.Is(err, entities.ErrEntryNotFound),
+			err == nil && entry.Deleted():
 			return fmt.Errorf("update_entry: entry not found: %w", entities.ErrEntryNotFound)
 		case err != nil:
 			return fmt.Errorf("update_entry: failed to get entry from storage: %w", err)
@@ -272,51 +349,57 @@ func (uc *EntryUC) Update(
 		err = entry.Update(
 			version,
 			entities.UpdateEntryMeta(request.Meta),
+			entities.UpdateEntryFolder(request.Folder),
+			entities.UpdateEntryTags(request.Tags),
 			entities.UpdateEntryData(request.Data))
 		switch {
-		// handle version conflict by saving conflict version of entry
+		// the entry is changed since the client version, the client resolves the conflict
 		case errors.Is(err, entities.ErrEntryVersionConflict):
-			conflictKey := uc.newConflictKey(entry.Key, request.Version)
-			conflictEntry, err := entities.NewEntry(conflictKey, entry.UserID, entry.Type, entry.Data)
-			if err != nil {
-				return fmt.Errorf("update_entry: failed to request conflict entry: %w", err)
+			if response.Conflict, err = uc.newConflict(ctx, entry, request); err != nil {
+				return err
 			}
-			conflictEntry.Meta = request.Meta
-			conflictEntry.Data = encrypted
-			if err = uc.entryRepo.Create(ctx, conflictEntry); err != nil {
-				return fmt.Errorf("update_entry: failed to request conflict entry in storage: %w", err)
-			}
-			entry = conflictEntry
-			return nil
+			return fmt.Errorf("update_entry: %w", entities.ErrEntryVersionConflict)
 		case errors.Is(err, entities.ErrEntryVersionInvalid):
 			return fmt.Errorf("update_entry: invalid entry version: %w", err)
 		case err != nil:
 			return fmt.Errorf("update_entry: failed to update entry: %w", err)
 		}
-		entry.Data = encrypted
 		if err := uc.entryRepo.Update(ctx, entry); err != nil {
 			return fmt.Errorf("update_entry: failed to update entry in storage: %w", err)
 		}
-		return nil
+		return uc.remember(ctx, request.RequestID, entry)
 	}); err != nil {
-		uc.logger.Error("failed to update entry in transaction",
+		uc.logError("failed to update entry", err,
 			zap.String("user_id", userID.String()),
-			zap.String("entry_id", id.String()),
-			zap.Error(err))
-		return response, err
+			zap.String("entry_id", id.String()))
+		return response, false, err
 	}
 	response.ID = entry.ID
 	response.Version = entry.Version
 
-	return response, nil
+	return response, replayed, nil
 }
 
 func (uc *EntryUC) Delete(
 	ctx context.Context,
 	request entities.DeleteEntryRequest,
 ) (response entities.DeleteEntryResponse, err error) {
+	response, replayed, err := uc.deleteEntry(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if !replayed {
+		uc.notifier.Notify(request.UserID, entities.EntryChange{ID: response.ID, Version: response.Version})
+	}
+	return response, nil
+}
+
+func (uc *EntryUC) deleteEntry(
+	ctx context.Context,
+	request entities.DeleteEntryRequest,
+) (response entities.DeleteEntryResponse, replayed bool, err error) {
 	if err = request.Validate(); err != nil {
-		return response, fmt.Errorf("delete entry: invalid request: %w", err)
+		return response, false, fmt.Errorf("delete entry: invalid request: %w", err)
 	}
 	userID := request.UserID
 	id := request.ID
@@ -324,34 +407,344 @@ func (uc *EntryUC) Delete(
 	var entry *entities.Entry
 	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
 		var err error
+		if entry, err = uc.replay(ctx, userID, request.RequestID, id); err != nil || entry != nil {
+			replayed = entry != nil
+			return err
+		}
 		entry, err = uc.entryRepo.Get(ctx, userID, id)
 		switch {
-		case errors.Is(err, entities.ErrEntryNotFound):
+		case errors.Is(err, entities.ErrEntryNotFound),
+			err == nil && entry.Deleted():
 			return fmt.Errorf("delete_entry: entry not found: %w", entities.ErrEntryNotFound)
 		case err != nil:
 			return fmt.Errorf("delete_entry: failed to get entry from storage: %w", err)
 		}
-		err = uc.entryRepo.Delete(ctx, userID, id)
+		// tombstone is kept until purge so other devices could sync the deletion
+		entry.Delete()
+		if err = uc.entryRepo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("delete_entry: failed to delete entry in storage: %w", err)
+		}
+		return uc.remember(ctx, request.RequestID, entry)
+	}); err != nil {
+		uc.logger.Error("failed to delete entry from storage",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", id.String()),
+			zap.Error(err))
+		return response, false, err
+	}
+	response.ID = entry.ID
+	response.Version = entry.Version
+
+	return response, replayed, nil
+}
+
+func (uc *EntryUC) GetHistory(
+	ctx context.Context,
+	request entities.GetEntryHistoryRequest,
+) (response entities.GetEntryHistoryResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("get_entry_history: invalid request: %w", err)
+	}
+	entries, err := uc.entryRepo.GetHistory(ctx, request.UserID, request.ID)
+	if err != nil {
+		uc.logger.Error("failed to get entry history",
+			zap.String("user_id", request.UserID.String()),
+			zap.String("entry_id", request.ID.String()),
+			zap.Error(err))
+		return response, fmt.Errorf("get_entry_history: failed to get entry history: %w", err)
+	}
+	response.Entries = entries
+	return response, nil
+}
+
+// Restore saves the entry version from the history as a new version of the entry.
+// The deleted entry is undeleted.
+func (uc *EntryUC) Restore(
+	ctx context.Context,
+	request entities.RestoreEntryRequest,
+) (response entities.RestoreEntryResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("restore_entry: invalid request: %w", err)
+	}
+	userID := request.UserID
+	id := request.ID
+
+	var (
+		entry    *entities.Entry
+		replayed bool
+	)
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if applied, err := uc.replay(ctx, userID, request.RequestID, id); err != nil || applied != nil {
+			entry, replayed = applied, applied != nil
+			return err
+		}
+		restored, err := uc.entryRepo.GetHistoryEntry(ctx, userID, id, request.Version)
+		switch {
+		case errors.Is(err, entities.ErrEntryHistoryNotFound):
+			return fmt.Errorf("restore_entry: %w", err)
+		case err != nil:
+			return fmt.Errorf("restore_entry: failed to get entry version from storage: %w", err)
+		}
+		entry, err = uc.entryRepo.Get(ctx, userID, id)
 		switch {
 		case errors.Is(err, entities.ErrEntryNotFound):
-			return fmt.Errorf("delete_entry: entry not found: %w", entities.ErrEntryNotFound)
+			return fmt.Errorf("restore_entry: entry purged: %w", err)
 		case err != nil:
-			return fmt.Errorf("delete_entry: failed to delete entry from storage: %w", err)
+			return fmt.Errorf("restore_entry: failed to get entry from storage: %w", err)
 		}
-		return nil
+		if err = entry.Update(
+			entry.Version,
+			entities.UpdateEntryMeta(restored.Meta),
+			entities.UpdateEntryFolder(restored.Folder),
+			entities.UpdateEntryTags(restored.Tags),
+			entities.UpdateEntryData(restored.Data),
+			entities.UndeleteEntry()); err != nil {
+			return fmt.Errorf("restore_entry: failed to update entry: %w", err)
+		}
+		if err = uc.entryRepo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("restore_entry: failed to update entry in storage: %w", err)
+		}
+		return uc.remember(ctx, request.RequestID, entry)
 	}); err != nil {
-		uc.logger.Error("failed to delete entry from storage",
+		uc.logger.Error("failed to restore entry",
 			zap.String("user_id", userID.String()),
 			zap.String("entry_id", id.String()),
+			zap.Int64("version", request.Version),
 			zap.Error(err))
 		return response, err
 	}
 	response.ID = entry.ID
 	response.Version = entry.Version
+	if !replayed {
+		uc.notifier.Notify(userID, entities.EntryChange{ID: entry.ID, Version: entry.Version})
+	}
 
 	return response, nil
 }
 
-func (uc *EntryUC) newConflictKey(key string, version int64) string {
-	return fmt.Sprintf("%s_conflict_%d_%s", key, version, uuid.New().String())
+func (uc *EntryUC) GetTrash(
+	ctx context.Context,
+	request entities.GetTrashRequest,
+) (response entities.GetTrashResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("get_trash: invalid request: %w", err)
+	}
+	entries, err := uc.entryRepo.GetDeleted(ctx, request.UserID)
+	if err != nil {
+		uc.logger.Error("failed to get deleted entries",
+			zap.String("user_id", request.UserID.String()),
+			zap.Error(err))
+		return response, fmt.Errorf("get_trash: failed to get deleted entries: %w", err)
+	}
+	response.Entries = entries
+	return response, nil
+}
+
+func (uc *EntryUC) Undelete(
+	ctx context.Context,
+	request entities.UndeleteEntryRequest,
+) (response entities.UndeleteEntryResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("undelete_entry: invalid request: %w", err)
+	}
+	userID := request.UserID
+	id := request.ID
+
+	var (
+		entry    *entities.Entry
+		replayed bool
+	)
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if entry, err = uc.replay(ctx, userID, request.RequestID, id); err != nil || entry != nil {
+			replayed = entry != nil
+			return err
+		}
+		entry, err = uc.entryRepo.Get(ctx, userID, id)
+		switch {
+		case errors.Is(err, entities.ErrEntryNotFound),
+			err == nil && !entry.Deleted():
+			return fmt.Errorf("undelete_entry: deleted entry not found: %w", entities.ErrEntryNotFound)
+		case err != nil:
+			return fmt.Errorf("undelete_entry: failed to get entry from storage: %w", err)
+		}
+		entry.Undelete()
+		err = uc.entryRepo.Update(ctx, entry)
+		switch {
+		case errors.Is(err, entities.ErrEntryExists):
+			return fmt.Errorf("undelete_entry: entry key is taken: %w", err)
+		case err != nil:
+			return fmt.Errorf("undelete_entry: failed to update entry in storage: %w", err)
+		}
+		return uc.remember(ctx, request.RequestID, entry)
+	}); err != nil {
+		uc.logger.Error("failed to undelete entry",
+			zap.String("user_id", userID.String()),
+			zap.String("entry_id", id.String()),
+			zap.Error(err))
+		return response, err
+	}
+	response.ID = entry.ID
+	response.Version = entry.Version
+	if !replayed {
+		uc.notifier.Notify(userID, entities.EntryChange{ID: entry.ID, Version: entry.Version})
+	}
+
+	return response, nil
+}
+
+// Purge permanently removes entries deleted more than retention ago
+func (uc *EntryUC) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged int64
+	if err := uc.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = uc.entryRepo.Purge(ctx, time.Now().UTC().Add(-retention))
+		return err
+	}); err != nil {
+		uc.logger.Error("failed to purge deleted entries", zap.Error(err))
+		return 0, fmt.Errorf("purge_entries: %w", err)
+	}
+	return purged, nil
+}
+
+// PurgeRequests removes request IDs applied more than entities.EntryRequestRetention ago
+func (uc *EntryUC) PurgeRequests(ctx context.Context) (int64, error) {
+	purged, err := uc.entryRepo.PurgeRequests(ctx, time.Now().UTC().Add(-entities.EntryRequestRetention))
+	if err != nil {
+		uc.logger.Error("failed to purge entry requests", zap.Error(err))
+		return 0, fmt.Errorf("purge_entry_requests: %w", err)
+	}
+	return purged, nil
+}
+
+func (uc *EntryUC) Watch(
+	ctx context.Context,
+	request entities.WatchEntriesRequest,
+) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("watch_entries: invalid request: %w", err)
+	}
+	changes, unsubscribe := uc.notifier.Subscribe(request.UserID)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change := <-changes:
+			if err := request.Send(change); err != nil {
+				uc.logger.Debug("failed to send entry change",
+					zap.String("user_id", request.UserID.String()),
+					zap.Error(err))
+				return fmt.Errorf("watch_entries: failed to send entry change: %w", err)
+			}
+		}
+	}
+}
+
+// recreateEntry applies the retried create to the entry created by the first try.
+// The same content is the replay, the entry unchanged since the creation takes the client content,
+// otherwise the client resolves the conflict.
+func (uc *EntryUC) recreateEntry(
+	ctx context.Context,
+	existing *entities.Entry,
+	entry *entities.Entry,
+) (replayed bool, conflict *entities.EntryConflict, err error) {
+	switch {
+	case existing.SameContent(entry):
+		return true, nil, nil
+	case existing.Version != 1 || existing.Type != entry.Type || existing.Deleted():
+		conflict = &entities.EntryConflict{Server: existing, Client: entry}
+		return false, conflict, fmt.Errorf("create_entry: %w", entities.ErrEntryKeyConflict)
+	}
+	if err = existing.Update(
+		existing.Version,
+		entities.UpdateEntryMeta(entry.Meta),
+		entities.UpdateEntryFolder(entry.Folder),
+		entities.UpdateEntryTags(entry.Tags),
+		entities.UpdateEntryData(entry.Data)); err != nil {
+		return false, nil, fmt.Errorf("create_entry: failed to update created entry: %w", err)
+	}
+	if err = uc.entryRepo.Update(ctx, existing); err != nil {
+		return false, nil, fmt.Errorf("create_entry: failed to update created entry in storage: %w", err)
+	}
+	return false, nil, nil
+}
+
+// newConflict returns both versions of the entry and the version the client change is based on
+func (uc *EntryUC) newConflict(
+	ctx context.Context,
+	entry *entities.Entry,
+	request entities.UpdateEntryRequest,
+) (*entities.EntryConflict, error) {
+	client := *entry
+	client.Meta = request.Meta
+	client.Folder = core.NormalizeFolder(request.Folder)
+	client.Tags = core.NormalizeTags(request.Tags)
+	client.Data = request.Data
+	client.Version = request.Version
+	base, err := uc.entryRepo.GetHistoryEntry(ctx, entry.UserID, entry.ID, request.Version)
+	switch {
+	case errors.Is(err, entities.ErrEntryHistoryNotFound):
+		base = nil
+	case err != nil:
+		return nil, fmt.Errorf("update_entry: failed to get conflict base entry from storage: %w", err)
+	}
+	return &entities.EntryConflict{
+		Server: entry,
+		Client: &client,
+		Base:   base,
+	}, nil
+}
+
+// replay returns the entry ID and version of the request applied before,
+// nil if the request isn't applied yet or has no ID
+func (uc *EntryUC) replay(
+	ctx context.Context,
+	userID uuid.UUID,
+	requestID string,
+	entryID uuid.UUID,
+) (*entities.Entry, error) {
+	if requestID == "" {
+		return nil, nil
+	}
+	applied, err := uc.entryRepo.GetRequest(ctx, userID, requestID)
+	switch {
+	case errors.Is(err, entities.ErrEntryRequestNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get applied request from storage: %w", err)
+	case entryID != uuid.Nil && applied.EntryID != entryID:
+		return nil, fmt.Errorf("%w: %s", entities.ErrEntryRequestIDReused, requestID)
+	}
+	return &entities.Entry{ID: applied.EntryID, UserID: userID, Version: applied.Version}, nil
+}
+
+// remember saves the applied request, so its retry is replayed
+func (uc *EntryUC) remember(ctx context.Context, requestID string, entry *entities.Entry) error {
+	if requestID == "" {
+		return nil
+	}
+	if err := uc.entryRepo.SaveRequest(ctx, entities.AppliedEntryRequest{
+		UserID:    entry.UserID,
+		RequestID: requestID,
+		EntryID:   entry.ID,
+		Version:   entry.Version,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to save applied request in storage: %w", err)
+	}
+	return nil
+}
+
+// logError logs conflicts with debug level, they are expected and resolved by the client
+func (uc *EntryUC) logError(msg string, err error, fields ...zap.Field) {
+	fields = append(fields, zap.Error(err))
+	if errors.Is(err, entities.ErrEntryVersionConflict) ||
+		errors.Is(err, entities.ErrEntryKeyConflict) ||
+		errors.Is(err, entities.ErrEntryIDTaken) {
+		uc.logger.Debug(msg, fields...)
+		return
+	}
+	uc.logger.Error(msg, fields...)
 }
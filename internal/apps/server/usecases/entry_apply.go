@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/core/apperrors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// entryChangeSettings runs every change of the batch under its own savepoint
+	entryChangeSettings = settings.Must(settings.WithPropagation(trm.PropagationNested))
+	// errEntryChangeRejected rolls back the change to its savepoint
+	errEntryChangeRejected = errors.New("entry change rejected")
+)
+
+// ApplyChanges applies the batch of client changes in one transaction.
+// Rejected changes are rolled back, reported in the results and don't affect the others,
+// the storage failure rolls back the whole batch.
+func (uc *EntryUC) ApplyChanges(
+	ctx context.Context,
+	request entities.ApplyEntryChangesRequest,
+) (response entities.ApplyEntryChangesResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return response, fmt.Errorf("apply_entry_changes: invalid request: %w", err)
+	}
+	userID := request.UserID
+
+	results := make([]entities.EntryChangeResult, len(request.Changes))
+	var notify []entities.EntryChange
+	if err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		for i, change := range request.Changes {
+			var (
+				result   entities.EntryChangeResult
+				replayed bool
+			)
+			err := uc.tx.DoWithSettings(ctx, entryChangeSettings, func(ctx context.Context) (err error) {
+				if result, replayed, err = uc.applyChange(ctx, userID, change); err != nil {
+					return err
+				}
+				if result.Status != entities.EntryChangeOK {
+					return errEntryChangeRejected
+				}
+				return nil
+			})
+			if err != nil && (!errors.Is(err, errEntryChangeRejected) || errors.Is(err, trm.ErrTransaction)) {
+				return fmt.Errorf("apply_entry_changes: failed to apply change %d: %w", i, err)
+			}
+			results[i] = result
+			if result.Status == entities.EntryChangeOK && !replayed {
+				notify = append(notify, entities.EntryChange{ID: result.ID, Version: result.Version})
+			}
+		}
+		return nil
+	}); err != nil {
+		uc.logger.Error("failed to apply entry changes",
+			zap.String("user_id", userID.String()),
+			zap.Int("changes", len(request.Changes)),
+			zap.Error(err))
+		return response, err
+	}
+	for _, change := range notify {
+		uc.notifier.Notify(userID, change)
+	}
+	response.Results = results
+
+	return response, nil
+}
+
+// applyChange returns the result of the rejected change without error,
+// the error is returned only if the batch can't be applied
+func (uc *EntryUC) applyChange(
+	ctx context.Context,
+	userID uuid.UUID,
+	change entities.EntryChangeRequest,
+) (result entities.EntryChangeResult, replayed bool, err error) {
+	if err = change.Validate(); err != nil {
+		return entities.EntryChangeResult{Status: entities.EntryChangeInvalid, Err: err}, false, nil
+	}
+	switch {
+	case change.Create != nil:
+		request := *change.Create
+		request.UserID = userID
+		var created entities.CreateEntryResponse
+		created, replayed, err = uc.createEntry(ctx, request)
+		result = entities.EntryChangeResult{ID: created.ID, Version: created.Version, Conflict: created.Conflict}
+	case change.Update != nil:
+		request := *change.Update
+		request.UserID = userID
+		var updated entities.UpdateEntryResponse
+		updated, replayed, err = uc.updateEntry(ctx, request)
+		result = entities.EntryChangeResult{ID: updated.ID, Version: updated.Version, Conflict: updated.Conflict}
+	default:
+		request := *change.Delete
+		request.UserID = userID
+		var deleted entities.DeleteEntryResponse
+		deleted, replayed, err = uc.deleteEntry(ctx, request)
+		result = entities.EntryChangeResult{ID: deleted.ID, Version: deleted.Version}
+	}
+
+	var (
+		invalid  *apperrors.AppErrorInvalid
+		notFound *apperrors.AppErrorNotFound
+	)
+	switch {
+	case err == nil:
+		result.Status = entities.EntryChangeOK
+		return result, replayed, nil
+	case errors.Is(err, entities.ErrEntryIDTaken):
+		result.Status = entities.EntryChangeIDTaken
+	case result.Conflict != nil:
+		result.Status = entities.EntryChangeConflict
+	case errors.As(err, &invalid):
+		result.Status = entities.EntryChangeInvalid
+	case errors.As(err, &notFound):
+		result.Status = entities.EntryChangeNotFound
+	default:
+		return result, false, err
+	}
+	result.Err = err
+	return result, false, nil
+}
@@ -2,17 +2,18 @@ package usecases_test
 
 import (
 	"context"
+	"fmt"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/diff"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/notify"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
 	"github.com/dlomanov/gophkeeper/internal/core"
-	"github.com/dlomanov/gophkeeper/internal/infra/encrypto"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
-	"strings"
 	"testing"
+	"time"
 )
 
 func TestEntryUC_GetAll(t *testing.T) {
@@ -255,11 +256,15 @@ func TestEntryUC_Create(t *testing.T) {
 		sut     = createSUT(t)
 		userID1 = uuid.New()
 		userID2 = uuid.New()
+		entryID = uuid.New()
 	)
 
 	okResponse := func(t require.TestingT, request any, response any, args ...any) {
 		req := request.(entities.CreateEntryRequest)
 		resp := response.(entities.CreateEntryResponse)
+		if req.ID != uuid.Nil {
+			require.Equal(t, req.ID, resp.ID, "client ID should be preserved")
+		}
 		getResp, err := sut.Get(ctx, entities.GetEntryRequest{
 			ID:     resp.ID,
 			UserID: req.UserID,
@@ -311,25 +316,98 @@ func TestEntryUC_Create(t *testing.T) {
 				UserID: userID2,
 				Type:   core.EntryTypePassword,
 				Meta:   nil,
-				Data:   []byte("test_data_1"),
+				Data:   []byte("test_data_2"),
+			},
+			wantErr: func(t require.TestingT, err error, args ...any) {
+				require.ErrorIs(t, err, entities.ErrEntryKeyConflict, args...)
 			},
-			wantErr: require.NoError,
 			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
 				req := request.(entities.CreateEntryRequest)
 				resp := response.(entities.CreateEntryResponse)
-				getResp, err := sut.Get(ctx, entities.GetEntryRequest{
-					ID:     resp.ID,
-					UserID: req.UserID,
-				})
-				require.NoError(t, err)
-				require.NotEqual(t, req.Key, getResp.Entry.Key)
-				require.True(t, strings.HasPrefix(getResp.Entry.Key, req.Key), "key should be prefix of %s", req.Key)
-				require.Equal(t, req.UserID, getResp.Entry.UserID)
-				require.Equal(t, req.Type, getResp.Entry.Type)
-				require.Equal(t, req.Meta, getResp.Entry.Meta)
-				require.Equal(t, req.Data, getResp.Entry.Data)
-				require.Equal(t, resp.ID, getResp.Entry.ID)
-				require.Equal(t, resp.Version, getResp.Entry.Version)
+				require.Equal(t, uuid.Nil, resp.ID, "entry should not be created")
+				require.NotNil(t, resp.Conflict, "conflict expected")
+				require.Equal(t, req.Key, resp.Conflict.Server.Key)
+				require.Equal(t, []byte("test_data_1"), resp.Conflict.Server.Data)
+				require.Equal(t, req.Data, resp.Conflict.Client.Data)
+				require.Nil(t, resp.Conflict.Base, "created entry has no base version")
+			},
+		},
+		{
+			name: "key2 user1 client id",
+			request: entities.CreateEntryRequest{
+				ID:     entryID,
+				Key:    "key2",
+				UserID: userID1,
+				Type:   core.EntryTypePassword,
+				Data:   []byte("test_data_2"),
+			},
+			wantErr:      require.NoError,
+			wantResponse: okResponse,
+		},
+		{
+			name: "key2 user1 client id replay",
+			request: entities.CreateEntryRequest{
+				ID:     entryID,
+				Key:    "key2",
+				UserID: userID1,
+				Type:   core.EntryTypePassword,
+				Data:   []byte("test_data_2"),
+			},
+			wantErr:      require.NoError,
+			wantResponse: okResponse,
+		},
+		{
+			name: "key2 user1 client id changed before response",
+			request: entities.CreateEntryRequest{
+				ID:     entryID,
+				Key:    "key2",
+				UserID: userID1,
+				Type:   core.EntryTypePassword,
+				Data:   []byte("test_data_3"),
+			},
+			wantErr: require.NoError,
+			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
+				okResponse(t, request, response, args...)
+				require.Equal(t, int64(2), response.(entities.CreateEntryResponse).Version, "changed data should be applied")
+			},
+		},
+		{
+			name: "key2 user1 client id changed after update",
+			request: entities.CreateEntryRequest{
+				ID:     entryID,
+				Key:    "key2",
+				UserID: userID1,
+				Type:   core.EntryTypePassword,
+				Data:   []byte("test_data_4"),
+			},
+			wantErr: func(t require.TestingT, err error, args ...any) {
+				require.ErrorIs(t, err, entities.ErrEntryKeyConflict, args...)
+			},
+			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
+				resp := response.(entities.CreateEntryResponse)
+				require.NotNil(t, resp.Conflict, "conflict expected")
+				require.Equal(t, entryID, resp.Conflict.Server.ID)
+				require.Equal(t, []byte("test_data_3"), resp.Conflict.Server.Data)
+				require.Equal(t, entryID, resp.Conflict.Client.ID)
+				require.Equal(t, []byte("test_data_4"), resp.Conflict.Client.Data)
+			},
+		},
+		{
+			name: "key2 user2 client id taken",
+			request: entities.CreateEntryRequest{
+				ID:     entryID,
+				Key:    "key2",
+				UserID: userID2,
+				Type:   core.EntryTypePassword,
+				Data:   []byte("test_data_2"),
+			},
+			wantErr: func(t require.TestingT, err error, args ...any) {
+				require.ErrorIs(t, err, entities.ErrEntryIDTaken, args...)
+			},
+			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
+				resp := response.(entities.CreateEntryResponse)
+				require.Equal(t, uuid.Nil, resp.ID, "entry should not be created")
+				require.Nil(t, resp.Conflict, "owner of the ID should not be disclosed")
 			},
 		},
 	}
@@ -403,9 +481,11 @@ func TestEntryUC_Delete(t *testing.T) {
 				resp := response.(entities.DeleteEntryResponse)
 				require.Equal(t, req.ID, resp.ID)
 				require.Equal(t, createResponse.ID, resp.ID)
-				require.Equal(t, createResponse.Version, resp.Version)
+				require.Equal(t, createResponse.Version+1, resp.Version, "tombstone is a new version")
 				_, err := sut.Get(ctx, entities.GetEntryRequest{ID: req.ID, UserID: req.UserID})
 				require.ErrorIs(t, err, entities.ErrEntryNotFound)
+				_, err = sut.Delete(ctx, req)
+				require.ErrorIs(t, err, entities.ErrEntryNotFound, "tombstone can't be deleted twice")
 			},
 		},
 	}
@@ -506,17 +586,38 @@ func TestEntryUC_Update(t *testing.T) {
 				Version: createResponse.Version + 2,
 				UserID:  userID1,
 			},
-			wantErr: require.NoError,
+			wantErr: exactErr(entities.ErrEntryVersionConflict),
 			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
 				req := request.(entities.UpdateEntryRequest)
 				resp := response.(entities.UpdateEntryResponse)
-				require.NotEqual(t, req.ID, resp.ID)
-				require.Equal(t, int64(1), resp.Version)
-				getResp, err := sut.Get(ctx, entities.GetEntryRequest{ID: resp.ID, UserID: req.UserID})
+				require.NotNil(t, resp.Conflict, "conflict expected")
+				require.Equal(t, req.ID, resp.Conflict.Server.ID)
+				require.Equal(t, createResponse.Version+1, resp.Conflict.Server.Version)
+				require.Equal(t, updatedData, resp.Conflict.Server.Data)
+				require.Equal(t, req.Data, resp.Conflict.Client.Data)
+				require.Equal(t, req.Meta, resp.Conflict.Client.Meta)
+				require.Nil(t, resp.Conflict.Base, "unknown base version")
+			},
+		},
+		{
+			name: "update conflict with base",
+			request: entities.UpdateEntryRequest{
+				ID:      createResponse.ID,
+				Meta:    map[string]string{"description": "conflict"},
+				Data:    []byte("conflict"),
+				Version: createResponse.Version,
+				UserID:  userID1,
+			},
+			wantErr: exactErr(entities.ErrEntryVersionConflict),
+			wantResponse: func(t require.TestingT, request any, response any, args ...any) {
+				resp := response.(entities.UpdateEntryResponse)
+				require.NotNil(t, resp.Conflict, "conflict expected")
+				require.NotNil(t, resp.Conflict.Base, "base version expected")
+				require.Equal(t, createResponse.Version, resp.Conflict.Base.Version)
+				require.Equal(t, []byte("test_data_1"), resp.Conflict.Base.Data)
+				getResp, err := sut.Get(ctx, entities.GetEntryRequest{ID: createResponse.ID, UserID: userID1})
 				require.NoError(t, err)
-				require.Equal(t, req.Meta, getResp.Entry.Meta)
-				require.Equal(t, req.Data, getResp.Entry.Data)
-				require.NotEqual(t, req.Version, getResp.Entry.Version)
+				require.Equal(t, updatedData, getResp.Entry.Data, "server version should not be changed")
 			},
 		},
 	}
@@ -530,14 +631,437 @@ func TestEntryUC_Update(t *testing.T) {
 	}
 }
 
+func TestEntryUC_Restore(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sut    = createSUT(t)
+		userID = uuid.New()
+	)
+
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key1",
+		UserID: userID,
+		Type:   core.EntryTypeNote,
+		Meta:   map[string]string{"description": "v1"},
+		Data:   []byte("data_v1"),
+	})
+	require.NoError(t, err)
+	updated, err := sut.Update(ctx, entities.UpdateEntryRequest{
+		ID:      created.ID,
+		UserID:  userID,
+		Meta:    map[string]string{"description": "v2"},
+		Data:    []byte("data_v2"),
+		Version: created.Version,
+	})
+	require.NoError(t, err)
+
+	history, err := sut.GetHistory(ctx, entities.GetEntryHistoryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 1, "expected previous version in history")
+	require.Equal(t, created.Version, history.Entries[0].Version)
+	require.Equal(t, []byte("data_v1"), history.Entries[0].Data)
+
+	// restore as a new version
+	restored, err := sut.Restore(ctx, entities.RestoreEntryRequest{UserID: userID, ID: created.ID, Version: created.Version})
+	require.NoError(t, err)
+	require.Equal(t, updated.Version+1, restored.Version, "expected new version")
+	got, err := sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	require.Equal(t, []byte("data_v1"), got.Entry.Data)
+	require.Equal(t, "v1", got.Entry.Meta["description"])
+
+	// restore deleted entry
+	deleted, err := sut.Delete(ctx, entities.DeleteEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	restored, err = sut.Restore(ctx, entities.RestoreEntryRequest{UserID: userID, ID: created.ID, Version: updated.Version})
+	require.NoError(t, err)
+	require.Equal(t, created.ID, restored.ID, "expected same entry ID")
+	require.Equal(t, deleted.Version+1, restored.Version, "expected new version")
+	got, err = sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	require.Equal(t, []byte("data_v2"), got.Entry.Data)
+
+	_, err = sut.Restore(ctx, entities.RestoreEntryRequest{UserID: userID, ID: created.ID, Version: 100})
+	require.ErrorIs(t, err, entities.ErrEntryHistoryNotFound)
+	_, err = sut.Restore(ctx, entities.RestoreEntryRequest{UserID: userID, ID: created.ID})
+	require.ErrorIs(t, err, entities.ErrEntryVersionInvalid)
+}
+
+func TestEntryUC_Trash(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sut    = createSUT(t)
+		userID = uuid.New()
+	)
+
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key1",
+		UserID: userID,
+		Type:   core.EntryTypeNote,
+		Data:   []byte("data_v1"),
+	})
+	require.NoError(t, err)
+	deleted, err := sut.Delete(ctx, entities.DeleteEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+
+	trash, err := sut.GetTrash(ctx, entities.GetTrashRequest{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, trash.Entries, 1, "expected deleted entry in trash")
+	require.Equal(t, created.ID, trash.Entries[0].ID)
+	diff, err := sut.GetEntriesDiff(ctx, entities.GetEntriesDiffRequest{
+		UserID:   userID,
+		Versions: []core.EntryVersion{{ID: created.ID, Version: created.Version}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{created.ID}, diff.DeleteIDs, "expected tombstone synced as delete")
+
+	// key of deleted entry is free until the entry is undeleted
+	taken, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key1",
+		UserID: userID,
+		Type:   core.EntryTypeNote,
+		Data:   []byte("data_v2"),
+	})
+	require.NoError(t, err)
+	_, err = sut.Undelete(ctx, entities.UndeleteEntryRequest{UserID: userID, ID: created.ID})
+	require.ErrorIs(t, err, entities.ErrEntryExists)
+	_, err = sut.Delete(ctx, entities.DeleteEntryRequest{UserID: userID, ID: taken.ID})
+	require.NoError(t, err)
+
+	undeleted, err := sut.Undelete(ctx, entities.UndeleteEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	require.Equal(t, deleted.Version+1, undeleted.Version, "expected new version")
+	got, err := sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err)
+	require.Equal(t, []byte("data_v1"), got.Entry.Data)
+	_, err = sut.Undelete(ctx, entities.UndeleteEntryRequest{UserID: userID, ID: created.ID})
+	require.ErrorIs(t, err, entities.ErrEntryNotFound, "expected alive entry can't be undeleted")
+
+	purged, err := sut.Purge(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, purged, "expected recently deleted entry kept")
+	purged, err = sut.Purge(ctx, -time.Second)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged, "expected deleted entry purged")
+	trash, err = sut.GetTrash(ctx, entities.GetTrashRequest{UserID: userID})
+	require.NoError(t, err)
+	require.Empty(t, trash.Entries)
+}
+
+func TestEntryUC_GetChangesSince(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sut    = createSUT(t)
+		userID = uuid.New()
+		ids    = make([]uuid.UUID, 3)
+	)
+
+	for i := range ids {
+		created, err := sut.Create(ctx, entities.CreateEntryRequest{
+			Key:    fmt.Sprintf("key%d", i),
+			UserID: userID,
+			Type:   core.EntryTypeNote,
+			Data:   []byte("data"),
+		})
+		require.NoError(t, err)
+		ids[i] = created.ID
+	}
+	diff, err := sut.GetEntriesDiff(ctx, entities.GetEntriesDiffRequest{UserID: userID})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), diff.Cursor, "expected diff cursor at the last change")
+
+	_, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: -1})
+	require.ErrorIs(t, err, entities.ErrEntryCursorInvalid)
+	_, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Limit: entities.EntryChangesMaxLimit + 1})
+	require.ErrorIs(t, err, entities.ErrEntryChangesLimitInvalid)
+
+	page, err := sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	require.True(t, page.HasMore, "expected next page")
+	require.Equal(t, ids[:2], []uuid.UUID{page.Entries[0].ID, page.Entries[1].ID}, "expected change order")
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: page.Cursor, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.False(t, page.HasMore, "expected last page")
+	require.Equal(t, diff.Cursor, page.Cursor)
+
+	_, err = sut.Delete(ctx, entities.DeleteEntryRequest{UserID: userID, ID: ids[0]})
+	require.NoError(t, err)
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: page.Cursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.True(t, page.Entries[0].Deleted(), "expected tombstone in changes")
+	cursor := page.Cursor
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: cursor})
+	require.NoError(t, err)
+	require.Empty(t, page.Entries)
+	require.Equal(t, cursor, page.Cursor, "expected cursor kept without changes")
+
+	_, err = sut.Purge(ctx, -time.Second)
+	require.NoError(t, err)
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: diff.Cursor})
+	require.NoError(t, err)
+	require.True(t, page.Reset, "expected reset for cursor behind purged tombstone")
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: cursor})
+	require.NoError(t, err)
+	require.False(t, page.Reset, "expected cursor after purged tombstone served")
+	page, err = sut.GetChangesSince(ctx, entities.GetChangesSinceRequest{UserID: userID, Cursor: cursor + 1})
+	require.NoError(t, err)
+	require.True(t, page.Reset, "expected reset for unknown cursor")
+}
+
+func TestEntryUC_Watch(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		sut         = createSUT(t)
+		userID1     = uuid.New()
+		userID2     = uuid.New()
+		changes     = make(chan entities.EntryChange, 10)
+		done        = make(chan error)
+	)
+	defer cancel()
+
+	watch := func(userID uuid.UUID) {
+		done <- sut.Watch(ctx, entities.WatchEntriesRequest{
+			UserID: userID,
+			Send: func(change entities.EntryChange) error {
+				changes <- change
+				return nil
+			},
+		})
+	}
+	go watch(userID1)
+	go watch(userID2)
+	time.Sleep(100 * time.Millisecond)
+
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key1",
+		UserID: userID1,
+		Type:   core.EntryTypeNote,
+		Data:   []byte("test_data_1"),
+	})
+	require.NoError(t, err)
+	select {
+	case change := <-changes:
+		require.Equal(t, created.ID, change.ID, "unexpected entry id")
+		require.Equal(t, created.Version, change.Version, "unexpected entry version")
+	case <-time.After(time.Second):
+		require.Fail(t, "entry change not received")
+	}
+	select {
+	case change := <-changes:
+		require.Failf(t, "unexpected entry change", "%v", change)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+
+	err = sut.Watch(context.Background(), entities.WatchEntriesRequest{UserID: userID1})
+	require.ErrorIs(t, err, entities.ErrEntryWatchInvalid)
+}
+
+func TestEntryUC_ApplyChanges(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		sut     = createSUT(t)
+		userID  = uuid.New()
+		entryID = uuid.New()
+	)
+	created, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key1",
+		UserID: userID,
+		Type:   core.EntryTypeNote,
+		Data:   []byte("data1"),
+	})
+	require.NoError(t, err, "failed to create entry")
+
+	_, err = sut.ApplyChanges(ctx, entities.ApplyEntryChangesRequest{UserID: userID})
+	require.ErrorIs(t, err, entities.ErrEntryChangesBatchInvalid, "empty batch should be rejected")
+
+	response, err := sut.ApplyChanges(ctx, entities.ApplyEntryChangesRequest{
+		UserID: userID,
+		Changes: []entities.EntryChangeRequest{
+			{Create: &entities.CreateEntryRequest{ID: entryID, Key: "key2", Type: core.EntryTypeNote, Data: []byte("data2")}},
+			{Update: &entities.UpdateEntryRequest{ID: created.ID, Version: created.Version, Data: []byte("data1_v2")}},
+			{Update: &entities.UpdateEntryRequest{ID: created.ID, Version: created.Version, Data: []byte("data1_v3")}},
+			{Delete: &entities.DeleteEntryRequest{ID: uuid.New()}},
+			{Create: &entities.CreateEntryRequest{Key: "key2", Type: core.EntryTypeNote, Data: []byte("data3")}},
+			{Create: &entities.CreateEntryRequest{Type: core.EntryTypeNote, Data: []byte("data4")}},
+			{},
+			{Create: &entities.CreateEntryRequest{Key: "key3", Type: core.EntryTypeNote, Data: []byte("data5")}, Err: entities.ErrEntryIDInvalid},
+		},
+	})
+	require.NoError(t, err, "rejected changes should not fail the batch")
+	require.Len(t, response.Results, 8)
+
+	statuses := make([]entities.EntryChangeStatus, len(response.Results))
+	for i, v := range response.Results {
+		statuses[i] = v.Status
+	}
+	require.Equal(t, []entities.EntryChangeStatus{
+		entities.EntryChangeOK,
+		entities.EntryChangeOK,
+		entities.EntryChangeConflict,
+		entities.EntryChangeNotFound,
+		entities.EntryChangeConflict,
+		entities.EntryChangeInvalid,
+		entities.EntryChangeInvalid,
+		entities.EntryChangeInvalid,
+	}, statuses)
+	require.Equal(t, entryID, response.Results[0].ID, "client ID should be preserved")
+	require.NotNil(t, response.Results[2].Conflict, "version conflict expected")
+	require.NotNil(t, response.Results[4].Conflict, "key conflict expected")
+	require.Error(t, response.Results[5].Err, "rejection reason expected")
+	require.ErrorIs(t, response.Results[7].Err, entities.ErrEntryIDInvalid, "malformed change should be rejected")
+
+	got, err := sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, []byte("data1_v2"), got.Entry.Data, "rejected change should not be applied")
+	got, err = sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: entryID})
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, []byte("data2"), got.Entry.Data)
+}
+
+func TestEntryUC_ApplyChangesRollback(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		userID = uuid.New()
+		repo   = NewMockEntryRepo()
+		sut    = usecases.NewEntryUC(
+			zaptest.NewLogger(t, zaptest.Level(zap.FatalLevel)),
+			&rejectingEntryRepo{MockEntryRepo: repo, requestID: "rejected"},
+			diff.NewEntry(),
+			notify.NewHub(),
+			NewMockTrmManager(repo))
+	)
+
+	response, err := sut.ApplyChanges(ctx, entities.ApplyEntryChangesRequest{
+		UserID: userID,
+		Changes: []entities.EntryChangeRequest{
+			{Create: &entities.CreateEntryRequest{Key: "key1", Type: core.EntryTypeNote, Data: []byte("data1"), RequestID: "rejected"}},
+			{Create: &entities.CreateEntryRequest{Key: "key2", Type: core.EntryTypeNote, Data: []byte("data2"), RequestID: "applied"}},
+		},
+	})
+	require.NoError(t, err, "rejected change should not fail the batch")
+	require.Len(t, response.Results, 2)
+	require.Equal(t, entities.EntryChangeInvalid, response.Results[0].Status)
+	require.Equal(t, entities.EntryChangeOK, response.Results[1].Status)
+
+	got, err := sut.GetEntries(ctx, entities.GetEntriesRequest{UserID: userID})
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, got.Entries, 1, "rejected change should be rolled back")
+	require.Equal(t, "key2", got.Entries[0].Key)
+}
+
+// rejectingEntryRepo rejects the applied request after the entry is written
+type rejectingEntryRepo struct {
+	*MockEntryRepo
+	requestID string
+}
+
+func (r *rejectingEntryRepo) SaveRequest(ctx context.Context, request entities.AppliedEntryRequest) error {
+	if request.RequestID == r.requestID {
+		return entities.ErrEntryRequestIDReused
+	}
+	return r.MockEntryRepo.SaveRequest(ctx, request)
+}
+
+func TestEntryUC_RequestID(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		sut    = createSUT(t)
+		userID = uuid.New()
+	)
+	create := entities.CreateEntryRequest{
+		Key:       "key1",
+		UserID:    userID,
+		Type:      core.EntryTypeNote,
+		Data:      []byte("data1"),
+		RequestID: "create1",
+	}
+	created, err := sut.Create(ctx, create)
+	require.NoError(t, err, "failed to create entry")
+	replayed, err := sut.Create(ctx, create)
+	require.NoError(t, err, "retry should be replayed")
+	require.Equal(t, created, replayed, "original response expected")
+
+	update := entities.UpdateEntryRequest{
+		ID:        created.ID,
+		UserID:    userID,
+		Data:      []byte("data1_v2"),
+		Version:   created.Version,
+		RequestID: "update1",
+	}
+	updated, err := sut.Update(ctx, update)
+	require.NoError(t, err, "failed to update entry")
+	for range 2 {
+		response, err := sut.ApplyChanges(ctx, entities.ApplyEntryChangesRequest{
+			UserID:  userID,
+			Changes: []entities.EntryChangeRequest{{Update: &update}},
+		})
+		require.NoError(t, err, "failed to apply changes")
+		require.Equal(t, entities.EntryChangeOK, response.Results[0].Status, "retry should not conflict")
+		require.Equal(t, updated.Version, response.Results[0].Version, "original version expected")
+	}
+
+	other, err := sut.Create(ctx, entities.CreateEntryRequest{
+		Key:    "key2",
+		UserID: userID,
+		Type:   core.EntryTypeNote,
+		Data:   []byte("data2"),
+	})
+	require.NoError(t, err, "failed to create entry")
+	_, err = sut.Delete(ctx, entities.DeleteEntryRequest{ID: other.ID, UserID: userID, RequestID: "update1"})
+	require.ErrorIs(t, err, entities.ErrEntryRequestIDReused)
+
+	_, err = sut.Create(ctx, entities.CreateEntryRequest{
+		Key:       "key1",
+		UserID:    uuid.New(),
+		Type:      core.EntryTypeNote,
+		Data:      []byte("data1"),
+		RequestID: "create1",
+	})
+	require.NoError(t, err, "request IDs should be scoped by user")
+
+	got, err := sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: created.ID})
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, updated.Version, got.Entry.Version, "retries should not be applied")
+	entries, err := sut.GetEntries(ctx, entities.GetEntriesRequest{UserID: userID})
+	require.NoError(t, err, "failed to get entries")
+	require.Len(t, entries.Entries, 2, "retried create should not be duplicated")
+
+	// the create response is lost and the entry is changed before the retry
+	create = entities.CreateEntryRequest{
+		ID:        uuid.New(),
+		Key:       "key3",
+		UserID:    userID,
+		Type:      core.EntryTypeNote,
+		Data:      []byte("data3"),
+		RequestID: "create3",
+	}
+	_, err = sut.Create(ctx, create)
+	require.NoError(t, err, "failed to create entry")
+	create.Data, create.RequestID = []byte("data3_v2"), "create4"
+	recreated, err := sut.Create(ctx, create)
+	require.NoError(t, err, "changed retry should be applied")
+	require.Equal(t, int64(2), recreated.Version)
+	replayed, err = sut.Create(ctx, create)
+	require.NoError(t, err, "changed retry should be replayed")
+	require.Equal(t, recreated, replayed, "original response expected")
+	got, err = sut.Get(ctx, entities.GetEntryRequest{UserID: userID, ID: create.ID})
+	require.NoError(t, err, "failed to get entry")
+	require.Equal(t, []byte("data3_v2"), got.Entry.Data, "changed data should be kept")
+}
+
 func createSUT(t *testing.T) *usecases.EntryUC {
 	merger := diff.NewEntry()
-	enc, err := encrypto.NewEncrypter([]byte("1234567890123456"))
-	require.NoError(t, err, "no error expected")
 	return usecases.NewEntryUC(
 		zaptest.NewLogger(t, zaptest.Level(zap.FatalLevel)),
 		NewMockEntryRepo(),
 		merger,
-		enc,
+		notify.NewHub(),
 		NewMockTrmManager())
 }
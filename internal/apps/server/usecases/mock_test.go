@@ -7,33 +7,65 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
+	"maps"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 )
 
 var (
-	_ usecases.UserRepo  = (*MockUserRepo)(nil)
-	_ usecases.EntryRepo = (*MockEntryRepo)(nil)
-	_ trm.Manager        = (*MockTrmManager)(nil)
+	_ usecases.UserRepo    = (*MockUserRepo)(nil)
+	_ usecases.EntryRepo   = (*MockEntryRepo)(nil)
+	_ usecases.BlobRepo    = (*MockBlobRepo)(nil)
+	_ usecases.SessionRepo = (*MockSessionRepo)(nil)
+	_ trm.Manager          = (*MockTrmManager)(nil)
 )
 
 type (
 	MockUserRepo struct {
-		mu      sync.RWMutex
-		storage map[entities.Login]entities.User
+		mu       sync.RWMutex
+		storage  map[entities.Login]entities.User
+		tokens   map[string]entities.RefreshToken
+		recovery map[uuid.UUID][]entities.RecoveryCode
+		attempts map[uuid.UUID]mockOTPAttempts
+	}
+	mockOTPAttempts struct {
+		count       int
+		lockedUntil time.Time
 	}
 	MockEntryRepo struct {
+		mu       sync.RWMutex
+		storage  map[string]entities.Entry
+		history  map[string][]entities.Entry
+		seqs     map[uuid.UUID]entities.EntryChangeSeq
+		requests map[string]entities.AppliedEntryRequest
+	}
+	MockBlobRepo struct {
+		mu      sync.RWMutex
+		storage map[string][]entities.BlobChunk
+		entries *MockEntryRepo
+	}
+	MockSessionRepo struct {
 		mu      sync.RWMutex
-		storage map[string]entities.Entry
+		storage map[uuid.UUID]entities.Session
 	}
 	MockTrmManager struct {
+		repos []mockSavepointRepo
+	}
+	// mockSavepointRepo is rolled back by MockTrmManager when the nested transaction fails
+	mockSavepointRepo interface {
+		savepoint() (rollback func())
 	}
 )
 
 func NewMockUserRepo() *MockUserRepo {
 	return &MockUserRepo{
-		mu:      sync.RWMutex{},
-		storage: make(map[entities.Login]entities.User),
+		mu:       sync.RWMutex{},
+		storage:  make(map[entities.Login]entities.User),
+		tokens:   make(map[string]entities.RefreshToken),
+		recovery: make(map[uuid.UUID][]entities.RecoveryCode),
+		attempts: make(map[uuid.UUID]mockOTPAttempts),
 	}
 }
 
@@ -62,6 +94,191 @@ func (r *MockUserRepo) Create(_ context.Context, user entities.User) error {
 	return nil
 }
 
+func (r *MockUserRepo) UpdatePass(_ context.Context, id uuid.UUID, hash core.PassHash) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID == id {
+			v.PassHash = hash
+			r.storage[k] = v
+			return nil
+		}
+	}
+
+	return entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) SetVaultKey(_ context.Context, id uuid.UUID, key []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID == id {
+			v.VaultKey = key
+			r.storage[k] = v
+			return nil
+		}
+	}
+
+	return entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) SetVaultCheck(_ context.Context, id uuid.UUID, check []byte) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID == id {
+			if len(v.VaultCheck) == 0 {
+				v.VaultCheck = check
+				r.storage[k] = v
+			}
+			return v.VaultCheck, nil
+		}
+	}
+
+	return nil, entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID != id {
+			continue
+		}
+		delete(r.storage, k)
+		delete(r.recovery, id)
+		for hash, token := range r.tokens {
+			if token.UserID == id {
+				delete(r.tokens, hash)
+			}
+		}
+		return nil
+	}
+
+	return entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) GetRefreshToken(_ context.Context, hash string) (entities.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	token, ok := r.tokens[hash]
+	if !ok {
+		return entities.RefreshToken{}, entities.ErrUserRefreshNotFound
+	}
+
+	return token, nil
+}
+
+func (r *MockUserRepo) CreateRefreshToken(_ context.Context, token entities.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.Hash] = token
+
+	return nil
+}
+
+func (r *MockUserRepo) RevokeRefreshToken(_ context.Context, id uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.tokens {
+		if v.ID == id && !v.Revoked() {
+			v.RevokedAt = &revokedAt
+			r.tokens[k] = v
+			return nil
+		}
+	}
+
+	return entities.ErrUserRefreshNotFound
+}
+
+func (r *MockUserRepo) GetByID(_ context.Context, id uuid.UUID) (entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.storage {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+
+	return entities.User{}, entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) UpdateOTP(_ context.Context, id uuid.UUID, otp entities.UserOTP) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID == id {
+			v.OTP = otp
+			r.storage[k] = v
+			return nil
+		}
+	}
+
+	return entities.ErrUserNotFound
+}
+
+func (r *MockUserRepo) UseOTPStep(_ context.Context, id uuid.UUID, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, v := range r.storage {
+		if v.ID == id && v.OTP.LastStep < step {
+			v.OTP.LastStep = step
+			r.storage[k] = v
+			return nil
+		}
+	}
+
+	return entities.ErrUserOTPInvalid
+}
+
+func (r *MockUserRepo) TakeOTPAttempt(
+	_ context.Context,
+	id uuid.UUID,
+	maxAttempts int,
+	lockedUntil time.Time,
+	now time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts := r.attempts[id]
+	if attempts.lockedUntil.After(now) {
+		return entities.ErrUserOTPLocked
+	}
+	if attempts.count++; attempts.count >= maxAttempts {
+		attempts = mockOTPAttempts{lockedUntil: lockedUntil}
+	}
+	r.attempts[id] = attempts
+	return nil
+}
+
+func (r *MockUserRepo) ResetOTPAttempts(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, id)
+	return nil
+}
+
+func (r *MockUserRepo) ReplaceRecoveryCodes(_ context.Context, userID uuid.UUID, codes []entities.RecoveryCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recovery[userID] = slices.Clone(codes)
+
+	return nil
+}
+
+func (r *MockUserRepo) UseRecoveryCode(_ context.Context, userID uuid.UUID, hash string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, v := range r.recovery[userID] {
+		if v.Hash == hash && v.UsedAt == nil {
+			r.recovery[userID][i].UsedAt = &usedAt
+			return nil
+		}
+	}
+
+	return entities.ErrUserRecoveryCodeNotFound
+}
+
 func (r *MockUserRepo) get(login entities.Login) (entities.User, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -72,8 +289,30 @@ func (r *MockUserRepo) get(login entities.Login) (entities.User, bool) {
 
 func NewMockEntryRepo() *MockEntryRepo {
 	return &MockEntryRepo{
-		mu:      sync.RWMutex{},
-		storage: make(map[string]entities.Entry),
+		mu:       sync.RWMutex{},
+		storage:  make(map[string]entities.Entry),
+		history:  make(map[string][]entities.Entry),
+		seqs:     make(map[uuid.UUID]entities.EntryChangeSeq),
+		requests: make(map[string]entities.AppliedEntryRequest),
+	}
+}
+
+func (r *MockEntryRepo) savepoint() (rollback func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var (
+		storage  = maps.Clone(r.storage)
+		history  = make(map[string][]entities.Entry, len(r.history))
+		seqs     = maps.Clone(r.seqs)
+		requests = maps.Clone(r.requests)
+	)
+	for k, v := range r.history {
+		history[k] = slices.Clone(v)
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.storage, r.history, r.seqs, r.requests = storage, history, seqs, requests
 	}
 }
 
@@ -90,12 +329,23 @@ func (r *MockEntryRepo) Get(_ context.Context, userID uuid.UUID, id uuid.UUID) (
 	return &entry, nil
 }
 
+func (r *MockEntryRepo) GetByKey(_ context.Context, userID uuid.UUID, key string) (*entities.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.storage {
+		if v.UserID == userID && v.Key == key && !v.Deleted() {
+			return &v, nil
+		}
+	}
+	return nil, entities.ErrEntryNotFound
+}
+
 func (r *MockEntryRepo) GetAll(_ context.Context, userID uuid.UUID) ([]entities.Entry, error) {
 	var entries []entities.Entry
 
 	r.mu.RLock()
 	for _, v := range r.storage {
-		if v.UserID == userID {
+		if v.UserID == userID && !v.Deleted() {
 			entries = append(entries, v)
 		}
 	}
@@ -108,6 +358,24 @@ func (r *MockEntryRepo) GetAll(_ context.Context, userID uuid.UUID) ([]entities.
 	return entries, nil
 }
 
+func (r *MockEntryRepo) GetDeleted(_ context.Context, userID uuid.UUID) ([]entities.Entry, error) {
+	var entries []entities.Entry
+
+	r.mu.RLock()
+	for _, v := range r.storage {
+		if v.UserID == userID && v.Deleted() {
+			entries = append(entries, v)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(*entries[j].DeletedAt)
+	})
+
+	return entries, nil
+}
+
 func (r *MockEntryRepo) GetByIDs(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -134,19 +402,20 @@ func (r *MockEntryRepo) GetByIDs(
 }
 
 func (r *MockEntryRepo) GetVersions(
-	ctx context.Context,
+	_ context.Context,
 	userID uuid.UUID,
 ) ([]core.EntryVersion, error) {
-	entries, err := r.GetAll(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
+	var versions []core.EntryVersion
 
-	versions := make([]core.EntryVersion, len(entries))
-	for i, v := range entries {
-		versions[i] = core.EntryVersion{
-			ID:      v.ID,
-			Version: v.Version,
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.storage {
+		if v.UserID == userID {
+			versions = append(versions, core.EntryVersion{
+				ID:      v.ID,
+				Version: v.Version,
+				Deleted: v.Deleted(),
+			})
 		}
 	}
 	return versions, nil
@@ -157,14 +426,10 @@ func (r *MockEntryRepo) Create(_ context.Context, entry *entities.Entry) error {
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.storage[key]; ok {
+	if r.idTaken(entry.ID) || r.keyTaken(entry) {
 		return entities.ErrEntryExists
 	}
-	for _, v := range r.storage {
-		if v.Key == entry.Key && v.UserID == entry.UserID {
-			return entities.ErrEntryExists
-		}
-	}
+	entry.Seq = r.nextSeq(entry.UserID)
 	r.storage[key] = *entry
 	return nil
 }
@@ -174,31 +439,333 @@ func (r *MockEntryRepo) Update(_ context.Context, entry *entities.Entry) error {
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.storage[key]; !ok {
+	prev, ok := r.storage[key]
+	if !ok {
 		return entities.ErrEntryNotFound
 	}
+	if !entry.Deleted() && r.keyTaken(entry) {
+		return entities.ErrEntryExists
+	}
+	r.history[key] = append(r.history[key], prev)
+	entry.Seq = r.nextSeq(entry.UserID)
 	r.storage[key] = *entry
 	return nil
 }
 
-func (r *MockEntryRepo) Delete(_ context.Context, userID uuid.UUID, id uuid.UUID) error {
+func (r *MockEntryRepo) Purge(_ context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for key, v := range r.storage {
+		if v.Deleted() && v.DeletedAt.Before(deletedBefore) {
+			delete(r.storage, key)
+			delete(r.history, key)
+			seq := r.seqs[v.UserID]
+			seq.Purged = max(seq.Purged, v.Seq)
+			r.seqs[v.UserID] = seq
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MockEntryRepo) GetRequest(
+	_ context.Context,
+	userID uuid.UUID,
+	requestID string,
+) (*entities.AppliedEntryRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	request, ok := r.requests[userID.String()+requestID]
+	if !ok {
+		return nil, entities.ErrEntryRequestNotFound
+	}
+	return &request, nil
+}
+
+func (r *MockEntryRepo) SaveRequest(_ context.Context, request entities.AppliedEntryRequest) error {
+	key := request.UserID.String() + request.RequestID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.requests[key]; !ok {
+		r.requests[key] = request
+	}
+	return nil
+}
+
+func (r *MockEntryRepo) PurgeRequests(_ context.Context, createdBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for key, v := range r.requests {
+		if v.CreatedAt.Before(createdBefore) {
+			delete(r.requests, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MockEntryRepo) GetHistory(_ context.Context, userID uuid.UUID, id uuid.UUID) ([]entities.Entry, error) {
+	key := r.toKey(userID, id)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	history := slices.Clone(r.history[key])
+	slices.Reverse(history)
+	return history, nil
+}
+
+func (r *MockEntryRepo) GetHistoryEntry(
+	_ context.Context,
+	userID uuid.UUID,
+	id uuid.UUID,
+	version int64,
+) (*entities.Entry, error) {
 	key := r.toKey(userID, id)
 
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.history[key] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, entities.ErrEntryHistoryNotFound
+}
+
+func (r *MockEntryRepo) GetChangesSince(
+	_ context.Context,
+	userID uuid.UUID,
+	seq int64,
+	limit int,
+) ([]entities.Entry, error) {
+	var entries []entities.Entry
+
+	r.mu.RLock()
+	for _, v := range r.storage {
+		if v.UserID == userID && v.Seq > seq {
+			entries = append(entries, v)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Seq < entries[j].Seq
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (r *MockEntryRepo) GetChangeSeq(_ context.Context, userID uuid.UUID) (entities.EntryChangeSeq, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.seqs[userID], nil
+}
+
+func (r *MockEntryRepo) nextSeq(userID uuid.UUID) int64 {
+	seq := r.seqs[userID]
+	seq.Current++
+	r.seqs[userID] = seq
+	return seq.Current
+}
+
+func (r *MockEntryRepo) keyTaken(entry *entities.Entry) bool {
+	for _, v := range r.storage {
+		if v.ID != entry.ID && v.Key == entry.Key && v.UserID == entry.UserID && !v.Deleted() {
+			return true
+		}
+	}
+	return false
+}
+
+// idTaken checks the entry ID across all users like the primary key does
+func (r *MockEntryRepo) idTaken(id uuid.UUID) bool {
+	for _, v := range r.storage {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MockEntryRepo) toKey(userID uuid.UUID, id uuid.UUID) string {
+	return userID.String() + id.String()
+}
+
+func NewMockBlobRepo(entries *MockEntryRepo) *MockBlobRepo {
+	return &MockBlobRepo{
+		mu:      sync.RWMutex{},
+		storage: make(map[string][]entities.BlobChunk),
+		entries: entries,
+	}
+}
+
+func (r *MockBlobRepo) GetChunk(
+	_ context.Context,
+	userID uuid.UUID,
+	id uuid.UUID,
+	index int,
+) (entities.BlobChunk, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chunks := r.storage[userID.String()+id.String()]
+	if index >= len(chunks) {
+		return entities.BlobChunk{}, entities.ErrBlobNotFound
+	}
+	return chunks[index], nil
+}
+
+func (r *MockBlobRepo) CreateChunk(_ context.Context, chunk entities.BlobChunk) error {
+	key := chunk.UserID.String() + chunk.ID.String()
+
 	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.storage[key] = append(r.storage[key], chunk)
+	return nil
+}
+
+func (r *MockBlobRepo) Delete(_ context.Context, userID uuid.UUID, id uuid.UUID) error {
+	key := userID.String() + id.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, ok := r.storage[key]; !ok {
-		return entities.ErrEntryNotFound
+		return entities.ErrBlobNotFound
 	}
 	delete(r.storage, key)
-	r.mu.Unlock()
 	return nil
 }
 
-func (r *MockEntryRepo) toKey(userID uuid.UUID, id uuid.UUID) string {
-	return userID.String() + id.String()
+func (r *MockBlobRepo) Move(_ context.Context, userID uuid.UUID, from uuid.UUID, to uuid.UUID) error {
+	key := userID.String() + from.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chunks, ok := r.storage[key]
+	if !ok {
+		return entities.ErrBlobNotFound
+	}
+	for i := range chunks {
+		chunks[i].ID = to
+	}
+	delete(r.storage, key)
+	r.storage[userID.String()+to.String()] = chunks
+	return nil
+}
+
+func (r *MockBlobRepo) PurgeOrphans(_ context.Context, createdBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries.mu.RLock()
+	defer r.entries.mu.RUnlock()
+	var purged int64
+	for key, chunks := range r.storage {
+		if _, ok := r.entries.storage[key]; ok || !chunks[0].CreatedAt.Before(createdBefore) {
+			continue
+		}
+		delete(r.storage, key)
+		purged += int64(len(chunks))
+	}
+	return purged, nil
+}
+
+func NewMockSessionRepo() *MockSessionRepo {
+	return &MockSessionRepo{
+		mu:      sync.RWMutex{},
+		storage: make(map[uuid.UUID]entities.Session),
+	}
 }
 
-func NewMockTrmManager() *MockTrmManager {
-	return &MockTrmManager{}
+func (r *MockSessionRepo) Get(_ context.Context, userID uuid.UUID, id uuid.UUID) (entities.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	session, ok := r.storage[id]
+	if !ok || session.UserID != userID {
+		return entities.Session{}, entities.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (r *MockSessionRepo) GetAll(_ context.Context, userID uuid.UUID) ([]entities.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sessions := make([]entities.Session, 0)
+	for _, v := range r.storage {
+		if v.UserID == userID && !v.Revoked() {
+			sessions = append(sessions, v)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (r *MockSessionRepo) Create(_ context.Context, session entities.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.storage[session.ID] = session
+
+	return nil
+}
+
+func (r *MockSessionRepo) Touch(_ context.Context, id uuid.UUID, seenAt time.Time, ip string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.storage[id]
+	if !ok {
+		return nil
+	}
+	session.LastSeenAt = seenAt
+	if ip != "" {
+		session.IP = ip
+	}
+	r.storage[id] = session
+
+	return nil
+}
+
+func (r *MockSessionRepo) Revoke(_ context.Context, userID uuid.UUID, id uuid.UUID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.storage[id]
+	if !ok || session.UserID != userID || session.Revoked() {
+		return entities.ErrSessionNotFound
+	}
+	session.RevokedAt = &revokedAt
+	r.storage[id] = session
+
+	return nil
+}
+
+func (r *MockSessionRepo) RevokeOthers(
+	_ context.Context,
+	userID uuid.UUID,
+	keepID uuid.UUID,
+	revokedAt time.Time,
+) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var revoked int64
+	for id, v := range r.storage {
+		if v.UserID == userID && id != keepID && !v.Revoked() {
+			v.RevokedAt = &revokedAt
+			r.storage[id] = v
+			revoked++
+		}
+	}
+
+	return revoked, nil
+}
+
+func NewMockTrmManager(repos ...mockSavepointRepo) *MockTrmManager {
+	return &MockTrmManager{repos: repos}
 }
 
 func (m *MockTrmManager) Do(ctx context.Context, f func(ctx context.Context) error) error {
@@ -206,7 +773,20 @@ func (m *MockTrmManager) Do(ctx context.Context, f func(ctx context.Context) err
 }
 
 func (m *MockTrmManager) DoWithSettings(
-	ctx context.Context, _ trm.Settings, f func(ctx context.Context) error,
+	ctx context.Context, s trm.Settings, f func(ctx context.Context) error,
 ) error {
-	return f(ctx)
+	if s.Propagation() != trm.PropagationNested {
+		return f(ctx)
+	}
+	rollbacks := make([]func(), len(m.repos))
+	for i, v := range m.repos {
+		rollbacks[i] = v.savepoint()
+	}
+	if err := f(ctx); err != nil {
+		for _, rollback := range rollbacks {
+			rollback()
+		}
+		return err
+	}
+	return nil
 }
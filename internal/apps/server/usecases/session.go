@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+type (
+	SessionUC struct {
+		logger      *zap.Logger
+		sessionRepo SessionRepo
+	}
+	SessionRepo interface {
+		Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (entities.Session, error)
+		GetAll(ctx context.Context, userID uuid.UUID) ([]entities.Session, error)
+		Create(ctx context.Context, session entities.Session) error
+		Touch(ctx context.Context, id uuid.UUID, seenAt time.Time, ip string) error
+		Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID, revokedAt time.Time) error
+		RevokeOthers(ctx context.Context, userID uuid.UUID, keepID uuid.UUID, revokedAt time.Time) (int64, error)
+	}
+)
+
+func NewSessionUC(
+	logger *zap.Logger,
+	sessionRepo SessionRepo,
+) *SessionUC {
+	return &SessionUC{
+		logger:      logger,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (uc *SessionUC) GetAll(
+	ctx context.Context,
+	request entities.GetSessionsRequest,
+) (response entities.GetSessionsResponse, err error) {
+	if request.UserID == uuid.Nil {
+		return response, fmt.Errorf("get_sessions: %w", entities.ErrUserIDInvalid)
+	}
+
+	sessions, err := uc.sessionRepo.GetAll(ctx, request.UserID)
+	if err != nil {
+		uc.logger.Error("failed to get sessions", zap.Error(err))
+		return response, fmt.Errorf("get_sessions: failed to get sessions: %w", err)
+	}
+	response.Sessions = make([]entities.GetSessionResponse, len(sessions))
+	for i, session := range sessions {
+		response.Sessions[i] = entities.GetSessionResponse{
+			Session: session,
+			Current: session.ID == request.SessionID,
+		}
+	}
+	return response, nil
+}
+
+// Revoke revokes the session, tokens of the session are rejected from now on
+func (uc *SessionUC) Revoke(ctx context.Context, request entities.RevokeSessionRequest) error {
+	switch {
+	case request.UserID == uuid.Nil:
+		return fmt.Errorf("revoke_session: %w", entities.ErrUserIDInvalid)
+	case request.ID == uuid.Nil:
+		return fmt.Errorf("revoke_session: %w", entities.ErrSessionIDInvalid)
+	}
+
+	err := uc.sessionRepo.Revoke(ctx, request.UserID, request.ID, time.Now().UTC())
+	switch {
+	case errors.Is(err, entities.ErrSessionNotFound):
+		return fmt.Errorf("revoke_session: %w", err)
+	case err != nil:
+		uc.logger.Error("failed to revoke session", zap.Error(err))
+		return fmt.Errorf("revoke_session: failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeOthers revokes all sessions of the user except the current one
+func (uc *SessionUC) RevokeOthers(
+	ctx context.Context,
+	request entities.RevokeOtherSessionsRequest,
+) (response entities.RevokeOtherSessionsResponse, err error) {
+	switch {
+	case request.UserID == uuid.Nil:
+		return response, fmt.Errorf("revoke_other_sessions: %w", entities.ErrUserIDInvalid)
+	case request.SessionID == uuid.Nil:
+		return response, fmt.Errorf("revoke_other_sessions: %w", entities.ErrSessionIDInvalid)
+	}
+
+	response.Revoked, err = uc.sessionRepo.RevokeOthers(ctx, request.UserID, request.SessionID, time.Now().UTC())
+	if err != nil {
+		uc.logger.Error("failed to revoke other sessions", zap.Error(err))
+		return response, fmt.Errorf("revoke_other_sessions: failed to revoke sessions: %w", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/pass"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/token"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"testing"
+	"time"
+)
+
+func TestSessionUC(t *testing.T) {
+	ctx := context.Background()
+	logger := zaptest.NewLogger(t)
+	sessionRepo := NewMockSessionRepo()
+	userUC := usecases.NewUserUC(
+		logger,
+		NewMockUserRepo(),
+		pass.NewHasher(0),
+		token.NewJWT([]byte("testsecret"), time.Minute, time.Hour),
+		sessionRepo,
+		NewMockTrmManager(),
+	)
+	sut := usecases.NewSessionUC(logger, sessionRepo)
+
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+	laptop, err := userUC.SignUp(ctx, creds, entities.Device{Name: "laptop", IP: "10.0.0.1"})
+	require.NoError(t, err, "failed to sign up")
+	phone, err := userUC.SignIn(ctx, creds, entities.Device{Name: "phone", IP: "10.0.0.2"})
+	require.NoError(t, err, "failed to sign in")
+	tablet, err := userUC.SignIn(ctx, creds, entities.Device{Name: "tablet", IP: "10.0.0.3"})
+	require.NoError(t, err, "failed to sign in")
+	current, err := userUC.Authenticate(ctx, laptop.Token)
+	require.NoError(t, err, "failed to authenticate")
+
+	_, err = sut.GetAll(ctx, entities.GetSessionsRequest{})
+	require.ErrorIs(t, err, entities.ErrUserIDInvalid, "user ID is required")
+	got, err := sut.GetAll(ctx, entities.GetSessionsRequest{UserID: current.UserID, SessionID: current.SessionID})
+	require.NoError(t, err, "failed to get sessions")
+	require.Len(t, got.Sessions, 3, "all sessions expected")
+	devices := make(map[string]bool, len(got.Sessions))
+	for _, v := range got.Sessions {
+		devices[v.Device] = v.Current
+	}
+	require.Equal(t, map[string]bool{"laptop": true, "phone": false, "tablet": false}, devices)
+
+	phoneClaims, err := userUC.Authenticate(ctx, phone.Token)
+	require.NoError(t, err, "failed to authenticate")
+	err = sut.Revoke(ctx, entities.RevokeSessionRequest{UserID: uuid.New(), ID: phoneClaims.SessionID})
+	require.ErrorIs(t, err, entities.ErrSessionNotFound, "session of another user should not be revoked")
+	err = sut.Revoke(ctx, entities.RevokeSessionRequest{UserID: current.UserID, ID: phoneClaims.SessionID})
+	require.NoError(t, err, "failed to revoke session")
+	_, err = userUC.Authenticate(ctx, phone.Token)
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "revoked session token should be rejected")
+	_, err = userUC.Refresh(ctx, phone.RefreshToken, "")
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "revoked session should not be refreshed")
+
+	revoked, err := sut.RevokeOthers(ctx, entities.RevokeOtherSessionsRequest{UserID: current.UserID, SessionID: current.SessionID})
+	require.NoError(t, err, "failed to revoke other sessions")
+	require.Equal(t, int64(1), revoked.Revoked, "only tablet session should be revoked")
+	_, err = userUC.Authenticate(ctx, tablet.Token)
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "other session token should be rejected")
+	_, err = userUC.Authenticate(ctx, laptop.Token)
+	require.NoError(t, err, "current session should be kept")
+}
@@ -4,35 +4,50 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
 	"github.com/dlomanov/gophkeeper/internal/core"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-)
-
-var (
-	emptyToken = entities.Token("")
+	"time"
 )
 
 type (
 	UserUC struct {
-		logger   *zap.Logger
-		userRepo UserRepo
-		pass     PassHasher
-		tokener  Tokener
+		logger      *zap.Logger
+		userRepo    UserRepo
+		pass        PassHasher
+		tokener     Tokener
+		sessionRepo SessionRepo
+		tx          trm.Manager
 	}
 	UserRepo interface {
 		Get(ctx context.Context, login entities.Login) (entities.User, error)
+		GetByID(ctx context.Context, id uuid.UUID) (entities.User, error)
 		Exists(ctx context.Context, login entities.Login) (bool, error)
 		Create(ctx context.Context, user entities.User) error
+		UpdatePass(ctx context.Context, id uuid.UUID, hash core.PassHash) error
+		SetVaultCheck(ctx context.Context, id uuid.UUID, check []byte) ([]byte, error)
+		SetVaultKey(ctx context.Context, id uuid.UUID, key []byte) error
+		Delete(ctx context.Context, id uuid.UUID) error
+		GetRefreshToken(ctx context.Context, hash string) (entities.RefreshToken, error)
+		CreateRefreshToken(ctx context.Context, token entities.RefreshToken) error
+		RevokeRefreshToken(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+		UpdateOTP(ctx context.Context, id uuid.UUID, otp entities.UserOTP) error
+		UseOTPStep(ctx context.Context, id uuid.UUID, step int64) error
+		TakeOTPAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, lockedUntil time.Time, now time.Time) error
+		ResetOTPAttempts(ctx context.Context, id uuid.UUID) error
+		ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entities.RecoveryCode) error
+		UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, usedAt time.Time) error
 	}
 	PassHasher interface {
 		Hash(password core.Pass) (core.PassHash, error)
 		Compare(password core.Pass, hash core.PassHash) bool
 	}
 	Tokener interface {
-		Create(id uuid.UUID) (entities.Token, error)
-		GetUserID(token entities.Token) (uuid.UUID, error)
+		Create(claims entities.TokenClaims) (entities.Token, error)
+		CreateRefresh(claims entities.TokenClaims) (entities.Token, *entities.RefreshToken, error)
+		GetClaims(token entities.Token) (entities.TokenClaims, error)
 	}
 )
 
@@ -41,34 +56,42 @@ func NewUserUC(
 	userRepo UserRepo,
 	pass PassHasher,
 	tokener Tokener,
+	sessionRepo SessionRepo,
+	tx trm.Manager,
 ) *UserUC {
 	return &UserUC{
-		logger:   logger,
-		userRepo: userRepo,
-		pass:     pass,
-		tokener:  tokener,
+		logger:      logger,
+		userRepo:    userRepo,
+		pass:        pass,
+		tokener:     tokener,
+		sessionRepo: sessionRepo,
+		tx:          tx,
 	}
 }
 
-func (uc *UserUC) SignUp(ctx context.Context, creds entities.Creds) (entities.Token, error) {
+func (uc *UserUC) SignUp(
+	ctx context.Context,
+	creds entities.Creds,
+	device entities.Device,
+) (auth entities.UserAuth, err error) {
 	if !creds.Valid() {
-		return emptyToken, entities.ErrUserCredsInvalid
+		return auth, entities.ErrUserCredsInvalid
 	}
 
 	exists, err := uc.userRepo.Exists(ctx, creds.Login)
 	switch {
 	case err != nil:
 		uc.logger.Error("failed to check user existence", zap.Error(err))
-		return emptyToken, err
+		return auth, err
 	case exists:
 		uc.logger.Debug("user already exists", zap.String("login", string(creds.Login)))
-		return emptyToken, entities.ErrUserExists
+		return auth, entities.ErrUserExists
 	}
 
 	passHash, err := uc.pass.Hash(creds.Pass)
 	if err != nil {
 		uc.logger.Debug("failed to calculate pass hash", zap.Error(err))
-		return emptyToken, err
+		return auth, err
 	}
 	user, err := entities.NewUser(entities.HashCreds{
 		Login:    creds.Login,
@@ -76,57 +99,189 @@ func (uc *UserUC) SignUp(ctx context.Context, creds entities.Creds) (entities.To
 	})
 	if err != nil {
 		uc.logger.Error("failed to request user", zap.Error(err))
-		return emptyToken, err
+		return auth, err
 	}
 	if err := uc.userRepo.Create(ctx, *user); err != nil {
 		uc.logger.Error("failed to request user", zap.Error(err))
 	}
-	token, err := uc.tokener.Create(user.ID)
-	if err != nil {
-		uc.logger.Debug("failed to request token", zap.Error(err))
-		return emptyToken, err
+	if auth, err = uc.openSession(ctx, user.ID, device); err != nil {
+		return auth, err
 	}
-	return token, nil
+	auth.VaultSalt = user.VaultSalt
+	return auth, nil
 }
 
 func (uc *UserUC) SignIn(
 	ctx context.Context,
 	creds entities.Creds,
-) (entities.Token, error) {
+	device entities.Device,
+) (auth entities.UserAuth, err error) {
 	if !creds.Valid() {
-		return emptyToken, entities.ErrUserCredsInvalid
+		return auth, entities.ErrUserCredsInvalid
 	}
 
 	user, err := uc.userRepo.Get(ctx, creds.Login)
 	if err != nil {
 		uc.logger.Debug("failed to get user", zap.Error(err))
-		return emptyToken, err
+		return auth, err
 	}
 	if !uc.pass.Compare(creds.Pass, user.PassHash) {
 		uc.logger.Debug("invalid credentials", zap.Error(err))
-		return emptyToken, entities.ErrUserCredsInvalid
+		return auth, entities.ErrUserCredsInvalid
 	}
-	token, err := uc.tokener.Create(user.ID)
-	if err != nil {
-		uc.logger.Error("failed to request token", zap.Error(err))
-		return emptyToken, err
+	if err = uc.checkOTP(ctx, user, creds.OTP); err != nil {
+		return auth, err
+	}
+	if auth, err = uc.openSession(ctx, user.ID, device); err != nil {
+		return auth, err
+	}
+	auth.VaultSalt = user.VaultSalt
+	auth.VaultCheck = user.VaultCheck
+	auth.VaultKey = user.VaultKey
+
+	return auth, nil
+}
+
+// Refresh exchanges the refresh token for a new token pair, the used refresh token is revoked.
+// Reuse of a revoked refresh token revokes the whole session.
+func (uc *UserUC) Refresh(
+	ctx context.Context,
+	refreshToken entities.Token,
+	ip string,
+) (auth entities.UserAuth, err error) {
+	if !refreshToken.Valid() {
+		return auth, entities.ErrUserRefreshInvalid
+	}
+
+	stored, err := uc.userRepo.GetRefreshToken(ctx, refreshToken.Hash())
+	switch {
+	case errors.Is(err, entities.ErrUserRefreshNotFound):
+		uc.logger.Debug("refresh token not found")
+		return auth, entities.ErrUserRefreshInvalid
+	case err != nil:
+		uc.logger.Error("failed to get refresh token", zap.Error(err))
+		return auth, err
+	}
+	session, err := uc.sessionRepo.Get(ctx, stored.UserID, stored.SessionID)
+	switch {
+	case errors.Is(err, entities.ErrSessionNotFound):
+		uc.logger.Debug("refresh token session not found")
+		return auth, entities.ErrUserRefreshInvalid
+	case err != nil:
+		uc.logger.Error("failed to get session", zap.Error(err))
+		return auth, err
+	case session.Revoked():
+		uc.logger.Debug("session revoked", zap.String("session_id", session.ID.String()))
+		return auth, entities.ErrSessionRevoked
+	}
+	now := time.Now().UTC()
+	switch {
+	case stored.Revoked():
+		uc.logger.Warn("revoked refresh token reused", zap.String("session_id", session.ID.String()))
+		if err = uc.sessionRepo.Revoke(ctx, session.UserID, session.ID, now); err != nil {
+			uc.logger.Error("failed to revoke session", zap.Error(err))
+			return auth, err
+		}
+		return auth, entities.ErrUserRefreshInvalid
+	case stored.Expired(now):
+		uc.logger.Debug("refresh token expired")
+		return auth, entities.ErrUserRefreshExpired
 	}
 
-	return token, nil
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		err := uc.userRepo.RevokeRefreshToken(ctx, stored.ID, now)
+		switch {
+		// concurrent refresh has already rotated the token
+		case errors.Is(err, entities.ErrUserRefreshNotFound):
+			return entities.ErrUserRefreshInvalid
+		case err != nil:
+			uc.logger.Error("failed to revoke refresh token", zap.Error(err))
+			return err
+		}
+		if err = uc.sessionRepo.Touch(ctx, session.ID, now, ip); err != nil {
+			uc.logger.Error("failed to update session", zap.Error(err))
+			return err
+		}
+		auth, err = uc.issue(ctx, entities.TokenClaims{UserID: session.UserID, SessionID: session.ID})
+		return err
+	})
+	if err != nil {
+		return entities.UserAuth{}, err
+	}
+	return auth, nil
 }
 
-func (uc *UserUC) GetUserID(_ context.Context, token entities.Token) (uuid.UUID, error) {
-	userID, err := uc.tokener.GetUserID(token)
+// Authenticate checks the token and its session, the session last seen time is updated on the way
+func (uc *UserUC) Authenticate(ctx context.Context, token entities.Token) (entities.TokenClaims, error) {
+	claims, err := uc.tokener.GetClaims(token)
 	switch {
 	case errors.Is(err, entities.ErrUserTokenInvalid):
 		uc.logger.Debug("invalid token", zap.Error(err))
-		return uuid.Nil, fmt.Errorf("user_usecase: %w", err)
+		return claims, fmt.Errorf("user_usecase: %w", err)
 	case errors.Is(err, entities.ErrUserTokenExpired):
 		uc.logger.Debug("token expired", zap.Error(err))
-		return uuid.Nil, fmt.Errorf("user_usecase: %w", err)
+		return claims, fmt.Errorf("user_usecase: %w", err)
 	case err != nil:
-		uc.logger.Error("failed to get userID from token", zap.Error(err))
-		return uuid.Nil, fmt.Errorf("user_usecase: failed to get userID from token: %w", err)
+		uc.logger.Error("failed to get claims from token", zap.Error(err))
+		return claims, fmt.Errorf("user_usecase: failed to get claims from token: %w", err)
+	}
+
+	session, err := uc.sessionRepo.Get(ctx, claims.UserID, claims.SessionID)
+	switch {
+	case errors.Is(err, entities.ErrSessionNotFound):
+		uc.logger.Debug("token session not found", zap.String("session_id", claims.SessionID.String()))
+		return claims, fmt.Errorf("user_usecase: %w", entities.ErrSessionRevoked)
+	case err != nil:
+		uc.logger.Error("failed to get session", zap.Error(err))
+		return claims, fmt.Errorf("user_usecase: failed to get session: %w", err)
+	case session.Revoked():
+		uc.logger.Debug("session revoked", zap.String("session_id", session.ID.String()))
+		return claims, fmt.Errorf("user_usecase: %w", entities.ErrSessionRevoked)
+	}
+	if now := time.Now().UTC(); session.Stale(now) {
+		if err = uc.sessionRepo.Touch(ctx, session.ID, now, ""); err != nil {
+			uc.logger.Error("failed to update session last seen", zap.Error(err))
+		}
+	}
+	return claims, nil
+}
+
+func (uc *UserUC) openSession(
+	ctx context.Context,
+	userID uuid.UUID,
+	device entities.Device,
+) (auth entities.UserAuth, err error) {
+	session := entities.NewSession(userID, device)
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err := uc.sessionRepo.Create(ctx, *session); err != nil {
+			uc.logger.Error("failed to create session", zap.Error(err))
+			return err
+		}
+		auth, err = uc.issue(ctx, entities.TokenClaims{UserID: userID, SessionID: session.ID})
+		return err
+	})
+	if err != nil {
+		return entities.UserAuth{}, err
+	}
+	return auth, nil
+}
+
+func (uc *UserUC) issue(ctx context.Context, claims entities.TokenClaims) (auth entities.UserAuth, err error) {
+	token, err := uc.tokener.Create(claims)
+	if err != nil {
+		uc.logger.Error("failed to request token", zap.Error(err))
+		return auth, err
+	}
+	refreshToken, stored, err := uc.tokener.CreateRefresh(claims)
+	if err != nil {
+		uc.logger.Error("failed to request refresh token", zap.Error(err))
+		return auth, err
+	}
+	if err = uc.userRepo.CreateRefreshToken(ctx, *stored); err != nil {
+		uc.logger.Error("failed to save refresh token", zap.Error(err))
+		return auth, err
 	}
-	return userID, nil
+	auth.Token = token
+	auth.RefreshToken = refreshToken
+	return auth, nil
 }
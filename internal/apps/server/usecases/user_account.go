@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ChangePassword replaces the password and revokes all sessions of the user,
+// the calling device gets a new session, so only it stays signed in.
+// The two-factor code is required when two-factor authentication is enabled.
+func (uc *UserUC) ChangePassword(
+	ctx context.Context,
+	request entities.ChangePasswordRequest,
+) (auth entities.UserAuth, err error) {
+	if len(request.New) == 0 || string(request.New) == string(request.Old) {
+		return auth, fmt.Errorf("change_password: %w", entities.ErrUserPassInvalid)
+	}
+	user, err := uc.getUser(ctx, request.UserID)
+	if err != nil {
+		return auth, fmt.Errorf("change_password: %w", err)
+	}
+	if !uc.pass.Compare(request.Old, user.PassHash) {
+		uc.logger.Debug("invalid current password", zap.String("user_id", user.ID.String()))
+		return auth, fmt.Errorf("change_password: %w", entities.ErrUserCredsInvalid)
+	}
+	if err = uc.checkOTP(ctx, user, request.OTP); err != nil {
+		return auth, fmt.Errorf("change_password: %w", err)
+	}
+	current, err := uc.sessionRepo.Get(ctx, user.ID, request.SessionID)
+	switch {
+	case errors.Is(err, entities.ErrSessionNotFound):
+		return auth, fmt.Errorf("change_password: %w", entities.ErrSessionRevoked)
+	case err != nil:
+		uc.logger.Error("failed to get session", zap.Error(err))
+		return auth, fmt.Errorf("change_password: failed to get session: %w", err)
+	}
+
+	passHash, err := uc.pass.Hash(request.New)
+	if err != nil {
+		uc.logger.Debug("failed to calculate pass hash", zap.Error(err))
+		return auth, fmt.Errorf("change_password: %w", err)
+	}
+	session := entities.NewSession(user.ID, entities.Device{Name: current.Device, IP: current.IP})
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.UpdatePass(ctx, user.ID, passHash); err != nil {
+			uc.logger.Error("failed to update password", zap.Error(err))
+			return err
+		}
+		if err := uc.sessionRepo.Create(ctx, *session); err != nil {
+			uc.logger.Error("failed to create session", zap.Error(err))
+			return err
+		}
+		revoked, err := uc.sessionRepo.RevokeOthers(ctx, user.ID, session.ID, time.Now().UTC())
+		if err != nil {
+			uc.logger.Error("failed to revoke sessions", zap.Error(err))
+			return err
+		}
+		uc.logger.Debug("sessions revoked on password change",
+			zap.String("user_id", user.ID.String()),
+			zap.Int64("revoked", revoked))
+		auth, err = uc.issue(ctx, entities.TokenClaims{UserID: user.ID, SessionID: session.ID})
+		return err
+	})
+	if err != nil {
+		return entities.UserAuth{}, fmt.Errorf("change_password: %w", err)
+	}
+	auth.VaultSalt = user.VaultSalt
+	return auth, nil
+}
+
+// SetVaultCheck sets the vault check once, the stored check is returned to be verified by the device
+func (uc *UserUC) SetVaultCheck(ctx context.Context, request entities.SetVaultCheckRequest) ([]byte, error) {
+	if len(request.VaultCheck) == 0 || len(request.VaultCheck) > entities.UserVaultCheckMaxSize {
+		return nil, fmt.Errorf("set_vault_check: %w", entities.ErrUserVaultCheckInvalid)
+	}
+	user, err := uc.getUser(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("set_vault_check: %w", err)
+	}
+	if len(user.VaultCheck) != 0 {
+		return user.VaultCheck, nil
+	}
+	check, err := uc.userRepo.SetVaultCheck(ctx, user.ID, request.VaultCheck)
+	if err != nil {
+		uc.logger.Error("failed to set vault check", zap.Error(err))
+		return nil, fmt.Errorf("set_vault_check: %w", err)
+	}
+	return check, nil
+}
+
+// SetVaultKey replaces the vault key wrapped by the device on the master password change
+func (uc *UserUC) SetVaultKey(ctx context.Context, request entities.SetVaultKeyRequest) error {
+	if len(request.VaultKey) > entities.UserVaultKeyMaxSize {
+		return fmt.Errorf("set_vault_key: %w", entities.ErrUserVaultKeyInvalid)
+	}
+	if err := uc.userRepo.SetVaultKey(ctx, request.UserID, request.VaultKey); err != nil {
+		if !errors.Is(err, entities.ErrUserNotFound) {
+			uc.logger.Error("failed to set vault key", zap.Error(err))
+		}
+		return fmt.Errorf("set_vault_key: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount removes the user with all entries, history and sessions,
+// the password and the two-factor code, when it's enabled, are required
+func (uc *UserUC) DeleteAccount(ctx context.Context, request entities.DeleteAccountRequest) error {
+	user, err := uc.getUser(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("delete_account: %w", err)
+	}
+	if !uc.pass.Compare(request.Pass, user.PassHash) {
+		uc.logger.Debug("invalid password", zap.String("user_id", user.ID.String()))
+		return fmt.Errorf("delete_account: %w", entities.ErrUserCredsInvalid)
+	}
+	if err = uc.checkOTP(ctx, user, request.OTP); err != nil {
+		return fmt.Errorf("delete_account: %w", err)
+	}
+
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		return uc.userRepo.Delete(ctx, user.ID)
+	})
+	if err != nil {
+		uc.logger.Error("failed to delete account", zap.Error(err))
+		return fmt.Errorf("delete_account: failed to delete user: %w", err)
+	}
+	uc.logger.Info("account deleted", zap.String("user_id", user.ID.String()))
+	return nil
+}
@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dlomanov/gophkeeper/internal/apps/server/entities"
+	"github.com/dlomanov/gophkeeper/internal/infra/otp"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+// EnrollOTP generates a pending two-factor secret, it's enabled only after ConfirmOTP
+func (uc *UserUC) EnrollOTP(
+	ctx context.Context,
+	request entities.EnrollOTPRequest,
+) (response entities.EnrollOTPResponse, err error) {
+	user, err := uc.getUser(ctx, request.UserID)
+	switch {
+	case err != nil:
+		return response, fmt.Errorf("enroll_otp: %w", err)
+	case user.OTP.Enabled:
+		return response, fmt.Errorf("enroll_otp: %w", entities.ErrUserOTPEnabled)
+	}
+
+	secret, err := otp.GenerateSecret()
+	if err != nil {
+		uc.logger.Error("failed to generate two-factor secret", zap.Error(err))
+		return response, fmt.Errorf("enroll_otp: %w", err)
+	}
+	if err = uc.userRepo.UpdateOTP(ctx, user.ID, entities.UserOTP{Secret: secret}); err != nil {
+		uc.logger.Error("failed to save two-factor secret", zap.Error(err))
+		return response, fmt.Errorf("enroll_otp: failed to save secret: %w", err)
+	}
+
+	response.Secret = secret
+	response.URI = otp.Key{
+		Issuer:  entities.UserOTPIssuer,
+		Account: string(user.Login),
+		Secret:  secret,
+	}.URI()
+	return response, nil
+}
+
+// ConfirmOTP enables two-factor authentication when the code matches the pending secret.
+// Recovery codes are returned once, previous ones are dropped.
+func (uc *UserUC) ConfirmOTP(
+	ctx context.Context,
+	request entities.ConfirmOTPRequest,
+) (response entities.ConfirmOTPResponse, err error) {
+	user, err := uc.getUser(ctx, request.UserID)
+	switch {
+	case err != nil:
+		return response, fmt.Errorf("confirm_otp: %w", err)
+	case user.OTP.Enabled:
+		return response, fmt.Errorf("confirm_otp: %w", entities.ErrUserOTPEnabled)
+	case user.OTP.Secret == "":
+		return response, fmt.Errorf("confirm_otp: %w", entities.ErrUserOTPNotEnrolled)
+	}
+
+	step, ok, err := otp.Validate(user.OTP.Secret, request.Code, time.Now())
+	switch {
+	case err != nil:
+		uc.logger.Error("failed to validate two-factor code", zap.Error(err))
+		return response, fmt.Errorf("confirm_otp: %w", err)
+	case !ok:
+		return response, fmt.Errorf("confirm_otp: %w", entities.ErrUserOTPInvalid)
+	}
+
+	codes, stored, err := entities.NewRecoveryCodes(user.ID)
+	if err != nil {
+		uc.logger.Error("failed to generate recovery codes", zap.Error(err))
+		return response, fmt.Errorf("confirm_otp: %w", err)
+	}
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		err := uc.userRepo.UpdateOTP(ctx, user.ID, entities.UserOTP{
+			Secret:   user.OTP.Secret,
+			Enabled:  true,
+			LastStep: step,
+		})
+		if err != nil {
+			uc.logger.Error("failed to enable two-factor authentication", zap.Error(err))
+			return err
+		}
+		if err = uc.userRepo.ReplaceRecoveryCodes(ctx, user.ID, stored); err != nil {
+			uc.logger.Error("failed to save recovery codes", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return response, fmt.Errorf("confirm_otp: %w", err)
+	}
+
+	response.RecoveryCodes = codes
+	return response, nil
+}
+
+// DisableOTP turns two-factor authentication off, a two-factor code or a recovery code is required
+func (uc *UserUC) DisableOTP(ctx context.Context, request entities.DisableOTPRequest) error {
+	user, err := uc.getUser(ctx, request.UserID)
+	switch {
+	case err != nil:
+		return fmt.Errorf("disable_otp: %w", err)
+	case !user.OTP.Enabled:
+		return fmt.Errorf("disable_otp: %w", entities.ErrUserOTPNotEnabled)
+	}
+	if err = uc.verifyOTP(ctx, user, request.Code); err != nil {
+		return fmt.Errorf("disable_otp: %w", err)
+	}
+
+	err = uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.UpdateOTP(ctx, user.ID, entities.UserOTP{}); err != nil {
+			uc.logger.Error("failed to disable two-factor authentication", zap.Error(err))
+			return err
+		}
+		if err := uc.userRepo.ReplaceRecoveryCodes(ctx, user.ID, nil); err != nil {
+			uc.logger.Error("failed to drop recovery codes", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("disable_otp: %w", err)
+	}
+	return nil
+}
+
+// checkOTP requires the two-factor code only when two-factor authentication is enabled
+func (uc *UserUC) checkOTP(ctx context.Context, user entities.User, code string) error {
+	switch {
+	case !user.OTP.Enabled:
+		return nil
+	case code == "":
+		uc.logger.Debug("two-factor code required", zap.String("user_id", user.ID.String()))
+		return entities.ErrUserOTPRequired
+	}
+	return uc.verifyOTP(ctx, user, code)
+}
+
+// verifyOTP accepts a two-factor code once per time step, otherwise the code is checked as a recovery code.
+// The attempt is taken before the check, so the codes can't be guessed by concurrent requests
+// after the attempts limit is reached.
+func (uc *UserUC) verifyOTP(ctx context.Context, user entities.User, code string) error {
+	now := time.Now().UTC()
+	err := uc.userRepo.TakeOTPAttempt(ctx, user.ID, entities.UserOTPMaxAttempts, now.Add(entities.UserOTPLockout), now)
+	switch {
+	case errors.Is(err, entities.ErrUserOTPLocked):
+		uc.logger.Warn("two-factor attempts exceeded", zap.String("user_id", user.ID.String()))
+		return err
+	case err != nil:
+		uc.logger.Error("failed to take two-factor attempt", zap.Error(err))
+		return err
+	}
+	if err = uc.useOTP(ctx, user, code, now); err != nil {
+		return err
+	}
+	if err = uc.userRepo.ResetOTPAttempts(ctx, user.ID); err != nil {
+		uc.logger.Error("failed to reset two-factor attempts", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (uc *UserUC) useOTP(ctx context.Context, user entities.User, code string, now time.Time) error {
+	step, ok, err := otp.Validate(user.OTP.Secret, code, now)
+	if err != nil {
+		uc.logger.Error("failed to validate two-factor code", zap.Error(err))
+		return err
+	}
+	if ok {
+		err = uc.userRepo.UseOTPStep(ctx, user.ID, step)
+		switch {
+		case errors.Is(err, entities.ErrUserOTPInvalid):
+			uc.logger.Debug("two-factor code reused", zap.String("user_id", user.ID.String()))
+			return err
+		case err != nil:
+			uc.logger.Error("failed to save two-factor step", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	err = uc.userRepo.UseRecoveryCode(ctx, user.ID, entities.RecoveryCodeHash(code), now)
+	switch {
+	case errors.Is(err, entities.ErrUserRecoveryCodeNotFound):
+		uc.logger.Debug("invalid two-factor code", zap.String("user_id", user.ID.String()))
+		return entities.ErrUserOTPInvalid
+	case err != nil:
+		uc.logger.Error("failed to use recovery code", zap.Error(err))
+		return err
+	}
+	uc.logger.Info("recovery code used", zap.String("user_id", user.ID.String()))
+	return nil
+}
+
+func (uc *UserUC) getUser(ctx context.Context, id uuid.UUID) (entities.User, error) {
+	if id == uuid.Nil {
+		return entities.User{}, entities.ErrUserIDInvalid
+	}
+	user, err := uc.userRepo.GetByID(ctx, id)
+	switch {
+	case errors.Is(err, entities.ErrUserNotFound):
+		return user, err
+	case err != nil:
+		uc.logger.Error("failed to get user", zap.Error(err))
+		return user, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/pass"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/infra/services/token"
 	"github.com/dlomanov/gophkeeper/internal/apps/server/usecases"
+	"github.com/dlomanov/gophkeeper/internal/infra/otp"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -144,27 +146,30 @@ func TestUserUC(t *testing.T) {
 		},
 	}
 
-	tokener := token.NewJWT([]byte("testsecret"), time.Minute)
+	tokener := token.NewJWT([]byte("testsecret"), time.Minute, time.Hour)
 	uc := usecases.NewUserUC(
 		zaptest.NewLogger(t),
 		NewMockUserRepo(),
 		pass.NewHasher(0),
 		tokener,
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
 	)
 	ctx := context.Background()
+	device := entities.Device{Name: "laptop", IP: "127.0.0.1"}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
-				gotToken entities.Token
-				err      error
+				gotAuth entities.UserAuth
+				err     error
 			)
 
 			switch tt.args.action {
 			case ActionRegister:
-				gotToken, err = uc.SignUp(ctx, tt.args.creds)
+				gotAuth, err = uc.SignUp(ctx, tt.args.creds, device)
 			case ActionLogin:
-				gotToken, err = uc.SignIn(ctx, tt.args.creds)
+				gotAuth, err = uc.SignIn(ctx, tt.args.creds, device)
 			default:
 				t.Fatalf("unknown action type: %s", tt.args.action)
 			}
@@ -174,11 +179,345 @@ func TestUserUC(t *testing.T) {
 			}
 
 			require.NoErrorf(t, err, "%s: unexpected error occured: '%v'", tt.args.action, err)
-			require.NotEmptyf(t, gotToken, "%s: token should not be empty", tt.args.action)
+			require.NotEmptyf(t, gotAuth.Token, "%s: token should not be empty", tt.args.action)
+			require.NotEmptyf(t, gotAuth.RefreshToken, "%s: refresh token should not be empty", tt.args.action)
+			require.Lenf(t, gotAuth.VaultSalt, entities.UserVaultSaltSize, "%s: vault salt should be returned", tt.args.action)
 
-			userID, err := tokener.GetUserID(gotToken)
+			claims, err := uc.Authenticate(ctx, gotAuth.Token)
 			require.NoErrorf(t, err, "%s: error '%v' occured while extracting userID from token", tt.args.action, err)
-			require.NotEmptyf(t, uuid.UUID(userID), "%s: userID should not be empty", tt.args.action)
+			require.NotEmptyf(t, claims.UserID, "%s: userID should not be empty", tt.args.action)
+			require.NotEmptyf(t, claims.SessionID, "%s: sessionID should not be empty", tt.args.action)
 		})
 	}
 }
+
+func TestUserUC_Refresh(t *testing.T) {
+	ctx := context.Background()
+	tokener := token.NewJWT([]byte("testsecret"), time.Minute, time.Hour)
+	userRepo := NewMockUserRepo()
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		userRepo,
+		pass.NewHasher(0),
+		tokener,
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+	device := entities.Device{Name: "laptop", IP: "127.0.0.1"}
+
+	_, err := uc.Refresh(ctx, "", "")
+	require.ErrorIs(t, err, entities.ErrUserRefreshInvalid, "empty refresh token should be invalid")
+	_, err = uc.Refresh(ctx, "unknown", "")
+	require.ErrorIs(t, err, entities.ErrUserRefreshInvalid, "unknown refresh token should be invalid")
+
+	auth, err := uc.SignUp(ctx, creds, device)
+	require.NoError(t, err, "failed to sign up")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+
+	refreshed, err := uc.Refresh(ctx, auth.RefreshToken, "127.0.0.2")
+	require.NoError(t, err, "failed to refresh token")
+	require.NotEqual(t, auth.RefreshToken, refreshed.RefreshToken, "refresh token should be rotated")
+	refreshedClaims, err := uc.Authenticate(ctx, refreshed.Token)
+	require.NoError(t, err, "failed to authenticate refreshed token")
+	require.Equal(t, claims, refreshedClaims, "refreshed token should belong to the same session")
+
+	rotated, err := uc.Refresh(ctx, refreshed.RefreshToken, "")
+	require.NoError(t, err, "failed to refresh rotated token")
+
+	_, err = uc.Refresh(ctx, auth.RefreshToken, "")
+	require.ErrorIs(t, err, entities.ErrUserRefreshInvalid, "reused refresh token should be invalid")
+	_, err = uc.Refresh(ctx, rotated.RefreshToken, "")
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "reuse should revoke the session")
+	_, err = uc.Authenticate(ctx, rotated.Token)
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "tokens of revoked session should be rejected")
+
+	auth, err = uc.SignIn(ctx, creds, device)
+	require.NoError(t, err, "failed to sign in")
+	claims, err = uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+	expired, stored, err := entities.NewRefreshToken(claims, -time.Minute)
+	require.NoError(t, err, "failed to create refresh token")
+	require.NoError(t, userRepo.CreateRefreshToken(ctx, *stored), "failed to save refresh token")
+	_, err = uc.Refresh(ctx, expired, "")
+	require.ErrorIs(t, err, entities.ErrUserRefreshExpired, "expired refresh token expected")
+}
+
+func TestUserUC_OTP(t *testing.T) {
+	ctx := context.Background()
+	tokener := token.NewJWT([]byte("testsecret"), time.Minute, time.Hour)
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		NewMockUserRepo(),
+		pass.NewHasher(0),
+		tokener,
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+	device := entities.Device{Name: "laptop", IP: "127.0.0.1"}
+
+	auth, err := uc.SignUp(ctx, creds, device)
+	require.NoError(t, err, "failed to sign up")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+
+	_, err = uc.ConfirmOTP(ctx, entities.ConfirmOTPRequest{UserID: claims.UserID, Code: "123456"})
+	require.ErrorIs(t, err, entities.ErrUserOTPNotEnrolled, "confirm without enrollment should fail")
+	enrolled, err := uc.EnrollOTP(ctx, entities.EnrollOTPRequest{UserID: claims.UserID})
+	require.NoError(t, err, "failed to enroll")
+	require.NotEmpty(t, enrolled.Secret, "secret expected")
+	key, err := otp.ParseURI(enrolled.URI)
+	require.NoError(t, err, "failed to parse provisioning uri")
+	require.Equal(t, enrolled.Secret, key.Secret, "uri should contain the secret")
+	require.Equal(t, "admin", key.Account, "uri should contain the login")
+
+	_, err = uc.SignIn(ctx, creds, device)
+	require.NoError(t, err, "pending enrollment shouldn't require code")
+
+	_, err = uc.ConfirmOTP(ctx, entities.ConfirmOTPRequest{UserID: claims.UserID, Code: "000000x"})
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "invalid code should be rejected")
+	code, err := otp.Code(enrolled.Secret, time.Now().Add(-otp.Period))
+	require.NoError(t, err, "failed to generate code")
+	confirmed, err := uc.ConfirmOTP(ctx, entities.ConfirmOTPRequest{UserID: claims.UserID, Code: code})
+	require.NoError(t, err, "failed to confirm")
+	require.Len(t, confirmed.RecoveryCodes, entities.UserRecoveryCodesCount, "recovery codes expected")
+	_, err = uc.EnrollOTP(ctx, entities.EnrollOTPRequest{UserID: claims.UserID})
+	require.ErrorIs(t, err, entities.ErrUserOTPEnabled, "enabled two-factor can't be enrolled again")
+
+	_, err = uc.SignIn(ctx, creds, device)
+	require.ErrorIs(t, err, entities.ErrUserOTPRequired, "code should be required")
+	withCode := creds
+	withCode.OTP = code
+	_, err = uc.SignIn(ctx, withCode, device)
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "code used for confirmation should be rejected")
+	withCode.OTP, err = otp.Code(enrolled.Secret, time.Now())
+	require.NoError(t, err, "failed to generate code")
+	_, err = uc.SignIn(ctx, withCode, device)
+	require.NoError(t, err, "failed to sign in with code")
+	_, err = uc.SignIn(ctx, withCode, device)
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "code replay should be rejected")
+
+	withCode.OTP = strings.ToUpper(confirmed.RecoveryCodes[0])
+	_, err = uc.SignIn(ctx, withCode, device)
+	require.NoError(t, err, "failed to sign in with recovery code")
+	_, err = uc.SignIn(ctx, withCode, device)
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "recovery code should be used once")
+
+	err = uc.DisableOTP(ctx, entities.DisableOTPRequest{UserID: claims.UserID, Code: confirmed.RecoveryCodes[0]})
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "used recovery code should be rejected")
+	err = uc.DisableOTP(ctx, entities.DisableOTPRequest{UserID: claims.UserID, Code: confirmed.RecoveryCodes[1]})
+	require.NoError(t, err, "failed to disable")
+	_, err = uc.SignIn(ctx, creds, device)
+	require.NoError(t, err, "code shouldn't be required after disabling")
+	err = uc.DisableOTP(ctx, entities.DisableOTPRequest{UserID: claims.UserID, Code: code})
+	require.ErrorIs(t, err, entities.ErrUserOTPNotEnabled, "disabled two-factor can't be disabled again")
+}
+
+func TestUserUC_OTPAttempts(t *testing.T) {
+	ctx := context.Background()
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		NewMockUserRepo(),
+		pass.NewHasher(0),
+		token.NewJWT([]byte("testsecret"), time.Minute, time.Hour),
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+	device := entities.Device{Name: "laptop"}
+
+	auth, err := uc.SignUp(ctx, creds, device)
+	require.NoError(t, err, "failed to sign up")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+	enrolled, err := uc.EnrollOTP(ctx, entities.EnrollOTPRequest{UserID: claims.UserID})
+	require.NoError(t, err, "failed to enroll")
+	code, err := otp.Code(enrolled.Secret, time.Now().Add(-otp.Period))
+	require.NoError(t, err, "failed to generate code")
+	_, err = uc.ConfirmOTP(ctx, entities.ConfirmOTPRequest{UserID: claims.UserID, Code: code})
+	require.NoError(t, err, "failed to confirm")
+
+	invalid := creds
+	invalid.OTP = "invalid"
+	for range entities.UserOTPMaxAttempts - 1 {
+		_, err = uc.SignIn(ctx, invalid, device)
+		require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "invalid code should be rejected")
+	}
+	valid := creds
+	valid.OTP, err = otp.Code(enrolled.Secret, time.Now())
+	require.NoError(t, err, "failed to generate code")
+	_, err = uc.SignIn(ctx, valid, device)
+	require.NoError(t, err, "last allowed attempt should be checked")
+
+	for range entities.UserOTPMaxAttempts {
+		_, err = uc.SignIn(ctx, invalid, device)
+		require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "attempts should be reset by valid code")
+	}
+	_, err = uc.SignIn(ctx, valid, device)
+	require.ErrorIs(t, err, entities.ErrUserOTPLocked, "user should be locked out")
+	err = uc.DisableOTP(ctx, entities.DisableOTPRequest{UserID: claims.UserID, Code: "invalid"})
+	require.ErrorIs(t, err, entities.ErrUserOTPLocked, "locked out user can't disable two-factor")
+}
+
+func TestUserUC_AccountOTP(t *testing.T) {
+	ctx := context.Background()
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		NewMockUserRepo(),
+		pass.NewHasher(0),
+		token.NewJWT([]byte("testsecret"), time.Minute, time.Hour),
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+
+	auth, err := uc.SignUp(ctx, creds, entities.Device{Name: "laptop"})
+	require.NoError(t, err, "failed to sign up")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+	enrolled, err := uc.EnrollOTP(ctx, entities.EnrollOTPRequest{UserID: claims.UserID})
+	require.NoError(t, err, "failed to enroll")
+	code, err := otp.Code(enrolled.Secret, time.Now().Add(-otp.Period))
+	require.NoError(t, err, "failed to generate code")
+	confirmed, err := uc.ConfirmOTP(ctx, entities.ConfirmOTPRequest{UserID: claims.UserID, Code: code})
+	require.NoError(t, err, "failed to confirm")
+
+	request := entities.ChangePasswordRequest{
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Old:       creds.Pass,
+		New:       []byte("2"),
+	}
+	_, err = uc.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserOTPRequired, "code should be required to change password")
+	request.OTP = "invalid"
+	_, err = uc.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "invalid code should be rejected")
+	request.OTP = confirmed.RecoveryCodes[0]
+	changed, err := uc.ChangePassword(ctx, request)
+	require.NoError(t, err, "failed to change password")
+	changedClaims, err := uc.Authenticate(ctx, changed.Token)
+	require.NoError(t, err, "failed to authenticate")
+
+	deleteRequest := entities.DeleteAccountRequest{UserID: changedClaims.UserID, Pass: request.New}
+	err = uc.DeleteAccount(ctx, deleteRequest)
+	require.ErrorIs(t, err, entities.ErrUserOTPRequired, "code should be required to delete account")
+	deleteRequest.OTP = confirmed.RecoveryCodes[0]
+	err = uc.DeleteAccount(ctx, deleteRequest)
+	require.ErrorIs(t, err, entities.ErrUserOTPInvalid, "used recovery code should be rejected")
+	deleteRequest.OTP = confirmed.RecoveryCodes[1]
+	require.NoError(t, uc.DeleteAccount(ctx, deleteRequest), "failed to delete account")
+}
+
+func TestUserUC_Account(t *testing.T) {
+	ctx := context.Background()
+	tokener := token.NewJWT([]byte("testsecret"), time.Minute, time.Hour)
+	userRepo := NewMockUserRepo()
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		userRepo,
+		pass.NewHasher(0),
+		tokener,
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+
+	auth, err := uc.SignUp(ctx, creds, entities.Device{Name: "laptop"})
+	require.NoError(t, err, "failed to sign up")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+	other, err := uc.SignIn(ctx, creds, entities.Device{Name: "phone"})
+	require.NoError(t, err, "failed to sign in")
+
+	request := entities.ChangePasswordRequest{
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Old:       []byte("2"),
+		New:       []byte("3"),
+	}
+	_, err = uc.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "current password should be checked")
+	request.Old = creds.Pass
+	request.New = creds.Pass
+	_, err = uc.ChangePassword(ctx, request)
+	require.ErrorIs(t, err, entities.ErrUserPassInvalid, "new password should differ")
+	request.New = []byte("3")
+	changed, err := uc.ChangePassword(ctx, request)
+	require.NoError(t, err, "failed to change password")
+
+	_, err = uc.Authenticate(ctx, auth.Token)
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "token of the current session should be replaced")
+	_, err = uc.Authenticate(ctx, other.Token)
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "tokens of other devices should be rejected")
+	_, err = uc.Refresh(ctx, other.RefreshToken, "")
+	require.ErrorIs(t, err, entities.ErrSessionRevoked, "refresh tokens of other devices should be rejected")
+	changedClaims, err := uc.Authenticate(ctx, changed.Token)
+	require.NoError(t, err, "new token should be accepted")
+	_, err = uc.SignIn(ctx, creds, entities.Device{})
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "old password should be rejected")
+	creds.Pass = request.New
+	_, err = uc.SignIn(ctx, creds, entities.Device{})
+	require.NoError(t, err, "failed to sign in with new password")
+
+	err = uc.DeleteAccount(ctx, entities.DeleteAccountRequest{UserID: changedClaims.UserID, Pass: []byte("1")})
+	require.ErrorIs(t, err, entities.ErrUserCredsInvalid, "password should be checked")
+	err = uc.DeleteAccount(ctx, entities.DeleteAccountRequest{UserID: changedClaims.UserID, Pass: creds.Pass})
+	require.NoError(t, err, "failed to delete account")
+	_, err = uc.SignIn(ctx, creds, entities.Device{})
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "deleted user shouldn't sign in")
+	_, err = uc.Refresh(ctx, changed.RefreshToken, "")
+	require.ErrorIs(t, err, entities.ErrUserRefreshInvalid, "refresh tokens should be deleted")
+	err = uc.DeleteAccount(ctx, entities.DeleteAccountRequest{UserID: changedClaims.UserID, Pass: creds.Pass})
+	require.ErrorIs(t, err, entities.ErrUserNotFound, "deleted user can't be deleted again")
+}
+
+func TestUserUC_VaultCheck(t *testing.T) {
+	ctx := context.Background()
+	uc := usecases.NewUserUC(
+		zaptest.NewLogger(t),
+		NewMockUserRepo(),
+		pass.NewHasher(0),
+		token.NewJWT([]byte("testsecret"), time.Minute, time.Hour),
+		NewMockSessionRepo(),
+		NewMockTrmManager(),
+	)
+	creds := entities.Creds{Login: "admin", Pass: []byte("1")}
+
+	auth, err := uc.SignUp(ctx, creds, entities.Device{Name: "laptop"})
+	require.NoError(t, err, "failed to sign up")
+	require.Empty(t, auth.VaultCheck, "new user should have no vault check")
+	claims, err := uc.Authenticate(ctx, auth.Token)
+	require.NoError(t, err, "failed to authenticate")
+
+	_, err = uc.SetVaultCheck(ctx, entities.SetVaultCheckRequest{UserID: claims.UserID})
+	require.ErrorIs(t, err, entities.ErrUserVaultCheckInvalid, "empty vault check should be rejected")
+	check, err := uc.SetVaultCheck(ctx, entities.SetVaultCheckRequest{UserID: claims.UserID, VaultCheck: []byte("check1")})
+	require.NoError(t, err, "failed to set vault check")
+	require.Equal(t, []byte("check1"), check)
+	check, err = uc.SetVaultCheck(ctx, entities.SetVaultCheckRequest{UserID: claims.UserID, VaultCheck: []byte("check2")})
+	require.NoError(t, err, "failed to set vault check")
+	require.Equal(t, []byte("check1"), check, "vault check should be set once")
+
+	auth, err = uc.SignIn(ctx, creds, entities.Device{Name: "phone"})
+	require.NoError(t, err, "failed to sign in")
+	require.Equal(t, []byte("check1"), auth.VaultCheck, "vault check should be returned on sign in")
+	require.Empty(t, auth.VaultKey, "vault key should not be wrapped until master password is changed")
+
+	err = uc.SetVaultKey(ctx, entities.SetVaultKeyRequest{
+		UserID:   claims.UserID,
+		VaultKey: make([]byte, entities.UserVaultKeyMaxSize+1),
+	})
+	require.ErrorIs(t, err, entities.ErrUserVaultKeyInvalid, "oversized vault key should be rejected")
+	err = uc.SetVaultKey(ctx, entities.SetVaultKeyRequest{UserID: uuid.New(), VaultKey: []byte("key")})
+	require.ErrorIs(t, err, entities.ErrUserNotFound)
+	for _, key := range [][]byte{[]byte("key1"), []byte("key2")} {
+		err = uc.SetVaultKey(ctx, entities.SetVaultKeyRequest{UserID: claims.UserID, VaultKey: key})
+		require.NoError(t, err, "failed to set vault key")
+	}
+	auth, err = uc.SignIn(ctx, creds, entities.Device{Name: "tablet"})
+	require.NoError(t, err, "failed to sign in")
+	require.Equal(t, []byte("key2"), auth.VaultKey, "vault key should be replaced and returned on sign in")
+}
@@ -7,36 +7,33 @@ import (
 
 type EntryMapper struct{}
 
-func (EntryMapper) ToEntityType(t pb.EntryType) core.EntryType {
-	switch t {
-	case pb.EntryType_ENTRY_TYPE_UNSPECIFIED:
-		return core.EntryTypeUnspecified
-	case pb.EntryType_ENTRY_TYPE_PASSWORD:
-		return core.EntryTypePassword
-	case pb.EntryType_ENTRY_TYPE_NOTE:
-		return core.EntryTypeNote
-	case pb.EntryType_ENTRY_TYPE_CARD:
-		return core.EntryTypeCard
-	case pb.EntryType_ENTRY_TYPE_BINARY:
-		return core.EntryTypeBinary
-	default:
-		return core.EntryTypeUnspecified
+// apiTypes are the enum values of the types known before the type names were sent,
+// the types registered later are sent by name only, see core.RegisterEntrySchema
+var apiTypes = map[core.EntryType]pb.EntryType{
+	core.EntryTypePassword: pb.EntryType_ENTRY_TYPE_PASSWORD,
+	core.EntryTypeNote:     pb.EntryType_ENTRY_TYPE_NOTE,
+	core.EntryTypeCard:     pb.EntryType_ENTRY_TYPE_CARD,
+	core.EntryTypeBinary:   pb.EntryType_ENTRY_TYPE_BINARY,
+	core.EntryTypeTOTP:     pb.EntryType_ENTRY_TYPE_TOTP,
+}
+
+// ToEntityType returns the type by name, the enum is used when the name isn't sent
+func (EntryMapper) ToEntityType(t pb.EntryType, name string) core.EntryType {
+	if name != "" {
+		return core.EntryType(name)
+	}
+	for k, v := range apiTypes {
+		if v == t {
+			return k
+		}
 	}
+	return core.EntryTypeUnspecified
 }
 
+// ToAPIType returns the enum value of the type, ENTRY_TYPE_UNSPECIFIED for the types sent by name only
 func (EntryMapper) ToAPIType(t core.EntryType) pb.EntryType {
-	switch t {
-	case core.EntryTypeUnspecified:
-		return pb.EntryType_ENTRY_TYPE_UNSPECIFIED
-	case core.EntryTypePassword:
-		return pb.EntryType_ENTRY_TYPE_PASSWORD
-	case core.EntryTypeNote:
-		return pb.EntryType_ENTRY_TYPE_NOTE
-	case core.EntryTypeCard:
-		return pb.EntryType_ENTRY_TYPE_CARD
-	case core.EntryTypeBinary:
-		return pb.EntryType_ENTRY_TYPE_BINARY
-	default:
-		return pb.EntryType_ENTRY_TYPE_UNSPECIFIED
+	if v, ok := apiTypes[t]; ok {
+		return v
 	}
+	return pb.EntryType_ENTRY_TYPE_UNSPECIFIED
 }
@@ -19,8 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	UserService_SignUp_FullMethodName = "/proto.UserService/SignUp"
-	UserService_SignIn_FullMethodName = "/proto.UserService/SignIn"
+	UserService_SignUp_FullMethodName         = "/proto.UserService/SignUp"
+	UserService_SignIn_FullMethodName         = "/proto.UserService/SignIn"
+	UserService_Refresh_FullMethodName        = "/proto.UserService/Refresh"
+	UserService_ChangePassword_FullMethodName = "/proto.UserService/ChangePassword"
+	UserService_DeleteAccount_FullMethodName  = "/proto.UserService/DeleteAccount"
+	UserService_SetVaultCheck_FullMethodName  = "/proto.UserService/SetVaultCheck"
+	UserService_SetVaultKey_FullMethodName    = "/proto.UserService/SetVaultKey"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -29,6 +34,11 @@ const (
 type UserServiceClient interface {
 	SignUp(ctx context.Context, in *SignUpUserRequest, opts ...grpc.CallOption) (*SignUpUserResponse, error)
 	SignIn(ctx context.Context, in *SignInUserRequest, opts ...grpc.CallOption) (*SignInUserResponse, error)
+	Refresh(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
+	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	SetVaultCheck(ctx context.Context, in *SetVaultCheckRequest, opts ...grpc.CallOption) (*SetVaultCheckResponse, error)
+	SetVaultKey(ctx context.Context, in *SetVaultKeyRequest, opts ...grpc.CallOption) (*SetVaultKeyResponse, error)
 }
 
 type userServiceClient struct {
@@ -57,12 +67,62 @@ func (c *userServiceClient) SignIn(ctx context.Context, in *SignInUserRequest, o
 	return out, nil
 }
 
+func (c *userServiceClient) Refresh(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, UserService_Refresh_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error) {
+	out := new(ChangePasswordResponse)
+	err := c.cc.Invoke(ctx, UserService_ChangePassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, UserService_DeleteAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetVaultCheck(ctx context.Context, in *SetVaultCheckRequest, opts ...grpc.CallOption) (*SetVaultCheckResponse, error) {
+	out := new(SetVaultCheckResponse)
+	err := c.cc.Invoke(ctx, UserService_SetVaultCheck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetVaultKey(ctx context.Context, in *SetVaultKeyRequest, opts ...grpc.CallOption) (*SetVaultKeyResponse, error) {
+	out := new(SetVaultKeyResponse)
+	err := c.cc.Invoke(ctx, UserService_SetVaultKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
 type UserServiceServer interface {
 	SignUp(context.Context, *SignUpUserRequest) (*SignUpUserResponse, error)
 	SignIn(context.Context, *SignInUserRequest) (*SignInUserResponse, error)
+	Refresh(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	SetVaultCheck(context.Context, *SetVaultCheckRequest) (*SetVaultCheckResponse, error)
+	SetVaultKey(context.Context, *SetVaultKeyRequest) (*SetVaultKeyResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -76,6 +136,21 @@ func (UnimplementedUserServiceServer) SignUp(context.Context, *SignUpUserRequest
 func (UnimplementedUserServiceServer) SignIn(context.Context, *SignInUserRequest) (*SignInUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignIn not implemented")
 }
+func (UnimplementedUserServiceServer) Refresh(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (UnimplementedUserServiceServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
+}
+func (UnimplementedUserServiceServer) SetVaultCheck(context.Context, *SetVaultCheckRequest) (*SetVaultCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVaultCheck not implemented")
+}
+func (UnimplementedUserServiceServer) SetVaultKey(context.Context, *SetVaultKeyRequest) (*SetVaultKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVaultKey not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -89,56 +164,494 @@ func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
 	s.RegisterService(&UserService_ServiceDesc, srv)
 }
 
-func _UserService_SignUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignUpUserRequest)
+func _UserService_SignUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignUpUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SignUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SignUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SignUp(ctx, req.(*SignUpUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SignIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignInUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SignIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SignIn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SignIn(ctx, req.(*SignInUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_Refresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Refresh(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteAccount(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetVaultCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVaultCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetVaultCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetVaultCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetVaultCheck(ctx, req.(*SetVaultCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetVaultKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVaultKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetVaultKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetVaultKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetVaultKey(ctx, req.(*SetVaultKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignUp",
+			Handler:    _UserService_SignUp_Handler,
+		},
+		{
+			MethodName: "SignIn",
+			Handler:    _UserService_SignIn_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _UserService_Refresh_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _UserService_ChangePassword_Handler,
+		},
+		{
+			MethodName: "DeleteAccount",
+			Handler:    _UserService_DeleteAccount_Handler,
+		},
+		{
+			MethodName: "SetVaultCheck",
+			Handler:    _UserService_SetVaultCheck_Handler,
+		},
+		{
+			MethodName: "SetVaultKey",
+			Handler:    _UserService_SetVaultKey_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gophkeeper.proto",
+}
+
+const (
+	TwoFactorService_Enroll_FullMethodName  = "/proto.TwoFactorService/Enroll"
+	TwoFactorService_Confirm_FullMethodName = "/proto.TwoFactorService/Confirm"
+	TwoFactorService_Disable_FullMethodName = "/proto.TwoFactorService/Disable"
+)
+
+// TwoFactorServiceClient is the client API for TwoFactorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TwoFactorServiceClient interface {
+	Enroll(ctx context.Context, in *EnrollTwoFactorRequest, opts ...grpc.CallOption) (*EnrollTwoFactorResponse, error)
+	Confirm(ctx context.Context, in *ConfirmTwoFactorRequest, opts ...grpc.CallOption) (*ConfirmTwoFactorResponse, error)
+	Disable(ctx context.Context, in *DisableTwoFactorRequest, opts ...grpc.CallOption) (*DisableTwoFactorResponse, error)
+}
+
+type twoFactorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTwoFactorServiceClient(cc grpc.ClientConnInterface) TwoFactorServiceClient {
+	return &twoFactorServiceClient{cc}
+}
+
+func (c *twoFactorServiceClient) Enroll(ctx context.Context, in *EnrollTwoFactorRequest, opts ...grpc.CallOption) (*EnrollTwoFactorResponse, error) {
+	out := new(EnrollTwoFactorResponse)
+	err := c.cc.Invoke(ctx, TwoFactorService_Enroll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twoFactorServiceClient) Confirm(ctx context.Context, in *ConfirmTwoFactorRequest, opts ...grpc.CallOption) (*ConfirmTwoFactorResponse, error) {
+	out := new(ConfirmTwoFactorResponse)
+	err := c.cc.Invoke(ctx, TwoFactorService_Confirm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twoFactorServiceClient) Disable(ctx context.Context, in *DisableTwoFactorRequest, opts ...grpc.CallOption) (*DisableTwoFactorResponse, error) {
+	out := new(DisableTwoFactorResponse)
+	err := c.cc.Invoke(ctx, TwoFactorService_Disable_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TwoFactorServiceServer is the server API for TwoFactorService service.
+// All implementations must embed UnimplementedTwoFactorServiceServer
+// for forward compatibility
+type TwoFactorServiceServer interface {
+	Enroll(context.Context, *EnrollTwoFactorRequest) (*EnrollTwoFactorResponse, error)
+	Confirm(context.Context, *ConfirmTwoFactorRequest) (*ConfirmTwoFactorResponse, error)
+	Disable(context.Context, *DisableTwoFactorRequest) (*DisableTwoFactorResponse, error)
+	mustEmbedUnimplementedTwoFactorServiceServer()
+}
+
+// UnimplementedTwoFactorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTwoFactorServiceServer struct {
+}
+
+func (UnimplementedTwoFactorServiceServer) Enroll(context.Context, *EnrollTwoFactorRequest) (*EnrollTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Enroll not implemented")
+}
+func (UnimplementedTwoFactorServiceServer) Confirm(context.Context, *ConfirmTwoFactorRequest) (*ConfirmTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Confirm not implemented")
+}
+func (UnimplementedTwoFactorServiceServer) Disable(context.Context, *DisableTwoFactorRequest) (*DisableTwoFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disable not implemented")
+}
+func (UnimplementedTwoFactorServiceServer) mustEmbedUnimplementedTwoFactorServiceServer() {}
+
+// UnsafeTwoFactorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TwoFactorServiceServer will
+// result in compilation errors.
+type UnsafeTwoFactorServiceServer interface {
+	mustEmbedUnimplementedTwoFactorServiceServer()
+}
+
+func RegisterTwoFactorServiceServer(s grpc.ServiceRegistrar, srv TwoFactorServiceServer) {
+	s.RegisterService(&TwoFactorService_ServiceDesc, srv)
+}
+
+func _TwoFactorService_Enroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwoFactorServiceServer).Enroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwoFactorService_Enroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwoFactorServiceServer).Enroll(ctx, req.(*EnrollTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwoFactorService_Confirm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwoFactorServiceServer).Confirm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwoFactorService_Confirm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwoFactorServiceServer).Confirm(ctx, req.(*ConfirmTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwoFactorService_Disable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwoFactorServiceServer).Disable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwoFactorService_Disable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwoFactorServiceServer).Disable(ctx, req.(*DisableTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TwoFactorService_ServiceDesc is the grpc.ServiceDesc for TwoFactorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TwoFactorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.TwoFactorService",
+	HandlerType: (*TwoFactorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Enroll",
+			Handler:    _TwoFactorService_Enroll_Handler,
+		},
+		{
+			MethodName: "Confirm",
+			Handler:    _TwoFactorService_Confirm_Handler,
+		},
+		{
+			MethodName: "Disable",
+			Handler:    _TwoFactorService_Disable_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gophkeeper.proto",
+}
+
+const (
+	SessionService_GetAll_FullMethodName       = "/proto.SessionService/GetAll"
+	SessionService_Revoke_FullMethodName       = "/proto.SessionService/Revoke"
+	SessionService_RevokeOthers_FullMethodName = "/proto.SessionService/RevokeOthers"
+)
+
+// SessionServiceClient is the client API for SessionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SessionServiceClient interface {
+	GetAll(ctx context.Context, in *GetSessionsRequest, opts ...grpc.CallOption) (*GetSessionsResponse, error)
+	Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	RevokeOthers(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error)
+}
+
+type sessionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSessionServiceClient(cc grpc.ClientConnInterface) SessionServiceClient {
+	return &sessionServiceClient{cc}
+}
+
+func (c *sessionServiceClient) GetAll(ctx context.Context, in *GetSessionsRequest, opts ...grpc.CallOption) (*GetSessionsResponse, error) {
+	out := new(GetSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionService_GetAll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, SessionService_Revoke_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionServiceClient) RevokeOthers(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error) {
+	out := new(RevokeOtherSessionsResponse)
+	err := c.cc.Invoke(ctx, SessionService_RevokeOthers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionServiceServer is the server API for SessionService service.
+// All implementations must embed UnimplementedSessionServiceServer
+// for forward compatibility
+type SessionServiceServer interface {
+	GetAll(context.Context, *GetSessionsRequest) (*GetSessionsResponse, error)
+	Revoke(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	RevokeOthers(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error)
+	mustEmbedUnimplementedSessionServiceServer()
+}
+
+// UnimplementedSessionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSessionServiceServer struct {
+}
+
+func (UnimplementedSessionServiceServer) GetAll(context.Context, *GetSessionsRequest) (*GetSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAll not implemented")
+}
+func (UnimplementedSessionServiceServer) Revoke(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedSessionServiceServer) RevokeOthers(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeOthers not implemented")
+}
+func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
+
+// UnsafeSessionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SessionServiceServer will
+// result in compilation errors.
+type UnsafeSessionServiceServer interface {
+	mustEmbedUnimplementedSessionServiceServer()
+}
+
+func RegisterSessionServiceServer(s grpc.ServiceRegistrar, srv SessionServiceServer) {
+	s.RegisterService(&SessionService_ServiceDesc, srv)
+}
+
+func _SessionService_GetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).GetAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_GetAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).GetAll(ctx, req.(*GetSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SessionService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServiceServer).SignUp(ctx, in)
+		return srv.(SessionServiceServer).Revoke(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UserService_SignUp_FullMethodName,
+		FullMethod: SessionService_Revoke_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).SignUp(ctx, req.(*SignUpUserRequest))
+		return srv.(SessionServiceServer).Revoke(ctx, req.(*RevokeSessionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UserService_SignIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignInUserRequest)
+func _SessionService_RevokeOthers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeOtherSessionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UserServiceServer).SignIn(ctx, in)
+		return srv.(SessionServiceServer).RevokeOthers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UserService_SignIn_FullMethodName,
+		FullMethod: SessionService_RevokeOthers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).SignIn(ctx, req.(*SignInUserRequest))
+		return srv.(SessionServiceServer).RevokeOthers(ctx, req.(*RevokeOtherSessionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var UserService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.UserService",
-	HandlerType: (*UserServiceServer)(nil),
+var SessionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.SessionService",
+	HandlerType: (*SessionServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "SignUp",
-			Handler:    _UserService_SignUp_Handler,
+			MethodName: "GetAll",
+			Handler:    _SessionService_GetAll_Handler,
 		},
 		{
-			MethodName: "SignIn",
-			Handler:    _UserService_SignIn_Handler,
+			MethodName: "Revoke",
+			Handler:    _SessionService_Revoke_Handler,
+		},
+		{
+			MethodName: "RevokeOthers",
+			Handler:    _SessionService_RevokeOthers_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -146,12 +659,21 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	EntryService_Get_FullMethodName     = "/proto.EntryService/Get"
-	EntryService_GetAll_FullMethodName  = "/proto.EntryService/GetAll"
-	EntryService_GetDiff_FullMethodName = "/proto.EntryService/GetDiff"
-	EntryService_Create_FullMethodName  = "/proto.EntryService/Create"
-	EntryService_Update_FullMethodName  = "/proto.EntryService/Update"
-	EntryService_Delete_FullMethodName  = "/proto.EntryService/Delete"
+	EntryService_Get_FullMethodName             = "/proto.EntryService/Get"
+	EntryService_GetAll_FullMethodName          = "/proto.EntryService/GetAll"
+	EntryService_GetDiff_FullMethodName         = "/proto.EntryService/GetDiff"
+	EntryService_Create_FullMethodName          = "/proto.EntryService/Create"
+	EntryService_Update_FullMethodName          = "/proto.EntryService/Update"
+	EntryService_Delete_FullMethodName          = "/proto.EntryService/Delete"
+	EntryService_UploadBlob_FullMethodName      = "/proto.EntryService/UploadBlob"
+	EntryService_DownloadBlob_FullMethodName    = "/proto.EntryService/DownloadBlob"
+	EntryService_Watch_FullMethodName           = "/proto.EntryService/Watch"
+	EntryService_GetHistory_FullMethodName      = "/proto.EntryService/GetHistory"
+	EntryService_Restore_FullMethodName         = "/proto.EntryService/Restore"
+	EntryService_GetTrash_FullMethodName        = "/proto.EntryService/GetTrash"
+	EntryService_Undelete_FullMethodName        = "/proto.EntryService/Undelete"
+	EntryService_GetChangesSince_FullMethodName = "/proto.EntryService/GetChangesSince"
+	EntryService_ApplyChanges_FullMethodName    = "/proto.EntryService/ApplyChanges"
 )
 
 // EntryServiceClient is the client API for EntryService service.
@@ -164,6 +686,15 @@ type EntryServiceClient interface {
 	Create(ctx context.Context, in *CreateEntryRequest, opts ...grpc.CallOption) (*CreateEntryResponse, error)
 	Update(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*UpdateEntryResponse, error)
 	Delete(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*DeleteEntryResponse, error)
+	UploadBlob(ctx context.Context, opts ...grpc.CallOption) (EntryService_UploadBlobClient, error)
+	DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (EntryService_DownloadBlobClient, error)
+	Watch(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (EntryService_WatchClient, error)
+	GetHistory(ctx context.Context, in *GetEntryHistoryRequest, opts ...grpc.CallOption) (*GetEntryHistoryResponse, error)
+	Restore(ctx context.Context, in *RestoreEntryRequest, opts ...grpc.CallOption) (*RestoreEntryResponse, error)
+	GetTrash(ctx context.Context, in *GetTrashRequest, opts ...grpc.CallOption) (*GetTrashResponse, error)
+	Undelete(ctx context.Context, in *UndeleteEntryRequest, opts ...grpc.CallOption) (*UndeleteEntryResponse, error)
+	GetChangesSince(ctx context.Context, in *GetChangesSinceRequest, opts ...grpc.CallOption) (*GetChangesSinceResponse, error)
+	ApplyChanges(ctx context.Context, in *ApplyChangesRequest, opts ...grpc.CallOption) (*ApplyChangesResponse, error)
 }
 
 type entryServiceClient struct {
@@ -228,6 +759,158 @@ func (c *entryServiceClient) Delete(ctx context.Context, in *DeleteEntryRequest,
 	return out, nil
 }
 
+func (c *entryServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (EntryService_UploadBlobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[0], EntryService_UploadBlob_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &entryServiceUploadBlobClient{stream}
+	return x, nil
+}
+
+type EntryService_UploadBlobClient interface {
+	Send(*UploadBlobRequest) error
+	CloseAndRecv() (*UploadBlobResponse, error)
+	grpc.ClientStream
+}
+
+type entryServiceUploadBlobClient struct {
+	grpc.ClientStream
+}
+
+func (x *entryServiceUploadBlobClient) Send(m *UploadBlobRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *entryServiceUploadBlobClient) CloseAndRecv() (*UploadBlobResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadBlobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *entryServiceClient) DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (EntryService_DownloadBlobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[1], EntryService_DownloadBlob_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &entryServiceDownloadBlobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EntryService_DownloadBlobClient interface {
+	Recv() (*DownloadBlobResponse, error)
+	grpc.ClientStream
+}
+
+type entryServiceDownloadBlobClient struct {
+	grpc.ClientStream
+}
+
+func (x *entryServiceDownloadBlobClient) Recv() (*DownloadBlobResponse, error) {
+	m := new(DownloadBlobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *entryServiceClient) Watch(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (EntryService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[2], EntryService_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &entryServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EntryService_WatchClient interface {
+	Recv() (*WatchEntriesResponse, error)
+	grpc.ClientStream
+}
+
+type entryServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *entryServiceWatchClient) Recv() (*WatchEntriesResponse, error) {
+	m := new(WatchEntriesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *entryServiceClient) GetHistory(ctx context.Context, in *GetEntryHistoryRequest, opts ...grpc.CallOption) (*GetEntryHistoryResponse, error) {
+	out := new(GetEntryHistoryResponse)
+	err := c.cc.Invoke(ctx, EntryService_GetHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) Restore(ctx context.Context, in *RestoreEntryRequest, opts ...grpc.CallOption) (*RestoreEntryResponse, error) {
+	out := new(RestoreEntryResponse)
+	err := c.cc.Invoke(ctx, EntryService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetTrash(ctx context.Context, in *GetTrashRequest, opts ...grpc.CallOption) (*GetTrashResponse, error) {
+	out := new(GetTrashResponse)
+	err := c.cc.Invoke(ctx, EntryService_GetTrash_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) Undelete(ctx context.Context, in *UndeleteEntryRequest, opts ...grpc.CallOption) (*UndeleteEntryResponse, error) {
+	out := new(UndeleteEntryResponse)
+	err := c.cc.Invoke(ctx, EntryService_Undelete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) GetChangesSince(ctx context.Context, in *GetChangesSinceRequest, opts ...grpc.CallOption) (*GetChangesSinceResponse, error) {
+	out := new(GetChangesSinceResponse)
+	err := c.cc.Invoke(ctx, EntryService_GetChangesSince_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entryServiceClient) ApplyChanges(ctx context.Context, in *ApplyChangesRequest, opts ...grpc.CallOption) (*ApplyChangesResponse, error) {
+	out := new(ApplyChangesResponse)
+	err := c.cc.Invoke(ctx, EntryService_ApplyChanges_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EntryServiceServer is the server API for EntryService service.
 // All implementations must embed UnimplementedEntryServiceServer
 // for forward compatibility
@@ -238,6 +921,15 @@ type EntryServiceServer interface {
 	Create(context.Context, *CreateEntryRequest) (*CreateEntryResponse, error)
 	Update(context.Context, *UpdateEntryRequest) (*UpdateEntryResponse, error)
 	Delete(context.Context, *DeleteEntryRequest) (*DeleteEntryResponse, error)
+	UploadBlob(EntryService_UploadBlobServer) error
+	DownloadBlob(*DownloadBlobRequest, EntryService_DownloadBlobServer) error
+	Watch(*WatchEntriesRequest, EntryService_WatchServer) error
+	GetHistory(context.Context, *GetEntryHistoryRequest) (*GetEntryHistoryResponse, error)
+	Restore(context.Context, *RestoreEntryRequest) (*RestoreEntryResponse, error)
+	GetTrash(context.Context, *GetTrashRequest) (*GetTrashResponse, error)
+	Undelete(context.Context, *UndeleteEntryRequest) (*UndeleteEntryResponse, error)
+	GetChangesSince(context.Context, *GetChangesSinceRequest) (*GetChangesSinceResponse, error)
+	ApplyChanges(context.Context, *ApplyChangesRequest) (*ApplyChangesResponse, error)
 	mustEmbedUnimplementedEntryServiceServer()
 }
 
@@ -263,6 +955,33 @@ func (UnimplementedEntryServiceServer) Update(context.Context, *UpdateEntryReque
 func (UnimplementedEntryServiceServer) Delete(context.Context, *DeleteEntryRequest) (*DeleteEntryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedEntryServiceServer) UploadBlob(EntryService_UploadBlobServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBlob not implemented")
+}
+func (UnimplementedEntryServiceServer) DownloadBlob(*DownloadBlobRequest, EntryService_DownloadBlobServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBlob not implemented")
+}
+func (UnimplementedEntryServiceServer) Watch(*WatchEntriesRequest, EntryService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedEntryServiceServer) GetHistory(context.Context, *GetEntryHistoryRequest) (*GetEntryHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedEntryServiceServer) Restore(context.Context, *RestoreEntryRequest) (*RestoreEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedEntryServiceServer) GetTrash(context.Context, *GetTrashRequest) (*GetTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrash not implemented")
+}
+func (UnimplementedEntryServiceServer) Undelete(context.Context, *UndeleteEntryRequest) (*UndeleteEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Undelete not implemented")
+}
+func (UnimplementedEntryServiceServer) GetChangesSince(context.Context, *GetChangesSinceRequest) (*GetChangesSinceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChangesSince not implemented")
+}
+func (UnimplementedEntryServiceServer) ApplyChanges(context.Context, *ApplyChangesRequest) (*ApplyChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyChanges not implemented")
+}
 func (UnimplementedEntryServiceServer) mustEmbedUnimplementedEntryServiceServer() {}
 
 // UnsafeEntryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -384,6 +1103,182 @@ func _EntryService_Delete_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EntryService_UploadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EntryServiceServer).UploadBlob(&entryServiceUploadBlobServer{stream})
+}
+
+type EntryService_UploadBlobServer interface {
+	SendAndClose(*UploadBlobResponse) error
+	Recv() (*UploadBlobRequest, error)
+	grpc.ServerStream
+}
+
+type entryServiceUploadBlobServer struct {
+	grpc.ServerStream
+}
+
+func (x *entryServiceUploadBlobServer) SendAndClose(m *UploadBlobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *entryServiceUploadBlobServer) Recv() (*UploadBlobRequest, error) {
+	m := new(UploadBlobRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _EntryService_DownloadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBlobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).DownloadBlob(m, &entryServiceDownloadBlobServer{stream})
+}
+
+type EntryService_DownloadBlobServer interface {
+	Send(*DownloadBlobResponse) error
+	grpc.ServerStream
+}
+
+type entryServiceDownloadBlobServer struct {
+	grpc.ServerStream
+}
+
+func (x *entryServiceDownloadBlobServer) Send(m *DownloadBlobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EntryService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEntriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).Watch(m, &entryServiceWatchServer{stream})
+}
+
+type EntryService_WatchServer interface {
+	Send(*WatchEntriesResponse) error
+	grpc.ServerStream
+}
+
+type entryServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *entryServiceWatchServer) Send(m *WatchEntriesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EntryService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEntryHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetHistory(ctx, req.(*GetEntryHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).Restore(ctx, req.(*RestoreEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetTrash(ctx, req.(*GetTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_Undelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).Undelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_Undelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).Undelete(ctx, req.(*UndeleteEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_GetChangesSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangesSinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).GetChangesSince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_GetChangesSince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).GetChangesSince(ctx, req.(*GetChangesSinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntryService_ApplyChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryServiceServer).ApplyChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EntryService_ApplyChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryServiceServer).ApplyChanges(ctx, req.(*ApplyChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EntryService_ServiceDesc is the grpc.ServiceDesc for EntryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -415,7 +1310,47 @@ var EntryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _EntryService_Delete_Handler,
 		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _EntryService_GetHistory_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _EntryService_Restore_Handler,
+		},
+		{
+			MethodName: "GetTrash",
+			Handler:    _EntryService_GetTrash_Handler,
+		},
+		{
+			MethodName: "Undelete",
+			Handler:    _EntryService_Undelete_Handler,
+		},
+		{
+			MethodName: "GetChangesSince",
+			Handler:    _EntryService_GetChangesSince_Handler,
+		},
+		{
+			MethodName: "ApplyChanges",
+			Handler:    _EntryService_ApplyChanges_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBlob",
+			Handler:       _EntryService_UploadBlob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBlob",
+			Handler:       _EntryService_DownloadBlob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _EntryService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "gophkeeper.proto",
 }
@@ -16,6 +16,7 @@ import (
 	proto "github.com/dlomanov/gophkeeper/internal/apps/shared/proto"
 	gomock "go.uber.org/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockUserServiceClient is a mock of UserServiceClient interface.
@@ -41,6 +42,106 @@ func (m *MockUserServiceClient) EXPECT() *MockUserServiceClientMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserServiceClient) ChangePassword(ctx context.Context, in *proto.ChangePasswordRequest, opts ...grpc.CallOption) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ChangePassword", varargs...)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserServiceClientMockRecorder) ChangePassword(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserServiceClient)(nil).ChangePassword), varargs...)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUserServiceClient) DeleteAccount(ctx context.Context, in *proto.DeleteAccountRequest, opts ...grpc.CallOption) (*proto.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAccount", varargs...)
+	ret0, _ := ret[0].(*proto.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUserServiceClientMockRecorder) DeleteAccount(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUserServiceClient)(nil).DeleteAccount), varargs...)
+}
+
+// Refresh mocks base method.
+func (m *MockUserServiceClient) Refresh(ctx context.Context, in *proto.RefreshTokenRequest, opts ...grpc.CallOption) (*proto.RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Refresh", varargs...)
+	ret0, _ := ret[0].(*proto.RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserServiceClientMockRecorder) Refresh(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserServiceClient)(nil).Refresh), varargs...)
+}
+
+// SetVaultCheck mocks base method.
+func (m *MockUserServiceClient) SetVaultCheck(ctx context.Context, in *proto.SetVaultCheckRequest, opts ...grpc.CallOption) (*proto.SetVaultCheckResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetVaultCheck", varargs...)
+	ret0, _ := ret[0].(*proto.SetVaultCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVaultCheck indicates an expected call of SetVaultCheck.
+func (mr *MockUserServiceClientMockRecorder) SetVaultCheck(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultCheck", reflect.TypeOf((*MockUserServiceClient)(nil).SetVaultCheck), varargs...)
+}
+
+// SetVaultKey mocks base method.
+func (m *MockUserServiceClient) SetVaultKey(ctx context.Context, in *proto.SetVaultKeyRequest, opts ...grpc.CallOption) (*proto.SetVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetVaultKey", varargs...)
+	ret0, _ := ret[0].(*proto.SetVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVaultKey indicates an expected call of SetVaultKey.
+func (mr *MockUserServiceClientMockRecorder) SetVaultKey(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultKey", reflect.TypeOf((*MockUserServiceClient)(nil).SetVaultKey), varargs...)
+}
+
 // SignIn mocks base method.
 func (m *MockUserServiceClient) SignIn(ctx context.Context, in *proto.SignInUserRequest, opts ...grpc.CallOption) (*proto.SignInUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -104,6 +205,81 @@ func (m *MockUserServiceServer) EXPECT() *MockUserServiceServerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserServiceServer) ChangePassword(arg0 context.Context, arg1 *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserServiceServerMockRecorder) ChangePassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserServiceServer)(nil).ChangePassword), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUserServiceServer) DeleteAccount(arg0 context.Context, arg1 *proto.DeleteAccountRequest) (*proto.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(*proto.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUserServiceServerMockRecorder) DeleteAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUserServiceServer)(nil).DeleteAccount), arg0, arg1)
+}
+
+// Refresh mocks base method.
+func (m *MockUserServiceServer) Refresh(arg0 context.Context, arg1 *proto.RefreshTokenRequest) (*proto.RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserServiceServerMockRecorder) Refresh(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserServiceServer)(nil).Refresh), arg0, arg1)
+}
+
+// SetVaultCheck mocks base method.
+func (m *MockUserServiceServer) SetVaultCheck(arg0 context.Context, arg1 *proto.SetVaultCheckRequest) (*proto.SetVaultCheckResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVaultCheck", arg0, arg1)
+	ret0, _ := ret[0].(*proto.SetVaultCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVaultCheck indicates an expected call of SetVaultCheck.
+func (mr *MockUserServiceServerMockRecorder) SetVaultCheck(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultCheck", reflect.TypeOf((*MockUserServiceServer)(nil).SetVaultCheck), arg0, arg1)
+}
+
+// SetVaultKey mocks base method.
+func (m *MockUserServiceServer) SetVaultKey(arg0 context.Context, arg1 *proto.SetVaultKeyRequest) (*proto.SetVaultKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVaultKey", arg0, arg1)
+	ret0, _ := ret[0].(*proto.SetVaultKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVaultKey indicates an expected call of SetVaultKey.
+func (mr *MockUserServiceServerMockRecorder) SetVaultKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVaultKey", reflect.TypeOf((*MockUserServiceServer)(nil).SetVaultKey), arg0, arg1)
+}
+
 // SignIn mocks base method.
 func (m *MockUserServiceServer) SignIn(arg0 context.Context, arg1 *proto.SignInUserRequest) (*proto.SignInUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -181,203 +357,1191 @@ func (mr *MockUnsafeUserServiceServerMockRecorder) mustEmbedUnimplementedUserSer
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedUserServiceServer", reflect.TypeOf((*MockUnsafeUserServiceServer)(nil).mustEmbedUnimplementedUserServiceServer))
 }
 
-// MockEntryServiceClient is a mock of EntryServiceClient interface.
-type MockEntryServiceClient struct {
+// MockTwoFactorServiceClient is a mock of TwoFactorServiceClient interface.
+type MockTwoFactorServiceClient struct {
 	ctrl     *gomock.Controller
-	recorder *MockEntryServiceClientMockRecorder
+	recorder *MockTwoFactorServiceClientMockRecorder
 }
 
-// MockEntryServiceClientMockRecorder is the mock recorder for MockEntryServiceClient.
-type MockEntryServiceClientMockRecorder struct {
-	mock *MockEntryServiceClient
+// MockTwoFactorServiceClientMockRecorder is the mock recorder for MockTwoFactorServiceClient.
+type MockTwoFactorServiceClientMockRecorder struct {
+	mock *MockTwoFactorServiceClient
 }
 
-// NewMockEntryServiceClient creates a new mock instance.
-func NewMockEntryServiceClient(ctrl *gomock.Controller) *MockEntryServiceClient {
-	mock := &MockEntryServiceClient{ctrl: ctrl}
-	mock.recorder = &MockEntryServiceClientMockRecorder{mock}
+// NewMockTwoFactorServiceClient creates a new mock instance.
+func NewMockTwoFactorServiceClient(ctrl *gomock.Controller) *MockTwoFactorServiceClient {
+	mock := &MockTwoFactorServiceClient{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorServiceClientMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockEntryServiceClient) EXPECT() *MockEntryServiceClientMockRecorder {
+func (m *MockTwoFactorServiceClient) EXPECT() *MockTwoFactorServiceClientMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockEntryServiceClient) Create(ctx context.Context, in *proto.CreateEntryRequest, opts ...grpc.CallOption) (*proto.CreateEntryResponse, error) {
+// Confirm mocks base method.
+func (m *MockTwoFactorServiceClient) Confirm(ctx context.Context, in *proto.ConfirmTwoFactorRequest, opts ...grpc.CallOption) (*proto.ConfirmTwoFactorResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Create", varargs...)
-	ret0, _ := ret[0].(*proto.CreateEntryResponse)
+	ret := m.ctrl.Call(m, "Confirm", varargs...)
+	ret0, _ := ret[0].(*proto.ConfirmTwoFactorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockEntryServiceClientMockRecorder) Create(ctx, in any, opts ...any) *gomock.Call {
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorServiceClientMockRecorder) Confirm(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEntryServiceClient)(nil).Create), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorServiceClient)(nil).Confirm), varargs...)
 }
 
-// Delete mocks base method.
-func (m *MockEntryServiceClient) Delete(ctx context.Context, in *proto.DeleteEntryRequest, opts ...grpc.CallOption) (*proto.DeleteEntryResponse, error) {
+// Disable mocks base method.
+func (m *MockTwoFactorServiceClient) Disable(ctx context.Context, in *proto.DisableTwoFactorRequest, opts ...grpc.CallOption) (*proto.DisableTwoFactorResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Delete", varargs...)
-	ret0, _ := ret[0].(*proto.DeleteEntryResponse)
+	ret := m.ctrl.Call(m, "Disable", varargs...)
+	ret0, _ := ret[0].(*proto.DisableTwoFactorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockEntryServiceClientMockRecorder) Delete(ctx, in any, opts ...any) *gomock.Call {
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorServiceClientMockRecorder) Disable(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryServiceClient)(nil).Delete), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorServiceClient)(nil).Disable), varargs...)
 }
 
-// Get mocks base method.
-func (m *MockEntryServiceClient) Get(ctx context.Context, in *proto.GetEntryRequest, opts ...grpc.CallOption) (*proto.GetEntryResponse, error) {
+// Enroll mocks base method.
+func (m *MockTwoFactorServiceClient) Enroll(ctx context.Context, in *proto.EnrollTwoFactorRequest, opts ...grpc.CallOption) (*proto.EnrollTwoFactorResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Get", varargs...)
-	ret0, _ := ret[0].(*proto.GetEntryResponse)
+	ret := m.ctrl.Call(m, "Enroll", varargs...)
+	ret0, _ := ret[0].(*proto.EnrollTwoFactorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockEntryServiceClientMockRecorder) Get(ctx, in any, opts ...any) *gomock.Call {
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorServiceClientMockRecorder) Enroll(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEntryServiceClient)(nil).Get), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorServiceClient)(nil).Enroll), varargs...)
+}
+
+// MockTwoFactorServiceServer is a mock of TwoFactorServiceServer interface.
+type MockTwoFactorServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorServiceServerMockRecorder
+}
+
+// MockTwoFactorServiceServerMockRecorder is the mock recorder for MockTwoFactorServiceServer.
+type MockTwoFactorServiceServerMockRecorder struct {
+	mock *MockTwoFactorServiceServer
+}
+
+// NewMockTwoFactorServiceServer creates a new mock instance.
+func NewMockTwoFactorServiceServer(ctrl *gomock.Controller) *MockTwoFactorServiceServer {
+	mock := &MockTwoFactorServiceServer{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorServiceServer) EXPECT() *MockTwoFactorServiceServerMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTwoFactorServiceServer) Confirm(arg0 context.Context, arg1 *proto.ConfirmTwoFactorRequest) (*proto.ConfirmTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ConfirmTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorServiceServerMockRecorder) Confirm(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorServiceServer)(nil).Confirm), arg0, arg1)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactorServiceServer) Disable(arg0 context.Context, arg1 *proto.DisableTwoFactorRequest) (*proto.DisableTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", arg0, arg1)
+	ret0, _ := ret[0].(*proto.DisableTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorServiceServerMockRecorder) Disable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorServiceServer)(nil).Disable), arg0, arg1)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactorServiceServer) Enroll(arg0 context.Context, arg1 *proto.EnrollTwoFactorRequest) (*proto.EnrollTwoFactorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", arg0, arg1)
+	ret0, _ := ret[0].(*proto.EnrollTwoFactorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorServiceServerMockRecorder) Enroll(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorServiceServer)(nil).Enroll), arg0, arg1)
+}
+
+// mustEmbedUnimplementedTwoFactorServiceServer mocks base method.
+func (m *MockTwoFactorServiceServer) mustEmbedUnimplementedTwoFactorServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedTwoFactorServiceServer")
+}
+
+// mustEmbedUnimplementedTwoFactorServiceServer indicates an expected call of mustEmbedUnimplementedTwoFactorServiceServer.
+func (mr *MockTwoFactorServiceServerMockRecorder) mustEmbedUnimplementedTwoFactorServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedTwoFactorServiceServer", reflect.TypeOf((*MockTwoFactorServiceServer)(nil).mustEmbedUnimplementedTwoFactorServiceServer))
+}
+
+// MockUnsafeTwoFactorServiceServer is a mock of UnsafeTwoFactorServiceServer interface.
+type MockUnsafeTwoFactorServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeTwoFactorServiceServerMockRecorder
+}
+
+// MockUnsafeTwoFactorServiceServerMockRecorder is the mock recorder for MockUnsafeTwoFactorServiceServer.
+type MockUnsafeTwoFactorServiceServerMockRecorder struct {
+	mock *MockUnsafeTwoFactorServiceServer
+}
+
+// NewMockUnsafeTwoFactorServiceServer creates a new mock instance.
+func NewMockUnsafeTwoFactorServiceServer(ctrl *gomock.Controller) *MockUnsafeTwoFactorServiceServer {
+	mock := &MockUnsafeTwoFactorServiceServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeTwoFactorServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeTwoFactorServiceServer) EXPECT() *MockUnsafeTwoFactorServiceServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedTwoFactorServiceServer mocks base method.
+func (m *MockUnsafeTwoFactorServiceServer) mustEmbedUnimplementedTwoFactorServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedTwoFactorServiceServer")
+}
+
+// mustEmbedUnimplementedTwoFactorServiceServer indicates an expected call of mustEmbedUnimplementedTwoFactorServiceServer.
+func (mr *MockUnsafeTwoFactorServiceServerMockRecorder) mustEmbedUnimplementedTwoFactorServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedTwoFactorServiceServer", reflect.TypeOf((*MockUnsafeTwoFactorServiceServer)(nil).mustEmbedUnimplementedTwoFactorServiceServer))
+}
+
+// MockSessionServiceClient is a mock of SessionServiceClient interface.
+type MockSessionServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionServiceClientMockRecorder
+}
+
+// MockSessionServiceClientMockRecorder is the mock recorder for MockSessionServiceClient.
+type MockSessionServiceClientMockRecorder struct {
+	mock *MockSessionServiceClient
+}
+
+// NewMockSessionServiceClient creates a new mock instance.
+func NewMockSessionServiceClient(ctrl *gomock.Controller) *MockSessionServiceClient {
+	mock := &MockSessionServiceClient{ctrl: ctrl}
+	mock.recorder = &MockSessionServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionServiceClient) EXPECT() *MockSessionServiceClientMockRecorder {
+	return m.recorder
 }
 
 // GetAll mocks base method.
-func (m *MockEntryServiceClient) GetAll(ctx context.Context, in *proto.GetEntriesRequest, opts ...grpc.CallOption) (*proto.GetEntriesResponse, error) {
+func (m *MockSessionServiceClient) GetAll(ctx context.Context, in *proto.GetSessionsRequest, opts ...grpc.CallOption) (*proto.GetSessionsResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "GetAll", varargs...)
-	ret0, _ := ret[0].(*proto.GetEntriesResponse)
+	ret0, _ := ret[0].(*proto.GetSessionsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockEntryServiceClientMockRecorder) GetAll(ctx, in any, opts ...any) *gomock.Call {
+func (mr *MockSessionServiceClientMockRecorder) GetAll(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockEntryServiceClient)(nil).GetAll), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSessionServiceClient)(nil).GetAll), varargs...)
 }
 
-// GetDiff mocks base method.
-func (m *MockEntryServiceClient) GetDiff(ctx context.Context, in *proto.GetEntriesDiffRequest, opts ...grpc.CallOption) (*proto.GetEntriesDiffResponse, error) {
+// Revoke mocks base method.
+func (m *MockSessionServiceClient) Revoke(ctx context.Context, in *proto.RevokeSessionRequest, opts ...grpc.CallOption) (*proto.RevokeSessionResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "GetDiff", varargs...)
-	ret0, _ := ret[0].(*proto.GetEntriesDiffResponse)
+	ret := m.ctrl.Call(m, "Revoke", varargs...)
+	ret0, _ := ret[0].(*proto.RevokeSessionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDiff indicates an expected call of GetDiff.
-func (mr *MockEntryServiceClientMockRecorder) GetDiff(ctx, in any, opts ...any) *gomock.Call {
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionServiceClientMockRecorder) Revoke(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiff", reflect.TypeOf((*MockEntryServiceClient)(nil).GetDiff), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionServiceClient)(nil).Revoke), varargs...)
 }
 
-// Update mocks base method.
-func (m *MockEntryServiceClient) Update(ctx context.Context, in *proto.UpdateEntryRequest, opts ...grpc.CallOption) (*proto.UpdateEntryResponse, error) {
+// RevokeOthers mocks base method.
+func (m *MockSessionServiceClient) RevokeOthers(ctx context.Context, in *proto.RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*proto.RevokeOtherSessionsResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, in}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Update", varargs...)
-	ret0, _ := ret[0].(*proto.UpdateEntryResponse)
+	ret := m.ctrl.Call(m, "RevokeOthers", varargs...)
+	ret0, _ := ret[0].(*proto.RevokeOtherSessionsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockEntryServiceClientMockRecorder) Update(ctx, in any, opts ...any) *gomock.Call {
+// RevokeOthers indicates an expected call of RevokeOthers.
+func (mr *MockSessionServiceClientMockRecorder) RevokeOthers(ctx, in any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEntryServiceClient)(nil).Update), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOthers", reflect.TypeOf((*MockSessionServiceClient)(nil).RevokeOthers), varargs...)
 }
 
-// MockEntryServiceServer is a mock of EntryServiceServer interface.
-type MockEntryServiceServer struct {
+// MockSessionServiceServer is a mock of SessionServiceServer interface.
+type MockSessionServiceServer struct {
 	ctrl     *gomock.Controller
-	recorder *MockEntryServiceServerMockRecorder
+	recorder *MockSessionServiceServerMockRecorder
 }
 
-// MockEntryServiceServerMockRecorder is the mock recorder for MockEntryServiceServer.
-type MockEntryServiceServerMockRecorder struct {
-	mock *MockEntryServiceServer
+// MockSessionServiceServerMockRecorder is the mock recorder for MockSessionServiceServer.
+type MockSessionServiceServerMockRecorder struct {
+	mock *MockSessionServiceServer
 }
 
-// NewMockEntryServiceServer creates a new mock instance.
-func NewMockEntryServiceServer(ctrl *gomock.Controller) *MockEntryServiceServer {
-	mock := &MockEntryServiceServer{ctrl: ctrl}
-	mock.recorder = &MockEntryServiceServerMockRecorder{mock}
+// NewMockSessionServiceServer creates a new mock instance.
+func NewMockSessionServiceServer(ctrl *gomock.Controller) *MockSessionServiceServer {
+	mock := &MockSessionServiceServer{ctrl: ctrl}
+	mock.recorder = &MockSessionServiceServerMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockEntryServiceServer) EXPECT() *MockEntryServiceServerMockRecorder {
+func (m *MockSessionServiceServer) EXPECT() *MockSessionServiceServerMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockEntryServiceServer) Create(arg0 context.Context, arg1 *proto.CreateEntryRequest) (*proto.CreateEntryResponse, error) {
+// GetAll mocks base method.
+func (m *MockSessionServiceServer) GetAll(arg0 context.Context, arg1 *proto.GetSessionsRequest) (*proto.GetSessionsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1)
-	ret0, _ := ret[0].(*proto.CreateEntryResponse)
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetSessionsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockEntryServiceServerMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSessionServiceServerMockRecorder) GetAll(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEntryServiceServer)(nil).Create), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSessionServiceServer)(nil).GetAll), arg0, arg1)
 }
 
-// Delete mocks base method.
-func (m *MockEntryServiceServer) Delete(arg0 context.Context, arg1 *proto.DeleteEntryRequest) (*proto.DeleteEntryResponse, error) {
+// Revoke mocks base method.
+func (m *MockSessionServiceServer) Revoke(arg0 context.Context, arg1 *proto.RevokeSessionRequest) (*proto.RevokeSessionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
-	ret0, _ := ret[0].(*proto.DeleteEntryResponse)
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RevokeSessionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockEntryServiceServerMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionServiceServerMockRecorder) Revoke(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryServiceServer)(nil).Delete), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionServiceServer)(nil).Revoke), arg0, arg1)
 }
 
-// Get mocks base method.
+// RevokeOthers mocks base method.
+func (m *MockSessionServiceServer) RevokeOthers(arg0 context.Context, arg1 *proto.RevokeOtherSessionsRequest) (*proto.RevokeOtherSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOthers", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RevokeOtherSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOthers indicates an expected call of RevokeOthers.
+func (mr *MockSessionServiceServerMockRecorder) RevokeOthers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOthers", reflect.TypeOf((*MockSessionServiceServer)(nil).RevokeOthers), arg0, arg1)
+}
+
+// mustEmbedUnimplementedSessionServiceServer mocks base method.
+func (m *MockSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedSessionServiceServer")
+}
+
+// mustEmbedUnimplementedSessionServiceServer indicates an expected call of mustEmbedUnimplementedSessionServiceServer.
+func (mr *MockSessionServiceServerMockRecorder) mustEmbedUnimplementedSessionServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedSessionServiceServer", reflect.TypeOf((*MockSessionServiceServer)(nil).mustEmbedUnimplementedSessionServiceServer))
+}
+
+// MockUnsafeSessionServiceServer is a mock of UnsafeSessionServiceServer interface.
+type MockUnsafeSessionServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeSessionServiceServerMockRecorder
+}
+
+// MockUnsafeSessionServiceServerMockRecorder is the mock recorder for MockUnsafeSessionServiceServer.
+type MockUnsafeSessionServiceServerMockRecorder struct {
+	mock *MockUnsafeSessionServiceServer
+}
+
+// NewMockUnsafeSessionServiceServer creates a new mock instance.
+func NewMockUnsafeSessionServiceServer(ctrl *gomock.Controller) *MockUnsafeSessionServiceServer {
+	mock := &MockUnsafeSessionServiceServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeSessionServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeSessionServiceServer) EXPECT() *MockUnsafeSessionServiceServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedSessionServiceServer mocks base method.
+func (m *MockUnsafeSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedSessionServiceServer")
+}
+
+// mustEmbedUnimplementedSessionServiceServer indicates an expected call of mustEmbedUnimplementedSessionServiceServer.
+func (mr *MockUnsafeSessionServiceServerMockRecorder) mustEmbedUnimplementedSessionServiceServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedSessionServiceServer", reflect.TypeOf((*MockUnsafeSessionServiceServer)(nil).mustEmbedUnimplementedSessionServiceServer))
+}
+
+// MockEntryServiceClient is a mock of EntryServiceClient interface.
+type MockEntryServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryServiceClientMockRecorder
+}
+
+// MockEntryServiceClientMockRecorder is the mock recorder for MockEntryServiceClient.
+type MockEntryServiceClientMockRecorder struct {
+	mock *MockEntryServiceClient
+}
+
+// NewMockEntryServiceClient creates a new mock instance.
+func NewMockEntryServiceClient(ctrl *gomock.Controller) *MockEntryServiceClient {
+	mock := &MockEntryServiceClient{ctrl: ctrl}
+	mock.recorder = &MockEntryServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryServiceClient) EXPECT() *MockEntryServiceClientMockRecorder {
+	return m.recorder
+}
+
+// ApplyChanges mocks base method.
+func (m *MockEntryServiceClient) ApplyChanges(ctx context.Context, in *proto.ApplyChangesRequest, opts ...grpc.CallOption) (*proto.ApplyChangesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApplyChanges", varargs...)
+	ret0, _ := ret[0].(*proto.ApplyChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyChanges indicates an expected call of ApplyChanges.
+func (mr *MockEntryServiceClientMockRecorder) ApplyChanges(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyChanges", reflect.TypeOf((*MockEntryServiceClient)(nil).ApplyChanges), varargs...)
+}
+
+// Create mocks base method.
+func (m *MockEntryServiceClient) Create(ctx context.Context, in *proto.CreateEntryRequest, opts ...grpc.CallOption) (*proto.CreateEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
+	ret0, _ := ret[0].(*proto.CreateEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEntryServiceClientMockRecorder) Create(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEntryServiceClient)(nil).Create), varargs...)
+}
+
+// Delete mocks base method.
+func (m *MockEntryServiceClient) Delete(ctx context.Context, in *proto.DeleteEntryRequest, opts ...grpc.CallOption) (*proto.DeleteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(*proto.DeleteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEntryServiceClientMockRecorder) Delete(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryServiceClient)(nil).Delete), varargs...)
+}
+
+// DownloadBlob mocks base method.
+func (m *MockEntryServiceClient) DownloadBlob(ctx context.Context, in *proto.DownloadBlobRequest, opts ...grpc.CallOption) (proto.EntryService_DownloadBlobClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadBlob", varargs...)
+	ret0, _ := ret[0].(proto.EntryService_DownloadBlobClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBlob indicates an expected call of DownloadBlob.
+func (mr *MockEntryServiceClientMockRecorder) DownloadBlob(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBlob", reflect.TypeOf((*MockEntryServiceClient)(nil).DownloadBlob), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockEntryServiceClient) Get(ctx context.Context, in *proto.GetEntryRequest, opts ...grpc.CallOption) (*proto.GetEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Get", varargs...)
+	ret0, _ := ret[0].(*proto.GetEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEntryServiceClientMockRecorder) Get(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEntryServiceClient)(nil).Get), varargs...)
+}
+
+// GetAll mocks base method.
+func (m *MockEntryServiceClient) GetAll(ctx context.Context, in *proto.GetEntriesRequest, opts ...grpc.CallOption) (*proto.GetEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAll", varargs...)
+	ret0, _ := ret[0].(*proto.GetEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockEntryServiceClientMockRecorder) GetAll(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockEntryServiceClient)(nil).GetAll), varargs...)
+}
+
+// GetChangesSince mocks base method.
+func (m *MockEntryServiceClient) GetChangesSince(ctx context.Context, in *proto.GetChangesSinceRequest, opts ...grpc.CallOption) (*proto.GetChangesSinceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetChangesSince", varargs...)
+	ret0, _ := ret[0].(*proto.GetChangesSinceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangesSince indicates an expected call of GetChangesSince.
+func (mr *MockEntryServiceClientMockRecorder) GetChangesSince(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangesSince", reflect.TypeOf((*MockEntryServiceClient)(nil).GetChangesSince), varargs...)
+}
+
+// GetDiff mocks base method.
+func (m *MockEntryServiceClient) GetDiff(ctx context.Context, in *proto.GetEntriesDiffRequest, opts ...grpc.CallOption) (*proto.GetEntriesDiffResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDiff", varargs...)
+	ret0, _ := ret[0].(*proto.GetEntriesDiffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDiff indicates an expected call of GetDiff.
+func (mr *MockEntryServiceClientMockRecorder) GetDiff(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiff", reflect.TypeOf((*MockEntryServiceClient)(nil).GetDiff), varargs...)
+}
+
+// GetHistory mocks base method.
+func (m *MockEntryServiceClient) GetHistory(ctx context.Context, in *proto.GetEntryHistoryRequest, opts ...grpc.CallOption) (*proto.GetEntryHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetHistory", varargs...)
+	ret0, _ := ret[0].(*proto.GetEntryHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockEntryServiceClientMockRecorder) GetHistory(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockEntryServiceClient)(nil).GetHistory), varargs...)
+}
+
+// GetTrash mocks base method.
+func (m *MockEntryServiceClient) GetTrash(ctx context.Context, in *proto.GetTrashRequest, opts ...grpc.CallOption) (*proto.GetTrashResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTrash", varargs...)
+	ret0, _ := ret[0].(*proto.GetTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockEntryServiceClientMockRecorder) GetTrash(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockEntryServiceClient)(nil).GetTrash), varargs...)
+}
+
+// Restore mocks base method.
+func (m *MockEntryServiceClient) Restore(ctx context.Context, in *proto.RestoreEntryRequest, opts ...grpc.CallOption) (*proto.RestoreEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Restore", varargs...)
+	ret0, _ := ret[0].(*proto.RestoreEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEntryServiceClientMockRecorder) Restore(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEntryServiceClient)(nil).Restore), varargs...)
+}
+
+// Undelete mocks base method.
+func (m *MockEntryServiceClient) Undelete(ctx context.Context, in *proto.UndeleteEntryRequest, opts ...grpc.CallOption) (*proto.UndeleteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Undelete", varargs...)
+	ret0, _ := ret[0].(*proto.UndeleteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockEntryServiceClientMockRecorder) Undelete(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockEntryServiceClient)(nil).Undelete), varargs...)
+}
+
+// Update mocks base method.
+func (m *MockEntryServiceClient) Update(ctx context.Context, in *proto.UpdateEntryRequest, opts ...grpc.CallOption) (*proto.UpdateEntryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
+	ret0, _ := ret[0].(*proto.UpdateEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEntryServiceClientMockRecorder) Update(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEntryServiceClient)(nil).Update), varargs...)
+}
+
+// UploadBlob mocks base method.
+func (m *MockEntryServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (proto.EntryService_UploadBlobClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadBlob", varargs...)
+	ret0, _ := ret[0].(proto.EntryService_UploadBlobClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockEntryServiceClientMockRecorder) UploadBlob(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*MockEntryServiceClient)(nil).UploadBlob), varargs...)
+}
+
+// Watch mocks base method.
+func (m *MockEntryServiceClient) Watch(ctx context.Context, in *proto.WatchEntriesRequest, opts ...grpc.CallOption) (proto.EntryService_WatchClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(proto.EntryService_WatchClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockEntryServiceClientMockRecorder) Watch(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockEntryServiceClient)(nil).Watch), varargs...)
+}
+
+// MockEntryService_UploadBlobClient is a mock of EntryService_UploadBlobClient interface.
+type MockEntryService_UploadBlobClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_UploadBlobClientMockRecorder
+}
+
+// MockEntryService_UploadBlobClientMockRecorder is the mock recorder for MockEntryService_UploadBlobClient.
+type MockEntryService_UploadBlobClientMockRecorder struct {
+	mock *MockEntryService_UploadBlobClient
+}
+
+// NewMockEntryService_UploadBlobClient creates a new mock instance.
+func NewMockEntryService_UploadBlobClient(ctrl *gomock.Controller) *MockEntryService_UploadBlobClient {
+	mock := &MockEntryService_UploadBlobClient{ctrl: ctrl}
+	mock.recorder = &MockEntryService_UploadBlobClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_UploadBlobClient) EXPECT() *MockEntryService_UploadBlobClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method.
+func (m *MockEntryService_UploadBlobClient) CloseAndRecv() (*proto.UploadBlobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*proto.UploadBlobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method.
+func (m *MockEntryService_UploadBlobClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockEntryService_UploadBlobClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockEntryService_UploadBlobClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).Header))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_UploadBlobClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockEntryService_UploadBlobClient) Send(arg0 *proto.UploadBlobRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_UploadBlobClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockEntryService_UploadBlobClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockEntryService_UploadBlobClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockEntryService_UploadBlobClient)(nil).Trailer))
+}
+
+// MockEntryService_DownloadBlobClient is a mock of EntryService_DownloadBlobClient interface.
+type MockEntryService_DownloadBlobClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_DownloadBlobClientMockRecorder
+}
+
+// MockEntryService_DownloadBlobClientMockRecorder is the mock recorder for MockEntryService_DownloadBlobClient.
+type MockEntryService_DownloadBlobClientMockRecorder struct {
+	mock *MockEntryService_DownloadBlobClient
+}
+
+// NewMockEntryService_DownloadBlobClient creates a new mock instance.
+func NewMockEntryService_DownloadBlobClient(ctrl *gomock.Controller) *MockEntryService_DownloadBlobClient {
+	mock := &MockEntryService_DownloadBlobClient{ctrl: ctrl}
+	mock.recorder = &MockEntryService_DownloadBlobClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_DownloadBlobClient) EXPECT() *MockEntryService_DownloadBlobClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockEntryService_DownloadBlobClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockEntryService_DownloadBlobClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockEntryService_DownloadBlobClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockEntryService_DownloadBlobClient) Recv() (*proto.DownloadBlobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.DownloadBlobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_DownloadBlobClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_DownloadBlobClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockEntryService_DownloadBlobClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockEntryService_DownloadBlobClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockEntryService_DownloadBlobClient)(nil).Trailer))
+}
+
+// MockEntryService_WatchClient is a mock of EntryService_WatchClient interface.
+type MockEntryService_WatchClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_WatchClientMockRecorder
+}
+
+// MockEntryService_WatchClientMockRecorder is the mock recorder for MockEntryService_WatchClient.
+type MockEntryService_WatchClientMockRecorder struct {
+	mock *MockEntryService_WatchClient
+}
+
+// NewMockEntryService_WatchClient creates a new mock instance.
+func NewMockEntryService_WatchClient(ctrl *gomock.Controller) *MockEntryService_WatchClient {
+	mock := &MockEntryService_WatchClient{ctrl: ctrl}
+	mock.recorder = &MockEntryService_WatchClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_WatchClient) EXPECT() *MockEntryService_WatchClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockEntryService_WatchClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockEntryService_WatchClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockEntryService_WatchClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockEntryService_WatchClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_WatchClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_WatchClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockEntryService_WatchClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockEntryService_WatchClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockEntryService_WatchClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockEntryService_WatchClient) Recv() (*proto.WatchEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.WatchEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockEntryService_WatchClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockEntryService_WatchClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_WatchClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_WatchClientMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_WatchClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_WatchClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_WatchClientMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_WatchClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockEntryService_WatchClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockEntryService_WatchClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockEntryService_WatchClient)(nil).Trailer))
+}
+
+// MockEntryServiceServer is a mock of EntryServiceServer interface.
+type MockEntryServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryServiceServerMockRecorder
+}
+
+// MockEntryServiceServerMockRecorder is the mock recorder for MockEntryServiceServer.
+type MockEntryServiceServerMockRecorder struct {
+	mock *MockEntryServiceServer
+}
+
+// NewMockEntryServiceServer creates a new mock instance.
+func NewMockEntryServiceServer(ctrl *gomock.Controller) *MockEntryServiceServer {
+	mock := &MockEntryServiceServer{ctrl: ctrl}
+	mock.recorder = &MockEntryServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryServiceServer) EXPECT() *MockEntryServiceServerMockRecorder {
+	return m.recorder
+}
+
+// ApplyChanges mocks base method.
+func (m *MockEntryServiceServer) ApplyChanges(arg0 context.Context, arg1 *proto.ApplyChangesRequest) (*proto.ApplyChangesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyChanges", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ApplyChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyChanges indicates an expected call of ApplyChanges.
+func (mr *MockEntryServiceServerMockRecorder) ApplyChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyChanges", reflect.TypeOf((*MockEntryServiceServer)(nil).ApplyChanges), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockEntryServiceServer) Create(arg0 context.Context, arg1 *proto.CreateEntryRequest) (*proto.CreateEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*proto.CreateEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEntryServiceServerMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEntryServiceServer)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockEntryServiceServer) Delete(arg0 context.Context, arg1 *proto.DeleteEntryRequest) (*proto.DeleteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(*proto.DeleteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEntryServiceServerMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEntryServiceServer)(nil).Delete), arg0, arg1)
+}
+
+// DownloadBlob mocks base method.
+func (m *MockEntryServiceServer) DownloadBlob(arg0 *proto.DownloadBlobRequest, arg1 proto.EntryService_DownloadBlobServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBlob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadBlob indicates an expected call of DownloadBlob.
+func (mr *MockEntryServiceServerMockRecorder) DownloadBlob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBlob", reflect.TypeOf((*MockEntryServiceServer)(nil).DownloadBlob), arg0, arg1)
+}
+
+// Get mocks base method.
 func (m *MockEntryServiceServer) Get(arg0 context.Context, arg1 *proto.GetEntryRequest) (*proto.GetEntryResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
@@ -407,6 +1571,21 @@ func (mr *MockEntryServiceServerMockRecorder) GetAll(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockEntryServiceServer)(nil).GetAll), arg0, arg1)
 }
 
+// GetChangesSince mocks base method.
+func (m *MockEntryServiceServer) GetChangesSince(arg0 context.Context, arg1 *proto.GetChangesSinceRequest) (*proto.GetChangesSinceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangesSince", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetChangesSinceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangesSince indicates an expected call of GetChangesSince.
+func (mr *MockEntryServiceServerMockRecorder) GetChangesSince(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangesSince", reflect.TypeOf((*MockEntryServiceServer)(nil).GetChangesSince), arg0, arg1)
+}
+
 // GetDiff mocks base method.
 func (m *MockEntryServiceServer) GetDiff(arg0 context.Context, arg1 *proto.GetEntriesDiffRequest) (*proto.GetEntriesDiffResponse, error) {
 	m.ctrl.T.Helper()
@@ -422,6 +1601,66 @@ func (mr *MockEntryServiceServerMockRecorder) GetDiff(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiff", reflect.TypeOf((*MockEntryServiceServer)(nil).GetDiff), arg0, arg1)
 }
 
+// GetHistory mocks base method.
+func (m *MockEntryServiceServer) GetHistory(arg0 context.Context, arg1 *proto.GetEntryHistoryRequest) (*proto.GetEntryHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetEntryHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockEntryServiceServerMockRecorder) GetHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockEntryServiceServer)(nil).GetHistory), arg0, arg1)
+}
+
+// GetTrash mocks base method.
+func (m *MockEntryServiceServer) GetTrash(arg0 context.Context, arg1 *proto.GetTrashRequest) (*proto.GetTrashResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1)
+	ret0, _ := ret[0].(*proto.GetTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockEntryServiceServerMockRecorder) GetTrash(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockEntryServiceServer)(nil).GetTrash), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockEntryServiceServer) Restore(arg0 context.Context, arg1 *proto.RestoreEntryRequest) (*proto.RestoreEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(*proto.RestoreEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEntryServiceServerMockRecorder) Restore(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEntryServiceServer)(nil).Restore), arg0, arg1)
+}
+
+// Undelete mocks base method.
+func (m *MockEntryServiceServer) Undelete(arg0 context.Context, arg1 *proto.UndeleteEntryRequest) (*proto.UndeleteEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", arg0, arg1)
+	ret0, _ := ret[0].(*proto.UndeleteEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockEntryServiceServerMockRecorder) Undelete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockEntryServiceServer)(nil).Undelete), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockEntryServiceServer) Update(arg0 context.Context, arg1 *proto.UpdateEntryRequest) (*proto.UpdateEntryResponse, error) {
 	m.ctrl.T.Helper()
@@ -437,6 +1676,34 @@ func (mr *MockEntryServiceServerMockRecorder) Update(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEntryServiceServer)(nil).Update), arg0, arg1)
 }
 
+// UploadBlob mocks base method.
+func (m *MockEntryServiceServer) UploadBlob(arg0 proto.EntryService_UploadBlobServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBlob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockEntryServiceServerMockRecorder) UploadBlob(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*MockEntryServiceServer)(nil).UploadBlob), arg0)
+}
+
+// Watch mocks base method.
+func (m *MockEntryServiceServer) Watch(arg0 *proto.WatchEntriesRequest, arg1 proto.EntryService_WatchServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockEntryServiceServerMockRecorder) Watch(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockEntryServiceServer)(nil).Watch), arg0, arg1)
+}
+
 // mustEmbedUnimplementedEntryServiceServer mocks base method.
 func (m *MockEntryServiceServer) mustEmbedUnimplementedEntryServiceServer() {
 	m.ctrl.T.Helper()
@@ -483,3 +1750,375 @@ func (mr *MockUnsafeEntryServiceServerMockRecorder) mustEmbedUnimplementedEntryS
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedEntryServiceServer", reflect.TypeOf((*MockUnsafeEntryServiceServer)(nil).mustEmbedUnimplementedEntryServiceServer))
 }
+
+// MockEntryService_UploadBlobServer is a mock of EntryService_UploadBlobServer interface.
+type MockEntryService_UploadBlobServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_UploadBlobServerMockRecorder
+}
+
+// MockEntryService_UploadBlobServerMockRecorder is the mock recorder for MockEntryService_UploadBlobServer.
+type MockEntryService_UploadBlobServerMockRecorder struct {
+	mock *MockEntryService_UploadBlobServer
+}
+
+// NewMockEntryService_UploadBlobServer creates a new mock instance.
+func NewMockEntryService_UploadBlobServer(ctrl *gomock.Controller) *MockEntryService_UploadBlobServer {
+	mock := &MockEntryService_UploadBlobServer{ctrl: ctrl}
+	mock.recorder = &MockEntryService_UploadBlobServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_UploadBlobServer) EXPECT() *MockEntryService_UploadBlobServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockEntryService_UploadBlobServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockEntryService_UploadBlobServer) Recv() (*proto.UploadBlobRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*proto.UploadBlobRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_UploadBlobServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).RecvMsg), m)
+}
+
+// SendAndClose mocks base method.
+func (m *MockEntryService_UploadBlobServer) SendAndClose(arg0 *proto.UploadBlobResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) SendAndClose(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockEntryService_UploadBlobServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_UploadBlobServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockEntryService_UploadBlobServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockEntryService_UploadBlobServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockEntryService_UploadBlobServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockEntryService_UploadBlobServer)(nil).SetTrailer), arg0)
+}
+
+// MockEntryService_DownloadBlobServer is a mock of EntryService_DownloadBlobServer interface.
+type MockEntryService_DownloadBlobServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_DownloadBlobServerMockRecorder
+}
+
+// MockEntryService_DownloadBlobServerMockRecorder is the mock recorder for MockEntryService_DownloadBlobServer.
+type MockEntryService_DownloadBlobServerMockRecorder struct {
+	mock *MockEntryService_DownloadBlobServer
+}
+
+// NewMockEntryService_DownloadBlobServer creates a new mock instance.
+func NewMockEntryService_DownloadBlobServer(ctrl *gomock.Controller) *MockEntryService_DownloadBlobServer {
+	mock := &MockEntryService_DownloadBlobServer{ctrl: ctrl}
+	mock.recorder = &MockEntryService_DownloadBlobServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_DownloadBlobServer) EXPECT() *MockEntryService_DownloadBlobServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockEntryService_DownloadBlobServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_DownloadBlobServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockEntryService_DownloadBlobServer) Send(arg0 *proto.DownloadBlobResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockEntryService_DownloadBlobServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_DownloadBlobServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockEntryService_DownloadBlobServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockEntryService_DownloadBlobServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockEntryService_DownloadBlobServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockEntryService_DownloadBlobServer)(nil).SetTrailer), arg0)
+}
+
+// MockEntryService_WatchServer is a mock of EntryService_WatchServer interface.
+type MockEntryService_WatchServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryService_WatchServerMockRecorder
+}
+
+// MockEntryService_WatchServerMockRecorder is the mock recorder for MockEntryService_WatchServer.
+type MockEntryService_WatchServerMockRecorder struct {
+	mock *MockEntryService_WatchServer
+}
+
+// NewMockEntryService_WatchServer creates a new mock instance.
+func NewMockEntryService_WatchServer(ctrl *gomock.Controller) *MockEntryService_WatchServer {
+	mock := &MockEntryService_WatchServer{ctrl: ctrl}
+	mock.recorder = &MockEntryService_WatchServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryService_WatchServer) EXPECT() *MockEntryService_WatchServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockEntryService_WatchServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockEntryService_WatchServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockEntryService_WatchServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockEntryService_WatchServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockEntryService_WatchServerMockRecorder) RecvMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockEntryService_WatchServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockEntryService_WatchServer) Send(arg0 *proto.WatchEntriesResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockEntryService_WatchServerMockRecorder) Send(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockEntryService_WatchServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockEntryService_WatchServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockEntryService_WatchServerMockRecorder) SendHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockEntryService_WatchServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockEntryService_WatchServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockEntryService_WatchServerMockRecorder) SendMsg(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockEntryService_WatchServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockEntryService_WatchServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockEntryService_WatchServerMockRecorder) SetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockEntryService_WatchServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockEntryService_WatchServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockEntryService_WatchServerMockRecorder) SetTrailer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockEntryService_WatchServer)(nil).SetTrailer), arg0)
+}
@@ -8,6 +8,11 @@ const (
 	EntryTypeNote        EntryType = "note"
 	EntryTypeCard        EntryType = "card"
 	EntryTypeBinary      EntryType = "binary"
+	EntryTypeTOTP        EntryType = "totp"
+	EntryTypeSSHKey      EntryType = "ssh_key"
+	EntryTypeAPIToken    EntryType = "api_token"
+	EntryTypeIdentity    EntryType = "identity"
+	EntryTypeWiFi        EntryType = "wifi"
 )
 
 type (
@@ -15,13 +20,12 @@ type (
 	EntryVersion struct {
 		ID      uuid.UUID
 		Version int64
+		Deleted bool
 	}
 )
 
+// Valid reports whether the type is registered, see RegisterEntrySchema
 func (t EntryType) Valid() bool {
-	switch t {
-	case EntryTypePassword, EntryTypeNote, EntryTypeCard, EntryTypeBinary:
-		return true
-	}
-	return false
+	_, ok := LookupEntrySchema(t)
+	return ok
 }
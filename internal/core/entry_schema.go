@@ -0,0 +1,249 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FieldKindText      FieldKind = "text"
+	FieldKindSecret    FieldKind = "secret"
+	FieldKindMultiline FieldKind = "multiline"
+	FieldKindDate      FieldKind = "date" // DateLayout
+	FieldKindFile      FieldKind = "file" // the file content, forms read it by the file path
+
+	DateLayout = "2006-01-02"
+)
+
+type (
+	// FieldKind describes how the entry field is edited and validated
+	FieldKind string
+	// EntryField declares the entry data field
+	EntryField struct {
+		Name     string
+		Kind     FieldKind
+		Required bool
+		Validate func(value string) error // optional, called for non-empty values
+	}
+	// EntrySchema declares the entry type and its data fields, the data of built-in types is typed,
+	// the data of other types is the field map marshaled, validated and edited by the schema
+	EntrySchema struct {
+		Type   EntryType
+		Fields []EntryField
+		// Copy is the data field copied by default, the first secret field when empty
+		Copy string
+		// Data is the zero typed data of the built-in type, nil for the field map, see RegisterEntryData
+		Data EntryData
+	}
+	// EntryData is the typed data of the built-in type, its values are named by the schema fields
+	EntryData interface {
+		Marshal() ([]byte, error)
+		// Unmarshal returns the new data of the same type decoded from data
+		Unmarshal(data []byte) (EntryData, error)
+		Fields() map[string]any
+		// WithFields returns the copy of the data with the non-empty values set
+		WithFields(values map[string]string) (EntryData, error)
+	}
+)
+
+var (
+	entrySchemasMu sync.RWMutex
+	entrySchemas   []EntrySchema
+)
+
+func init() {
+	for _, schema := range []EntrySchema{
+		{Type: EntryTypePassword, Copy: "password", Fields: []EntryField{
+			{Name: "login", Kind: FieldKindText, Required: true},
+			{Name: "password", Kind: FieldKindSecret, Required: true},
+		}},
+		{Type: EntryTypeNote, Fields: []EntryField{
+			{Name: "note", Kind: FieldKindMultiline, Required: true},
+		}},
+		{Type: EntryTypeCard, Copy: "number", Fields: []EntryField{
+			{Name: "number", Kind: FieldKindText, Required: true},
+			{Name: "expires", Kind: FieldKindText},
+			{Name: "cvc", Kind: FieldKindSecret},
+			{Name: "owner", Kind: FieldKindText},
+		}},
+		{Type: EntryTypeBinary, Fields: []EntryField{
+			{Name: "binary", Kind: FieldKindFile, Required: true},
+		}},
+		// the copied code is computed from the secret
+		{Type: EntryTypeTOTP, Copy: "code", Fields: []EntryField{
+			{Name: "secret", Kind: FieldKindSecret, Required: true},
+			{Name: "algorithm", Kind: FieldKindText},
+			{Name: "digits", Kind: FieldKindText},
+			{Name: "period", Kind: FieldKindText},
+		}},
+		{Type: EntryTypeSSHKey, Fields: []EntryField{
+			{Name: "private_key", Kind: FieldKindFile, Required: true, Validate: validatePEM},
+			{Name: "public_key", Kind: FieldKindMultiline},
+			{Name: "passphrase", Kind: FieldKindSecret},
+		}},
+		{Type: EntryTypeAPIToken, Fields: []EntryField{
+			{Name: "service", Kind: FieldKindText, Required: true},
+			{Name: "token", Kind: FieldKindSecret, Required: true},
+			{Name: "expires", Kind: FieldKindDate},
+		}},
+		{Type: EntryTypeIdentity, Fields: []EntryField{
+			{Name: "document", Kind: FieldKindText, Required: true},
+			{Name: "number", Kind: FieldKindSecret, Required: true},
+			{Name: "full_name", Kind: FieldKindText},
+			{Name: "country", Kind: FieldKindText},
+			{Name: "issued", Kind: FieldKindDate},
+			{Name: "expires", Kind: FieldKindDate},
+		}},
+		{Type: EntryTypeWiFi, Fields: []EntryField{
+			{Name: "ssid", Kind: FieldKindText, Required: true},
+			{Name: "password", Kind: FieldKindSecret},
+			{Name: "security", Kind: FieldKindText, Validate: validateWiFiSecurity},
+		}},
+	} {
+		RegisterEntrySchema(schema)
+	}
+}
+
+// RegisterEntrySchema adds the entry type, it panics if the type is registered twice or the schema is invalid
+func RegisterEntrySchema(schema EntrySchema) {
+	entrySchemasMu.Lock()
+	defer entrySchemasMu.Unlock()
+	if schema.Type == EntryTypeUnspecified || len(schema.Fields) == 0 {
+		panic(fmt.Sprintf("core: invalid entry schema %q", schema.Type))
+	}
+	if slices.ContainsFunc(entrySchemas, func(v EntrySchema) bool { return v.Type == schema.Type }) {
+		panic(fmt.Sprintf("core: entry schema %q registered twice", schema.Type))
+	}
+	entrySchemas = append(entrySchemas, schema)
+}
+
+// RegisterEntryData sets the typed data of the registered type,
+// it panics if the type isn't registered or its data is set twice
+func RegisterEntryData(t EntryType, data EntryData) {
+	entrySchemasMu.Lock()
+	defer entrySchemasMu.Unlock()
+	idx := slices.IndexFunc(entrySchemas, func(v EntrySchema) bool { return v.Type == t })
+	if idx < 0 || data == nil {
+		panic(fmt.Sprintf("core: invalid entry data of %q", t))
+	}
+	if entrySchemas[idx].Data != nil {
+		panic(fmt.Sprintf("core: entry data of %q registered twice", t))
+	}
+	entrySchemas[idx].Data = data
+}
+
+// LookupEntryDataType returns the type the typed data is registered for
+func LookupEntryDataType(data EntryData) (EntryType, bool) {
+	entrySchemasMu.RLock()
+	defer entrySchemasMu.RUnlock()
+	idx := slices.IndexFunc(entrySchemas, func(v EntrySchema) bool {
+		return v.Data != nil && reflect.TypeOf(v.Data) == reflect.TypeOf(data)
+	})
+	if idx < 0 {
+		return EntryTypeUnspecified, false
+	}
+	return entrySchemas[idx].Type, true
+}
+
+// EntrySchemas returns the registered entry types in the registration order
+func EntrySchemas() []EntrySchema {
+	entrySchemasMu.RLock()
+	defer entrySchemasMu.RUnlock()
+	return slices.Clone(entrySchemas)
+}
+
+// EntryTypes returns the registered entry types in the registration order
+func EntryTypes() []EntryType {
+	schemas := EntrySchemas()
+	types := make([]EntryType, len(schemas))
+	for i, v := range schemas {
+		types[i] = v.Type
+	}
+	return types
+}
+
+func LookupEntrySchema(t EntryType) (EntrySchema, bool) {
+	entrySchemasMu.RLock()
+	defer entrySchemasMu.RUnlock()
+	idx := slices.IndexFunc(entrySchemas, func(v EntrySchema) bool { return v.Type == t })
+	if idx < 0 {
+		return EntrySchema{}, false
+	}
+	return entrySchemas[idx], true
+}
+
+func (s EntrySchema) Field(name string) (EntryField, bool) {
+	idx := slices.IndexFunc(s.Fields, func(v EntryField) bool { return v.Name == name })
+	if idx < 0 {
+		return EntryField{}, false
+	}
+	return s.Fields[idx], true
+}
+
+// CopyField returns the data field copied by default, it's empty if the type has no secret
+func (s EntrySchema) CopyField() string {
+	if s.Copy != "" {
+		return s.Copy
+	}
+	for _, f := range s.Fields {
+		if f.Kind == FieldKindSecret || f.Kind == FieldKindFile {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// Validate checks the field values: required fields, unknown fields, dates and field validators
+func (s EntrySchema) Validate(values map[string]string) (err error) {
+	for name := range values {
+		if _, ok := s.Field(name); !ok {
+			err = errors.Join(err, fmt.Errorf("%s: unknown field %q", s.Type, name))
+		}
+	}
+	for _, f := range s.Fields {
+		if fieldErr := f.validate(values[f.Name]); fieldErr != nil {
+			err = errors.Join(err, fmt.Errorf("%s: %w", s.Type, fieldErr))
+		}
+	}
+	return err
+}
+
+func (f EntryField) validate(value string) error {
+	if value == "" {
+		if f.Required {
+			return fmt.Errorf("%s should not be empty", f.Name)
+		}
+		return nil
+	}
+	if f.Kind == FieldKindDate {
+		if _, err := time.Parse(DateLayout, value); err != nil {
+			return fmt.Errorf("%s should be a YYYY-MM-DD date", f.Name)
+		}
+	}
+	if f.Validate != nil {
+		if err := f.Validate(value); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func validatePEM(value string) error {
+	if !strings.Contains(value, "-----BEGIN ") {
+		return errors.New("PEM or OpenSSH private key expected")
+	}
+	return nil
+}
+
+func validateWiFiSecurity(value string) error {
+	switch strings.ToUpper(value) {
+	case "WPA3", "WPA2", "WPA", "WEP", "NONE":
+		return nil
+	}
+	return errors.New("WPA3, WPA2, WPA, WEP or none expected")
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// FolderSeparator separates the nested folder names in the folder path, e.g. "Work/Servers"
+const FolderSeparator = "/"
+
+// NormalizeFolder trims the folder path names and drops the empty ones, the root folder is empty
+func NormalizeFolder(folder string) string {
+	names := strings.Split(folder, FolderSeparator)
+	result := names[:0]
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return strings.Join(result, FolderSeparator)
+}
+
+// NormalizeTags trims, sorts and dedupes the tags, nil is returned for no tags
+func NormalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// InFolder reports whether the folder is the parent folder or one of its subfolders
+func InFolder(folder, parent string) bool {
+	return parent == "" ||
+		folder == parent ||
+		strings.HasPrefix(folder, parent+FolderSeparator)
+}
@@ -0,0 +1,260 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Digits = 6
+	Period = 30 * time.Second
+	// Skew is a number of steps accepted before and after the current one
+	Skew = 1
+
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+
+	secretSize = 20
+)
+
+var (
+	ErrSecretInvalid = errors.New("otp: invalid secret")
+	ErrURIInvalid    = errors.New("otp: invalid uri")
+	ErrKeyInvalid    = errors.New("otp: invalid key")
+
+	encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// Key describes the otpauth://totp key
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    string
+	Algorithm string // SHA1 by default
+	Digits    int
+	Period    time.Duration
+}
+
+// GenerateSecret returns a random base32 encoded secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("otp: failed to generate secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// Step returns the time step number for t
+func Step(t time.Time, period time.Duration) int64 {
+	return t.Unix() / int64(period/time.Second)
+}
+
+// Code returns the code of secret for t
+func Code(secret string, t time.Time) (string, error) {
+	return Key{Secret: secret}.Code(t)
+}
+
+// Validate checks the code of secret for t with Skew steps tolerance,
+// the matched step is returned to reject replays.
+func Validate(secret string, code string, t time.Time) (step int64, ok bool, err error) {
+	return Key{Secret: secret}.Validate(code, t)
+}
+
+func (k Key) Code(t time.Time) (string, error) {
+	return k.code(Step(t, k.period()))
+}
+
+func (k Key) Validate(code string, t time.Time) (step int64, ok bool, err error) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != k.digits() {
+		return 0, false, nil
+	}
+	current := Step(t, k.period())
+	for i := -Skew; i <= Skew; i++ {
+		expected, err := k.code(current + int64(i))
+		if err != nil {
+			return 0, false, err
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return current + int64(i), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// URI returns the provisioning otpauth://totp URI
+func (k Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+	query := url.Values{}
+	query.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		query.Set("issuer", k.Issuer)
+	}
+	query.Set("algorithm", k.algorithm())
+	query.Set("digits", strconv.Itoa(k.digits()))
+	query.Set("period", strconv.Itoa(int(k.period()/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// ParseURI parses the otpauth://totp URI, SHA1, SHA256 and SHA512 keys are supported
+func ParseURI(uri string) (Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %w", ErrURIInvalid, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return Key{}, fmt.Errorf("%w: otpauth://totp expected", ErrURIInvalid)
+	}
+	query := u.Query()
+	key := Key{
+		Secret:    strings.ToUpper(query.Get("secret")),
+		Issuer:    query.Get("issuer"),
+		Algorithm: AlgorithmSHA1,
+		Digits:    Digits,
+		Period:    Period,
+	}
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, found := strings.Cut(label, ":"); found {
+		key.Account = strings.TrimSpace(account)
+		if key.Issuer == "" {
+			key.Issuer = issuer
+		}
+	} else {
+		key.Account = label
+	}
+	if alg := query.Get("algorithm"); alg != "" {
+		key.Algorithm = strings.ToUpper(alg)
+		if _, err = key.hash(); err != nil {
+			return Key{}, fmt.Errorf("%w: %w", ErrURIInvalid, err)
+		}
+	}
+	if v := query.Get("digits"); v != "" {
+		if key.Digits, err = strconv.Atoi(v); err != nil || (key.Digits != 6 && key.Digits != 8) {
+			return Key{}, fmt.Errorf("%w: invalid digits %s", ErrURIInvalid, v)
+		}
+	}
+	if v := query.Get("period"); v != "" {
+		period, err := strconv.Atoi(v)
+		if err != nil || period <= 0 {
+			return Key{}, fmt.Errorf("%w: invalid period %s", ErrURIInvalid, v)
+		}
+		key.Period = time.Duration(period) * time.Second
+	}
+	if _, err = decode(key.Secret); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+func (k Key) code(step int64) (string, error) {
+	secret, err := decode(k.Secret)
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+	h, err := k.hash()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(h, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < k.digits(); i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", k.digits(), value%mod), nil
+}
+
+// Remaining returns the time left until the code of t expires
+func (k Key) Remaining(t time.Time) time.Duration {
+	period := k.period()
+	return period - time.Duration(t.Unix()%int64(period/time.Second))*time.Second
+}
+
+// Valid checks the algorithm, digits, period and secret of the key
+func (k Key) Valid() error {
+	if _, err := k.hash(); err != nil {
+		return err
+	}
+	if d := k.digits(); d != 6 && d != 8 {
+		return fmt.Errorf("%w: invalid digits %d", ErrKeyInvalid, d)
+	}
+	if k.period() < time.Second {
+		return fmt.Errorf("%w: invalid period %s", ErrKeyInvalid, k.period())
+	}
+	_, err := decode(k.Secret)
+	return err
+}
+
+func (k Key) hash() (func() hash.Hash, error) {
+	switch k.algorithm() {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %s", ErrKeyInvalid, k.Algorithm)
+	}
+}
+
+func (k Key) algorithm() string {
+	if k.Algorithm == "" {
+		return AlgorithmSHA1
+	}
+	return k.Algorithm
+}
+
+func (k Key) digits() int {
+	if k.Digits == 0 {
+		return Digits
+	}
+	return k.Digits
+}
+
+func (k Key) period() time.Duration {
+	if k.Period == 0 {
+		return Period
+	}
+	return k.Period
+}
+
+func decode(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, ErrSecretInvalid
+	}
+	v, err := encoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSecretInvalid, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,134 @@
+package otp_test
+
+import (
+	"encoding/base32"
+	"github.com/dlomanov/gophkeeper/internal/infra/otp"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// RFC 6238 test vectors for SHA1
+func TestCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+	}
+	for _, tt := range tests {
+		key := otp.Key{Secret: secret, Digits: 8}
+		got, err := key.Code(time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+
+		got, err = otp.Code(secret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tt.want[2:], got, "6 digits expected by default")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := otp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	code, err := otp.Code(secret, now.Add(-otp.Period))
+	require.NoError(t, err)
+	step, ok, err := otp.Validate(secret, code, now)
+	require.NoError(t, err)
+	require.True(t, ok, "previous step should be accepted")
+	require.Equal(t, otp.Step(now, otp.Period)-1, step)
+
+	code, err = otp.Code(secret, now.Add(-3*otp.Period))
+	require.NoError(t, err)
+	_, ok, err = otp.Validate(secret, code, now)
+	require.NoError(t, err)
+	require.False(t, ok, "stale code should be rejected")
+
+	_, ok, err = otp.Validate(secret, "12", now)
+	require.NoError(t, err)
+	require.False(t, ok, "short code should be rejected")
+
+	_, _, err = otp.Validate("!", "123456", now)
+	require.ErrorIs(t, err, otp.ErrSecretInvalid)
+}
+
+func TestURI(t *testing.T) {
+	secret, err := otp.GenerateSecret()
+	require.NoError(t, err)
+	key := otp.Key{Issuer: "gophkeeper", Account: "user@example.com", Secret: secret}
+
+	got, err := otp.ParseURI(key.URI())
+	require.NoError(t, err)
+	require.Equal(t, otp.Key{
+		Issuer:    "gophkeeper",
+		Account:   "user@example.com",
+		Secret:    secret,
+		Algorithm: otp.AlgorithmSHA1,
+		Digits:    otp.Digits,
+		Period:    otp.Period,
+	}, got)
+
+	got, err = otp.ParseURI("otpauth://totp/ACME:john?secret=jbswy3dpehpk3pxp&algorithm=sha256&digits=8&period=60")
+	require.NoError(t, err)
+	require.Equal(t, otp.Key{
+		Issuer:    "ACME",
+		Account:   "john",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: otp.AlgorithmSHA256,
+		Digits:    8,
+		Period:    time.Minute,
+	}, got)
+
+	for _, uri := range []string{
+		"otpauth://hotp/john?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&digits=7",
+		"otpauth://totp/john",
+	} {
+		_, err = otp.ParseURI(uri)
+		require.Error(t, err, uri)
+	}
+}
+
+// RFC 6238 test vectors for SHA256 and SHA512
+func TestKey_Code_algorithms(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		secret    string
+		unix      int64
+		want      string
+	}{
+		{algorithm: otp.AlgorithmSHA256, secret: "12345678901234567890123456789012", unix: 59, want: "46119246"},
+		{algorithm: otp.AlgorithmSHA256, secret: "12345678901234567890123456789012", unix: 1111111109, want: "68084774"},
+		{algorithm: otp.AlgorithmSHA512, secret: "1234567890123456789012345678901234567890123456789012345678901234", unix: 59, want: "90693936"},
+		{algorithm: otp.AlgorithmSHA512, secret: "1234567890123456789012345678901234567890123456789012345678901234", unix: 1111111109, want: "25091201"},
+	}
+	for _, tt := range tests {
+		key := otp.Key{
+			Secret:    base32.StdEncoding.EncodeToString([]byte(tt.secret)),
+			Algorithm: tt.algorithm,
+			Digits:    8,
+		}
+		require.NoError(t, key.Valid())
+		got, err := key.Code(time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, "%s code mismatch", tt.algorithm)
+	}
+
+	require.ErrorIs(t, otp.Key{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "MD5"}.Valid(), otp.ErrKeyInvalid)
+	require.ErrorIs(t, otp.Key{Secret: "JBSWY3DPEHPK3PXP", Digits: 4}.Valid(), otp.ErrKeyInvalid)
+	require.ErrorIs(t, otp.Key{Secret: "!"}.Valid(), otp.ErrSecretInvalid)
+}
+
+func TestKey_Remaining(t *testing.T) {
+	key := otp.Key{Secret: "JBSWY3DPEHPK3PXP"}
+	require.Equal(t, 30*time.Second, key.Remaining(time.Unix(60, 0)))
+	require.Equal(t, 1*time.Second, key.Remaining(time.Unix(89, 0)))
+}